@@ -3,6 +3,7 @@ package abciclient
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/tendermint/tendermint/abci/types"
@@ -22,6 +23,9 @@ const (
 //
 // All `Async` methods return a `ReqRes` object and an error.
 // All `Sync` methods return the appropriate protobuf ResponseXxx struct and an error.
+// All `Stream` methods return a reader over the payload of the response, which
+// the app sends in parts, and an error. The context bounds the whole response,
+// including reading it. The reader must be closed.
 //
 // NOTE these are client errors, eg. ABCI socket connectivity issues.
 // Application-related errors are reflected in response via ABCI error codes
@@ -63,16 +67,22 @@ type Client interface {
 	OfferSnapshotSync(context.Context, types.RequestOfferSnapshot) (*types.ResponseOfferSnapshot, error)
 	LoadSnapshotChunkSync(context.Context, types.RequestLoadSnapshotChunk) (*types.ResponseLoadSnapshotChunk, error)
 	ApplySnapshotChunkSync(context.Context, types.RequestApplySnapshotChunk) (*types.ResponseApplySnapshotChunk, error)
+
+	// Streamed requests
+	QueryStream(context.Context, types.RequestQuery) (*types.ResponseQuery, io.ReadCloser, error)
+	LoadSnapshotChunkStream(context.Context, types.RequestLoadSnapshotChunk) (io.ReadCloser, error)
 }
 
 //----------------------------------------
 
 // NewClient returns a new ABCI client of the specified transport type.
-// It returns an error if the transport is not "socket" or "grpc"
+// It returns an error if the transport is not "socket", "shm" or "grpc"
 func NewClient(logger log.Logger, addr, transport string, mustConnect bool) (client Client, err error) {
 	switch transport {
 	case "socket":
 		client = NewSocketClient(logger, addr, mustConnect)
+	case "shm":
+		client = NewShmClient(logger, addr, mustConnect)
 	case "grpc":
 		client = NewGRPCClient(logger, addr, mustConnect)
 	default:
@@ -91,6 +101,8 @@ type ReqRes struct {
 	mtx  tmsync.Mutex
 	done bool                  // Gets set to true once *after* WaitGroup.Done().
 	cb   func(*types.Response) // A single callback that may be set.
+
+	stream *responseStream // set for streamed requests, which take all parts
 }
 
 func NewReqRes(req *types.Request) *ReqRes {
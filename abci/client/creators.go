@@ -12,7 +12,8 @@ import (
 type Creator func(log.Logger) (Client, error)
 
 // NewLocalCreator returns a Creator for the given app,
-// which will be running locally.
+// which will be running locally. All calls made through its clients are
+// serialized by a single mutex, no matter how many clients are created.
 func NewLocalCreator(app types.Application) Creator {
 	mtx := new(tmsync.Mutex)
 
@@ -21,6 +22,19 @@ func NewLocalCreator(app types.Application) Creator {
 	}
 }
 
+// NewConcurrentQueryLocalCreator returns a Creator for the given app, which
+// will be running locally. Unlike with NewLocalCreator, Query calls made
+// through its clients are not serialized with the other calls, so read-heavy
+// query traffic can use several cores. The app must be safe for concurrent
+// use by Query.
+func NewConcurrentQueryLocalCreator(app types.Application) Creator {
+	mtx := new(tmsync.Mutex)
+
+	return func(_ log.Logger) (Client, error) {
+		return NewConcurrentQueryLocalClient(mtx, app), nil
+	}
+}
+
 // NewRemoteCreator returns a Creator for the given address (e.g.
 // "192.168.0.1") and transport (e.g. "tcp"). Set mustConnect to true if you
 // want the client to connect before reporting success.
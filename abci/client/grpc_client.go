@@ -1,13 +1,17 @@
 package abciclient
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"sync"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/tendermint/tendermint/abci/types"
 	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
@@ -368,6 +372,95 @@ func (cli *grpcClient) finishSyncCall(reqres *ReqRes) *types.Response {
 
 //----------------------------------------
 
+// NOTE: Apps serving the ABCIApplication service without the streaming
+// methods are sent a unary request instead.
+func (cli *grpcClient) QueryStream(
+	ctx context.Context,
+	params types.RequestQuery,
+) (*types.ResponseQuery, io.ReadCloser, error) {
+	sctx, cancel := context.WithCancel(ctx)
+	stream, err := cli.client.QueryStream(sctx, &params, grpc.WaitForReady(true))
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	next := grpcStreamNext(sctx, func() (*types.Response, error) {
+		part, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return types.ToResponseQueryPart(*part), nil
+	})
+
+	header, r, err := openQueryStream(sctx, next, cancel)
+	if status.Code(err) == codes.Unimplemented {
+		res, err := cli.QuerySync(ctx, params)
+		if err != nil {
+			return nil, nil, err
+		}
+		header := *res
+		header.Value = nil
+		return &header, io.NopCloser(bytes.NewReader(res.Value)), nil
+	}
+	return header, r, err
+}
+
+// NOTE: Apps serving the ABCIApplication service without the streaming
+// methods are sent a unary request instead.
+func (cli *grpcClient) LoadSnapshotChunkStream(
+	ctx context.Context,
+	params types.RequestLoadSnapshotChunk,
+) (io.ReadCloser, error) {
+	sctx, cancel := context.WithCancel(ctx)
+	stream, err := cli.client.LoadSnapshotChunkStream(sctx, &params, grpc.WaitForReady(true))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	next := grpcStreamNext(sctx, func() (*types.Response, error) {
+		part, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return types.ToResponseLoadSnapshotChunkPart(*part), nil
+	})
+
+	r, err := openSnapshotChunkStream(sctx, next, cancel)
+	if status.Code(err) == codes.Unimplemented {
+		res, err := cli.LoadSnapshotChunkSync(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(res.Chunk)), nil
+	}
+	return r, err
+}
+
+// grpcStreamNext returns a function taking the parts of a gRPC response
+// stream opened with ctx from recv. The HTTP/2 flow control of the stream
+// holds up the server while the parts are not read. The stream must end with
+// its last part.
+func grpcStreamNext(
+	ctx context.Context,
+	recv func() (*types.Response, error),
+) func(context.Context) (*types.Response, error) {
+	return func(context.Context) (*types.Response, error) {
+		res, err := recv()
+		switch {
+		case err == nil:
+			return res, nil
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err == io.EOF:
+			return nil, io.ErrUnexpectedEOF
+		default:
+			return nil, err
+		}
+	}
+}
+
+//----------------------------------------
+
 func (cli *grpcClient) FlushSync(ctx context.Context) error {
 	return nil
 }
@@ -2,6 +2,7 @@ package abciclient
 
 import (
 	"context"
+	"io"
 
 	types "github.com/tendermint/tendermint/abci/types"
 	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
@@ -18,6 +19,9 @@ type localClient struct {
 	mtx *tmsync.Mutex
 	types.Application
 	Callback
+
+	// if set, Query calls do not take mtx
+	concurrentQueries bool
 }
 
 var _ Client = (*localClient)(nil)
@@ -26,6 +30,9 @@ var _ Client = (*localClient)(nil)
 // methods of the given app.
 //
 // Both Async and Sync methods ignore the given context.Context parameter.
+// Stream methods use it to bound the whole response; the mutex is held while
+// the application produces the response, but not while waiting for the reader
+// to take a part.
 func NewLocalClient(mtx *tmsync.Mutex, app types.Application) Client {
 	if mtx == nil {
 		mtx = new(tmsync.Mutex)
@@ -38,6 +45,25 @@ func NewLocalClient(mtx *tmsync.Mutex, app types.Application) Client {
 	return cli
 }
 
+// NewConcurrentQueryLocalClient creates a local client like NewLocalClient,
+// except that Query calls do not take the given mutex and may thus run
+// concurrently with any other call to the app. The app must be safe for such
+// concurrent use.
+func NewConcurrentQueryLocalClient(mtx *tmsync.Mutex, app types.Application) Client {
+	cli := NewLocalClient(mtx, app).(*localClient)
+	cli.concurrentQueries = true
+	return cli
+}
+
+// IsLocalClient returns true if the given client calls the app directly. The
+// local clients created for an app share a single mutex, so calls are not
+// spread over several of them; an app serving concurrent queries uses
+// NewConcurrentQueryLocalClient instead.
+func IsLocalClient(client Client) bool {
+	_, ok := client.(*localClient)
+	return ok
+}
+
 func (*localClient) OnStart(context.Context) error { return nil }
 func (*localClient) OnStop()                       {}
 
@@ -101,8 +127,7 @@ func (app *localClient) CheckTxAsync(ctx context.Context, req types.RequestCheck
 }
 
 func (app *localClient) QueryAsync(ctx context.Context, req types.RequestQuery) (*ReqRes, error) {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	defer app.lockQuery()()
 
 	res := app.Application.Query(req)
 	return app.callback(
@@ -250,8 +275,7 @@ func (app *localClient) QuerySync(
 	ctx context.Context,
 	req types.RequestQuery,
 ) (*types.ResponseQuery, error) {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	defer app.lockQuery()()
 
 	res := app.Application.Query(req)
 	return &res, nil
@@ -349,6 +373,60 @@ func (app *localClient) ApplySnapshotChunkSync(
 
 //-------------------------------------------------------
 
+func (app *localClient) QueryStream(
+	ctx context.Context,
+	req types.RequestQuery,
+) (*types.ResponseQuery, io.ReadCloser, error) {
+	stream := newResponseStream(streamStallTimeout)
+	go func() {
+		unlock := app.lockQuery()
+		defer func() { unlock() }()
+
+		err := types.StreamQuery(app.Application, req, func(part *types.ResponseQueryPart) error {
+			unlock()
+			defer func() { unlock = app.lockQuery() }()
+			return stream.deliver(types.ToResponseQueryPart(*part))
+		})
+		if err != nil {
+			stream.abort(err)
+		}
+	}()
+	return openQueryStream(ctx, stream.next, stream.close)
+}
+
+func (app *localClient) LoadSnapshotChunkStream(
+	ctx context.Context,
+	req types.RequestLoadSnapshotChunk,
+) (io.ReadCloser, error) {
+	stream := newResponseStream(streamStallTimeout)
+	go func() {
+		app.mtx.Lock()
+		defer app.mtx.Unlock()
+
+		err := types.StreamSnapshotChunk(app.Application, req, func(part *types.ResponseLoadSnapshotChunkPart) error {
+			app.mtx.Unlock()
+			defer app.mtx.Lock()
+			return stream.deliver(types.ToResponseLoadSnapshotChunkPart(*part))
+		})
+		if err != nil {
+			stream.abort(err)
+		}
+	}()
+	return openSnapshotChunkStream(ctx, stream.next, stream.close)
+}
+
+//-------------------------------------------------------
+
+// lockQuery takes the mutex unless the client serves concurrent queries, and
+// returns the function releasing it.
+func (app *localClient) lockQuery() func() {
+	if app.concurrentQueries {
+		return func() {}
+	}
+	app.mtx.Lock()
+	return app.mtx.Unlock
+}
+
 func (app *localClient) callback(req *types.Request, res *types.Response) *ReqRes {
 	app.Callback(req, res)
 	return newLocalReqRes(req, res)
@@ -0,0 +1,45 @@
+//go:build linux
+// +build linux
+
+package abciclient_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abciclient "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/server"
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestShmClientSetupNotBlocked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.TestingLogger()
+	path := filepath.Join(t.TempDir(), "abci.sock")
+	addr := "unix://" + path
+
+	s, err := server.NewServer(logger, addr, "shm", types.NewBaseApplication())
+	require.NoError(t, err)
+	require.NoError(t, s.Start(ctx))
+	t.Cleanup(s.Wait)
+
+	// a client which never sets up shared memory must not hold up others
+	stalled, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	defer stalled.Close()
+
+	c := abciclient.NewShmClient(logger, addr, true)
+	require.NoError(t, c.Start(ctx))
+	t.Cleanup(c.Wait)
+
+	echo, err := c.EchoSync(ctx, "hello")
+	require.NoError(t, err)
+	require.Equal(t, "hello", echo.Message)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/internal/libs/shmconn"
 	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
 	"github.com/tendermint/tendermint/libs/log"
 	tmnet "github.com/tendermint/tendermint/libs/net"
@@ -37,6 +38,7 @@ type socketClient struct {
 
 	addr        string
 	mustConnect bool
+	dial        func(addr string) (net.Conn, error)
 	conn        net.Conn
 
 	reqQueue chan *reqResWithContext
@@ -45,8 +47,13 @@ type socketClient struct {
 	err     error
 	reqSent *list.List                            // list of requests sent, waiting for response
 	resCb   func(*types.Request, *types.Response) // called on all requests, if set.
+	drained bool                                  // set once all pending requests were resolved
 }
 
+// errSocketClientStopped ends the response streams pending when the client
+// is stopped without an error.
+var errSocketClientStopped = errors.New("abci.socketClient stopped")
+
 var _ Client = (*socketClient)(nil)
 
 // NewSocketClient creates a new socket client, which connects to a given
@@ -58,6 +65,7 @@ func NewSocketClient(logger log.Logger, addr string, mustConnect bool) Client {
 		reqQueue:    make(chan *reqResWithContext, reqQueueSize),
 		mustConnect: mustConnect,
 		addr:        addr,
+		dial:        tmnet.Connect,
 		reqSent:     list.New(),
 		resCb:       nil,
 	}
@@ -65,6 +73,35 @@ func NewSocketClient(logger log.Logger, addr string, mustConnect bool) Client {
 	return cli
 }
 
+// NewShmClient creates a new socket client, which connects to a given unix
+// socket address and transfers large requests and responses through shared
+// memory. The server must have been created with server.NewShmServer. If
+// mustConnect is true, the client will return an error upon start if it
+// fails to connect.
+func NewShmClient(logger log.Logger, addr string, mustConnect bool) Client {
+	cli := NewSocketClient(logger, addr, mustConnect).(*socketClient)
+	cli.dial = dialShm
+	return cli
+}
+
+func dialShm(addr string) (net.Conn, error) {
+	conn, err := tmnet.Connect(addr)
+	if err != nil {
+		return nil, err
+	}
+	uconn, ok := conn.(*net.UnixConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("shared memory transport requires a unix socket address, got %s", addr)
+	}
+	sconn, err := shmconn.Client(uconn, shmconn.DefaultRingSize)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return sconn, nil
+}
+
 // OnStart implements Service by connecting to the server and spawning reading
 // and writing goroutines.
 func (cli *socketClient) OnStart(ctx context.Context) error {
@@ -74,7 +111,7 @@ func (cli *socketClient) OnStart(ctx context.Context) error {
 	)
 
 	for {
-		conn, err = tmnet.Connect(cli.addr)
+		conn, err = cli.dial(cli.addr)
 		if err != nil {
 			if cli.mustConnect {
 				return err
@@ -202,6 +239,31 @@ func (cli *socketClient) didRecvResponse(res *types.Response) error {
 			reflect.TypeOf(res.Value), reflect.TypeOf(reqres.Request.Value))
 	}
 
+	if reqres.stream != nil {
+		// The parts of a streamed response are handed to its reader, which
+		// may take its time, so the mutex is released in the meantime. Parts
+		// are dropped once the reader was closed, or didn't keep up. An
+		// aborted stream only fails its reader.
+		_, last, err := partData(res)
+		if err != nil && !errors.Is(err, errStreamAborted) {
+			return err
+		}
+		cli.mtx.Unlock()
+		_ = reqres.stream.deliver(res)
+		cli.mtx.Lock()
+		if cli.drained {
+			// reqres was resolved while the mutex was released
+			return errSocketClientStopped
+		}
+		if !last {
+			return nil
+		}
+		reqres.Response = res
+		reqres.Done()
+		cli.reqSent.Remove(next)
+		return nil
+	}
+
 	reqres.Response = res
 	reqres.Done()            // release waiters
 	cli.reqSent.Remove(next) // pop first item from linked list
@@ -456,6 +518,32 @@ func (cli *socketClient) ApplySnapshotChunkSync(
 
 //----------------------------------------
 
+func (cli *socketClient) QueryStream(
+	ctx context.Context,
+	req types.RequestQuery,
+) (*types.ResponseQuery, io.ReadCloser, error) {
+	req.Stream = true
+	reqres, err := cli.queueStreamRequest(ctx, types.ToRequestQuery(req))
+	if err != nil {
+		return nil, nil, err
+	}
+	return openQueryStream(ctx, reqres.stream.next, reqres.stream.close)
+}
+
+func (cli *socketClient) LoadSnapshotChunkStream(
+	ctx context.Context,
+	req types.RequestLoadSnapshotChunk,
+) (io.ReadCloser, error) {
+	req.Stream = true
+	reqres, err := cli.queueStreamRequest(ctx, types.ToRequestLoadSnapshotChunk(req))
+	if err != nil {
+		return nil, err
+	}
+	return openSnapshotChunkStream(ctx, reqres.stream.next, reqres.stream.close)
+}
+
+//----------------------------------------
+
 // queueRequest enqueues req onto the queue. If the queue is full, it ether
 // returns an error (sync=false) or blocks (sync=true).
 //
@@ -466,22 +554,25 @@ func (cli *socketClient) ApplySnapshotChunkSync(
 // The caller is responsible for checking cli.Error.
 func (cli *socketClient) queueRequest(ctx context.Context, req *types.Request, sync bool) (*ReqRes, error) {
 	reqres := NewReqRes(req)
+	return reqres, cli.queue(ctx, reqres, sync)
+}
 
+func (cli *socketClient) queue(ctx context.Context, reqres *ReqRes, sync bool) error {
 	if sync {
 		select {
 		case cli.reqQueue <- &reqResWithContext{R: reqres, C: context.Background()}:
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return ctx.Err()
 		}
 	} else {
 		select {
 		case cli.reqQueue <- &reqResWithContext{R: reqres, C: ctx}:
 		default:
-			return nil, errors.New("buffer is full")
+			return errors.New("buffer is full")
 		}
 	}
 
-	return reqres, nil
+	return nil
 }
 
 func (cli *socketClient) queueRequestAsync(
@@ -514,6 +605,20 @@ func (cli *socketClient) queueRequestAndFlushSync(
 	return reqres, cli.Error()
 }
 
+// queueStreamRequest enqueues a request whose response is streamed. Since the
+// server writes out every response as soon as it's ready, no flush is needed.
+func (cli *socketClient) queueStreamRequest(ctx context.Context, req *types.Request) (*ReqRes, error) {
+	reqres := NewReqRes(req)
+	reqres.stream = newResponseStream(streamStallTimeout)
+	if err := cli.queue(ctx, reqres, true); err != nil {
+		return nil, queueErr(err)
+	}
+	if err := cli.Error(); err != nil {
+		reqres.stream.abort(err)
+	}
+	return reqres, nil
+}
+
 func queueErr(e error) error {
 	return fmt.Errorf("can't queue req: %w", e)
 }
@@ -523,10 +628,12 @@ func queueErr(e error) error {
 func (cli *socketClient) drainQueue() {
 	cli.mtx.Lock()
 	defer cli.mtx.Unlock()
+	cli.drained = true
 
 	// mark all in-flight messages as resolved (they will get cli.Error())
 	for req := cli.reqSent.Front(); req != nil; req = req.Next() {
 		reqres := req.Value.(*ReqRes)
+		cli.abortStream(reqres)
 		reqres.Done()
 	}
 
@@ -538,6 +645,7 @@ func (cli *socketClient) drainQueue() {
 	for {
 		select {
 		case reqres := <-cli.reqQueue:
+			cli.abortStream(reqres.R)
 			reqres.R.Done()
 		default:
 			return
@@ -545,6 +653,18 @@ func (cli *socketClient) drainQueue() {
 	}
 }
 
+// abortStream ends the response stream of reqres, if any, with cli.err.
+func (cli *socketClient) abortStream(reqres *ReqRes) {
+	if reqres.stream == nil {
+		return
+	}
+	err := cli.err
+	if err == nil {
+		err = errSocketClientStopped
+	}
+	reqres.stream.abort(err)
+}
+
 //----------------------------------------
 
 func resMatchesReq(req *types.Request, res *types.Response) (ok bool) {
@@ -563,6 +683,9 @@ func resMatchesReq(req *types.Request, res *types.Response) (ok bool) {
 		_, ok = res.Value.(*types.Response_Commit)
 	case *types.Request_Query:
 		_, ok = res.Value.(*types.Response_Query)
+		if !ok && req.GetQuery().Stream {
+			_, ok = res.Value.(*types.Response_QueryPart)
+		}
 	case *types.Request_InitChain:
 		_, ok = res.Value.(*types.Response_InitChain)
 	case *types.Request_BeginBlock:
@@ -573,6 +696,9 @@ func resMatchesReq(req *types.Request, res *types.Response) (ok bool) {
 		_, ok = res.Value.(*types.Response_ApplySnapshotChunk)
 	case *types.Request_LoadSnapshotChunk:
 		_, ok = res.Value.(*types.Response_LoadSnapshotChunk)
+		if !ok && req.GetLoadSnapshotChunk().Stream {
+			_, ok = res.Value.(*types.Response_LoadSnapshotChunkPart)
+		}
 	case *types.Request_ListSnapshots:
 		_, ok = res.Value.(*types.Response_ListSnapshots)
 	case *types.Request_OfferSnapshot:
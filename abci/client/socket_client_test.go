@@ -1,8 +1,11 @@
 package abciclient_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
@@ -48,6 +51,138 @@ func TestProperSyncCalls(t *testing.T) {
 	}
 }
 
+func TestStreamCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.TestingLogger()
+	chunk := bytes.Repeat([]byte{0xab, 0xcd, 0xef}, types.StreamPartSize)
+
+	for name, app := range map[string]types.Application{
+		"streaming":     streamApp{chunkApp{chunk: chunk}},
+		"non-streaming": chunkApp{chunk: chunk},
+	} {
+		app := app
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			_, c := setupClientServer(ctx, t, logger, app)
+
+			r, err := c.LoadSnapshotChunkStream(ctx, types.RequestLoadSnapshotChunk{Height: 1})
+			require.NoError(t, err)
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, chunk, data)
+
+			res, r, err := c.QueryStream(ctx, types.RequestQuery{Path: "/chunk"})
+			require.NoError(t, err)
+			assert.EqualValues(t, 7, res.Height)
+			assert.Empty(t, res.Value)
+			data, err = io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, chunk, data)
+
+			// the connection is usable once the streams have been read
+			echo, err := c.EchoSync(ctx, "hello")
+			require.NoError(t, err)
+			assert.Equal(t, "hello", echo.Message)
+		})
+	}
+}
+
+func TestStreamCallsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.TestingLogger()
+	chunk := bytes.Repeat([]byte{0xab}, 8*types.StreamPartSize)
+	_, c := setupClientServer(ctx, t, logger, streamApp{chunkApp{chunk: chunk}})
+
+	rctx, rcancel := context.WithCancel(ctx)
+	r, err := c.LoadSnapshotChunkStream(rctx, types.RequestLoadSnapshotChunk{Height: 1})
+	require.NoError(t, err)
+	defer r.Close()
+
+	buf := make([]byte, 1024)
+	_, err = io.ReadFull(r, buf)
+	require.NoError(t, err)
+
+	// giving up on the response fails further reads, and the rest of it is
+	// dropped without holding up the connection
+	rcancel()
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, context.Canceled)
+
+	echo, err := c.EchoSync(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", echo.Message)
+}
+
+func TestStreamCallsError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.TestingLogger()
+	chunk := bytes.Repeat([]byte{0xab}, 2*types.StreamPartSize)
+	_, c := setupClientServer(ctx, t, logger, failingStreamApp{streamApp{chunkApp{chunk: chunk}}})
+
+	// the error of a stream failing midway is returned by the reader once
+	// the parts sent before have been read
+	r, err := c.LoadSnapshotChunkStream(ctx, types.RequestLoadSnapshotChunk{Height: 1})
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "disk failure")
+	require.NoError(t, r.Close())
+	assert.Less(t, len(data), len(chunk))
+
+	// a query failing before its response is opened fails to open
+	_, _, err = c.QueryStream(ctx, types.RequestQuery{Path: "/chunk"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "disk failure")
+
+	// the errors are reported in the streams, and don't stop the client
+	require.NoError(t, c.Error())
+	echo, err := c.EchoSync(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", echo.Message)
+}
+
+func TestStreamCallsSlowReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := log.TestingLogger()
+	chunk := bytes.Repeat([]byte{0xab}, 32*types.StreamPartSize)
+
+	addr := fmt.Sprintf("localhost:%d", 20000+rand.Int31()%10000)
+	s := server.NewSocketServer(logger, addr, streamApp{chunkApp{chunk: chunk}})
+	require.NoError(t, s.Start(ctx))
+	t.Cleanup(s.Wait)
+	c := abciclient.NewSocketClient(logger, addr, true)
+	require.NoError(t, c.Start(ctx))
+	t.Cleanup(c.Wait)
+	other := abciclient.NewSocketClient(logger, addr, true)
+	require.NoError(t, other.Start(ctx))
+	t.Cleanup(other.Wait)
+
+	// a stream which isn't read holds up its connection, but not the
+	// requests of other connections to the server
+	r, err := c.LoadSnapshotChunkStream(ctx, types.RequestLoadSnapshotChunk{Height: 1})
+	require.NoError(t, err)
+	defer r.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	octx, ocancel := context.WithTimeout(ctx, 5*time.Second)
+	defer ocancel()
+	echo, err := other.EchoSync(octx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", echo.Message)
+}
+
 func setupClientServer(
 	ctx context.Context,
 	t *testing.T,
@@ -83,3 +218,49 @@ func (slowApp) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginBlock
 	time.Sleep(200 * time.Millisecond)
 	return types.ResponseBeginBlock{}
 }
+
+// chunkApp serves chunk as every snapshot chunk and query value.
+type chunkApp struct {
+	types.BaseApplication
+	chunk []byte
+}
+
+func (app chunkApp) Query(req types.RequestQuery) types.ResponseQuery {
+	return types.ResponseQuery{Height: 7, Value: app.chunk}
+}
+
+func (app chunkApp) LoadSnapshotChunk(req types.RequestLoadSnapshotChunk) types.ResponseLoadSnapshotChunk {
+	return types.ResponseLoadSnapshotChunk{Chunk: app.chunk}
+}
+
+// streamApp is a chunkApp which streams its responses.
+type streamApp struct {
+	chunkApp
+}
+
+func (app streamApp) QueryStream(req types.RequestQuery, begin func(types.ResponseQuery) io.Writer) error {
+	_, err := io.Copy(begin(types.ResponseQuery{Height: 7}), bytes.NewReader(app.chunk))
+	return err
+}
+
+func (app streamApp) LoadSnapshotChunkStream(req types.RequestLoadSnapshotChunk, w io.Writer) error {
+	_, err := io.Copy(w, bytes.NewReader(app.chunk))
+	return err
+}
+
+// failingStreamApp is a streamApp which fails after streaming its chunk, and
+// before answering a query.
+type failingStreamApp struct {
+	streamApp
+}
+
+func (app failingStreamApp) QueryStream(req types.RequestQuery, begin func(types.ResponseQuery) io.Writer) error {
+	return errors.New("disk failure")
+}
+
+func (app failingStreamApp) LoadSnapshotChunkStream(req types.RequestLoadSnapshotChunk, w io.Writer) error {
+	if err := app.streamApp.LoadSnapshotChunkStream(req, w); err != nil {
+		return err
+	}
+	return errors.New("disk failure")
+}
@@ -0,0 +1,249 @@
+package abciclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/abci/types"
+)
+
+const (
+	// streamBufferedParts is the number of parts of a streamed response
+	// which are held for its reader, so that the producer of the parts
+	// need not wait for every single read.
+	streamBufferedParts = 4
+
+	// streamStallTimeout is how long the producer of a streamed response
+	// waits for the reader to take a part once the buffer is full. The
+	// stream is then aborted, so that a reader which stopped reading
+	// without closing the stream does not hold up the connection for good.
+	streamStallTimeout = 30 * time.Second
+)
+
+var (
+	// errStreamClosed is returned when passing a part to a stream whose
+	// reader was closed.
+	errStreamClosed = errors.New("response stream closed")
+
+	// errStreamStalled is returned when the reader of a stream did not take
+	// a part within the stall timeout.
+	errStreamStalled = errors.New("response stream stalled: parts were not read in time")
+
+	// errStreamAborted is returned when the server ends a stream with an
+	// error, e.g. because the application failed.
+	errStreamAborted = errors.New("response stream aborted")
+)
+
+// responseStream hands the parts of a streamed response over from the
+// goroutine producing them to the reader of the response. Only a few parts
+// are buffered, so that a slow reader holds up the producer instead of
+// having the parts pile up in memory, but not for longer than the stall
+// timeout.
+type responseStream struct {
+	parts        chan *types.Response
+	closed       chan struct{} // closed by the reader
+	aborted      chan struct{} // closed if the response won't be completed
+	stallTimeout time.Duration
+
+	closeOnce sync.Once
+	abortOnce sync.Once
+	err       error // set before aborted is closed
+}
+
+func newResponseStream(stallTimeout time.Duration) *responseStream {
+	return &responseStream{
+		parts:        make(chan *types.Response, streamBufferedParts),
+		closed:       make(chan struct{}),
+		aborted:      make(chan struct{}),
+		stallTimeout: stallTimeout,
+	}
+}
+
+// deliver passes a part to the reader. If the buffer is full, it waits for
+// the reader to take a part, and aborts the stream with errStreamStalled if
+// that takes longer than the stall timeout. It returns errStreamClosed if
+// the reader was closed, in which case the part is dropped.
+func (s *responseStream) deliver(res *types.Response) error {
+	select {
+	case <-s.closed:
+		return errStreamClosed
+	case <-s.aborted:
+		return s.err
+	default:
+	}
+	select {
+	case s.parts <- res:
+		return nil
+	default:
+	}
+
+	timer := time.NewTimer(s.stallTimeout)
+	defer timer.Stop()
+
+	select {
+	case s.parts <- res:
+		return nil
+	case <-s.closed:
+		return errStreamClosed
+	case <-s.aborted:
+		return s.err
+	case <-timer.C:
+		s.abort(errStreamStalled)
+		return errStreamStalled
+	}
+}
+
+// abort ends the stream with err, which the reader returns instead of the
+// parts not delivered yet.
+func (s *responseStream) abort(err error) {
+	s.abortOnce.Do(func() {
+		s.err = err
+		close(s.aborted)
+	})
+}
+
+func (s *responseStream) close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// next waits for the next part.
+func (s *responseStream) next(ctx context.Context) (*types.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	select {
+	case res := <-s.parts:
+		return res, nil
+	case <-s.aborted:
+		return nil, s.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// openQueryStream waits for the first part of a streamed query response,
+// which it takes from next, and returns the response without its value and a
+// reader over the value. ctx bounds both the wait and the reads. stop is
+// called once the reader is closed or fails, or the stream fails to open.
+func openQueryStream(
+	ctx context.Context,
+	next func(context.Context) (*types.Response, error),
+	stop func(),
+) (*types.ResponseQuery, io.ReadCloser, error) {
+	first, err := next(ctx)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+
+	var header *types.ResponseQuery
+	switch res := first.Value.(type) {
+	case *types.Response_QueryPart:
+		if res.QueryPart.Error != "" {
+			stop()
+			return nil, nil, fmt.Errorf("%w: %s", errStreamAborted, res.QueryPart.Error)
+		}
+		header = res.QueryPart.Header
+	case *types.Response_Query:
+		h := *res.Query
+		h.Value = nil
+		header = &h
+	}
+	if header == nil {
+		stop()
+		return nil, nil, fmt.Errorf("unexpected %v at the start of a streamed query response",
+			reflect.TypeOf(first.Value))
+	}
+	return header, &streamReader{ctx: ctx, pending: first, next: next, stop: stop}, nil
+}
+
+// openSnapshotChunkStream waits for the first part of a streamed snapshot
+// chunk, which it takes from next, and returns a reader over the chunk. ctx
+// bounds both the wait and the reads. stop is called once the reader is
+// closed or fails, or the stream fails to open.
+func openSnapshotChunkStream(
+	ctx context.Context,
+	next func(context.Context) (*types.Response, error),
+	stop func(),
+) (io.ReadCloser, error) {
+	first, err := next(ctx)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	return &streamReader{ctx: ctx, pending: first, next: next, stop: stop}, nil
+}
+
+// streamReader reads the payload of a streamed response, taking its parts
+// from next as needed.
+type streamReader struct {
+	ctx     context.Context
+	pending *types.Response
+	next    func(context.Context) (*types.Response, error)
+	stop    func()
+
+	buf  []byte
+	last bool
+	err  error
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		switch {
+		case r.err != nil:
+			return 0, r.err
+		case r.last:
+			return 0, io.EOF
+		}
+
+		res := r.pending
+		r.pending = nil
+		if res == nil {
+			res, r.err = r.next(r.ctx)
+			if r.err != nil {
+				// the rest of the response is of no use anymore
+				r.stop()
+				continue
+			}
+		}
+		r.buf, r.last, r.err = partData(res)
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *streamReader) Close() error {
+	r.stop()
+	return nil
+}
+
+// partData returns the payload of a part of a streamed response, and whether
+// it's the last part. A whole response, sent by servers which don't stream,
+// is its only part. A part carrying an error fails the stream.
+func partData(res *types.Response) ([]byte, bool, error) {
+	switch res := res.Value.(type) {
+	case *types.Response_QueryPart:
+		if res.QueryPart.Error != "" {
+			return nil, true, fmt.Errorf("%w: %s", errStreamAborted, res.QueryPart.Error)
+		}
+		return res.QueryPart.Value, res.QueryPart.Last, nil
+	case *types.Response_LoadSnapshotChunkPart:
+		if res.LoadSnapshotChunkPart.Error != "" {
+			return nil, true, fmt.Errorf("%w: %s", errStreamAborted, res.LoadSnapshotChunkPart.Error)
+		}
+		return res.LoadSnapshotChunkPart.Chunk, res.LoadSnapshotChunkPart.Last, nil
+	case *types.Response_Query:
+		return res.Query.Value, true, nil
+	case *types.Response_LoadSnapshotChunk:
+		return res.LoadSnapshotChunk.Chunk, true, nil
+	default:
+		return nil, false, fmt.Errorf("unexpected %v in a streamed response", reflect.TypeOf(res))
+	}
+}
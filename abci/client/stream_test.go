@@ -0,0 +1,41 @@
+package abciclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/abci/types"
+)
+
+func TestResponseStreamStalled(t *testing.T) {
+	s := newResponseStream(10 * time.Millisecond)
+	part := types.ToResponseLoadSnapshotChunkPart(types.ResponseLoadSnapshotChunkPart{Chunk: []byte{1}})
+
+	// the buffered parts are delivered without a reader
+	for i := 0; i < streamBufferedParts; i++ {
+		require.NoError(t, s.deliver(part))
+	}
+
+	// then the stream is aborted once the reader falls behind, and further
+	// parts are dropped right away
+	require.Equal(t, errStreamStalled, s.deliver(part))
+	require.Equal(t, errStreamStalled, s.deliver(part))
+
+	// the reader may still get the parts delivered before, but no more
+	var err error
+	for i := 0; i <= streamBufferedParts && err == nil; i++ {
+		_, err = s.next(context.Background())
+	}
+	require.Equal(t, errStreamStalled, err)
+}
+
+func TestResponseStreamClosed(t *testing.T) {
+	s := newResponseStream(time.Minute)
+	part := types.ToResponseLoadSnapshotChunkPart(types.ResponseLoadSnapshotChunkPart{Chunk: []byte{1}})
+
+	s.close()
+	require.Equal(t, errStreamClosed, s.deliver(part))
+}
@@ -108,7 +108,7 @@ func addGlobalFlags() {
 		"",
 		"tcp://0.0.0.0:26658",
 		"address of application socket")
-	RootCmd.PersistentFlags().StringVarP(&flagAbci, "abci", "", "socket", "either socket or grpc")
+	RootCmd.PersistentFlags().StringVarP(&flagAbci, "abci", "", "socket", "either socket, shm or grpc")
 	RootCmd.PersistentFlags().BoolVarP(&flagVerbose,
 		"verbose",
 		"v",
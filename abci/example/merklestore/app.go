@@ -0,0 +1,368 @@
+// Package merklestore implements a key/value store application whose state is
+// committed to by a merkle tree. Unlike kvstore, its app hash is a real
+// commitment to the state: queries can be answered with proofs that light
+// clients verify against the app hash, and the state is exported as snapshots
+// that state sync can restore and verify.
+package merklestore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/abci/example/code"
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	cryptoproto "github.com/tendermint/tendermint/proto/tendermint/crypto"
+	"github.com/tendermint/tendermint/version"
+)
+
+const ProtocolVersion uint64 = 0x1
+
+var stateKey = []byte("state")
+
+// Config configures the application.
+type Config struct {
+	// SnapshotInterval is the number of heights between snapshots, 0 disables
+	// snapshots.
+	SnapshotInterval uint64
+
+	// SnapshotKeepRecent is the number of snapshots to keep.
+	SnapshotKeepRecent int
+
+	// SnapshotChunkSize is the size of snapshot chunks in bytes.
+	SnapshotChunkSize int
+
+	// RetainBlocks is the number of blocks to retain after commit (via
+	// ResponseCommit.RetainHeight), 0 retains all blocks.
+	RetainBlocks int64
+}
+
+// DefaultConfig returns the default application configuration.
+func DefaultConfig() Config {
+	return Config{
+		SnapshotInterval:   100,
+		SnapshotKeepRecent: 2,
+		SnapshotChunkSize:  1024 * 1024, // 1MB
+		RetainBlocks:       0,
+	}
+}
+
+type state struct {
+	Height  int64  `json:"height"`
+	AppHash []byte `json:"app_hash"`
+}
+
+var _ types.Application = (*Application)(nil)
+
+// Application is a merkleized key/value store.
+//
+// Transactions are either "key=value" or arbitrary bytes, which are stored
+// as both key and value. Writes only become visible to queries on Commit, and
+// every committed height can be queried.
+type Application struct {
+	types.BaseApplication
+
+	// mtx guards the committed state. Queries only take a read lock, so they
+	// can be served concurrently with each other.
+	mtx       sync.RWMutex
+	cfg       Config
+	db        dbm.DB
+	tree      *tree
+	state     state
+	snapshots *snapshotStore
+
+	// only accessed from the consensus and snapshot connections
+	pending map[string][]byte
+	restore *restore
+}
+
+// NewApplication creates the application, loading its state from db.
+func NewApplication(db dbm.DB, cfg Config) (*Application, error) {
+	app := &Application{
+		cfg:       cfg,
+		db:        db,
+		tree:      &tree{db: db},
+		snapshots: &snapshotStore{db: db},
+		pending:   make(map[string][]byte),
+	}
+
+	stateBytes, err := db.Get(stateKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(stateBytes) > 0 {
+		if err := json.Unmarshal(stateBytes, &app.state); err != nil {
+			return nil, fmt.Errorf("invalid application state: %w", err)
+		}
+	}
+
+	if app.state.Height > 0 {
+		root, ok, err := app.tree.root(app.state.Height)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || !bytes.Equal(root.hash, app.state.AppHash) {
+			return nil, fmt.Errorf("stored state does not match app hash %X at height %d",
+				app.state.AppHash, app.state.Height)
+		}
+	}
+	return app, nil
+}
+
+// NewPersistentApplication creates the application with a goleveldb database
+// in dbDir.
+func NewPersistentApplication(dbDir string, cfg Config) (*Application, error) {
+	db, err := dbm.NewGoLevelDB("merklestore", dbDir)
+	if err != nil {
+		return nil, err
+	}
+	app, err := NewApplication(db, cfg)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return app, nil
+}
+
+// Close closes the database.
+func (app *Application) Close() error {
+	return app.db.Close()
+}
+
+func (app *Application) Info(req types.RequestInfo) types.ResponseInfo {
+	app.mtx.RLock()
+	defer app.mtx.RUnlock()
+
+	size, err := app.tree.size(app.state.Height)
+	if err != nil {
+		panic(err)
+	}
+	return types.ResponseInfo{
+		Data:             fmt.Sprintf("{\"size\":%v}", size),
+		Version:          version.ABCIVersion,
+		AppVersion:       ProtocolVersion,
+		LastBlockHeight:  app.state.Height,
+		LastBlockAppHash: app.state.AppHash,
+	}
+}
+
+func parseTx(tx []byte) (key, value []byte) {
+	parts := bytes.Split(tx, []byte("="))
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return tx, tx
+}
+
+// tx is either "key=value" or just arbitrary bytes
+func (app *Application) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverTx {
+	key, value := parseTx(req.Tx)
+	app.pending[string(key)] = value
+
+	events := []types.Event{
+		{
+			Type: "app",
+			Attributes: []types.EventAttribute{
+				{Key: "key", Value: string(key), Index: true},
+			},
+		},
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Events: events}
+}
+
+func (app *Application) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
+	if len(req.Tx) == 0 {
+		return types.ResponseCheckTx{Code: code.CodeTypeEncodingError, Log: "empty transaction"}
+	}
+	return types.ResponseCheckTx{Code: code.CodeTypeOK, GasWanted: 1}
+}
+
+// Commit applies the writes of the block, persists them and takes a snapshot
+// if one is due.
+func (app *Application) Commit() types.ResponseCommit {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	batch := app.db.NewBatch()
+	defer batch.Close()
+
+	appHash, err := app.tree.commit(batch, app.state.Height+1, app.pending)
+	if err != nil {
+		panic(err)
+	}
+	app.pending = make(map[string][]byte)
+
+	app.state.Height++
+	app.state.AppHash = appHash
+	if err := app.saveState(batch); err != nil {
+		panic(err)
+	}
+	if err := batch.WriteSync(); err != nil {
+		panic(err)
+	}
+
+	if app.cfg.SnapshotInterval > 0 && uint64(app.state.Height)%app.cfg.SnapshotInterval == 0 {
+		if _, err := app.snapshots.create(uint64(app.state.Height), app.tree, app.cfg.SnapshotChunkSize); err != nil {
+			panic(err)
+		}
+		if err := app.snapshots.prune(app.cfg.SnapshotKeepRecent); err != nil {
+			panic(err)
+		}
+	}
+
+	resp := types.ResponseCommit{Data: app.state.AppHash}
+	if app.cfg.RetainBlocks > 0 && app.state.Height >= app.cfg.RetainBlocks {
+		resp.RetainHeight = app.state.Height - app.cfg.RetainBlocks + 1
+	}
+	return resp
+}
+
+func (app *Application) saveState(batch dbm.Batch) error {
+	stateBytes, err := json.Marshal(app.state)
+	if err != nil {
+		return err
+	}
+	return batch.Set(stateKey, stateBytes)
+}
+
+// Query returns the value of the key in req.Data at req.Height, or at the
+// latest height if it is 0. If req.Prove is set and the key exists, the
+// response carries a proof of the value against the app hash of that height.
+func (app *Application) Query(req types.RequestQuery) types.ResponseQuery {
+	app.mtx.RLock()
+	defer app.mtx.RUnlock()
+
+	height := req.Height
+	if height == 0 {
+		height = app.state.Height
+	}
+	if height > app.state.Height {
+		return types.ResponseQuery{
+			Code:   code.CodeTypeUnknownError,
+			Log:    fmt.Sprintf("height %d is above the latest height %d", height, app.state.Height),
+			Height: app.state.Height,
+		}
+	}
+	value, proof, err := app.tree.prove(height, req.Data)
+	if err != nil {
+		return types.ResponseQuery{
+			Code:   code.CodeTypeUnknownError,
+			Log:    err.Error(),
+			Height: height,
+		}
+	}
+
+	res := types.ResponseQuery{
+		Key:    req.Data,
+		Value:  value,
+		Height: height,
+		Index:  -1,
+	}
+	if res.Value == nil {
+		res.Log = "does not exist"
+		return res
+	}
+	res.Log = "exists"
+
+	if req.Prove {
+		op := merkle.NewValueOp(req.Data, proof).ProofOp()
+		res.Index = proof.Index
+		res.ProofOps = &cryptoproto.ProofOps{Ops: []cryptoproto.ProofOp{op}}
+	}
+	return res
+}
+
+func (app *Application) ListSnapshots(req types.RequestListSnapshots) types.ResponseListSnapshots {
+	snapshots, err := app.snapshots.list()
+	if err != nil {
+		panic(err)
+	}
+	return types.ResponseListSnapshots{Snapshots: snapshots}
+}
+
+func (app *Application) LoadSnapshotChunk(req types.RequestLoadSnapshotChunk) types.ResponseLoadSnapshotChunk {
+	chunk, err := app.snapshots.loadChunk(req.Height, req.Format, req.Chunk)
+	if err != nil {
+		panic(err)
+	}
+	return types.ResponseLoadSnapshotChunk{Chunk: chunk}
+}
+
+func (app *Application) OfferSnapshot(req types.RequestOfferSnapshot) types.ResponseOfferSnapshot {
+	if req.Snapshot == nil {
+		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT}
+	}
+	if req.Snapshot.Format != snapshotFormat {
+		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT_FORMAT}
+	}
+	r, err := newRestore(req.Snapshot, req.AppHash)
+	if err != nil {
+		return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_REJECT}
+	}
+	app.restore = r
+	return types.ResponseOfferSnapshot{Result: types.ResponseOfferSnapshot_ACCEPT}
+}
+
+// ApplySnapshotChunk verifies each chunk against the snapshot metadata, asking
+// for it to be refetched from another peer if it does not match. Once all
+// chunks are applied, the restored state is checked against the trusted app
+// hash before it replaces the current state.
+func (app *Application) ApplySnapshotChunk(req types.RequestApplySnapshotChunk) types.ResponseApplySnapshotChunk {
+	r := app.restore
+	if r == nil {
+		return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ABORT}
+	}
+	if !r.verifyChunk(req.Index, req.Chunk) {
+		res := types.ResponseApplySnapshotChunk{
+			Result:        types.ResponseApplySnapshotChunk_RETRY,
+			RefetchChunks: []uint32{req.Index},
+		}
+		if req.Sender != "" {
+			res.RejectSenders = []string{req.Sender}
+		}
+		return res
+	}
+	if !r.add(req.Index, req.Chunk) {
+		return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ACCEPT}
+	}
+
+	app.restore = nil
+	keys, values, err := r.pairs()
+	if err != nil {
+		return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_REJECT_SNAPSHOT}
+	}
+	ok, err := app.replaceState(keys, values, state{Height: int64(r.snapshot.Height), AppHash: r.appHash})
+	if err != nil {
+		panic(err)
+	}
+	if !ok {
+		return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_REJECT_SNAPSHOT}
+	}
+	return types.ResponseApplySnapshotChunk{Result: types.ResponseApplySnapshotChunk_ACCEPT}
+}
+
+// replaceState replaces the stored state with the given pairs in leaf order
+// at the height of s, unless they don't match its app hash, in which case it
+// returns false. Earlier heights can no longer be queried.
+func (app *Application) replaceState(keys, values [][]byte, s state) (bool, error) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	batch := app.db.NewBatch()
+	defer batch.Close()
+
+	appHash, err := app.tree.replace(batch, s.Height, keys, values)
+	if err != nil || !bytes.Equal(appHash, s.AppHash) {
+		return false, nil
+	}
+
+	app.state = s
+	if err := app.saveState(batch); err != nil {
+		return false, err
+	}
+	return true, batch.WriteSync()
+}
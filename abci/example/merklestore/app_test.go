@@ -0,0 +1,224 @@
+package merklestore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/abci/example/code"
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+)
+
+func newTestApp(t *testing.T, cfg Config) *Application {
+	app, err := NewApplication(dbm.NewMemDB(), cfg)
+	require.NoError(t, err)
+	return app
+}
+
+func commitBlock(app *Application, txs ...string) types.ResponseCommit {
+	for _, tx := range txs {
+		app.DeliverTx(types.RequestDeliverTx{Tx: []byte(tx)})
+	}
+	return app.Commit()
+}
+
+func verifyValue(t *testing.T, res types.ResponseQuery, appHash []byte) error {
+	t.Helper()
+	require.NotNil(t, res.ProofOps)
+	keyPath := merkle.KeyPath{}.AppendKey(res.Key, merkle.KeyEncodingURL).String()
+	return merkle.DefaultProofRuntime().VerifyValue(res.ProofOps, appHash, keyPath, res.Value)
+}
+
+func TestQueryProofs(t *testing.T) {
+	app := newTestApp(t, DefaultConfig())
+
+	oldAppHash := commitBlock(app, "a=1", "b=2", "c/d=3").Data
+	res := commitBlock(app, "b=20", "e")
+	appHash := res.Data
+
+	for key, value := range map[string]string{"a": "1", "b": "20", "c/d": "3", "e": "e"} {
+		res := app.Query(types.RequestQuery{Data: []byte(key), Prove: true})
+		require.EqualValues(t, code.CodeTypeOK, res.Code)
+		require.Equal(t, value, string(res.Value))
+		require.EqualValues(t, 2, res.Height)
+		require.NoError(t, verifyValue(t, res, appHash), key)
+
+		res.Value = []byte("forged")
+		require.Error(t, verifyValue(t, res, appHash), key)
+	}
+
+	res2 := app.Query(types.RequestQuery{Data: []byte("missing"), Prove: true})
+	assert.Nil(t, res2.Value)
+	assert.Nil(t, res2.ProofOps)
+
+	// Earlier heights are queried and proven against their own app hash.
+	for key, value := range map[string]string{"a": "1", "b": "2", "c/d": "3"} {
+		res := app.Query(types.RequestQuery{Data: []byte(key), Height: 1, Prove: true})
+		require.EqualValues(t, code.CodeTypeOK, res.Code)
+		require.Equal(t, value, string(res.Value))
+		require.EqualValues(t, 1, res.Height)
+		require.NoError(t, verifyValue(t, res, oldAppHash), key)
+		require.Error(t, verifyValue(t, res, appHash), key)
+	}
+	res2 = app.Query(types.RequestQuery{Data: []byte("e"), Height: 1, Prove: true})
+	assert.EqualValues(t, code.CodeTypeOK, res2.Code)
+	assert.Nil(t, res2.Value)
+
+	res2 = app.Query(types.RequestQuery{Data: []byte("a"), Height: 3})
+	assert.NotEqualValues(t, code.CodeTypeOK, res2.Code)
+}
+
+func TestUncommittedWritesNotVisible(t *testing.T) {
+	app := newTestApp(t, DefaultConfig())
+	commitBlock(app, "a=1")
+
+	app.DeliverTx(types.RequestDeliverTx{Tx: []byte("a=2")})
+	assert.Equal(t, "1", string(app.Query(types.RequestQuery{Data: []byte("a")}).Value))
+
+	app.Commit()
+	assert.Equal(t, "2", string(app.Query(types.RequestQuery{Data: []byte("a")}).Value))
+}
+
+func TestReload(t *testing.T) {
+	db := dbm.NewMemDB()
+	app, err := NewApplication(db, DefaultConfig())
+	require.NoError(t, err)
+	res := commitBlock(app, "a=1", "b=2")
+
+	app, err = NewApplication(db, DefaultConfig())
+	require.NoError(t, err)
+	info := app.Info(types.RequestInfo{})
+	assert.EqualValues(t, 1, info.LastBlockHeight)
+	assert.Equal(t, res.Data, info.LastBlockAppHash)
+	assert.Equal(t, "2", string(app.Query(types.RequestQuery{Data: []byte("b")}).Value))
+}
+
+func TestRetainHeight(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RetainBlocks = 3
+	app := newTestApp(t, cfg)
+
+	for height := int64(1); height <= 5; height++ {
+		res := commitBlock(app, fmt.Sprintf("k=%d", height))
+		if height < 3 {
+			assert.Zero(t, res.RetainHeight)
+		} else {
+			assert.Equal(t, height-2, res.RetainHeight)
+		}
+	}
+}
+
+func newSnapshotApp(t *testing.T) *Application {
+	cfg := DefaultConfig()
+	cfg.SnapshotInterval = 2
+	cfg.SnapshotKeepRecent = 2
+	cfg.SnapshotChunkSize = 64
+	app := newTestApp(t, cfg)
+	for height := 1; height <= 6; height++ {
+		var txs []string
+		for i := 0; i < 10; i++ {
+			txs = append(txs, fmt.Sprintf("key%d-%d=value%d", height, i, i))
+		}
+		commitBlock(app, txs...)
+	}
+	return app
+}
+
+func loadChunks(t *testing.T, app *Application, snapshot *types.Snapshot) [][]byte {
+	chunks := make([][]byte, snapshot.Chunks)
+	for i := range chunks {
+		res := app.LoadSnapshotChunk(types.RequestLoadSnapshotChunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunk:  uint32(i),
+		})
+		require.NotNil(t, res.Chunk)
+		chunks[i] = res.Chunk
+	}
+	return chunks
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	source := newSnapshotApp(t)
+
+	snapshots := source.ListSnapshots(types.RequestListSnapshots{}).Snapshots
+	require.Len(t, snapshots, 2)
+	assert.EqualValues(t, 4, snapshots[0].Height)
+	assert.EqualValues(t, 6, snapshots[1].Height)
+
+	snapshot := snapshots[1]
+	require.Greater(t, snapshot.Chunks, uint32(1))
+	appHash := source.Info(types.RequestInfo{}).LastBlockAppHash
+
+	target := newTestApp(t, DefaultConfig())
+	require.Equal(t, types.ResponseOfferSnapshot_ACCEPT, target.OfferSnapshot(types.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  appHash,
+	}).Result)
+
+	for i, chunk := range loadChunks(t, source, snapshot) {
+		res := target.ApplySnapshotChunk(types.RequestApplySnapshotChunk{
+			Index:  uint32(i),
+			Chunk:  chunk,
+			Sender: "peer",
+		})
+		require.Equal(t, types.ResponseApplySnapshotChunk_ACCEPT, res.Result)
+	}
+
+	info := target.Info(types.RequestInfo{})
+	assert.EqualValues(t, 6, info.LastBlockHeight)
+	assert.Equal(t, appHash, info.LastBlockAppHash)
+
+	res := target.Query(types.RequestQuery{Data: []byte("key3-7"), Prove: true})
+	assert.Equal(t, "value7", string(res.Value))
+	assert.NoError(t, verifyValue(t, res, appHash))
+
+	// the restored app carries on from the snapshot height
+	assert.Equal(t, commitBlock(source, "x=y").Data, commitBlock(target, "x=y").Data)
+}
+
+func TestSnapshotRejectsBadChunk(t *testing.T) {
+	source := newSnapshotApp(t)
+	snapshots := source.ListSnapshots(types.RequestListSnapshots{}).Snapshots
+	snapshot := snapshots[len(snapshots)-1]
+	chunks := loadChunks(t, source, snapshot)
+
+	target := newTestApp(t, DefaultConfig())
+	target.OfferSnapshot(types.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  source.Info(types.RequestInfo{}).LastBlockAppHash,
+	})
+
+	bad := append([]byte{}, chunks[0]...)
+	bad[0] ^= 0xff
+	res := target.ApplySnapshotChunk(types.RequestApplySnapshotChunk{Index: 0, Chunk: bad, Sender: "evil"})
+	assert.Equal(t, types.ResponseApplySnapshotChunk_RETRY, res.Result)
+	assert.Equal(t, []uint32{0}, res.RefetchChunks)
+	assert.Equal(t, []string{"evil"}, res.RejectSenders)
+}
+
+func TestSnapshotRejectsWrongAppHash(t *testing.T) {
+	source := newSnapshotApp(t)
+	snapshots := source.ListSnapshots(types.RequestListSnapshots{}).Snapshots
+	snapshot := snapshots[len(snapshots)-1]
+
+	target := newTestApp(t, DefaultConfig())
+	target.OfferSnapshot(types.RequestOfferSnapshot{Snapshot: snapshot, AppHash: []byte("wrong")})
+
+	var res types.ResponseApplySnapshotChunk
+	for i, chunk := range loadChunks(t, source, snapshot) {
+		res = target.ApplySnapshotChunk(types.RequestApplySnapshotChunk{Index: uint32(i), Chunk: chunk})
+	}
+	assert.Equal(t, types.ResponseApplySnapshotChunk_REJECT_SNAPSHOT, res.Result)
+	assert.Zero(t, target.Info(types.RequestInfo{}).LastBlockHeight)
+}
+
+func TestOfferSnapshotRejectsFormat(t *testing.T) {
+	app := newTestApp(t, DefaultConfig())
+	res := app.OfferSnapshot(types.RequestOfferSnapshot{Snapshot: &types.Snapshot{Height: 1, Format: 2, Chunks: 1}})
+	assert.Equal(t, types.ResponseOfferSnapshot_REJECT_FORMAT, res.Result)
+}
@@ -0,0 +1,226 @@
+package merklestore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// snapshotFormat is the only snapshot format produced and accepted. A
+// snapshot is the stream of all pairs in leaf order, each encoded as the
+// uvarint length-prefixed key followed by the uvarint length-prefixed value,
+// cut into chunks of fixed size. Snapshot.Metadata holds the hash of every
+// chunk so that each chunk can be verified as it is applied, and
+// Snapshot.Hash is the hash of the metadata.
+const snapshotFormat = 1
+
+var (
+	snapshotPrefix = []byte("snapshot:")
+	chunkPrefix    = []byte("chunk:")
+)
+
+// snapshotStore keeps snapshots and their chunks in the application database.
+type snapshotStore struct {
+	db dbm.DB
+}
+
+func snapshotKey(height uint64) []byte {
+	key := make([]byte, len(snapshotPrefix)+8)
+	copy(key, snapshotPrefix)
+	binary.BigEndian.PutUint64(key[len(snapshotPrefix):], height)
+	return key
+}
+
+func chunkKey(height uint64, index uint32) []byte {
+	key := make([]byte, len(chunkPrefix)+12)
+	copy(key, chunkPrefix)
+	binary.BigEndian.PutUint64(key[len(chunkPrefix):], height)
+	binary.BigEndian.PutUint32(key[len(chunkPrefix)+8:], index)
+	return key
+}
+
+// create exports the version of t at the given height as a snapshot.
+func (s *snapshotStore) create(height uint64, t *tree, chunkSize int) (*types.Snapshot, error) {
+	var export bytes.Buffer
+	err := t.forEach(int64(height), func(key, value []byte) error {
+		writeByteSlice(&export, key)
+		writeByteSlice(&export, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	bz := export.Bytes()
+	var metadata []byte
+	var index uint32
+	for ; len(bz) > 0 || index == 0; index++ {
+		n := chunkSize
+		if n > len(bz) {
+			n = len(bz)
+		}
+		chunk := bz[:n]
+		bz = bz[n:]
+
+		metadata = append(metadata, tmhash.Sum(chunk)...)
+		if err := batch.Set(chunkKey(height, index), chunk); err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot := &types.Snapshot{
+		Height:   height,
+		Format:   snapshotFormat,
+		Chunks:   index,
+		Hash:     tmhash.Sum(metadata),
+		Metadata: metadata,
+	}
+	snapshotBytes, err := snapshot.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if err := batch.Set(snapshotKey(height), snapshotBytes); err != nil {
+		return nil, err
+	}
+	if err := batch.WriteSync(); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// list returns all snapshots, oldest first.
+func (s *snapshotStore) list() ([]*types.Snapshot, error) {
+	iter, err := dbm.IteratePrefix(s.db, snapshotPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var snapshots []*types.Snapshot
+	for ; iter.Valid(); iter.Next() {
+		snapshot := &types.Snapshot{}
+		if err := snapshot.Unmarshal(iter.Value()); err != nil {
+			return nil, fmt.Errorf("invalid snapshot metadata: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, iter.Error()
+}
+
+// loadChunk returns a chunk, or nil if it does not exist.
+func (s *snapshotStore) loadChunk(height uint64, format, index uint32) ([]byte, error) {
+	if format != snapshotFormat {
+		return nil, nil
+	}
+	return s.db.Get(chunkKey(height, index))
+}
+
+// prune deletes all but the keepRecent most recent snapshots.
+func (s *snapshotStore) prune(keepRecent int) error {
+	snapshots, err := s.list()
+	if err != nil {
+		return err
+	}
+	if len(snapshots) <= keepRecent {
+		return nil
+	}
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	for _, snapshot := range snapshots[:len(snapshots)-keepRecent] {
+		for index := uint32(0); index < snapshot.Chunks; index++ {
+			if err := batch.Delete(chunkKey(snapshot.Height, index)); err != nil {
+				return err
+			}
+		}
+		if err := batch.Delete(snapshotKey(snapshot.Height)); err != nil {
+			return err
+		}
+	}
+	return batch.WriteSync()
+}
+
+// restore tracks the chunks of a snapshot being restored.
+type restore struct {
+	snapshot *types.Snapshot
+	appHash  []byte
+	chunks   [][]byte
+	applied  uint32
+}
+
+func newRestore(snapshot *types.Snapshot, appHash []byte) (*restore, error) {
+	if snapshot.Format != snapshotFormat {
+		return nil, fmt.Errorf("unsupported snapshot format %d", snapshot.Format)
+	}
+	if snapshot.Chunks == 0 || len(snapshot.Metadata) != int(snapshot.Chunks)*tmhash.Size {
+		return nil, errors.New("snapshot metadata does not match its chunk count")
+	}
+	if !bytes.Equal(snapshot.Hash, tmhash.Sum(snapshot.Metadata)) {
+		return nil, errors.New("snapshot hash does not match its metadata")
+	}
+	return &restore{
+		snapshot: snapshot,
+		appHash:  appHash,
+		chunks:   make([][]byte, snapshot.Chunks),
+	}, nil
+}
+
+// verifyChunk checks a chunk against the hash in the snapshot metadata.
+func (r *restore) verifyChunk(index uint32, chunk []byte) bool {
+	if index >= r.snapshot.Chunks {
+		return false
+	}
+	expected := r.snapshot.Metadata[int(index)*tmhash.Size : int(index+1)*tmhash.Size]
+	return bytes.Equal(expected, tmhash.Sum(chunk))
+}
+
+// add records a verified chunk and reports whether all chunks were received.
+func (r *restore) add(index uint32, chunk []byte) bool {
+	if r.chunks[index] == nil {
+		r.chunks[index] = chunk
+		r.applied++
+	}
+	return r.applied == r.snapshot.Chunks
+}
+
+// pairs decodes the pairs of the complete snapshot, in leaf order.
+func (r *restore) pairs() (keys, values [][]byte, err error) {
+	rd := bytes.NewReader(bytes.Join(r.chunks, nil))
+	for rd.Len() > 0 {
+		key, err := readByteSlice(rd)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := readByteSlice(rd)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	return keys, values, nil
+}
+
+func readByteSlice(rd *bytes.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(rd)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot data: %w", err)
+	}
+	if n > uint64(rd.Len()) {
+		return nil, fmt.Errorf("invalid snapshot data: %w", io.ErrUnexpectedEOF)
+	}
+	bz := make([]byte, n)
+	_, err = io.ReadFull(rd, bz)
+	return bz, err
+}
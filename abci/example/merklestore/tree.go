@@ -0,0 +1,482 @@
+package merklestore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// tree is a versioned key/value map committed to by a merkle tree over its
+// pairs, in the order in which their keys were first set. Each leaf is the
+// length-prefixed key followed by the length-prefixed hash of the value, which
+// is the structure verified by merkle.ValueOp, so proofs can be checked with
+// merkle.DefaultProofRuntime.
+//
+// The tree has the shape of merkle.HashFromByteSlices over its leaves, so
+// setting a new key appends a leaf and changing a value rehashes the path to
+// its leaf, both in O(log n). Nodes are immutable and stored by hash, such that
+// versions share the nodes they have in common, and the root of every version
+// is kept so that each of them can be read and proven. Old versions are never
+// pruned.
+type tree struct {
+	db dbm.DB
+}
+
+var (
+	nodePrefix  = []byte("node:")
+	rootPrefix  = []byte("root:")
+	indexPrefix = []byte("index:")
+)
+
+// The hash prefixes of RFC 6962, as used by the merkle package.
+var (
+	leafPrefix  = []byte{0}
+	innerPrefix = []byte{1}
+)
+
+// node is a node of the tree. Inner nodes refer to their children by hash.
+type node struct {
+	hash        []byte
+	leaf        bool
+	key, value  []byte // leaf nodes only
+	left, right []byte // inner nodes only
+}
+
+func newLeaf(key, value []byte) *node {
+	var buf bytes.Buffer
+	writeByteSlice(&buf, key)
+	writeByteSlice(&buf, tmhash.Sum(value))
+	return &node{
+		hash:  tmhash.Sum(append(append([]byte{}, leafPrefix...), buf.Bytes()...)),
+		leaf:  true,
+		key:   key,
+		value: value,
+	}
+}
+
+func newInner(left, right []byte) *node {
+	bz := make([]byte, 0, len(innerPrefix)+len(left)+len(right))
+	bz = append(append(append(bz, innerPrefix...), left...), right...)
+	return &node{hash: tmhash.Sum(bz), left: left, right: right}
+}
+
+func (n *node) marshal() []byte {
+	var buf bytes.Buffer
+	if n.leaf {
+		buf.WriteByte(0)
+		writeByteSlice(&buf, n.key)
+		writeByteSlice(&buf, n.value)
+	} else {
+		buf.WriteByte(1)
+		writeByteSlice(&buf, n.left)
+		writeByteSlice(&buf, n.right)
+	}
+	return buf.Bytes()
+}
+
+func unmarshalNode(hash, bz []byte) (*node, error) {
+	if len(bz) == 0 {
+		return nil, errors.New("empty node")
+	}
+	rd := bytes.NewReader(bz[1:])
+	first, err := readByteSlice(rd)
+	if err != nil {
+		return nil, err
+	}
+	second, err := readByteSlice(rd)
+	if err != nil {
+		return nil, err
+	}
+	switch bz[0] {
+	case 0:
+		return &node{hash: hash, leaf: true, key: first, value: second}, nil
+	case 1:
+		return &node{hash: hash, left: first, right: second}, nil
+	default:
+		return nil, fmt.Errorf("invalid node type %d", bz[0])
+	}
+}
+
+func nodeKey(hash []byte) []byte {
+	return append(append([]byte{}, nodePrefix...), hash...)
+}
+
+func rootKey(version int64) []byte {
+	key := make([]byte, len(rootPrefix)+8)
+	copy(key, rootPrefix)
+	binary.BigEndian.PutUint64(key[len(rootPrefix):], uint64(version))
+	return key
+}
+
+func indexKey(key []byte) []byte {
+	return append(append([]byte{}, indexPrefix...), key...)
+}
+
+// root is the root of a version of the tree.
+type root struct {
+	hash []byte
+	size int64
+}
+
+// emptyRoot is the root of the empty tree, which has no nodes.
+var emptyRoot = root{hash: merkle.HashFromByteSlices(nil)}
+
+// root returns the root of the given version, or false if there is none. The
+// empty tree at version 0 always exists.
+func (t *tree) root(version int64) (root, bool, error) {
+	bz, err := t.db.Get(rootKey(version))
+	if err != nil {
+		return root{}, false, err
+	}
+	if bz == nil {
+		return emptyRoot, version == 0, nil
+	}
+	size, n := binary.Uvarint(bz)
+	if n <= 0 {
+		return root{}, false, fmt.Errorf("invalid root at version %d", version)
+	}
+	return root{hash: bz[n:], size: int64(size)}, true, nil
+}
+
+func (t *tree) setRoot(batch dbm.Batch, version int64, r root) error {
+	var buf [binary.MaxVarintLen64]byte
+	bz := append(buf[:binary.PutUvarint(buf[:], uint64(r.size))], r.hash...)
+	return batch.Set(rootKey(version), bz)
+}
+
+// index returns the leaf index of a key in the latest version, or false if
+// the key was never set. Since keys keep their index once set, a key exists in
+// a version if its index is below the size of the version.
+func (t *tree) index(key []byte) (int64, bool, error) {
+	bz, err := t.db.Get(indexKey(key))
+	if err != nil || bz == nil {
+		return 0, false, err
+	}
+	index, n := binary.Uvarint(bz)
+	if n <= 0 {
+		return 0, false, fmt.Errorf("invalid index of key %X", key)
+	}
+	return int64(index), true, nil
+}
+
+func (t *tree) setIndex(batch dbm.Batch, key []byte, index int64) error {
+	var buf [binary.MaxVarintLen64]byte
+	return batch.Set(indexKey(key), buf[:binary.PutUvarint(buf[:], uint64(index))])
+}
+
+// node loads a node, from the nodes created by an ongoing commit if given.
+func (t *tree) node(hash []byte, created map[string]*node) (*node, error) {
+	if n, ok := created[string(hash)]; ok {
+		return n, nil
+	}
+	bz, err := t.db.Get(nodeKey(hash))
+	if err != nil {
+		return nil, err
+	}
+	if bz == nil {
+		return nil, fmt.Errorf("missing node %X", hash)
+	}
+	return unmarshalNode(hash, bz)
+}
+
+// leaf returns the leaf at the given index of a subtree of the given size, and
+// the hashes of its siblings from the leaf up, as in merkle.Proof.
+func (t *tree) leaf(hash []byte, size, index int64) (*node, [][]byte, error) {
+	n, err := t.node(hash, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if size == 1 {
+		if !n.leaf {
+			return nil, nil, fmt.Errorf("expected leaf node %X", hash)
+		}
+		return n, nil, nil
+	}
+	if n.leaf {
+		return nil, nil, fmt.Errorf("expected inner node %X", hash)
+	}
+	split := splitPoint(size)
+	if index < split {
+		leaf, aunts, err := t.leaf(n.left, split, index)
+		return leaf, append(aunts, n.right), err
+	}
+	leaf, aunts, err := t.leaf(n.right, size-split, index-split)
+	return leaf, append(aunts, n.left), err
+}
+
+// get returns the value of a key in the given version, or nil if it is not set.
+func (t *tree) get(version int64, key []byte) ([]byte, error) {
+	value, _, err := t.prove(version, key)
+	return value, err
+}
+
+// prove returns the value of a key in the given version and a proof of it
+// against the root of the version, or nil if the key is not set.
+func (t *tree) prove(version int64, key []byte) ([]byte, *merkle.Proof, error) {
+	r, ok, err := t.root(version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("version %d does not exist", version)
+	}
+	index, ok, err := t.index(key)
+	if err != nil || !ok || index >= r.size {
+		return nil, nil, err
+	}
+	leaf, aunts, err := t.leaf(r.hash, r.size, index)
+	if err != nil {
+		return nil, nil, err
+	}
+	return leaf.value, &merkle.Proof{
+		Total:    r.size,
+		Index:    index,
+		LeafHash: leaf.hash,
+		Aunts:    aunts,
+	}, nil
+}
+
+// size returns the number of pairs in the given version.
+func (t *tree) size(version int64) (int64, error) {
+	r, _, err := t.root(version)
+	return r.size, err
+}
+
+// forEach calls fn for every pair of the given version in leaf order,
+// stopping at the first error.
+func (t *tree) forEach(version int64, fn func(key, value []byte) error) error {
+	r, ok, err := t.root(version)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("version %d does not exist", version)
+	}
+	if r.size == 0 {
+		return nil
+	}
+	return t.walk(r.hash, r.size, fn)
+}
+
+func (t *tree) walk(hash []byte, size int64, fn func(key, value []byte) error) error {
+	n, err := t.node(hash, nil)
+	if err != nil {
+		return err
+	}
+	if size == 1 {
+		return fn(n.key, n.value)
+	}
+	split := splitPoint(size)
+	if err := t.walk(n.left, split, fn); err != nil {
+		return err
+	}
+	return t.walk(n.right, size-split, fn)
+}
+
+// commit sets the given pairs on top of the previous version, saving the new
+// version to batch. New keys are appended in key order, so that the tree does
+// not depend on the iteration order of pairs. It returns the new root hash.
+func (t *tree) commit(batch dbm.Batch, version int64, pairs map[string][]byte) ([]byte, error) {
+	r, ok, err := t.root(version - 1)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("previous version %d does not exist", version-1)
+	}
+
+	keys := make([]string, 0, len(pairs))
+	for key := range pairs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	created := make(map[string]*node)
+	for _, key := range keys {
+		leaf := newLeaf([]byte(key), pairs[key])
+		created[string(leaf.hash)] = leaf
+
+		index, ok, err := t.index([]byte(key))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			r.hash, err = t.update(r.hash, r.size, index, leaf, created)
+		} else {
+			if err := t.setIndex(batch, []byte(key), r.size); err != nil {
+				return nil, err
+			}
+			if r.size == 0 {
+				r.hash = leaf.hash
+			} else {
+				r.hash, err = t.append(r.hash, r.size, leaf, created)
+			}
+			r.size++
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the created nodes that are still part of the tree are saved.
+	if err := t.save(batch, r.hash, created); err != nil {
+		return nil, err
+	}
+	if err := t.setRoot(batch, version, r); err != nil {
+		return nil, err
+	}
+	return r.hash, nil
+}
+
+// replace replaces all versions of the tree with a single version holding the
+// given pairs in leaf order, saving it to batch. It returns the root hash.
+func (t *tree) replace(batch dbm.Batch, version int64, keys, values [][]byte) ([]byte, error) {
+	for _, prefix := range [][]byte{nodePrefix, rootPrefix, indexPrefix} {
+		if err := deletePrefix(t.db, batch, prefix); err != nil {
+			return nil, err
+		}
+	}
+
+	r := emptyRoot
+	created := make(map[string]*node)
+	leaves := make([]*node, len(keys))
+	indexes := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		if indexes[string(key)] {
+			return nil, fmt.Errorf("duplicate key %X", key)
+		}
+		indexes[string(key)] = true
+		if err := t.setIndex(batch, key, int64(i)); err != nil {
+			return nil, err
+		}
+		leaves[i] = newLeaf(key, values[i])
+		created[string(leaves[i].hash)] = leaves[i]
+	}
+	if len(leaves) > 0 {
+		r = root{hash: build(leaves, created), size: int64(len(leaves))}
+	}
+
+	if err := t.save(batch, r.hash, created); err != nil {
+		return nil, err
+	}
+	if err := t.setRoot(batch, version, r); err != nil {
+		return nil, err
+	}
+	return r.hash, nil
+}
+
+// build builds a subtree over the given leaves, returning its hash.
+func build(leaves []*node, created map[string]*node) []byte {
+	if len(leaves) == 1 {
+		return leaves[0].hash
+	}
+	split := splitPoint(int64(len(leaves)))
+	inner := newInner(build(leaves[:split], created), build(leaves[split:], created))
+	created[string(inner.hash)] = inner
+	return inner.hash
+}
+
+func deletePrefix(db dbm.DB, batch dbm.Batch, prefix []byte) error {
+	iter, err := dbm.IteratePrefix(db, prefix)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		if err := batch.Delete(iter.Key()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// update replaces the leaf at the given index of a subtree of the given size,
+// returning the new hash of the subtree.
+func (t *tree) update(hash []byte, size, index int64, leaf *node, created map[string]*node) ([]byte, error) {
+	if size == 1 {
+		return leaf.hash, nil
+	}
+	n, err := t.node(hash, created)
+	if err != nil {
+		return nil, err
+	}
+	left, right := n.left, n.right
+	split := splitPoint(size)
+	if index < split {
+		left, err = t.update(n.left, split, index, leaf, created)
+	} else {
+		right, err = t.update(n.right, size-split, index-split, leaf, created)
+	}
+	if err != nil {
+		return nil, err
+	}
+	inner := newInner(left, right)
+	created[string(inner.hash)] = inner
+	return inner.hash, nil
+}
+
+// append appends a leaf to a non-empty subtree of the given size, returning
+// the new hash of the subtree. A subtree whose size is a power of two becomes
+// the left child of the new subtree, and otherwise the leaf is appended to its
+// right child, which keeps the shape of merkle.HashFromByteSlices.
+func (t *tree) append(hash []byte, size int64, leaf *node, created map[string]*node) ([]byte, error) {
+	if size&(size-1) == 0 {
+		inner := newInner(hash, leaf.hash)
+		created[string(inner.hash)] = inner
+		return inner.hash, nil
+	}
+	n, err := t.node(hash, created)
+	if err != nil {
+		return nil, err
+	}
+	split := splitPoint(size)
+	right, err := t.append(n.right, size-split, leaf, created)
+	if err != nil {
+		return nil, err
+	}
+	inner := newInner(n.left, right)
+	created[string(inner.hash)] = inner
+	return inner.hash, nil
+}
+
+// save saves the created nodes reachable from hash to batch.
+func (t *tree) save(batch dbm.Batch, hash []byte, created map[string]*node) error {
+	n, ok := created[string(hash)]
+	if !ok {
+		return nil // already saved
+	}
+	delete(created, string(hash))
+	if err := batch.Set(nodeKey(hash), n.marshal()); err != nil {
+		return err
+	}
+	if n.leaf {
+		return nil
+	}
+	if err := t.save(batch, n.left, created); err != nil {
+		return err
+	}
+	return t.save(batch, n.right, created)
+}
+
+// splitPoint returns the size of the left subtree of a tree of the given size,
+// the largest power of two less than size, as in the merkle package.
+func splitPoint(size int64) int64 {
+	split := int64(1)
+	for split*2 < size {
+		split *= 2
+	}
+	return split
+}
+
+func writeByteSlice(buf *bytes.Buffer, bz []byte) {
+	var n [binary.MaxVarintLen64]byte
+	buf.Write(n[:binary.PutUvarint(n[:], uint64(len(bz)))])
+	buf.Write(bz)
+}
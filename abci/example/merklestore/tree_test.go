@@ -0,0 +1,116 @@
+package merklestore
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// leafBytes returns the bytes hashed into the leaf of a pair.
+func leafBytes(key, value string) []byte {
+	var buf bytes.Buffer
+	writeByteSlice(&buf, []byte(key))
+	writeByteSlice(&buf, tmhash.Sum([]byte(value)))
+	return buf.Bytes()
+}
+
+func TestTree(t *testing.T) {
+	db := dbm.NewMemDB()
+	tr := &tree{db: db}
+
+	// The expected contents of every version, in leaf order.
+	var (
+		keys     []string
+		values   = map[string]string{}
+		versions []map[string]string
+		hashes   [][]byte
+	)
+	for version := int64(1); version <= 20; version++ {
+		pairs := map[string][]byte{}
+		for i := int64(0); i < version%4; i++ {
+			key := fmt.Sprintf("key%d", (version*7+i*3)%23)
+			value := fmt.Sprintf("value%d-%d", version, i)
+			pairs[key] = []byte(value)
+		}
+		// New keys are appended in key order.
+		var newKeys []string
+		for key, value := range pairs {
+			if _, ok := values[key]; !ok {
+				newKeys = append(newKeys, key)
+			}
+			values[key] = string(value)
+		}
+		sort.Strings(newKeys)
+		keys = append(keys, newKeys...)
+
+		batch := db.NewBatch()
+		hash, err := tr.commit(batch, version, pairs)
+		require.NoError(t, err)
+		require.NoError(t, batch.WriteSync())
+		require.NoError(t, batch.Close())
+
+		// The tree has the shape of merkle.HashFromByteSlices.
+		leaves := make([][]byte, len(keys))
+		for i, key := range keys {
+			leaves[i] = leafBytes(key, values[key])
+		}
+		require.Equal(t, merkle.HashFromByteSlices(leaves), hash, "version %d", version)
+
+		snapshot := map[string]string{}
+		for key, value := range values {
+			snapshot[key] = value
+		}
+		versions = append(versions, snapshot)
+		hashes = append(hashes, hash)
+	}
+
+	// Every version can be read and proven.
+	for i, expected := range versions {
+		version := int64(i + 1)
+		size, err := tr.size(version)
+		require.NoError(t, err)
+		require.EqualValues(t, len(expected), size)
+
+		for key, value := range expected {
+			v, proof, err := tr.prove(version, []byte(key))
+			require.NoError(t, err)
+			require.Equal(t, value, string(v))
+			op := merkle.NewValueOp([]byte(key), proof)
+			keyPath := merkle.KeyPath{}.AppendKey([]byte(key), merkle.KeyEncodingURL).String()
+			require.NoError(t, merkle.ProofOperators{op}.Verify(hashes[i], keyPath, [][]byte{v}))
+		}
+		for key := range values {
+			if _, ok := expected[key]; !ok {
+				v, err := tr.get(version, []byte(key))
+				require.NoError(t, err)
+				require.Nil(t, v)
+			}
+		}
+	}
+
+	// The latest version can be replaced with its own pairs.
+	var rkeys, rvalues [][]byte
+	require.NoError(t, tr.forEach(20, func(key, value []byte) error {
+		rkeys = append(rkeys, key)
+		rvalues = append(rvalues, value)
+		return nil
+	}))
+	batch := db.NewBatch()
+	hash, err := tr.replace(batch, 30, rkeys, rvalues)
+	require.NoError(t, err)
+	require.NoError(t, batch.WriteSync())
+	require.NoError(t, batch.Close())
+	require.Equal(t, hashes[19], hash)
+	_, _, err = tr.prove(20, []byte(keys[0]))
+	require.Error(t, err)
+	v, err := tr.get(30, []byte(keys[0]))
+	require.NoError(t, err)
+	require.Equal(t, values[keys[0]], string(v))
+}
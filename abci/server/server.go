@@ -4,6 +4,8 @@ Package server is used to start a new ABCI server.
 It contains two server implementation:
  * gRPC server
  * socket server
+ * shared memory server, a socket server for unix sockets which transfers
+   large messages through shared memory
 
 */
 package server
@@ -22,6 +24,8 @@ func NewServer(logger log.Logger, protoAddr, transport string, app types.Applica
 	switch transport {
 	case "socket":
 		s = NewSocketServer(logger, protoAddr, app)
+	case "shm":
+		s = NewShmServer(logger, protoAddr, app)
 	case "grpc":
 		s = NewGRPCServer(logger, protoAddr, types.NewGRPCApplication(app))
 	default:
@@ -3,12 +3,15 @@ package server
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"runtime"
+	"time"
 
 	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/internal/libs/shmconn"
 	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
 	"github.com/tendermint/tendermint/libs/log"
 	tmnet "github.com/tendermint/tendermint/libs/net"
@@ -24,6 +27,7 @@ type SocketServer struct {
 	proto    string
 	addr     string
 	listener net.Listener
+	wrapConn func(net.Conn) (net.Conn, error) // applied to accepted connections, if set
 
 	connsMtx   tmsync.Mutex
 	conns      map[int]net.Conn
@@ -47,6 +51,38 @@ func NewSocketServer(logger log.Logger, protoAddr string, app types.Application)
 	return s
 }
 
+// NewShmServer creates a socket server listening on a unix socket address,
+// which transfers large requests and responses through shared memory set up
+// by clients created with abciclient.NewShmClient.
+func NewShmServer(logger log.Logger, protoAddr string, app types.Application) service.Service {
+	s := NewSocketServer(logger, protoAddr, app).(*SocketServer)
+	s.wrapConn = acceptShm
+	return s
+}
+
+// shmSetupTimeout bounds the time a client may take to set up shared memory
+// after connecting.
+const shmSetupTimeout = 10 * time.Second
+
+func acceptShm(conn net.Conn) (net.Conn, error) {
+	uconn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return nil, fmt.Errorf("shared memory transport requires a unix socket, got %s", conn.LocalAddr().Network())
+	}
+	if err := uconn.SetDeadline(time.Now().Add(shmSetupTimeout)); err != nil {
+		return nil, err
+	}
+	sconn, err := shmconn.Server(uconn)
+	if err != nil {
+		return nil, err
+	}
+	if err := uconn.SetDeadline(time.Time{}); err != nil {
+		sconn.Close()
+		return nil, err
+	}
+	return sconn, nil
+}
+
 func (s *SocketServer) OnStart(ctx context.Context) error {
 	ln, err := net.Listen(s.proto, s.addr)
 	if err != nil {
@@ -86,6 +122,19 @@ func (s *SocketServer) addConn(conn net.Conn) int {
 	return connID
 }
 
+// replaceConn replaces the connection registered as connID, e.g. by a wrapped
+// one, returning false if it was removed already.
+func (s *SocketServer) replaceConn(connID int, conn net.Conn) bool {
+	s.connsMtx.Lock()
+	defer s.connsMtx.Unlock()
+
+	if _, ok := s.conns[connID]; !ok {
+		return false
+	}
+	s.conns[connID] = conn
+	return true
+}
+
 // deletes conn even if close errs
 func (s *SocketServer) rmConn(connID int) error {
 	s.connsMtx.Lock()
@@ -122,17 +171,43 @@ func (s *SocketServer) acceptConnectionsRoutine(ctx context.Context) {
 
 		connID := s.addConn(conn)
 
-		closeConn := make(chan error, 2)              // Push to signal connection closed
-		responses := make(chan *types.Response, 1000) // A channel to buffer responses
-
-		// Read requests from conn and deal with them
-		go s.handleRequests(ctx, closeConn, conn, responses)
-		// Pull responses from 'responses' and write them to conn.
-		go s.handleResponses(ctx, closeConn, conn, responses)
+		// the connection is set up in its own goroutine, so that a slow
+		// client does not hold up accepting others
+		go s.serveConn(ctx, connID, conn)
+	}
+}
 
-		// Wait until signal to close connection
-		go s.waitForClose(ctx, closeConn, connID)
+// serveConn sets up an accepted connection and handles its requests until it
+// is closed.
+func (s *SocketServer) serveConn(ctx context.Context, connID int, conn net.Conn) {
+	if s.wrapConn != nil {
+		wrapped, err := s.wrapConn(conn)
+		if err != nil {
+			s.logger.Error("Failed to set up connection", "err", err)
+			if err := s.rmConn(connID); err != nil {
+				s.logger.Error("Error closing connection", "err", err)
+			}
+			return
+		}
+		if !s.replaceConn(connID, wrapped) {
+			// the server was stopped during the setup
+			wrapped.Close()
+			return
+		}
+		conn = wrapped
 	}
+
+	closeConn := make(chan error, 2)              // Push to signal connection closed
+	responses := make(chan *types.Response, 1000) // A channel to buffer responses
+	parts := newStreamParts()
+
+	// Read requests from conn and deal with them
+	go s.handleRequests(ctx, closeConn, conn, responses, parts)
+	// Pull responses from 'responses' and write them to conn.
+	go s.handleResponses(ctx, closeConn, conn, responses, parts)
+
+	// Wait until signal to close connection
+	s.waitForClose(ctx, closeConn, connID)
 }
 
 func (s *SocketServer) waitForClose(ctx context.Context, closeConn chan error, connID int) {
@@ -165,6 +240,7 @@ func (s *SocketServer) handleRequests(
 	closeConn chan error,
 	conn io.Reader,
 	responses chan<- *types.Response,
+	parts *streamParts,
 ) {
 	var count int
 	var bufReader = bufio.NewReader(conn)
@@ -199,12 +275,12 @@ func (s *SocketServer) handleRequests(
 		}
 		s.appMtx.Lock()
 		count++
-		s.handleRequest(req, responses)
+		s.handleRequest(req, responses, parts)
 		s.appMtx.Unlock()
 	}
 }
 
-func (s *SocketServer) handleRequest(req *types.Request, responses chan<- *types.Response) {
+func (s *SocketServer) handleRequest(req *types.Request, responses chan<- *types.Response, parts *streamParts) {
 	switch r := req.Value.(type) {
 	case *types.Request_Echo:
 		responses <- types.ToResponseEcho(r.Echo.Message)
@@ -223,6 +299,14 @@ func (s *SocketServer) handleRequest(req *types.Request, responses chan<- *types
 		res := s.app.Commit()
 		responses <- types.ToResponseCommit(res)
 	case *types.Request_Query:
+		if r.Query.Stream {
+			// Application errors are sent in the last part, so the only
+			// error is the connection being stopped.
+			_ = types.StreamQuery(s.app, *r.Query, func(part *types.ResponseQueryPart) error {
+				return s.sendPart(responses, parts, types.ToResponseQueryPart(*part))
+			})
+			return
+		}
 		res := s.app.Query(*r.Query)
 		responses <- types.ToResponseQuery(res)
 	case *types.Request_InitChain:
@@ -241,6 +325,13 @@ func (s *SocketServer) handleRequest(req *types.Request, responses chan<- *types
 		res := s.app.OfferSnapshot(*r.OfferSnapshot)
 		responses <- types.ToResponseOfferSnapshot(res)
 	case *types.Request_LoadSnapshotChunk:
+		if r.LoadSnapshotChunk.Stream {
+			_ = types.StreamSnapshotChunk(s.app, *r.LoadSnapshotChunk,
+				func(part *types.ResponseLoadSnapshotChunkPart) error {
+					return s.sendPart(responses, parts, types.ToResponseLoadSnapshotChunkPart(*part))
+				})
+			return
+		}
 		res := s.app.LoadSnapshotChunk(*r.LoadSnapshotChunk)
 		responses <- types.ToResponseLoadSnapshotChunk(res)
 	case *types.Request_ApplySnapshotChunk:
@@ -251,13 +342,25 @@ func (s *SocketServer) handleRequest(req *types.Request, responses chan<- *types
 	}
 }
 
+// sendPart sends a part of a streamed response. The application mutex, held
+// while handling the request, is released until the part is written, so that
+// a slow client doesn't hold up the requests of other connections.
+func (s *SocketServer) sendPart(responses chan<- *types.Response, parts *streamParts, res *types.Response) error {
+	s.appMtx.Unlock()
+	defer s.appMtx.Lock()
+	return parts.send(responses, res)
+}
+
 // Pull responses from 'responses' and write them to conn.
 func (s *SocketServer) handleResponses(
 	ctx context.Context,
 	closeConn chan error,
 	conn io.Writer,
 	responses <-chan *types.Response,
+	parts *streamParts,
 ) {
+	defer close(parts.stopped)
+
 	bw := bufio.NewWriter(conn)
 	for res := range responses {
 		if err := types.WriteMessage(res, bw); err != nil {
@@ -268,5 +371,50 @@ func (s *SocketServer) handleResponses(
 			closeConn <- fmt.Errorf("error flushing write buffer: %w", err)
 			return
 		}
+		parts.wrote(res)
+	}
+}
+
+// errConnStopped is returned when sending a part of a streamed response on a
+// connection whose responses are no longer written.
+var errConnStopped = errors.New("connection stopped")
+
+// streamParts lets the request handler of a connection wait for each part of
+// a streamed response to be written to the connection before producing the
+// next one, so that a streamed response is not buffered in the responses
+// channel but held up by a slow client.
+type streamParts struct {
+	written chan struct{} // signaled when a part was written
+	stopped chan struct{} // closed once responses are no longer written
+}
+
+func newStreamParts() *streamParts {
+	return &streamParts{
+		written: make(chan struct{}, 1),
+		stopped: make(chan struct{}),
+	}
+}
+
+// send queues a part of a streamed response, and waits until it's written.
+func (p *streamParts) send(responses chan<- *types.Response, res *types.Response) error {
+	select {
+	case responses <- res:
+	case <-p.stopped:
+		return errConnStopped
+	}
+	select {
+	case <-p.written:
+		return nil
+	case <-p.stopped:
+		return errConnStopped
+	}
+}
+
+// wrote is called once res was written. Since the parts are sent one at a
+// time, it never blocks.
+func (p *streamParts) wrote(res *types.Response) {
+	switch res.Value.(type) {
+	case *types.Response_QueryPart, *types.Response_LoadSnapshotChunkPart:
+		p.written <- struct{}{}
 	}
 }
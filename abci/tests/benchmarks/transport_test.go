@@ -0,0 +1,74 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	abciclient "github.com/tendermint/tendermint/abci/client"
+	abciserver "github.com/tendermint/tendermint/abci/server"
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// BenchmarkTransportDeliverTx compares the socket and shared memory
+// transports by delivering batches of transactions of various sizes.
+func BenchmarkTransportDeliverTx(b *testing.B) {
+	transports := []string{"socket"}
+	if runtime.GOOS == "linux" {
+		transports = append(transports, "shm")
+	}
+
+	for _, transport := range transports {
+		for _, size := range []int{256, 64 * 1024, 1024 * 1024} {
+			b.Run(fmt.Sprintf("%s/%dB", transport, size), func(b *testing.B) {
+				benchmarkDeliverTx(b, transport, size)
+			})
+		}
+	}
+}
+
+func benchmarkDeliverTx(b *testing.B, transport string, size int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr := "unix://" + filepath.Join(b.TempDir(), "abci.sock")
+	logger := log.NewNopLogger()
+
+	server, err := abciserver.NewServer(logger, addr, transport, types.NewBaseApplication())
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := server.Start(ctx); err != nil {
+		b.Fatal(err)
+	}
+
+	client, err := abciclient.NewClient(logger, addr, transport, true)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := client.Start(ctx); err != nil {
+		b.Fatal(err)
+	}
+
+	const batch = 16
+	tx := make([]byte, size)
+
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.DeliverTxAsync(ctx, types.RequestDeliverTx{Tx: tx}); err != nil {
+			b.Fatal(err)
+		}
+		if i%batch == batch-1 {
+			if err := client.FlushSync(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	if err := client.FlushSync(ctx); err != nil {
+		b.Fatal(err)
+	}
+}
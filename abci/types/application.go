@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"io"
 )
 
 // Application is an interface that enables any finite, deterministic state machine
@@ -30,6 +31,27 @@ type Application interface {
 	ApplySnapshotChunk(RequestApplySnapshotChunk) ResponseApplySnapshotChunk // Apply a shapshot chunk
 }
 
+// StreamingApplication is an Application which streams query results and
+// snapshot chunks instead of returning them in a single response, so that
+// they need not fit in one message or be held in memory at once. The streamed
+// methods are only called for streamed requests, e.g. from the QueryStream
+// and LoadSnapshotChunkStream client methods; the others are answered by
+// Query and LoadSnapshotChunk as usual.
+//
+// Other requests may be handled while a part is being sent, including
+// Commit, so a stream must be produced from a consistent view of the
+// application state, e.g. the committed version the request refers to.
+type StreamingApplication interface {
+	Application
+
+	// QueryStream answers a query like Query, except that the result value
+	// is written to the writer returned by begin, which must be called once
+	// with the rest of the response before writing.
+	QueryStream(req RequestQuery, begin func(ResponseQuery) io.Writer) error
+	// LoadSnapshotChunkStream writes a snapshot chunk to w.
+	LoadSnapshotChunkStream(req RequestLoadSnapshotChunk, w io.Writer) error
+}
+
 //-------------------------------------------------------
 // BaseApplication is a base form of Application
 
@@ -172,3 +194,12 @@ func (app *GRPCApplication) ApplySnapshotChunk(
 	res := app.app.ApplySnapshotChunk(*req)
 	return &res, nil
 }
+
+func (app *GRPCApplication) QueryStream(req *RequestQuery, stream ABCIApplication_QueryStreamServer) error {
+	return StreamQuery(app.app, *req, stream.Send)
+}
+
+func (app *GRPCApplication) LoadSnapshotChunkStream(
+	req *RequestLoadSnapshotChunk, stream ABCIApplication_LoadSnapshotChunkStreamServer) error {
+	return StreamSnapshotChunk(app.app, *req, stream.Send)
+}
@@ -200,3 +200,15 @@ func ToResponseApplySnapshotChunk(res ResponseApplySnapshotChunk) *Response {
 		Value: &Response_ApplySnapshotChunk{&res},
 	}
 }
+
+func ToResponseQueryPart(res ResponseQueryPart) *Response {
+	return &Response{
+		Value: &Response_QueryPart{&res},
+	}
+}
+
+func ToResponseLoadSnapshotChunkPart(res ResponseLoadSnapshotChunkPart) *Response {
+	return &Response{
+		Value: &Response_LoadSnapshotChunkPart{&res},
+	}
+}
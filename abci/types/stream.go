@@ -0,0 +1,116 @@
+package types
+
+import (
+	"errors"
+	"io"
+)
+
+// StreamPartSize is the maximum size of the data carried by a single part of
+// a streamed response.
+const StreamPartSize = 1 << 20 // 1MB
+
+// StreamQuery answers a streamed query with app, passing the parts of the
+// response to send in order. Applications which do not implement
+// StreamingApplication are answered by Query, whose value is split into
+// parts. If the application fails, the error is sent in the last part; only
+// errors returned by send are returned.
+func StreamQuery(app Application, req RequestQuery, send func(*ResponseQueryPart) error) error {
+	var header *ResponseQuery
+	w := &partWriter{send: func(value []byte, last bool) error {
+		part := &ResponseQueryPart{Header: header, Value: value, Last: last}
+		header = nil
+		return send(part)
+	}}
+	begun := false
+	begin := func(res ResponseQuery) io.Writer {
+		res.Value = nil
+		header = &res
+		begun = true
+		return w
+	}
+
+	if sapp, ok := app.(StreamingApplication); ok {
+		err := sapp.QueryStream(req, begin)
+		if err == nil && !begun {
+			err = errors.New("streamed query answered without a response")
+		}
+		if err != nil {
+			if w.err != nil {
+				return w.err
+			}
+			return send(&ResponseQueryPart{Header: header, Last: true, Error: err.Error()})
+		}
+	} else {
+		res := app.Query(req)
+		if _, err := begin(res).Write(res.Value); err != nil {
+			return err
+		}
+	}
+	return w.close()
+}
+
+// StreamSnapshotChunk loads a snapshot chunk from app, passing its parts to
+// send in order. Applications which do not implement StreamingApplication
+// are answered by LoadSnapshotChunk, whose chunk is split into parts. Like
+// for StreamQuery, application errors are sent in the last part.
+func StreamSnapshotChunk(
+	app Application,
+	req RequestLoadSnapshotChunk,
+	send func(*ResponseLoadSnapshotChunkPart) error,
+) error {
+	w := &partWriter{send: func(chunk []byte, last bool) error {
+		return send(&ResponseLoadSnapshotChunkPart{Chunk: chunk, Last: last})
+	}}
+
+	if sapp, ok := app.(StreamingApplication); ok {
+		if err := sapp.LoadSnapshotChunkStream(req, w); err != nil {
+			if w.err != nil {
+				return w.err
+			}
+			return send(&ResponseLoadSnapshotChunkPart{Last: true, Error: err.Error()})
+		}
+	} else if _, err := w.Write(app.LoadSnapshotChunk(req).Chunk); err != nil {
+		return err
+	}
+	return w.close()
+}
+
+// partWriter cuts the data written to it into parts of at most
+// StreamPartSize bytes, which it passes to send. The data of a part is not
+// modified once sent. Once send fails, so do all further writes.
+type partWriter struct {
+	send func(data []byte, last bool) error
+	buf  []byte
+	err  error
+}
+
+func (w *partWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if w.err != nil {
+			return written, w.err
+		}
+		if len(w.buf) == StreamPartSize {
+			w.err = w.send(w.buf, false)
+			w.buf = nil
+			continue
+		}
+		n := StreamPartSize - len(w.buf)
+		if n > len(p) {
+			n = len(p)
+		}
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// close sends the last part.
+func (w *partWriter) close() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.send(w.buf, true)
+	return w.err
+}
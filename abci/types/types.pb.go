@@ -120,7 +120,7 @@ func (x ResponseOfferSnapshot_Result) String() string {
 }
 
 func (ResponseOfferSnapshot_Result) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{28, 0}
+	return fileDescriptor_252557cfdd89a31a, []int{29, 0}
 }
 
 type ResponseApplySnapshotChunk_Result int32
@@ -157,7 +157,7 @@ func (x ResponseApplySnapshotChunk_Result) String() string {
 }
 
 func (ResponseApplySnapshotChunk_Result) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{30, 0}
+	return fileDescriptor_252557cfdd89a31a, []int{32, 0}
 }
 
 type Request struct {
@@ -638,6 +638,7 @@ type RequestQuery struct {
 	Path   string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
 	Height int64  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
 	Prove  bool   `protobuf:"varint,4,opt,name=prove,proto3" json:"prove,omitempty"`
+	Stream bool   `protobuf:"varint,5,opt,name=stream,proto3" json:"stream,omitempty"`
 }
 
 func (m *RequestQuery) Reset()         { *m = RequestQuery{} }
@@ -701,6 +702,13 @@ func (m *RequestQuery) GetProve() bool {
 	return false
 }
 
+func (m *RequestQuery) GetStream() bool {
+	if m != nil {
+		return m.Stream
+	}
+	return false
+}
+
 type RequestBeginBlock struct {
 	Hash                []byte         `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
 	Header              types1.Header  `protobuf:"bytes,2,opt,name=header,proto3" json:"header"`
@@ -1040,6 +1048,7 @@ type RequestLoadSnapshotChunk struct {
 	Height uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
 	Format uint32 `protobuf:"varint,2,opt,name=format,proto3" json:"format,omitempty"`
 	Chunk  uint32 `protobuf:"varint,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	Stream bool   `protobuf:"varint,4,opt,name=stream,proto3" json:"stream,omitempty"`
 }
 
 func (m *RequestLoadSnapshotChunk) Reset()         { *m = RequestLoadSnapshotChunk{} }
@@ -1096,6 +1105,13 @@ func (m *RequestLoadSnapshotChunk) GetChunk() uint32 {
 	return 0
 }
 
+func (m *RequestLoadSnapshotChunk) GetStream() bool {
+	if m != nil {
+		return m.Stream
+	}
+	return false
+}
+
 // Applies a snapshot chunk
 type RequestApplySnapshotChunk struct {
 	Index  uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
@@ -1174,6 +1190,8 @@ type Response struct {
 	//	*Response_OfferSnapshot
 	//	*Response_LoadSnapshotChunk
 	//	*Response_ApplySnapshotChunk
+	//	*Response_QueryPart
+	//	*Response_LoadSnapshotChunkPart
 	Value isResponse_Value `protobuf_oneof:"value"`
 }
 
@@ -1261,22 +1279,30 @@ type Response_LoadSnapshotChunk struct {
 type Response_ApplySnapshotChunk struct {
 	ApplySnapshotChunk *ResponseApplySnapshotChunk `protobuf:"bytes,15,opt,name=apply_snapshot_chunk,json=applySnapshotChunk,proto3,oneof" json:"apply_snapshot_chunk,omitempty"`
 }
-
-func (*Response_Exception) isResponse_Value()          {}
-func (*Response_Echo) isResponse_Value()               {}
-func (*Response_Flush) isResponse_Value()              {}
-func (*Response_Info) isResponse_Value()               {}
-func (*Response_InitChain) isResponse_Value()          {}
-func (*Response_Query) isResponse_Value()              {}
-func (*Response_BeginBlock) isResponse_Value()         {}
-func (*Response_CheckTx) isResponse_Value()            {}
-func (*Response_DeliverTx) isResponse_Value()          {}
-func (*Response_EndBlock) isResponse_Value()           {}
-func (*Response_Commit) isResponse_Value()             {}
-func (*Response_ListSnapshots) isResponse_Value()      {}
-func (*Response_OfferSnapshot) isResponse_Value()      {}
-func (*Response_LoadSnapshotChunk) isResponse_Value()  {}
-func (*Response_ApplySnapshotChunk) isResponse_Value() {}
+type Response_QueryPart struct {
+	QueryPart *ResponseQueryPart `protobuf:"bytes,16,opt,name=query_part,json=queryPart,proto3,oneof" json:"query_part,omitempty"`
+}
+type Response_LoadSnapshotChunkPart struct {
+	LoadSnapshotChunkPart *ResponseLoadSnapshotChunkPart `protobuf:"bytes,17,opt,name=load_snapshot_chunk_part,json=loadSnapshotChunkPart,proto3,oneof" json:"load_snapshot_chunk_part,omitempty"`
+}
+
+func (*Response_Exception) isResponse_Value()             {}
+func (*Response_Echo) isResponse_Value()                  {}
+func (*Response_Flush) isResponse_Value()                 {}
+func (*Response_Info) isResponse_Value()                  {}
+func (*Response_InitChain) isResponse_Value()             {}
+func (*Response_Query) isResponse_Value()                 {}
+func (*Response_BeginBlock) isResponse_Value()            {}
+func (*Response_CheckTx) isResponse_Value()               {}
+func (*Response_DeliverTx) isResponse_Value()             {}
+func (*Response_EndBlock) isResponse_Value()              {}
+func (*Response_Commit) isResponse_Value()                {}
+func (*Response_ListSnapshots) isResponse_Value()         {}
+func (*Response_OfferSnapshot) isResponse_Value()         {}
+func (*Response_LoadSnapshotChunk) isResponse_Value()     {}
+func (*Response_ApplySnapshotChunk) isResponse_Value()    {}
+func (*Response_QueryPart) isResponse_Value()             {}
+func (*Response_LoadSnapshotChunkPart) isResponse_Value() {}
 
 func (m *Response) GetValue() isResponse_Value {
 	if m != nil {
@@ -1390,6 +1416,20 @@ func (m *Response) GetApplySnapshotChunk() *ResponseApplySnapshotChunk {
 	return nil
 }
 
+func (m *Response) GetQueryPart() *ResponseQueryPart {
+	if x, ok := m.GetValue().(*Response_QueryPart); ok {
+		return x.QueryPart
+	}
+	return nil
+}
+
+func (m *Response) GetLoadSnapshotChunkPart() *ResponseLoadSnapshotChunkPart {
+	if x, ok := m.GetValue().(*Response_LoadSnapshotChunkPart); ok {
+		return x.LoadSnapshotChunkPart
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Response) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -1408,6 +1448,8 @@ func (*Response) XXX_OneofWrappers() []interface{} {
 		(*Response_OfferSnapshot)(nil),
 		(*Response_LoadSnapshotChunk)(nil),
 		(*Response_ApplySnapshotChunk)(nil),
+		(*Response_QueryPart)(nil),
+		(*Response_LoadSnapshotChunkPart)(nil),
 	}
 }
 
@@ -1782,6 +1824,79 @@ func (m *ResponseQuery) GetCodespace() string {
 	return ""
 }
 
+// ResponseQueryPart is a part of a streamed query response. The first part
+// carries the response without its value, and the value is split over the
+// value of all parts. If the response can't be completed, e.g. because the
+// application failed, the last part carries the error instead, and the parts
+// received so far are to be discarded.
+type ResponseQueryPart struct {
+	Header *ResponseQuery `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Value  []byte         `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Last   bool           `protobuf:"varint,3,opt,name=last,proto3" json:"last,omitempty"`
+	Error  string         `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *ResponseQueryPart) Reset()         { *m = ResponseQueryPart{} }
+func (m *ResponseQueryPart) String() string { return proto.CompactTextString(m) }
+func (*ResponseQueryPart) ProtoMessage()    {}
+func (*ResponseQueryPart) Descriptor() ([]byte, []int) {
+	return fileDescriptor_252557cfdd89a31a, []int{22}
+}
+func (m *ResponseQueryPart) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ResponseQueryPart) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ResponseQueryPart.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ResponseQueryPart) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResponseQueryPart.Merge(m, src)
+}
+func (m *ResponseQueryPart) XXX_Size() int {
+	return m.Size()
+}
+func (m *ResponseQueryPart) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResponseQueryPart.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResponseQueryPart proto.InternalMessageInfo
+
+func (m *ResponseQueryPart) GetHeader() *ResponseQuery {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ResponseQueryPart) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *ResponseQueryPart) GetLast() bool {
+	if m != nil {
+		return m.Last
+	}
+	return false
+}
+
+func (m *ResponseQueryPart) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type ResponseBeginBlock struct {
 	Events []Event `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
 }
@@ -1790,7 +1905,7 @@ func (m *ResponseBeginBlock) Reset()         { *m = ResponseBeginBlock{} }
 func (m *ResponseBeginBlock) String() string { return proto.CompactTextString(m) }
 func (*ResponseBeginBlock) ProtoMessage()    {}
 func (*ResponseBeginBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{22}
+	return fileDescriptor_252557cfdd89a31a, []int{23}
 }
 func (m *ResponseBeginBlock) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1846,7 +1961,7 @@ func (m *ResponseCheckTx) Reset()         { *m = ResponseCheckTx{} }
 func (m *ResponseCheckTx) String() string { return proto.CompactTextString(m) }
 func (*ResponseCheckTx) ProtoMessage()    {}
 func (*ResponseCheckTx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{23}
+	return fileDescriptor_252557cfdd89a31a, []int{24}
 }
 func (m *ResponseCheckTx) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1967,7 +2082,7 @@ func (m *ResponseDeliverTx) Reset()         { *m = ResponseDeliverTx{} }
 func (m *ResponseDeliverTx) String() string { return proto.CompactTextString(m) }
 func (*ResponseDeliverTx) ProtoMessage()    {}
 func (*ResponseDeliverTx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{24}
+	return fileDescriptor_252557cfdd89a31a, []int{25}
 }
 func (m *ResponseDeliverTx) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2062,7 +2177,7 @@ func (m *ResponseEndBlock) Reset()         { *m = ResponseEndBlock{} }
 func (m *ResponseEndBlock) String() string { return proto.CompactTextString(m) }
 func (*ResponseEndBlock) ProtoMessage()    {}
 func (*ResponseEndBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{25}
+	return fileDescriptor_252557cfdd89a31a, []int{26}
 }
 func (m *ResponseEndBlock) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2122,7 +2237,7 @@ func (m *ResponseCommit) Reset()         { *m = ResponseCommit{} }
 func (m *ResponseCommit) String() string { return proto.CompactTextString(m) }
 func (*ResponseCommit) ProtoMessage()    {}
 func (*ResponseCommit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{26}
+	return fileDescriptor_252557cfdd89a31a, []int{27}
 }
 func (m *ResponseCommit) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2173,7 +2288,7 @@ func (m *ResponseListSnapshots) Reset()         { *m = ResponseListSnapshots{} }
 func (m *ResponseListSnapshots) String() string { return proto.CompactTextString(m) }
 func (*ResponseListSnapshots) ProtoMessage()    {}
 func (*ResponseListSnapshots) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{27}
+	return fileDescriptor_252557cfdd89a31a, []int{28}
 }
 func (m *ResponseListSnapshots) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2217,7 +2332,7 @@ func (m *ResponseOfferSnapshot) Reset()         { *m = ResponseOfferSnapshot{} }
 func (m *ResponseOfferSnapshot) String() string { return proto.CompactTextString(m) }
 func (*ResponseOfferSnapshot) ProtoMessage()    {}
 func (*ResponseOfferSnapshot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{28}
+	return fileDescriptor_252557cfdd89a31a, []int{29}
 }
 func (m *ResponseOfferSnapshot) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2261,7 +2376,7 @@ func (m *ResponseLoadSnapshotChunk) Reset()         { *m = ResponseLoadSnapshotC
 func (m *ResponseLoadSnapshotChunk) String() string { return proto.CompactTextString(m) }
 func (*ResponseLoadSnapshotChunk) ProtoMessage()    {}
 func (*ResponseLoadSnapshotChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{29}
+	return fileDescriptor_252557cfdd89a31a, []int{30}
 }
 func (m *ResponseLoadSnapshotChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2297,6 +2412,69 @@ func (m *ResponseLoadSnapshotChunk) GetChunk() []byte {
 	return nil
 }
 
+// ResponseLoadSnapshotChunkPart is a part of a streamed snapshot chunk. Like
+// for ResponseQueryPart, the last part carries an error if the chunk can't be
+// completed.
+type ResponseLoadSnapshotChunkPart struct {
+	Chunk []byte `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	Last  bool   `protobuf:"varint,2,opt,name=last,proto3" json:"last,omitempty"`
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *ResponseLoadSnapshotChunkPart) Reset()         { *m = ResponseLoadSnapshotChunkPart{} }
+func (m *ResponseLoadSnapshotChunkPart) String() string { return proto.CompactTextString(m) }
+func (*ResponseLoadSnapshotChunkPart) ProtoMessage()    {}
+func (*ResponseLoadSnapshotChunkPart) Descriptor() ([]byte, []int) {
+	return fileDescriptor_252557cfdd89a31a, []int{31}
+}
+func (m *ResponseLoadSnapshotChunkPart) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ResponseLoadSnapshotChunkPart) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ResponseLoadSnapshotChunkPart.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ResponseLoadSnapshotChunkPart) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResponseLoadSnapshotChunkPart.Merge(m, src)
+}
+func (m *ResponseLoadSnapshotChunkPart) XXX_Size() int {
+	return m.Size()
+}
+func (m *ResponseLoadSnapshotChunkPart) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResponseLoadSnapshotChunkPart.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResponseLoadSnapshotChunkPart proto.InternalMessageInfo
+
+func (m *ResponseLoadSnapshotChunkPart) GetChunk() []byte {
+	if m != nil {
+		return m.Chunk
+	}
+	return nil
+}
+
+func (m *ResponseLoadSnapshotChunkPart) GetLast() bool {
+	if m != nil {
+		return m.Last
+	}
+	return false
+}
+
+func (m *ResponseLoadSnapshotChunkPart) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type ResponseApplySnapshotChunk struct {
 	Result        ResponseApplySnapshotChunk_Result `protobuf:"varint,1,opt,name=result,proto3,enum=tendermint.abci.ResponseApplySnapshotChunk_Result" json:"result,omitempty"`
 	RefetchChunks []uint32                          `protobuf:"varint,2,rep,packed,name=refetch_chunks,json=refetchChunks,proto3" json:"refetch_chunks,omitempty"`
@@ -2307,7 +2485,7 @@ func (m *ResponseApplySnapshotChunk) Reset()         { *m = ResponseApplySnapsho
 func (m *ResponseApplySnapshotChunk) String() string { return proto.CompactTextString(m) }
 func (*ResponseApplySnapshotChunk) ProtoMessage()    {}
 func (*ResponseApplySnapshotChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{30}
+	return fileDescriptor_252557cfdd89a31a, []int{32}
 }
 func (m *ResponseApplySnapshotChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2366,7 +2544,7 @@ func (m *LastCommitInfo) Reset()         { *m = LastCommitInfo{} }
 func (m *LastCommitInfo) String() string { return proto.CompactTextString(m) }
 func (*LastCommitInfo) ProtoMessage()    {}
 func (*LastCommitInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{31}
+	return fileDescriptor_252557cfdd89a31a, []int{33}
 }
 func (m *LastCommitInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2421,7 +2599,7 @@ func (m *Event) Reset()         { *m = Event{} }
 func (m *Event) String() string { return proto.CompactTextString(m) }
 func (*Event) ProtoMessage()    {}
 func (*Event) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{32}
+	return fileDescriptor_252557cfdd89a31a, []int{34}
 }
 func (m *Event) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2475,7 +2653,7 @@ func (m *EventAttribute) Reset()         { *m = EventAttribute{} }
 func (m *EventAttribute) String() string { return proto.CompactTextString(m) }
 func (*EventAttribute) ProtoMessage()    {}
 func (*EventAttribute) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{33}
+	return fileDescriptor_252557cfdd89a31a, []int{35}
 }
 func (m *EventAttribute) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2539,7 +2717,7 @@ func (m *TxResult) Reset()         { *m = TxResult{} }
 func (m *TxResult) String() string { return proto.CompactTextString(m) }
 func (*TxResult) ProtoMessage()    {}
 func (*TxResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{34}
+	return fileDescriptor_252557cfdd89a31a, []int{36}
 }
 func (m *TxResult) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2607,7 +2785,7 @@ func (m *Validator) Reset()         { *m = Validator{} }
 func (m *Validator) String() string { return proto.CompactTextString(m) }
 func (*Validator) ProtoMessage()    {}
 func (*Validator) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{35}
+	return fileDescriptor_252557cfdd89a31a, []int{37}
 }
 func (m *Validator) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2660,7 +2838,7 @@ func (m *ValidatorUpdate) Reset()         { *m = ValidatorUpdate{} }
 func (m *ValidatorUpdate) String() string { return proto.CompactTextString(m) }
 func (*ValidatorUpdate) ProtoMessage()    {}
 func (*ValidatorUpdate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{36}
+	return fileDescriptor_252557cfdd89a31a, []int{38}
 }
 func (m *ValidatorUpdate) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2713,7 +2891,7 @@ func (m *VoteInfo) Reset()         { *m = VoteInfo{} }
 func (m *VoteInfo) String() string { return proto.CompactTextString(m) }
 func (*VoteInfo) ProtoMessage()    {}
 func (*VoteInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{37}
+	return fileDescriptor_252557cfdd89a31a, []int{39}
 }
 func (m *VoteInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2774,7 +2952,7 @@ func (m *Evidence) Reset()         { *m = Evidence{} }
 func (m *Evidence) String() string { return proto.CompactTextString(m) }
 func (*Evidence) ProtoMessage()    {}
 func (*Evidence) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{38}
+	return fileDescriptor_252557cfdd89a31a, []int{40}
 }
 func (m *Evidence) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2850,7 +3028,7 @@ func (m *Snapshot) Reset()         { *m = Snapshot{} }
 func (m *Snapshot) String() string { return proto.CompactTextString(m) }
 func (*Snapshot) ProtoMessage()    {}
 func (*Snapshot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_252557cfdd89a31a, []int{39}
+	return fileDescriptor_252557cfdd89a31a, []int{41}
 }
 func (m *Snapshot) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2941,6 +3119,7 @@ func init() {
 	proto.RegisterType((*ResponseInfo)(nil), "tendermint.abci.ResponseInfo")
 	proto.RegisterType((*ResponseInitChain)(nil), "tendermint.abci.ResponseInitChain")
 	proto.RegisterType((*ResponseQuery)(nil), "tendermint.abci.ResponseQuery")
+	proto.RegisterType((*ResponseQueryPart)(nil), "tendermint.abci.ResponseQueryPart")
 	proto.RegisterType((*ResponseBeginBlock)(nil), "tendermint.abci.ResponseBeginBlock")
 	proto.RegisterType((*ResponseCheckTx)(nil), "tendermint.abci.ResponseCheckTx")
 	proto.RegisterType((*ResponseDeliverTx)(nil), "tendermint.abci.ResponseDeliverTx")
@@ -2949,6 +3128,7 @@ func init() {
 	proto.RegisterType((*ResponseListSnapshots)(nil), "tendermint.abci.ResponseListSnapshots")
 	proto.RegisterType((*ResponseOfferSnapshot)(nil), "tendermint.abci.ResponseOfferSnapshot")
 	proto.RegisterType((*ResponseLoadSnapshotChunk)(nil), "tendermint.abci.ResponseLoadSnapshotChunk")
+	proto.RegisterType((*ResponseLoadSnapshotChunkPart)(nil), "tendermint.abci.ResponseLoadSnapshotChunkPart")
 	proto.RegisterType((*ResponseApplySnapshotChunk)(nil), "tendermint.abci.ResponseApplySnapshotChunk")
 	proto.RegisterType((*LastCommitInfo)(nil), "tendermint.abci.LastCommitInfo")
 	proto.RegisterType((*Event)(nil), "tendermint.abci.Event")
@@ -2964,172 +3144,180 @@ func init() {
 func init() { proto.RegisterFile("tendermint/abci/types.proto", fileDescriptor_252557cfdd89a31a) }
 
 var fileDescriptor_252557cfdd89a31a = []byte{
-	// 2627 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x5a, 0xcd, 0x73, 0xdb, 0xc6,
-	0x15, 0xe7, 0x37, 0x89, 0x47, 0x91, 0xa2, 0xd6, 0x8a, 0x43, 0x33, 0xb6, 0xe4, 0xc0, 0xe3, 0x34,
-	0x76, 0x12, 0xa9, 0x91, 0xc7, 0xae, 0x33, 0xe9, 0x47, 0x44, 0x9a, 0x2e, 0x15, 0xab, 0x92, 0xba,
-	0xa2, 0x9d, 0x49, 0xdb, 0x18, 0x01, 0x89, 0x15, 0x89, 0x98, 0x04, 0x10, 0x60, 0x29, 0x4b, 0x39,
-	0x76, 0xda, 0x8b, 0xa7, 0x07, 0x1f, 0x7b, 0xc9, 0x4c, 0xff, 0x83, 0x5e, 0x7b, 0xea, 0xa9, 0x87,
-	0x1c, 0xda, 0x99, 0x1c, 0x7b, 0xe8, 0xa4, 0x1d, 0xfb, 0xd6, 0x7f, 0xa0, 0xa7, 0xce, 0x74, 0xf6,
-	0x03, 0x20, 0x40, 0x12, 0x22, 0xd5, 0xf4, 0xd6, 0xdb, 0xee, 0xc3, 0x7b, 0x8f, 0xbb, 0x6f, 0xf7,
-	0xfd, 0xf6, 0xb7, 0x6f, 0x09, 0xaf, 0x51, 0x62, 0x19, 0xc4, 0x1d, 0x9a, 0x16, 0xdd, 0xd4, 0x3b,
-	0x5d, 0x73, 0x93, 0x9e, 0x3a, 0xc4, 0xdb, 0x70, 0x5c, 0x9b, 0xda, 0x68, 0x79, 0xfc, 0x71, 0x83,
-	0x7d, 0xac, 0x5d, 0x09, 0x69, 0x77, 0xdd, 0x53, 0x87, 0xda, 0x9b, 0x8e, 0x6b, 0xdb, 0x47, 0x42,
-	0xbf, 0x76, 0x39, 0xf4, 0x99, 0xfb, 0x09, 0x7b, 0x8b, 0x7c, 0x95, 0xc6, 0x4f, 0xc8, 0xa9, 0xff,
-	0xf5, 0xca, 0x94, 0xad, 0xa3, 0xbb, 0xfa, 0xd0, 0xff, 0xbc, 0xde, 0xb3, 0xed, 0xde, 0x80, 0x6c,
-	0xf2, 0x5e, 0x67, 0x74, 0xb4, 0x49, 0xcd, 0x21, 0xf1, 0xa8, 0x3e, 0x74, 0xa4, 0xc2, 0x6a, 0xcf,
-	0xee, 0xd9, 0xbc, 0xb9, 0xc9, 0x5a, 0x42, 0xaa, 0xfe, 0x25, 0x0f, 0x79, 0x4c, 0x3e, 0x1f, 0x11,
-	0x8f, 0xa2, 0x2d, 0xc8, 0x90, 0x6e, 0xdf, 0xae, 0x26, 0xaf, 0x26, 0xdf, 0x2c, 0x6e, 0x5d, 0xde,
-	0x98, 0x98, 0xdc, 0x86, 0xd4, 0x6b, 0x76, 0xfb, 0x76, 0x2b, 0x81, 0xb9, 0x2e, 0xba, 0x0d, 0xd9,
-	0xa3, 0xc1, 0xc8, 0xeb, 0x57, 0x53, 0xdc, 0xe8, 0x4a, 0x9c, 0xd1, 0x7d, 0xa6, 0xd4, 0x4a, 0x60,
-	0xa1, 0xcd, 0x7e, 0xca, 0xb4, 0x8e, 0xec, 0x6a, 0xfa, 0xec, 0x9f, 0xda, 0xb1, 0x8e, 0xf8, 0x4f,
-	0x31, 0x5d, 0x54, 0x07, 0x30, 0x2d, 0x93, 0x6a, 0xdd, 0xbe, 0x6e, 0x5a, 0xd5, 0x0c, 0xb7, 0x7c,
-	0x3d, 0xde, 0xd2, 0xa4, 0x0d, 0xa6, 0xd8, 0x4a, 0x60, 0xc5, 0xf4, 0x3b, 0x6c, 0xb8, 0x9f, 0x8f,
-	0x88, 0x7b, 0x5a, 0xcd, 0x9e, 0x3d, 0xdc, 0x9f, 0x32, 0x25, 0x36, 0x5c, 0xae, 0x8d, 0x9a, 0x50,
-	0xec, 0x90, 0x9e, 0x69, 0x69, 0x9d, 0x81, 0xdd, 0x7d, 0x52, 0xcd, 0x71, 0x63, 0x35, 0xce, 0xb8,
-	0xce, 0x54, 0xeb, 0x4c, 0xb3, 0x95, 0xc0, 0xd0, 0x09, 0x7a, 0xe8, 0xfb, 0x50, 0xe8, 0xf6, 0x49,
-	0xf7, 0x89, 0x46, 0x4f, 0xaa, 0x79, 0xee, 0x63, 0x3d, 0xce, 0x47, 0x83, 0xe9, 0xb5, 0x4f, 0x5a,
-	0x09, 0x9c, 0xef, 0x8a, 0x26, 0x9b, 0xbf, 0x41, 0x06, 0xe6, 0x31, 0x71, 0x99, 0x7d, 0xe1, 0xec,
-	0xf9, 0xdf, 0x13, 0x9a, 0xdc, 0x83, 0x62, 0xf8, 0x1d, 0xf4, 0x23, 0x50, 0x88, 0x65, 0xc8, 0x69,
-	0x28, 0xdc, 0xc5, 0xd5, 0xd8, 0x75, 0xb6, 0x0c, 0x7f, 0x12, 0x05, 0x22, 0xdb, 0xe8, 0x2e, 0xe4,
-	0xba, 0xf6, 0x70, 0x68, 0xd2, 0x2a, 0x70, 0xeb, 0xb5, 0xd8, 0x09, 0x70, 0xad, 0x56, 0x02, 0x4b,
-	0x7d, 0xb4, 0x07, 0xe5, 0x81, 0xe9, 0x51, 0xcd, 0xb3, 0x74, 0xc7, 0xeb, 0xdb, 0xd4, 0xab, 0x16,
-	0xb9, 0x87, 0xeb, 0x71, 0x1e, 0x76, 0x4d, 0x8f, 0x1e, 0xfa, 0xca, 0xad, 0x04, 0x2e, 0x0d, 0xc2,
-	0x02, 0xe6, 0xcf, 0x3e, 0x3a, 0x22, 0x6e, 0xe0, 0xb0, 0xba, 0x74, 0xb6, 0xbf, 0x7d, 0xa6, 0xed,
-	0xdb, 0x33, 0x7f, 0x76, 0x58, 0x80, 0x7e, 0x0e, 0x17, 0x06, 0xb6, 0x6e, 0x04, 0xee, 0xb4, 0x6e,
-	0x7f, 0x64, 0x3d, 0xa9, 0x96, 0xb8, 0xd3, 0x1b, 0xb1, 0x83, 0xb4, 0x75, 0xc3, 0x77, 0xd1, 0x60,
-	0x06, 0xad, 0x04, 0x5e, 0x19, 0x4c, 0x0a, 0xd1, 0x63, 0x58, 0xd5, 0x1d, 0x67, 0x70, 0x3a, 0xe9,
-	0xbd, 0xcc, 0xbd, 0xdf, 0x8c, 0xf3, 0xbe, 0xcd, 0x6c, 0x26, 0xdd, 0x23, 0x7d, 0x4a, 0x5a, 0xcf,
-	0x43, 0xf6, 0x58, 0x1f, 0x8c, 0x88, 0xfa, 0x1d, 0x28, 0x86, 0xd2, 0x14, 0x55, 0x21, 0x3f, 0x24,
-	0x9e, 0xa7, 0xf7, 0x08, 0xcf, 0x6a, 0x05, 0xfb, 0x5d, 0xb5, 0x0c, 0x4b, 0xe1, 0xd4, 0x54, 0x9f,
-	0x27, 0x03, 0x4b, 0x96, 0x75, 0xcc, 0xf2, 0x98, 0xb8, 0x9e, 0x69, 0x5b, 0xbe, 0xa5, 0xec, 0xa2,
-	0x6b, 0x50, 0xe2, 0xfb, 0x47, 0xf3, 0xbf, 0xb3, 0xd4, 0xcf, 0xe0, 0x25, 0x2e, 0x7c, 0x24, 0x95,
-	0xd6, 0xa1, 0xe8, 0x6c, 0x39, 0x81, 0x4a, 0x9a, 0xab, 0x80, 0xb3, 0xe5, 0xf8, 0x0a, 0xaf, 0xc3,
-	0x12, 0x9b, 0x69, 0xa0, 0x91, 0xe1, 0x3f, 0x52, 0x64, 0x32, 0xa9, 0xa2, 0xfe, 0x39, 0x05, 0x95,
-	0xc9, 0x74, 0x46, 0x77, 0x21, 0xc3, 0x90, 0x4d, 0x82, 0x54, 0x6d, 0x43, 0xc0, 0xde, 0x86, 0x0f,
-	0x7b, 0x1b, 0x6d, 0x1f, 0xf6, 0xea, 0x85, 0xaf, 0xbe, 0x59, 0x4f, 0x3c, 0xff, 0xfb, 0x7a, 0x12,
-	0x73, 0x0b, 0x74, 0x89, 0x65, 0x9f, 0x6e, 0x5a, 0x9a, 0x69, 0xf0, 0x21, 0x2b, 0x2c, 0xb5, 0x74,
-	0xd3, 0xda, 0x31, 0xd0, 0x2e, 0x54, 0xba, 0xb6, 0xe5, 0x11, 0xcb, 0x1b, 0x79, 0x9a, 0x80, 0x55,
-	0x09, 0x4d, 0x91, 0x04, 0x13, 0x60, 0xdd, 0xf0, 0x35, 0x0f, 0xb8, 0x22, 0x5e, 0xee, 0x46, 0x05,
-	0xe8, 0x3e, 0xc0, 0xb1, 0x3e, 0x30, 0x0d, 0x9d, 0xda, 0xae, 0x57, 0xcd, 0x5c, 0x4d, 0xcf, 0xcc,
-	0xb2, 0x47, 0xbe, 0xca, 0x43, 0xc7, 0xd0, 0x29, 0xa9, 0x67, 0xd8, 0x70, 0x71, 0xc8, 0x12, 0xbd,
-	0x01, 0xcb, 0xba, 0xe3, 0x68, 0x1e, 0xd5, 0x29, 0xd1, 0x3a, 0xa7, 0x94, 0x78, 0x1c, 0xb6, 0x96,
-	0x70, 0x49, 0x77, 0x9c, 0x43, 0x26, 0xad, 0x33, 0x21, 0xba, 0x0e, 0x65, 0x86, 0x70, 0xa6, 0x3e,
-	0xd0, 0xfa, 0xc4, 0xec, 0xf5, 0x29, 0x07, 0xa8, 0x34, 0x2e, 0x49, 0x69, 0x8b, 0x0b, 0x55, 0x23,
-	0x58, 0x71, 0x8e, 0x6e, 0x08, 0x41, 0xc6, 0xd0, 0xa9, 0xce, 0x23, 0xb9, 0x84, 0x79, 0x9b, 0xc9,
-	0x1c, 0x9d, 0xf6, 0x65, 0x7c, 0x78, 0x1b, 0x5d, 0x84, 0x9c, 0x74, 0x9b, 0xe6, 0x6e, 0x65, 0x0f,
-	0xad, 0x42, 0xd6, 0x71, 0xed, 0x63, 0xc2, 0x97, 0xae, 0x80, 0x45, 0x47, 0xfd, 0x55, 0x0a, 0x56,
-	0xa6, 0x70, 0x90, 0xf9, 0xed, 0xeb, 0x5e, 0xdf, 0xff, 0x2d, 0xd6, 0x46, 0x77, 0x98, 0x5f, 0xdd,
-	0x20, 0xae, 0x3c, 0x3b, 0xaa, 0xd3, 0xa1, 0x6e, 0xf1, 0xef, 0x32, 0x34, 0x52, 0x1b, 0xed, 0x43,
-	0x65, 0xa0, 0x7b, 0x54, 0x13, 0xb8, 0xa2, 0x85, 0xce, 0x91, 0x69, 0x34, 0xdd, 0xd5, 0x7d, 0x24,
-	0x62, 0x9b, 0x5a, 0x3a, 0x2a, 0x0f, 0x22, 0x52, 0x84, 0x61, 0xb5, 0x73, 0xfa, 0x85, 0x6e, 0x51,
-	0xd3, 0x22, 0xda, 0xd4, 0xca, 0x5d, 0x9a, 0x72, 0xda, 0x3c, 0x36, 0x0d, 0x62, 0x75, 0xfd, 0x25,
-	0xbb, 0x10, 0x18, 0x07, 0x4b, 0xea, 0xa9, 0x18, 0xca, 0x51, 0x24, 0x47, 0x65, 0x48, 0xd1, 0x13,
-	0x19, 0x80, 0x14, 0x3d, 0x41, 0xdf, 0x85, 0x0c, 0x9b, 0x24, 0x9f, 0x7c, 0x79, 0xc6, 0x11, 0x28,
-	0xed, 0xda, 0xa7, 0x0e, 0xc1, 0x5c, 0x53, 0x55, 0x83, 0x74, 0x08, 0xd0, 0x7d, 0xd2, 0xab, 0x7a,
-	0x03, 0x96, 0x27, 0xe0, 0x3b, 0xb4, 0x7e, 0xc9, 0xf0, 0xfa, 0xa9, 0xcb, 0x50, 0x8a, 0x60, 0xb5,
-	0x7a, 0x11, 0x56, 0x67, 0x41, 0xaf, 0xda, 0x0f, 0xe4, 0x11, 0x08, 0x45, 0xb7, 0xa1, 0x10, 0x60,
-	0xaf, 0x48, 0xc7, 0xe9, 0x58, 0xf9, 0xca, 0x38, 0x50, 0x65, 0x79, 0xc8, 0xb6, 0x35, 0xdf, 0x0f,
-	0x29, 0x3e, 0xf0, 0xbc, 0xee, 0x38, 0x2d, 0xdd, 0xeb, 0xab, 0x9f, 0x42, 0x35, 0x0e, 0x57, 0x27,
-	0xa6, 0x91, 0x09, 0xb6, 0xe1, 0x45, 0xc8, 0x1d, 0xd9, 0xee, 0x50, 0xa7, 0xdc, 0x59, 0x09, 0xcb,
-	0x1e, 0xdb, 0x9e, 0x02, 0x63, 0xd3, 0x5c, 0x2c, 0x3a, 0xaa, 0x06, 0x97, 0x62, 0xb1, 0x95, 0x99,
-	0x98, 0x96, 0x41, 0x44, 0x3c, 0x4b, 0x58, 0x74, 0xc6, 0x8e, 0xc4, 0x60, 0x45, 0x87, 0xfd, 0xac,
-	0xc7, 0xe7, 0xca, 0xfd, 0x2b, 0x58, 0xf6, 0xd4, 0xdf, 0x15, 0xa0, 0x80, 0x89, 0xe7, 0x30, 0x4c,
-	0x40, 0x75, 0x50, 0xc8, 0x49, 0x97, 0x38, 0xd4, 0x87, 0xd1, 0xd9, 0xac, 0x41, 0x68, 0x37, 0x7d,
-	0x4d, 0x76, 0x64, 0x07, 0x66, 0xe8, 0x96, 0x64, 0x65, 0xf1, 0x04, 0x4b, 0x9a, 0x87, 0x69, 0xd9,
-	0x1d, 0x9f, 0x96, 0xa5, 0x63, 0x4f, 0x69, 0x61, 0x35, 0xc1, 0xcb, 0x6e, 0x49, 0x5e, 0x96, 0x99,
-	0xf3, 0x63, 0x11, 0x62, 0xd6, 0x88, 0x10, 0xb3, 0xec, 0x9c, 0x69, 0xc6, 0x30, 0xb3, 0x3b, 0x3e,
-	0x33, 0xcb, 0xcd, 0x19, 0xf1, 0x04, 0x35, 0xbb, 0x1f, 0xa5, 0x66, 0x82, 0x56, 0x5d, 0x8b, 0xb5,
-	0x8e, 0xe5, 0x66, 0x3f, 0x08, 0x71, 0xb3, 0x42, 0x2c, 0x31, 0x12, 0x4e, 0x66, 0x90, 0xb3, 0x46,
-	0x84, 0x9c, 0x29, 0x73, 0x62, 0x10, 0xc3, 0xce, 0x3e, 0x08, 0xb3, 0x33, 0x88, 0x25, 0x78, 0x72,
-	0xbd, 0x67, 0xd1, 0xb3, 0xf7, 0x02, 0x7a, 0x56, 0x8c, 0xe5, 0x97, 0x72, 0x0e, 0x93, 0xfc, 0x6c,
-	0x7f, 0x8a, 0x9f, 0x09, 0x3e, 0xf5, 0x46, 0xac, 0x8b, 0x39, 0x04, 0x6d, 0x7f, 0x8a, 0xa0, 0x95,
-	0xe6, 0x38, 0x9c, 0xc3, 0xd0, 0x7e, 0x31, 0x9b, 0xa1, 0xc5, 0x73, 0x28, 0x39, 0xcc, 0xc5, 0x28,
-	0x9a, 0x16, 0x43, 0xd1, 0x96, 0xb9, 0xfb, 0xb7, 0x62, 0xdd, 0x9f, 0x9f, 0xa3, 0xdd, 0x60, 0x27,
-	0xe4, 0x44, 0xce, 0x33, 0x94, 0x21, 0xae, 0x6b, 0xbb, 0x92, 0x6d, 0x89, 0x8e, 0xfa, 0x26, 0x3b,
-	0xb3, 0xc7, 0xf9, 0x7d, 0x06, 0x9f, 0xe3, 0x68, 0x1e, 0xca, 0x69, 0xf5, 0x0f, 0xc9, 0xb1, 0x2d,
-	0x3f, 0xe6, 0xc2, 0xe7, 0xbd, 0x22, 0xcf, 0xfb, 0x10, 0xcb, 0x4b, 0x45, 0x59, 0xde, 0x3a, 0x14,
-	0x19, 0x4a, 0x4f, 0x10, 0x38, 0xdd, 0x09, 0x08, 0xdc, 0x4d, 0x58, 0xe1, 0xc7, 0xb0, 0xe0, 0x82,
-	0x12, 0x9a, 0x33, 0xfc, 0x84, 0x59, 0x66, 0x1f, 0xc4, 0xe6, 0x14, 0x18, 0xfd, 0x0e, 0x5c, 0x08,
-	0xe9, 0x06, 0xe8, 0x2f, 0xd8, 0x4c, 0x25, 0xd0, 0xde, 0x96, 0xc7, 0xc0, 0x9f, 0x92, 0xe3, 0x08,
-	0x8d, 0x99, 0xdf, 0x2c, 0x92, 0x96, 0xfc, 0x1f, 0x91, 0xb4, 0xd4, 0x7f, 0x4d, 0xd2, 0xc2, 0xa7,
-	0x59, 0x3a, 0x7a, 0x9a, 0xfd, 0x2b, 0x39, 0x5e, 0x93, 0x80, 0x72, 0x75, 0x6d, 0x83, 0xc8, 0xf3,
-	0x85, 0xb7, 0x51, 0x05, 0xd2, 0x03, 0xbb, 0x27, 0x4f, 0x11, 0xd6, 0x64, 0x5a, 0x01, 0x08, 0x2b,
-	0x12, 0x63, 0x83, 0xa3, 0x29, 0xcb, 0x23, 0x2c, 0x8f, 0xa6, 0x0a, 0xa4, 0x9f, 0x10, 0x01, 0x99,
-	0x4b, 0x98, 0x35, 0x99, 0x1e, 0xdf, 0x64, 0x1c, 0x08, 0x97, 0xb0, 0xe8, 0xa0, 0xbb, 0xa0, 0xf0,
-	0x32, 0x84, 0x66, 0x3b, 0x9e, 0x44, 0xb7, 0xd7, 0xc2, 0x73, 0x15, 0xd5, 0x86, 0x8d, 0x03, 0xa6,
-	0xb3, 0xef, 0x78, 0xb8, 0xe0, 0xc8, 0x56, 0xe8, 0xd4, 0x55, 0x22, 0xe4, 0xef, 0x32, 0x28, 0x6c,
-	0xf4, 0x9e, 0xa3, 0x77, 0x09, 0x87, 0x2a, 0x05, 0x8f, 0x05, 0xea, 0x63, 0x40, 0xd3, 0x80, 0x8b,
-	0x5a, 0x90, 0x23, 0xc7, 0xc4, 0xa2, 0x6c, 0xd9, 0x58, 0xb8, 0x2f, 0xce, 0x60, 0x56, 0xc4, 0xa2,
-	0xf5, 0x2a, 0x0b, 0xf2, 0x3f, 0xbf, 0x59, 0xaf, 0x08, 0xed, 0xb7, 0xed, 0xa1, 0x49, 0xc9, 0xd0,
-	0xa1, 0xa7, 0x58, 0xda, 0xab, 0x7f, 0x4b, 0x31, 0x9a, 0x13, 0x01, 0xe3, 0x99, 0xb1, 0xf5, 0xb7,
-	0x7c, 0x2a, 0x44, 0x71, 0x17, 0x8b, 0xf7, 0x1a, 0x40, 0x4f, 0xf7, 0xb4, 0xa7, 0xba, 0x45, 0x89,
-	0x21, 0x83, 0x1e, 0x92, 0xa0, 0x1a, 0x14, 0x58, 0x6f, 0xe4, 0x11, 0x43, 0xb2, 0xed, 0xa0, 0x1f,
-	0x9a, 0x67, 0xfe, 0xdb, 0xcd, 0x33, 0x1a, 0xe5, 0xc2, 0x44, 0x94, 0x43, 0x14, 0x44, 0x09, 0x53,
-	0x10, 0x36, 0x36, 0xc7, 0x35, 0x6d, 0xd7, 0xa4, 0xa7, 0x7c, 0x69, 0xd2, 0x38, 0xe8, 0xb3, 0xcb,
-	0xdb, 0x90, 0x0c, 0x1d, 0xdb, 0x1e, 0x68, 0x02, 0x6e, 0x8a, 0xdc, 0x74, 0x49, 0x0a, 0x9b, 0x1c,
-	0x75, 0x7e, 0x9d, 0x1a, 0xe7, 0xdf, 0x98, 0x6a, 0xfe, 0xdf, 0x05, 0x58, 0xfd, 0x0d, 0xbf, 0x80,
-	0x46, 0x8f, 0x5b, 0x74, 0x08, 0x2b, 0x41, 0xfa, 0x6b, 0x23, 0x0e, 0x0b, 0xfe, 0x86, 0x5e, 0x14,
-	0x3f, 0x2a, 0xc7, 0x51, 0xb1, 0x87, 0x3e, 0x86, 0x57, 0x27, 0xb0, 0x2d, 0x70, 0x9d, 0x5a, 0x14,
-	0xe2, 0x5e, 0x89, 0x42, 0x9c, 0xef, 0x7a, 0x1c, 0xac, 0xf4, 0xb7, 0xcc, 0xba, 0x1d, 0x76, 0xa7,
-	0x09, 0xb3, 0x87, 0x99, 0xcb, 0x7f, 0x0d, 0x4a, 0x2e, 0xa1, 0xec, 0x9e, 0x1d, 0xb9, 0x35, 0x2e,
-	0x09, 0xa1, 0xbc, 0x8b, 0x1e, 0xc0, 0x2b, 0x33, 0x59, 0x04, 0xfa, 0x1e, 0x28, 0x63, 0x02, 0x92,
-	0x8c, 0xb9, 0x80, 0x05, 0x97, 0x8a, 0xb1, 0xae, 0xfa, 0xc7, 0xe4, 0xd8, 0x65, 0xf4, 0x9a, 0xd2,
-	0x84, 0x9c, 0x4b, 0xbc, 0xd1, 0x40, 0x5c, 0x1c, 0xca, 0x5b, 0xef, 0x2c, 0xc6, 0x3f, 0x98, 0x74,
-	0x34, 0xa0, 0x58, 0x1a, 0xab, 0x8f, 0x21, 0x27, 0x24, 0xa8, 0x08, 0xf9, 0x87, 0x7b, 0x0f, 0xf6,
-	0xf6, 0x3f, 0xda, 0xab, 0x24, 0x10, 0x40, 0x6e, 0xbb, 0xd1, 0x68, 0x1e, 0xb4, 0x2b, 0x49, 0xa4,
-	0x40, 0x76, 0xbb, 0xbe, 0x8f, 0xdb, 0x95, 0x14, 0x13, 0xe3, 0xe6, 0x87, 0xcd, 0x46, 0xbb, 0x92,
-	0x46, 0x2b, 0x50, 0x12, 0x6d, 0xed, 0xfe, 0x3e, 0xfe, 0xc9, 0x76, 0xbb, 0x92, 0x09, 0x89, 0x0e,
-	0x9b, 0x7b, 0xf7, 0x9a, 0xb8, 0x92, 0x55, 0xdf, 0x65, 0x37, 0x93, 0x18, 0xc6, 0x32, 0xbe, 0x83,
-	0x24, 0x43, 0x77, 0x10, 0xf5, 0xb7, 0x29, 0xa8, 0xc5, 0xd3, 0x10, 0xf4, 0xe1, 0xc4, 0xc4, 0xb7,
-	0xce, 0xc1, 0x61, 0x26, 0x66, 0x8f, 0xae, 0x43, 0xd9, 0x25, 0x47, 0x84, 0x76, 0xfb, 0x82, 0x16,
-	0x89, 0x23, 0xb3, 0x84, 0x4b, 0x52, 0xca, 0x8d, 0x3c, 0xa1, 0xf6, 0x19, 0xe9, 0x52, 0x4d, 0x60,
-	0x91, 0xd8, 0x74, 0x0a, 0x53, 0x63, 0xd2, 0x43, 0x21, 0x54, 0x3f, 0x3d, 0x57, 0x2c, 0x15, 0xc8,
-	0xe2, 0x66, 0x1b, 0x7f, 0x5c, 0x49, 0x23, 0x04, 0x65, 0xde, 0xd4, 0x0e, 0xf7, 0xb6, 0x0f, 0x0e,
-	0x5b, 0xfb, 0x2c, 0x96, 0x17, 0x60, 0xd9, 0x8f, 0xa5, 0x2f, 0xcc, 0xaa, 0x9f, 0x40, 0x39, 0x7a,
-	0xf7, 0x67, 0x21, 0x74, 0xed, 0x91, 0x65, 0xf0, 0x60, 0x64, 0xb1, 0xe8, 0xa0, 0xdb, 0x90, 0x3d,
-	0xb6, 0x45, 0x9a, 0xcd, 0xde, 0x6b, 0x8f, 0x6c, 0x4a, 0x42, 0xb5, 0x03, 0xa1, 0xad, 0x7e, 0x01,
-	0x59, 0x9e, 0x35, 0x2c, 0x03, 0xf8, 0x2d, 0x5e, 0x92, 0x2a, 0xd6, 0x46, 0x9f, 0x00, 0xe8, 0x94,
-	0xba, 0x66, 0x67, 0x34, 0x76, 0xbc, 0x3e, 0x3b, 0xeb, 0xb6, 0x7d, 0xbd, 0xfa, 0x65, 0x99, 0x7e,
-	0xab, 0x63, 0xd3, 0x50, 0x0a, 0x86, 0x1c, 0xaa, 0x7b, 0x50, 0x8e, 0xda, 0xfa, 0x34, 0x40, 0x8c,
-	0x21, 0x4a, 0x03, 0x04, 0xab, 0x93, 0x34, 0x20, 0x20, 0x11, 0x69, 0x51, 0xb1, 0xe1, 0x1d, 0xf5,
-	0x59, 0x12, 0x0a, 0xed, 0x13, 0xb9, 0x1e, 0x31, 0xc5, 0x82, 0xb1, 0x69, 0x2a, 0x7c, 0x35, 0x16,
-	0xd5, 0x87, 0x74, 0x50, 0xd3, 0xf8, 0x20, 0xd8, 0x71, 0x99, 0x45, 0x6f, 0x40, 0x7e, 0x71, 0x47,
-	0x66, 0xd9, 0xfb, 0xa0, 0x04, 0x98, 0xc9, 0xd8, 0xa9, 0x6e, 0x18, 0x2e, 0xf1, 0x3c, 0xb9, 0xef,
-	0xfd, 0x2e, 0xaf, 0x3d, 0xd9, 0x4f, 0xe5, 0xe5, 0x3b, 0x8d, 0x45, 0x47, 0x35, 0x60, 0x79, 0x02,
-	0x70, 0xd1, 0xfb, 0x90, 0x77, 0x46, 0x1d, 0xcd, 0x0f, 0xcf, 0xc4, 0x5b, 0x83, 0xcf, 0x7b, 0x46,
-	0x9d, 0x81, 0xd9, 0x7d, 0x40, 0x4e, 0xfd, 0xc1, 0x38, 0xa3, 0xce, 0x03, 0x11, 0x45, 0xf1, 0x2b,
-	0xa9, 0xf0, 0xaf, 0x1c, 0x43, 0xc1, 0xdf, 0x14, 0xe8, 0x87, 0xa0, 0x04, 0x58, 0x1e, 0x94, 0x24,
-	0x63, 0x0f, 0x01, 0xe9, 0x7e, 0x6c, 0xc2, 0x48, 0xb4, 0x67, 0xf6, 0x2c, 0x62, 0x68, 0x63, 0x7e,
-	0xcc, 0x7f, 0xad, 0x80, 0x97, 0xc5, 0x87, 0x5d, 0x9f, 0x1c, 0xab, 0xff, 0x4e, 0x42, 0xc1, 0x2f,
-	0x3d, 0xa1, 0x77, 0x43, 0xfb, 0xae, 0x3c, 0xe3, 0xa2, 0xee, 0x2b, 0x8e, 0xcb, 0x47, 0xd1, 0xb1,
-	0xa6, 0xce, 0x3f, 0xd6, 0xb8, 0x3a, 0xa0, 0x5f, 0x91, 0xcd, 0x9c, 0xbb, 0x22, 0xfb, 0x36, 0x20,
-	0x6a, 0x53, 0x7d, 0xa0, 0x1d, 0xdb, 0xd4, 0xb4, 0x7a, 0x9a, 0x08, 0xb6, 0xe0, 0x02, 0x15, 0xfe,
-	0xe5, 0x11, 0xff, 0x70, 0xc0, 0xe3, 0xfe, 0xcb, 0x24, 0x14, 0x02, 0x50, 0x3f, 0x6f, 0x35, 0xe8,
-	0x22, 0xe4, 0x24, 0x6e, 0x89, 0x72, 0x90, 0xec, 0x05, 0x85, 0xc9, 0x4c, 0xa8, 0x30, 0x59, 0x83,
-	0xc2, 0x90, 0x50, 0x9d, 0x9f, 0x6c, 0xe2, 0x8a, 0x12, 0xf4, 0x6f, 0xbe, 0x07, 0xc5, 0x50, 0x61,
-	0x8e, 0x65, 0xde, 0x5e, 0xf3, 0xa3, 0x4a, 0xa2, 0x96, 0x7f, 0xf6, 0xe5, 0xd5, 0xf4, 0x1e, 0x79,
-	0xca, 0xf6, 0x2c, 0x6e, 0x36, 0x5a, 0xcd, 0xc6, 0x83, 0x4a, 0xb2, 0x56, 0x7c, 0xf6, 0xe5, 0xd5,
-	0x3c, 0x26, 0xbc, 0x48, 0x70, 0xb3, 0x05, 0x4b, 0xe1, 0x55, 0x89, 0x42, 0x1f, 0x82, 0xf2, 0xbd,
-	0x87, 0x07, 0xbb, 0x3b, 0x8d, 0xed, 0x76, 0x53, 0x7b, 0xb4, 0xdf, 0x6e, 0x56, 0x92, 0xe8, 0x55,
-	0xb8, 0xb0, 0xbb, 0xf3, 0xe3, 0x56, 0x5b, 0x6b, 0xec, 0xee, 0x34, 0xf7, 0xda, 0xda, 0x76, 0xbb,
-	0xbd, 0xdd, 0x78, 0x50, 0x49, 0x6d, 0xfd, 0x5e, 0x81, 0xe5, 0xed, 0x7a, 0x63, 0x87, 0xc1, 0xb6,
-	0xd9, 0xd5, 0xf9, 0xfd, 0xb1, 0x01, 0x19, 0x7e, 0x43, 0x3c, 0xf3, 0xd9, 0xae, 0x76, 0x76, 0xf9,
-	0x08, 0xdd, 0x87, 0x2c, 0xbf, 0x3c, 0xa2, 0xb3, 0xdf, 0xf1, 0x6a, 0x73, 0xea, 0x49, 0x6c, 0x30,
-	0x3c, 0x3d, 0xce, 0x7c, 0xd8, 0xab, 0x9d, 0x5d, 0x5e, 0x42, 0x18, 0x94, 0x31, 0xf9, 0x9c, 0xff,
-	0xd0, 0x55, 0x5b, 0x00, 0x6c, 0xd0, 0x2e, 0xe4, 0xfd, 0xfb, 0xc2, 0xbc, 0xa7, 0xb7, 0xda, 0xdc,
-	0xfa, 0x0f, 0x0b, 0x97, 0xb8, 0xd7, 0x9d, 0xfd, 0x8e, 0x58, 0x9b, 0x53, 0xcc, 0x42, 0x3b, 0x90,
-	0x93, 0x84, 0x6a, 0xce, 0x73, 0x5a, 0x6d, 0x5e, 0x3d, 0x87, 0x05, 0x6d, 0x7c, 0x63, 0x9e, 0xff,
-	0x3a, 0x5a, 0x5b, 0xa0, 0x4e, 0x87, 0x1e, 0x02, 0x84, 0x6e, 0x71, 0x0b, 0x3c, 0x7b, 0xd6, 0x16,
-	0xa9, 0xbf, 0xa1, 0x7d, 0x28, 0x04, 0xa4, 0x7a, 0xee, 0x23, 0x64, 0x6d, 0x7e, 0x21, 0x0c, 0x3d,
-	0x86, 0x52, 0x94, 0x4c, 0x2e, 0xf6, 0xb4, 0x58, 0x5b, 0xb0, 0xc2, 0xc5, 0xfc, 0x47, 0x99, 0xe5,
-	0x62, 0x4f, 0x8d, 0xb5, 0x05, 0x0b, 0x5e, 0xe8, 0x33, 0x58, 0x99, 0x66, 0x7e, 0x8b, 0xbf, 0x3c,
-	0xd6, 0xce, 0x51, 0x02, 0x43, 0x43, 0x40, 0x33, 0x18, 0xe3, 0x39, 0x1e, 0x22, 0x6b, 0xe7, 0xa9,
-	0x88, 0xd5, 0x9b, 0x5f, 0xbd, 0x58, 0x4b, 0x7e, 0xfd, 0x62, 0x2d, 0xf9, 0x8f, 0x17, 0x6b, 0xc9,
-	0xe7, 0x2f, 0xd7, 0x12, 0x5f, 0xbf, 0x5c, 0x4b, 0xfc, 0xf5, 0xe5, 0x5a, 0xe2, 0x67, 0x6f, 0xf5,
-	0x4c, 0xda, 0x1f, 0x75, 0x36, 0xba, 0xf6, 0x70, 0x33, 0xfc, 0x0f, 0x87, 0x59, 0xff, 0xba, 0xe8,
-	0xe4, 0xf8, 0xa1, 0x72, 0xeb, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x92, 0xa5, 0x39, 0xcc, 0x95,
-	0x21, 0x00, 0x00,
+	// 2766 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x5a, 0xcf, 0x73, 0x23, 0xc5,
+	0xf5, 0xd7, 0xe8, 0x87, 0xa5, 0x79, 0xfa, 0x61, 0xb9, 0xd7, 0xbb, 0x68, 0xc5, 0xae, 0xbd, 0x0c,
+	0x05, 0x5f, 0x58, 0xc0, 0x06, 0x53, 0xf0, 0x85, 0x22, 0x3f, 0xb0, 0x84, 0x36, 0x32, 0xeb, 0xd8,
+	0x4e, 0x5b, 0xbb, 0x14, 0x49, 0xd8, 0xc9, 0x48, 0x6a, 0x5b, 0xc3, 0x4a, 0x33, 0xc3, 0x4c, 0xcb,
+	0xd8, 0x54, 0x4e, 0xa9, 0xe4, 0x42, 0x72, 0xe0, 0x98, 0x0b, 0xff, 0x47, 0x4e, 0x39, 0xe5, 0xc0,
+	0x21, 0xa9, 0xe2, 0x98, 0x43, 0x8a, 0xa4, 0xe0, 0x96, 0x7f, 0x20, 0x87, 0x54, 0xaa, 0x52, 0xfd,
+	0x6b, 0x34, 0x23, 0x69, 0x2c, 0x39, 0xe4, 0x96, 0x5b, 0xbf, 0x37, 0xef, 0xbd, 0xee, 0x7e, 0xdd,
+	0xef, 0xf5, 0xa7, 0x5f, 0x0f, 0x3c, 0x49, 0x89, 0xd3, 0x27, 0xfe, 0xc8, 0x76, 0xe8, 0xb6, 0xd5,
+	0xed, 0xd9, 0xdb, 0xf4, 0xc2, 0x23, 0xc1, 0x96, 0xe7, 0xbb, 0xd4, 0x45, 0xab, 0x93, 0x8f, 0x5b,
+	0xec, 0x63, 0xfd, 0x76, 0x44, 0xba, 0xe7, 0x5f, 0x78, 0xd4, 0xdd, 0xf6, 0x7c, 0xd7, 0x3d, 0x11,
+	0xf2, 0xf5, 0x5b, 0x91, 0xcf, 0xdc, 0x4e, 0xd4, 0x5a, 0xfd, 0xd6, 0xac, 0xf2, 0x63, 0x72, 0xa1,
+	0xbe, 0xde, 0x9e, 0xd1, 0xf5, 0x2c, 0xdf, 0x1a, 0xa9, 0xcf, 0x9b, 0xa7, 0xae, 0x7b, 0x3a, 0x24,
+	0xdb, 0x9c, 0xea, 0x8e, 0x4f, 0xb6, 0xa9, 0x3d, 0x22, 0x01, 0xb5, 0x46, 0x9e, 0x14, 0x58, 0x3f,
+	0x75, 0x4f, 0x5d, 0xde, 0xdc, 0x66, 0x2d, 0xc1, 0x35, 0xfe, 0x94, 0x87, 0x3c, 0x26, 0x1f, 0x8d,
+	0x49, 0x40, 0xd1, 0x0e, 0x64, 0x49, 0x6f, 0xe0, 0xd6, 0xb4, 0x3b, 0xda, 0x73, 0xc5, 0x9d, 0x5b,
+	0x5b, 0x53, 0x93, 0xdb, 0x92, 0x72, 0xad, 0xde, 0xc0, 0x6d, 0xa7, 0x30, 0x97, 0x45, 0xaf, 0x41,
+	0xee, 0x64, 0x38, 0x0e, 0x06, 0xb5, 0x34, 0x57, 0xba, 0x9d, 0xa4, 0x74, 0x8f, 0x09, 0xb5, 0x53,
+	0x58, 0x48, 0xb3, 0xae, 0x6c, 0xe7, 0xc4, 0xad, 0x65, 0x2e, 0xef, 0x6a, 0xcf, 0x39, 0xe1, 0x5d,
+	0x31, 0x59, 0xd4, 0x00, 0xb0, 0x1d, 0x9b, 0x9a, 0xbd, 0x81, 0x65, 0x3b, 0xb5, 0x2c, 0xd7, 0x7c,
+	0x2a, 0x59, 0xd3, 0xa6, 0x4d, 0x26, 0xd8, 0x4e, 0x61, 0xdd, 0x56, 0x04, 0x1b, 0xee, 0x47, 0x63,
+	0xe2, 0x5f, 0xd4, 0x72, 0x97, 0x0f, 0xf7, 0x47, 0x4c, 0x88, 0x0d, 0x97, 0x4b, 0xa3, 0x16, 0x14,
+	0xbb, 0xe4, 0xd4, 0x76, 0xcc, 0xee, 0xd0, 0xed, 0x3d, 0xae, 0xad, 0x70, 0x65, 0x23, 0x49, 0xb9,
+	0xc1, 0x44, 0x1b, 0x4c, 0xb2, 0x9d, 0xc2, 0xd0, 0x0d, 0x29, 0xf4, 0x1d, 0x28, 0xf4, 0x06, 0xa4,
+	0xf7, 0xd8, 0xa4, 0xe7, 0xb5, 0x3c, 0xb7, 0xb1, 0x99, 0x64, 0xa3, 0xc9, 0xe4, 0x3a, 0xe7, 0xed,
+	0x14, 0xce, 0xf7, 0x44, 0x93, 0xcd, 0xbf, 0x4f, 0x86, 0xf6, 0x19, 0xf1, 0x99, 0x7e, 0xe1, 0xf2,
+	0xf9, 0xbf, 0x23, 0x24, 0xb9, 0x05, 0xbd, 0xaf, 0x08, 0xf4, 0x7d, 0xd0, 0x89, 0xd3, 0x97, 0xd3,
+	0xd0, 0xb9, 0x89, 0x3b, 0x89, 0xeb, 0xec, 0xf4, 0xd5, 0x24, 0x0a, 0x44, 0xb6, 0xd1, 0x1b, 0xb0,
+	0xd2, 0x73, 0x47, 0x23, 0x9b, 0xd6, 0x80, 0x6b, 0x6f, 0x24, 0x4e, 0x80, 0x4b, 0xb5, 0x53, 0x58,
+	0xca, 0xa3, 0x03, 0xa8, 0x0c, 0xed, 0x80, 0x9a, 0x81, 0x63, 0x79, 0xc1, 0xc0, 0xa5, 0x41, 0xad,
+	0xc8, 0x2d, 0x3c, 0x93, 0x64, 0x61, 0xdf, 0x0e, 0xe8, 0xb1, 0x12, 0x6e, 0xa7, 0x70, 0x79, 0x18,
+	0x65, 0x30, 0x7b, 0xee, 0xc9, 0x09, 0xf1, 0x43, 0x83, 0xb5, 0xd2, 0xe5, 0xf6, 0x0e, 0x99, 0xb4,
+	0xd2, 0x67, 0xf6, 0xdc, 0x28, 0x03, 0xfd, 0x04, 0xae, 0x0d, 0x5d, 0xab, 0x1f, 0x9a, 0x33, 0x7b,
+	0x83, 0xb1, 0xf3, 0xb8, 0x56, 0xe6, 0x46, 0x9f, 0x4f, 0x1c, 0xa4, 0x6b, 0xf5, 0x95, 0x89, 0x26,
+	0x53, 0x68, 0xa7, 0xf0, 0xda, 0x70, 0x9a, 0x89, 0x1e, 0xc1, 0xba, 0xe5, 0x79, 0xc3, 0x8b, 0x69,
+	0xeb, 0x15, 0x6e, 0xfd, 0x6e, 0x92, 0xf5, 0x5d, 0xa6, 0x33, 0x6d, 0x1e, 0x59, 0x33, 0xdc, 0x46,
+	0x1e, 0x72, 0x67, 0xd6, 0x70, 0x4c, 0x8c, 0xff, 0x83, 0x62, 0x24, 0x4c, 0x51, 0x0d, 0xf2, 0x23,
+	0x12, 0x04, 0xd6, 0x29, 0xe1, 0x51, 0xad, 0x63, 0x45, 0x1a, 0x15, 0x28, 0x45, 0x43, 0xd3, 0xf8,
+	0x4c, 0x83, 0x62, 0x24, 0xea, 0x98, 0xe6, 0x19, 0xf1, 0x03, 0xdb, 0x75, 0x94, 0xa6, 0x24, 0xd1,
+	0xd3, 0x50, 0xe6, 0xfb, 0xc7, 0x54, 0xdf, 0x59, 0xe8, 0x67, 0x71, 0x89, 0x33, 0x1f, 0x4a, 0xa1,
+	0x4d, 0x28, 0x7a, 0x3b, 0x5e, 0x28, 0x92, 0xe1, 0x22, 0xe0, 0xed, 0x78, 0x4a, 0xe0, 0x29, 0x28,
+	0xb1, 0x99, 0x86, 0x12, 0x59, 0xde, 0x49, 0x91, 0xf1, 0xa4, 0x88, 0xf1, 0xc7, 0x34, 0x54, 0xa7,
+	0xc3, 0x19, 0xbd, 0x01, 0x59, 0x96, 0xd9, 0x64, 0x92, 0xaa, 0x6f, 0x89, 0xb4, 0xb7, 0xa5, 0xd2,
+	0xde, 0x56, 0x47, 0xa5, 0xbd, 0x46, 0xe1, 0x8b, 0xaf, 0x36, 0x53, 0x9f, 0xfd, 0x75, 0x53, 0xc3,
+	0x5c, 0x03, 0xdd, 0x64, 0xd1, 0x67, 0xd9, 0x8e, 0x69, 0xf7, 0xf9, 0x90, 0x75, 0x16, 0x5a, 0x96,
+	0xed, 0xec, 0xf5, 0xd1, 0x3e, 0x54, 0x7b, 0xae, 0x13, 0x10, 0x27, 0x18, 0x07, 0xa6, 0x48, 0xab,
+	0xb5, 0xcc, 0x6c, 0x80, 0x89, 0x64, 0xdd, 0x54, 0x92, 0x47, 0x5c, 0x10, 0xaf, 0xf6, 0xe2, 0x0c,
+	0x74, 0x0f, 0xe0, 0xcc, 0x1a, 0xda, 0x7d, 0x8b, 0xba, 0x7e, 0x50, 0xcb, 0xde, 0xc9, 0xcc, 0x8d,
+	0xb2, 0x87, 0x4a, 0xe4, 0x81, 0xd7, 0xb7, 0x28, 0x69, 0x64, 0xd9, 0x70, 0x71, 0x44, 0x13, 0x3d,
+	0x0b, 0xab, 0x96, 0xe7, 0x99, 0x01, 0xb5, 0x28, 0x31, 0xbb, 0x17, 0x94, 0x04, 0x3c, 0x6d, 0x95,
+	0x70, 0xd9, 0xf2, 0xbc, 0x63, 0xc6, 0x6d, 0x30, 0x26, 0x7a, 0x06, 0x2a, 0x2c, 0xc3, 0xd9, 0xd6,
+	0xd0, 0x1c, 0x10, 0xfb, 0x74, 0x40, 0x79, 0x82, 0xca, 0xe0, 0xb2, 0xe4, 0xb6, 0x39, 0xd3, 0xf8,
+	0x39, 0x94, 0xa2, 0xd9, 0x0d, 0x21, 0xc8, 0xf6, 0x2d, 0x6a, 0x71, 0x4f, 0x96, 0x30, 0x6f, 0x33,
+	0x9e, 0x67, 0xd1, 0x81, 0xf4, 0x0f, 0x6f, 0xa3, 0x1b, 0xb0, 0x22, 0xcd, 0x66, 0xb8, 0x59, 0x49,
+	0xa1, 0x75, 0xc8, 0x79, 0xbe, 0x7b, 0x46, 0xf8, 0xd2, 0x15, 0xb0, 0x20, 0x98, 0x74, 0x40, 0x7d,
+	0x62, 0x8d, 0xf8, 0x58, 0x0b, 0x58, 0x52, 0xc6, 0x2f, 0xd3, 0xb0, 0x36, 0x93, 0x1f, 0x59, 0x7f,
+	0x03, 0x2b, 0x18, 0xa8, 0x31, 0xb0, 0x36, 0x7a, 0x9d, 0xf5, 0x67, 0xf5, 0x89, 0x2f, 0xcf, 0x94,
+	0xda, 0xec, 0x12, 0xb4, 0xf9, 0x77, 0xe9, 0x32, 0x29, 0x8d, 0x0e, 0xa1, 0x3a, 0xb4, 0x02, 0x6a,
+	0x8a, 0x7c, 0x63, 0x46, 0xce, 0x97, 0xd9, 0x2c, 0xbb, 0x6f, 0xa9, 0x0c, 0xc5, 0x36, 0xbb, 0x34,
+	0x54, 0x19, 0xc6, 0xb8, 0x08, 0xc3, 0x7a, 0xf7, 0xe2, 0x13, 0xcb, 0xa1, 0xb6, 0x43, 0xcc, 0x99,
+	0x15, 0xbd, 0x39, 0x63, 0xb4, 0x75, 0x66, 0xf7, 0x89, 0xd3, 0x53, 0x4b, 0x79, 0x2d, 0x54, 0x0e,
+	0x97, 0x3a, 0x30, 0x30, 0x54, 0xe2, 0x19, 0x1e, 0x55, 0x20, 0x4d, 0xcf, 0xa5, 0x03, 0xd2, 0xf4,
+	0x1c, 0xbd, 0x0c, 0x59, 0x36, 0x49, 0x3e, 0xf9, 0xca, 0x9c, 0xa3, 0x51, 0xea, 0x75, 0x2e, 0x3c,
+	0x82, 0xb9, 0xa4, 0x61, 0x40, 0x75, 0x3a, 0xeb, 0x4f, 0x5b, 0x35, 0x9e, 0x87, 0xd5, 0xa9, 0xb4,
+	0x1e, 0x59, 0x57, 0x2d, 0xba, 0xae, 0xc6, 0x2a, 0x94, 0x63, 0x39, 0xdc, 0xb8, 0x01, 0xeb, 0xf3,
+	0x52, 0xb2, 0x31, 0x80, 0xf5, 0x79, 0xa9, 0x15, 0xbd, 0x06, 0x85, 0x30, 0x27, 0x8b, 0x30, 0x9d,
+	0xf5, 0x95, 0x12, 0xc6, 0xa1, 0x28, 0x8b, 0x4f, 0xb6, 0xdd, 0xf9, 0x7e, 0x48, 0xf3, 0x81, 0xe7,
+	0x2d, 0xcf, 0x6b, 0x5b, 0xc1, 0xc0, 0x38, 0x87, 0x5a, 0x52, 0xbe, 0x9d, 0x9a, 0x46, 0x36, 0xdc,
+	0x9e, 0x37, 0x60, 0xe5, 0xc4, 0xf5, 0x47, 0x16, 0xe5, 0xc6, 0xca, 0x58, 0x52, 0x6c, 0xdb, 0x8a,
+	0xdc, 0x9b, 0xe1, 0x6c, 0x41, 0x44, 0xb6, 0x6d, 0x36, 0xb6, 0x6d, 0x4d, 0xb8, 0x99, 0x98, 0x8b,
+	0x99, 0x29, 0xdb, 0xe9, 0x13, 0xe1, 0xe7, 0x32, 0x16, 0xc4, 0xa4, 0x03, 0x31, 0x89, 0x48, 0x07,
+	0xdc, 0x07, 0xbc, 0x5f, 0x1d, 0x4b, 0xca, 0xf8, 0x52, 0x87, 0x02, 0x26, 0x81, 0xc7, 0x72, 0x08,
+	0x6a, 0x80, 0x4e, 0xce, 0x7b, 0xc4, 0xa3, 0x2a, 0xed, 0xce, 0x47, 0x19, 0x42, 0xba, 0xa5, 0x24,
+	0xd9, 0x11, 0x1f, 0xaa, 0xa1, 0x57, 0x25, 0x8a, 0x4b, 0x06, 0x64, 0x52, 0x3d, 0x0a, 0xe3, 0x5e,
+	0x57, 0x30, 0x2e, 0x93, 0x78, 0xaa, 0x0b, 0xad, 0x29, 0x1c, 0xf7, 0xaa, 0xc4, 0x71, 0xd9, 0x05,
+	0x9d, 0xc5, 0x80, 0x5c, 0x33, 0x06, 0xe4, 0x72, 0x0b, 0xa6, 0x99, 0x80, 0xe4, 0x5e, 0x57, 0x48,
+	0x6e, 0x65, 0xc1, 0x88, 0xa7, 0xa0, 0xdc, 0xbd, 0x38, 0x94, 0x13, 0x30, 0xec, 0xe9, 0x44, 0xed,
+	0x44, 0x2c, 0xf7, 0xdd, 0x08, 0x96, 0x2b, 0x24, 0x02, 0x29, 0x61, 0x64, 0x0e, 0x98, 0x6b, 0xc6,
+	0xc0, 0x9c, 0xbe, 0xc0, 0x07, 0x09, 0x68, 0xee, 0xed, 0x28, 0x9a, 0x83, 0x44, 0x40, 0x28, 0xd7,
+	0x7b, 0x1e, 0x9c, 0x7b, 0x33, 0x84, 0x73, 0xc5, 0x44, 0x3c, 0x2a, 0xe7, 0x30, 0x8d, 0xe7, 0x0e,
+	0x67, 0xf0, 0x9c, 0xc0, 0x5f, 0xcf, 0x26, 0x9a, 0x58, 0x00, 0xe8, 0x0e, 0x67, 0x00, 0x5d, 0x79,
+	0x81, 0xc1, 0x05, 0x88, 0xee, 0xa7, 0xf3, 0x11, 0x5d, 0x32, 0xe6, 0x92, 0xc3, 0x5c, 0x0e, 0xd2,
+	0x99, 0x09, 0x90, 0x6e, 0x95, 0x9b, 0x7f, 0x21, 0xd1, 0xfc, 0xb2, 0x98, 0x8e, 0x6d, 0x11, 0xbe,
+	0x65, 0x19, 0x20, 0xa1, 0xb5, 0xea, 0x82, 0x2d, 0xc2, 0xb7, 0xf9, 0x91, 0xe5, 0x33, 0x3f, 0xe8,
+	0x1f, 0x29, 0x02, 0xd9, 0x50, 0x9b, 0xe3, 0x03, 0x61, 0x72, 0x8d, 0x9b, 0xdc, 0x5a, 0xde, 0x11,
+	0xd2, 0xfc, 0xf5, 0xe1, 0xbc, 0x0f, 0x13, 0x0c, 0xfa, 0x3c, 0xac, 0x29, 0x13, 0x61, 0x8e, 0x62,
+	0x59, 0x91, 0xf8, 0xbe, 0xeb, 0x4b, 0x34, 0x29, 0x08, 0xe3, 0x39, 0x28, 0x85, 0xa2, 0x97, 0xe3,
+	0x55, 0x7e, 0x2a, 0x45, 0x72, 0x90, 0xf1, 0x3b, 0x0d, 0x4a, 0xd1, 0xf4, 0x12, 0xc3, 0x33, 0xba,
+	0xc4, 0x33, 0x11, 0x14, 0x9b, 0x8e, 0xa3, 0xd8, 0x4d, 0x28, 0xb2, 0xd3, 0x66, 0x0a, 0xa0, 0x5a,
+	0x5e, 0x08, 0x50, 0xef, 0xc2, 0x1a, 0x87, 0x13, 0x02, 0xeb, 0xca, 0x23, 0x26, 0xcb, 0x4f, 0xca,
+	0x55, 0xf6, 0x41, 0x04, 0x13, 0x67, 0xa3, 0x97, 0xe0, 0x5a, 0x44, 0x36, 0x3c, 0xc5, 0x04, 0x5a,
+	0xab, 0x86, 0xd2, 0xbb, 0xf2, 0x38, 0xfb, 0x83, 0x06, 0x6b, 0x33, 0xe9, 0x6d, 0x2e, 0x08, 0xd5,
+	0xfe, 0x4b, 0x20, 0x34, 0xfd, 0x1f, 0x83, 0xd0, 0xe8, 0xa9, 0x9c, 0x89, 0x9f, 0xca, 0xff, 0xd0,
+	0xa0, 0x1c, 0xdb, 0x7e, 0x6c, 0x09, 0x7a, 0x6e, 0x9f, 0xc8, 0xf3, 0x90, 0xb7, 0x51, 0x15, 0x32,
+	0x43, 0xf7, 0x54, 0x9e, 0x7a, 0xac, 0xc9, 0xa4, 0xc2, 0x43, 0x43, 0x97, 0x67, 0x42, 0x78, 0x94,
+	0xe6, 0xb8, 0x87, 0x05, 0xc1, 0x74, 0x1f, 0x13, 0x91, 0xe2, 0x4b, 0x98, 0x35, 0xd1, 0xba, 0xdc,
+	0x64, 0x3c, 0x71, 0x97, 0xb0, 0x20, 0xd0, 0x1b, 0xa0, 0xf3, 0x32, 0x8b, 0xe9, 0x7a, 0x81, 0xcc,
+	0xc6, 0x4f, 0x46, 0xe7, 0x2a, 0xaa, 0x29, 0x5b, 0x47, 0x4c, 0xe6, 0xd0, 0x0b, 0x70, 0xc1, 0x93,
+	0xad, 0x08, 0x7a, 0xd0, 0x63, 0xe0, 0xf6, 0x16, 0xe8, 0x6c, 0xf4, 0x81, 0x67, 0xf5, 0x08, 0x4f,
+	0xad, 0x3a, 0x9e, 0x30, 0x8c, 0x5f, 0x47, 0x16, 0x30, 0x0c, 0xbc, 0x08, 0x70, 0xd5, 0x96, 0x39,
+	0x93, 0x42, 0xe0, 0x1a, 0xce, 0x29, 0x1d, 0x9d, 0x13, 0x82, 0x2c, 0xdb, 0x38, 0xdc, 0x71, 0x05,
+	0xcc, 0xdb, 0x93, 0x20, 0xca, 0x46, 0x83, 0xe8, 0x11, 0xa0, 0xd9, 0xe3, 0x0a, 0xb5, 0x61, 0x85,
+	0x9c, 0x11, 0x87, 0xb2, 0x4d, 0xc4, 0x16, 0xff, 0xc6, 0x1c, 0xbc, 0x4a, 0x1c, 0xda, 0xa8, 0xb1,
+	0x25, 0xff, 0xfb, 0x57, 0x9b, 0x55, 0x21, 0xfd, 0xa2, 0x3b, 0xb2, 0x29, 0x19, 0x79, 0xf4, 0x02,
+	0x4b, 0x7d, 0xe3, 0x2f, 0x69, 0x58, 0x55, 0x1d, 0x28, 0xd4, 0x3a, 0x6f, 0xa5, 0x55, 0x00, 0xa6,
+	0x23, 0x17, 0x8a, 0xe5, 0x56, 0x7f, 0x03, 0xe0, 0xd4, 0x0a, 0xcc, 0x8f, 0x2d, 0x87, 0x92, 0xbe,
+	0xdc, 0x02, 0x11, 0x0e, 0xaa, 0x43, 0x81, 0x51, 0xe3, 0x80, 0xf4, 0xe5, 0xdd, 0x26, 0xa4, 0x23,
+	0xf3, 0xcc, 0x7f, 0xbb, 0x79, 0xc6, 0xd7, 0xbc, 0x30, 0xb5, 0xe6, 0x11, 0x00, 0xa7, 0x47, 0x01,
+	0x1c, 0x1b, 0x9b, 0xe7, 0xdb, 0xae, 0x6f, 0xd3, 0x0b, 0xbe, 0x51, 0x32, 0x38, 0xa4, 0xd9, 0x55,
+	0x79, 0x44, 0x46, 0x9e, 0xeb, 0x0e, 0x4d, 0xb1, 0x6e, 0x45, 0xae, 0x5a, 0x92, 0xcc, 0x16, 0x5f,
+	0xbe, 0x5f, 0xa5, 0x61, 0x6d, 0xe6, 0xa0, 0xff, 0xdf, 0x73, 0xb0, 0xf1, 0x1b, 0x7e, 0xdd, 0x8f,
+	0x83, 0x15, 0x74, 0x0c, 0x6b, 0x61, 0x32, 0x32, 0xc7, 0x3c, 0x49, 0xa9, 0x0d, 0xbd, 0x6c, 0x36,
+	0xab, 0x9e, 0xc5, 0xd9, 0x01, 0x7a, 0x1f, 0x9e, 0x98, 0xca, 0xb4, 0xa1, 0xe9, 0xf4, 0xb2, 0x09,
+	0xf7, 0x7a, 0x3c, 0xe1, 0x2a, 0xd3, 0x13, 0x67, 0x65, 0xbe, 0x65, 0xd4, 0xed, 0x41, 0x45, 0x79,
+	0x43, 0x60, 0xaf, 0xb9, 0xcb, 0xff, 0x34, 0x94, 0x7d, 0x42, 0x59, 0x55, 0x23, 0x76, 0x47, 0x2f,
+	0x09, 0xa6, 0xbc, 0xf9, 0x1f, 0xc1, 0xf5, 0xb9, 0x18, 0x0c, 0xfd, 0x3f, 0xe8, 0x13, 0xf8, 0xa6,
+	0x25, 0x5c, 0x6b, 0x95, 0x38, 0x9e, 0xc8, 0x1a, 0xbf, 0xd7, 0xe0, 0xfa, 0x5c, 0x14, 0x86, 0x5a,
+	0xb0, 0xe2, 0x93, 0x60, 0x3c, 0x14, 0xd7, 0xb1, 0xca, 0xce, 0x4b, 0xcb, 0xa1, 0x37, 0xc6, 0x1d,
+	0x0f, 0x29, 0x96, 0xca, 0xc6, 0x23, 0x58, 0x11, 0x1c, 0x54, 0x84, 0xfc, 0x83, 0x83, 0xfb, 0x07,
+	0x87, 0xef, 0x1d, 0x54, 0x53, 0x08, 0x60, 0x65, 0xb7, 0xd9, 0x6c, 0x1d, 0x75, 0xaa, 0x1a, 0xd2,
+	0x21, 0xb7, 0xdb, 0x38, 0xc4, 0x9d, 0x6a, 0x9a, 0xb1, 0x71, 0xeb, 0xdd, 0x56, 0xb3, 0x53, 0xcd,
+	0xa0, 0x35, 0x28, 0x8b, 0xb6, 0x79, 0xef, 0x10, 0xff, 0x70, 0xb7, 0x53, 0xcd, 0x46, 0x58, 0xc7,
+	0xad, 0x83, 0x77, 0x5a, 0xb8, 0x9a, 0x33, 0x5e, 0x81, 0x9b, 0x6a, 0x1c, 0xb3, 0x57, 0xca, 0xf0,
+	0x06, 0xa7, 0x45, 0x6e, 0x70, 0x86, 0x09, 0xb7, 0x2f, 0x45, 0x46, 0xf3, 0xd5, 0xc2, 0x3c, 0x9e,
+	0x9e, 0x97, 0xc7, 0x33, 0xd1, 0x3c, 0xfe, 0xdb, 0x34, 0xd4, 0x93, 0x51, 0x22, 0x7a, 0x77, 0xca,
+	0xb3, 0x3b, 0x57, 0x80, 0x98, 0x53, 0xee, 0x65, 0x25, 0x23, 0x9f, 0x9c, 0x10, 0xda, 0x1b, 0x08,
+	0x40, 0x28, 0x10, 0x42, 0x19, 0x97, 0x25, 0x97, 0x2b, 0x05, 0x42, 0xec, 0x43, 0xd2, 0xa3, 0xa6,
+	0x48, 0x76, 0x62, 0x57, 0xeb, 0xb8, 0x2c, 0xb8, 0xc7, 0x82, 0x69, 0xfc, 0xec, 0x4a, 0x8b, 0xa5,
+	0x43, 0x0e, 0xb7, 0x3a, 0xf8, 0xfd, 0x6a, 0x06, 0x21, 0xa8, 0xf0, 0xa6, 0x79, 0x7c, 0xb0, 0x7b,
+	0x74, 0xdc, 0x3e, 0x64, 0x8b, 0x75, 0x0d, 0x56, 0xd5, 0x62, 0x29, 0x66, 0xce, 0xf8, 0x00, 0x2a,
+	0xf1, 0x92, 0x0d, 0x73, 0xa1, 0xef, 0x8e, 0x9d, 0x3e, 0x77, 0x46, 0x0e, 0x0b, 0x82, 0xd5, 0xf7,
+	0xcf, 0x5c, 0x11, 0xc7, 0xf3, 0x37, 0xf3, 0x43, 0x97, 0x92, 0x48, 0xc9, 0x47, 0x48, 0x1b, 0x9f,
+	0x40, 0x8e, 0x87, 0x25, 0x5b, 0x2c, 0x5e, 0x7c, 0x91, 0x18, 0x92, 0xb5, 0xd1, 0x07, 0x00, 0x16,
+	0xa5, 0xbe, 0xdd, 0x1d, 0x4f, 0x0c, 0x6f, 0xce, 0x0f, 0xeb, 0x5d, 0x25, 0xd7, 0xb8, 0x25, 0xe3,
+	0x7b, 0x7d, 0xa2, 0x1a, 0x89, 0xf1, 0x88, 0x41, 0xe3, 0x00, 0x2a, 0x71, 0x5d, 0x85, 0x7a, 0xc4,
+	0x18, 0xe2, 0xa8, 0x47, 0x80, 0x58, 0x41, 0x4c, 0x30, 0x93, 0x80, 0x08, 0x82, 0x30, 0x3e, 0xd5,
+	0xa0, 0xd0, 0x39, 0x97, 0xeb, 0x91, 0x50, 0xe3, 0x99, 0xa8, 0xa6, 0xa3, 0x95, 0x0b, 0x51, 0x34,
+	0xca, 0x84, 0xa5, 0xa8, 0xb7, 0xc3, 0x1d, 0x97, 0x5d, 0xf6, 0x82, 0xaa, 0x6a, 0x72, 0x32, 0x8c,
+	0xdf, 0x02, 0x3d, 0x4c, 0xca, 0x0c, 0x8c, 0x5b, 0xfd, 0xbe, 0x4f, 0x82, 0x40, 0x46, 0x88, 0x22,
+	0xd9, 0x70, 0x3c, 0xf7, 0x63, 0x59, 0x1b, 0xc9, 0x60, 0x41, 0x18, 0x7d, 0x58, 0x9d, 0xca, 0xe8,
+	0xe8, 0x2d, 0xc8, 0x7b, 0xe3, 0xae, 0xa9, 0xdc, 0x33, 0xf5, 0x74, 0xa4, 0x60, 0xde, 0xb8, 0x3b,
+	0xb4, 0x7b, 0xf7, 0xc9, 0x85, 0x1a, 0x8c, 0x37, 0xee, 0xde, 0x17, 0x5e, 0x14, 0xbd, 0xa4, 0xa3,
+	0xbd, 0x9c, 0x41, 0x41, 0x6d, 0x0a, 0xf4, 0x3d, 0xd0, 0xc3, 0xc3, 0x22, 0xac, 0x30, 0x27, 0x9e,
+	0x32, 0xd2, 0xfc, 0x44, 0x85, 0xdd, 0x19, 0x02, 0xfb, 0xd4, 0x21, 0x7d, 0x73, 0x72, 0x1d, 0x90,
+	0x81, 0xbf, 0x2a, 0x3e, 0xec, 0xab, 0xbb, 0x80, 0xf1, 0x2f, 0x0d, 0x0a, 0xaa, 0x62, 0x88, 0x5e,
+	0x89, 0xec, 0xbb, 0xca, 0x9c, 0x3a, 0x8a, 0x12, 0x9c, 0x54, 0xfd, 0xe2, 0x63, 0x4d, 0x5f, 0x7d,
+	0xac, 0x49, 0x65, 0x5d, 0x55, 0x60, 0xcf, 0x5e, 0xb9, 0xc0, 0xfe, 0x22, 0x20, 0xea, 0x52, 0x6b,
+	0x68, 0x9e, 0xb9, 0xd4, 0x76, 0x4e, 0x4d, 0xe1, 0x6c, 0x01, 0x36, 0xaa, 0xfc, 0xcb, 0x43, 0xfe,
+	0xe1, 0x88, 0xfb, 0xfd, 0x17, 0x1a, 0x14, 0xc2, 0x53, 0xe3, 0xaa, 0x45, 0xbc, 0x1b, 0xb0, 0x22,
+	0xf3, 0x96, 0xa8, 0xe2, 0x49, 0x2a, 0xac, 0x27, 0x67, 0x23, 0xf5, 0xe4, 0x3a, 0x14, 0x46, 0x84,
+	0x5a, 0xfc, 0xe8, 0x14, 0x37, 0xb2, 0x90, 0xbe, 0xfb, 0x26, 0x14, 0x23, 0xf5, 0x54, 0x16, 0x79,
+	0x07, 0xad, 0xf7, 0xaa, 0xa9, 0x7a, 0xfe, 0xd3, 0xcf, 0xef, 0x64, 0x0e, 0xc8, 0xc7, 0x6c, 0xcf,
+	0xe2, 0x56, 0xb3, 0xdd, 0x6a, 0xde, 0xaf, 0x6a, 0xf5, 0xe2, 0xa7, 0x9f, 0xdf, 0xc9, 0x63, 0xc2,
+	0x6b, 0x38, 0x77, 0xdb, 0x50, 0x8a, 0xae, 0x4a, 0x3c, 0xf5, 0x21, 0xa8, 0xbc, 0xf3, 0xe0, 0x68,
+	0x7f, 0xaf, 0xb9, 0xdb, 0x69, 0x99, 0x0f, 0x0f, 0x3b, 0xad, 0xaa, 0x86, 0x9e, 0x80, 0x6b, 0xfb,
+	0x7b, 0x3f, 0x68, 0x77, 0xcc, 0xe6, 0xfe, 0x5e, 0xeb, 0xa0, 0x63, 0xee, 0x76, 0x3a, 0xbb, 0xcd,
+	0xfb, 0xd5, 0xf4, 0xce, 0x3f, 0x01, 0x56, 0x77, 0x1b, 0xcd, 0x3d, 0x96, 0xb6, 0xed, 0x9e, 0xc5,
+	0xaf, 0xcb, 0x4d, 0xc8, 0xf2, 0x0b, 0xf1, 0xa5, 0xaf, 0xb0, 0xf5, 0xcb, 0xab, 0x7b, 0xe8, 0x1e,
+	0xe4, 0xf8, 0x5d, 0x19, 0x5d, 0xfe, 0x2c, 0x5b, 0x5f, 0x50, 0xee, 0x63, 0x83, 0xe1, 0xe1, 0x71,
+	0xe9, 0x3b, 0x6d, 0xfd, 0xf2, 0xea, 0x1f, 0xc2, 0xa0, 0x4f, 0xd0, 0xed, 0xe2, 0x77, 0xcb, 0xfa,
+	0x12, 0xc9, 0x06, 0xed, 0x43, 0x5e, 0x5d, 0x48, 0x16, 0xbd, 0xa4, 0xd6, 0x17, 0x96, 0xe7, 0x98,
+	0xbb, 0xc4, 0x35, 0xf6, 0xf2, 0x67, 0xe1, 0xfa, 0x82, 0x7b, 0x1d, 0xda, 0x83, 0x15, 0x89, 0xd8,
+	0x16, 0xbc, 0x8e, 0xd6, 0x17, 0x95, 0xdb, 0x98, 0xd3, 0x26, 0x05, 0x82, 0xc5, 0x8f, 0xdd, 0xf5,
+	0x25, 0xca, 0xa8, 0xe8, 0x01, 0x40, 0xe4, 0x9a, 0xb8, 0xc4, 0x2b, 0x76, 0x7d, 0x99, 0xf2, 0x28,
+	0x3a, 0x84, 0x42, 0x88, 0xda, 0x17, 0xbe, 0x29, 0xd7, 0x17, 0xd7, 0x29, 0xd1, 0x23, 0x28, 0xc7,
+	0xd1, 0xea, 0x72, 0x2f, 0xc5, 0xf5, 0x25, 0x0b, 0x90, 0xcc, 0x7e, 0x1c, 0xba, 0x2e, 0xf7, 0x72,
+	0x5c, 0x5f, 0xb2, 0x1e, 0x89, 0x3e, 0x84, 0xb5, 0x59, 0x68, 0xb9, 0xfc, 0x43, 0x72, 0xfd, 0x0a,
+	0x15, 0x4a, 0x34, 0x02, 0x34, 0x07, 0x31, 0x5e, 0xe1, 0x5d, 0xb9, 0x7e, 0x95, 0x82, 0x25, 0xc2,
+	0x50, 0xe4, 0x5b, 0xfd, 0x98, 0x3f, 0x92, 0x2c, 0x8a, 0x97, 0x25, 0x8a, 0x96, 0x2f, 0x6b, 0xe8,
+	0x0c, 0x9e, 0x98, 0x99, 0x97, 0xb4, 0x7f, 0x05, 0xa7, 0x5d, 0xb1, 0x9a, 0xf9, 0xb2, 0xd6, 0x68,
+	0x7d, 0xf1, 0xf5, 0x86, 0xf6, 0xe5, 0xd7, 0x1b, 0xda, 0xdf, 0xbe, 0xde, 0xd0, 0x3e, 0xfb, 0x66,
+	0x23, 0xf5, 0xe5, 0x37, 0x1b, 0xa9, 0x3f, 0x7f, 0xb3, 0x91, 0xfa, 0xf1, 0x0b, 0xa7, 0x36, 0x1d,
+	0x8c, 0xbb, 0x5b, 0x3d, 0x77, 0xb4, 0x1d, 0xfd, 0xf9, 0x66, 0xde, 0x0f, 0x41, 0xdd, 0x15, 0x7e,
+	0x40, 0xbe, 0xfa, 0xef, 0x01, 0x00, 0xed, 0xb0, 0x24, 0xed, 0x30, 0x24, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3158,6 +3346,8 @@ type ABCIApplicationClient interface {
 	OfferSnapshot(ctx context.Context, in *RequestOfferSnapshot, opts ...grpc.CallOption) (*ResponseOfferSnapshot, error)
 	LoadSnapshotChunk(ctx context.Context, in *RequestLoadSnapshotChunk, opts ...grpc.CallOption) (*ResponseLoadSnapshotChunk, error)
 	ApplySnapshotChunk(ctx context.Context, in *RequestApplySnapshotChunk, opts ...grpc.CallOption) (*ResponseApplySnapshotChunk, error)
+	QueryStream(ctx context.Context, in *RequestQuery, opts ...grpc.CallOption) (ABCIApplication_QueryStreamClient, error)
+	LoadSnapshotChunkStream(ctx context.Context, in *RequestLoadSnapshotChunk, opts ...grpc.CallOption) (ABCIApplication_LoadSnapshotChunkStreamClient, error)
 }
 
 type aBCIApplicationClient struct {
@@ -3294,6 +3484,70 @@ func (c *aBCIApplicationClient) ApplySnapshotChunk(ctx context.Context, in *Requ
 	return out, nil
 }
 
+func (c *aBCIApplicationClient) QueryStream(ctx context.Context, in *RequestQuery, opts ...grpc.CallOption) (ABCIApplication_QueryStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ABCIApplication_serviceDesc.Streams[0], "/tendermint.abci.ABCIApplication/QueryStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aBCIApplicationQueryStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ABCIApplication_QueryStreamClient interface {
+	Recv() (*ResponseQueryPart, error)
+	grpc.ClientStream
+}
+
+type aBCIApplicationQueryStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *aBCIApplicationQueryStreamClient) Recv() (*ResponseQueryPart, error) {
+	m := new(ResponseQueryPart)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aBCIApplicationClient) LoadSnapshotChunkStream(ctx context.Context, in *RequestLoadSnapshotChunk, opts ...grpc.CallOption) (ABCIApplication_LoadSnapshotChunkStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ABCIApplication_serviceDesc.Streams[1], "/tendermint.abci.ABCIApplication/LoadSnapshotChunkStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aBCIApplicationLoadSnapshotChunkStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ABCIApplication_LoadSnapshotChunkStreamClient interface {
+	Recv() (*ResponseLoadSnapshotChunkPart, error)
+	grpc.ClientStream
+}
+
+type aBCIApplicationLoadSnapshotChunkStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *aBCIApplicationLoadSnapshotChunkStreamClient) Recv() (*ResponseLoadSnapshotChunkPart, error) {
+	m := new(ResponseLoadSnapshotChunkPart)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ABCIApplicationServer is the server API for ABCIApplication service.
 type ABCIApplicationServer interface {
 	Echo(context.Context, *RequestEcho) (*ResponseEcho, error)
@@ -3310,6 +3564,8 @@ type ABCIApplicationServer interface {
 	OfferSnapshot(context.Context, *RequestOfferSnapshot) (*ResponseOfferSnapshot, error)
 	LoadSnapshotChunk(context.Context, *RequestLoadSnapshotChunk) (*ResponseLoadSnapshotChunk, error)
 	ApplySnapshotChunk(context.Context, *RequestApplySnapshotChunk) (*ResponseApplySnapshotChunk, error)
+	QueryStream(*RequestQuery, ABCIApplication_QueryStreamServer) error
+	LoadSnapshotChunkStream(*RequestLoadSnapshotChunk, ABCIApplication_LoadSnapshotChunkStreamServer) error
 }
 
 // UnimplementedABCIApplicationServer can be embedded to have forward compatible implementations.
@@ -3358,6 +3614,12 @@ func (*UnimplementedABCIApplicationServer) LoadSnapshotChunk(ctx context.Context
 func (*UnimplementedABCIApplicationServer) ApplySnapshotChunk(ctx context.Context, req *RequestApplySnapshotChunk) (*ResponseApplySnapshotChunk, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ApplySnapshotChunk not implemented")
 }
+func (*UnimplementedABCIApplicationServer) QueryStream(req *RequestQuery, srv ABCIApplication_QueryStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method QueryStream not implemented")
+}
+func (*UnimplementedABCIApplicationServer) LoadSnapshotChunkStream(req *RequestLoadSnapshotChunk, srv ABCIApplication_LoadSnapshotChunkStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method LoadSnapshotChunkStream not implemented")
+}
 
 func RegisterABCIApplicationServer(s *grpc.Server, srv ABCIApplicationServer) {
 	s.RegisterService(&_ABCIApplication_serviceDesc, srv)
@@ -3615,6 +3877,48 @@ func _ABCIApplication_ApplySnapshotChunk_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ABCIApplication_QueryStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RequestQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ABCIApplicationServer).QueryStream(m, &aBCIApplicationQueryStreamServer{stream})
+}
+
+type ABCIApplication_QueryStreamServer interface {
+	Send(*ResponseQueryPart) error
+	grpc.ServerStream
+}
+
+type aBCIApplicationQueryStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *aBCIApplicationQueryStreamServer) Send(m *ResponseQueryPart) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ABCIApplication_LoadSnapshotChunkStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RequestLoadSnapshotChunk)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ABCIApplicationServer).LoadSnapshotChunkStream(m, &aBCIApplicationLoadSnapshotChunkStreamServer{stream})
+}
+
+type ABCIApplication_LoadSnapshotChunkStreamServer interface {
+	Send(*ResponseLoadSnapshotChunkPart) error
+	grpc.ServerStream
+}
+
+type aBCIApplicationLoadSnapshotChunkStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *aBCIApplicationLoadSnapshotChunkStreamServer) Send(m *ResponseLoadSnapshotChunkPart) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _ABCIApplication_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "tendermint.abci.ABCIApplication",
 	HandlerType: (*ABCIApplicationServer)(nil),
@@ -3676,7 +3980,18 @@ var _ABCIApplication_serviceDesc = grpc.ServiceDesc{
 			Handler:    _ABCIApplication_ApplySnapshotChunk_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QueryStream",
+			Handler:       _ABCIApplication_QueryStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "LoadSnapshotChunkStream",
+			Handler:       _ABCIApplication_LoadSnapshotChunkStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "tendermint/abci/types.proto",
 }
 
@@ -4202,6 +4517,16 @@ func (m *RequestQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Stream {
+		i--
+		if m.Stream {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.Prove {
 		i--
 		if m.Prove {
@@ -4499,6 +4824,16 @@ func (m *RequestLoadSnapshotChunk) MarshalToSizedBuffer(dAtA []byte) (int, error
 	_ = i
 	var l int
 	_ = l
+	if m.Stream {
+		i--
+		if m.Stream {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.Chunk != 0 {
 		i = encodeVarintTypes(dAtA, i, uint64(m.Chunk))
 		i--
@@ -4906,6 +5241,52 @@ func (m *Response_ApplySnapshotChunk) MarshalToSizedBuffer(dAtA []byte) (int, er
 	}
 	return len(dAtA) - i, nil
 }
+func (m *Response_QueryPart) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Response_QueryPart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.QueryPart != nil {
+		{
+			size, err := m.QueryPart.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Response_LoadSnapshotChunkPart) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Response_LoadSnapshotChunkPart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.LoadSnapshotChunkPart != nil {
+		{
+			size, err := m.LoadSnapshotChunkPart.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
+	return len(dAtA) - i, nil
+}
 func (m *ResponseException) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -5184,7 +5565,7 @@ func (m *ResponseQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *ResponseBeginBlock) Marshal() (dAtA []byte, err error) {
+func (m *ResponseQueryPart) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5194,22 +5575,81 @@ func (m *ResponseBeginBlock) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ResponseBeginBlock) MarshalTo(dAtA []byte) (int, error) {
+func (m *ResponseQueryPart) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ResponseBeginBlock) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ResponseQueryPart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Events) > 0 {
-		for iNdEx := len(m.Events) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Events[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Last {
+		i--
+		if m.Last {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Header != nil {
+		{
+			size, err := m.Header.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ResponseBeginBlock) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ResponseBeginBlock) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ResponseBeginBlock) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Events) > 0 {
+		for iNdEx := len(m.Events) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Events[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
 				}
 				i -= size
 				i = encodeVarintTypes(dAtA, i, uint64(size))
@@ -5593,6 +6033,53 @@ func (m *ResponseLoadSnapshotChunk) MarshalToSizedBuffer(dAtA []byte) (int, erro
 	return len(dAtA) - i, nil
 }
 
+func (m *ResponseLoadSnapshotChunkPart) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ResponseLoadSnapshotChunkPart) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ResponseLoadSnapshotChunkPart) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Last {
+		i--
+		if m.Last {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Chunk) > 0 {
+		i -= len(m.Chunk)
+		copy(dAtA[i:], m.Chunk)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Chunk)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ResponseApplySnapshotChunk) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -5623,20 +6110,20 @@ func (m *ResponseApplySnapshotChunk) MarshalToSizedBuffer(dAtA []byte) (int, err
 		}
 	}
 	if len(m.RefetchChunks) > 0 {
-		dAtA39 := make([]byte, len(m.RefetchChunks)*10)
-		var j38 int
+		dAtA42 := make([]byte, len(m.RefetchChunks)*10)
+		var j41 int
 		for _, num := range m.RefetchChunks {
 			for num >= 1<<7 {
-				dAtA39[j38] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA42[j41] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j38++
+				j41++
 			}
-			dAtA39[j38] = uint8(num)
-			j38++
+			dAtA42[j41] = uint8(num)
+			j41++
 		}
-		i -= j38
-		copy(dAtA[i:], dAtA39[:j38])
-		i = encodeVarintTypes(dAtA, i, uint64(j38))
+		i -= j41
+		copy(dAtA[i:], dAtA42[:j41])
+		i = encodeVarintTypes(dAtA, i, uint64(j41))
 		i--
 		dAtA[i] = 0x12
 	}
@@ -5972,12 +6459,12 @@ func (m *Evidence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x28
 	}
-	n43, err43 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
-	if err43 != nil {
-		return 0, err43
+	n46, err46 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
+	if err46 != nil {
+		return 0, err46
 	}
-	i -= n43
-	i = encodeVarintTypes(dAtA, i, uint64(n43))
+	i -= n46
+	i = encodeVarintTypes(dAtA, i, uint64(n46))
 	i--
 	dAtA[i] = 0x22
 	if m.Height != 0 {
@@ -6343,6 +6830,9 @@ func (m *RequestQuery) Size() (n int) {
 	if m.Prove {
 		n += 2
 	}
+	if m.Stream {
+		n += 2
+	}
 	return n
 }
 
@@ -6460,6 +6950,9 @@ func (m *RequestLoadSnapshotChunk) Size() (n int) {
 	if m.Chunk != 0 {
 		n += 1 + sovTypes(uint64(m.Chunk))
 	}
+	if m.Stream {
+		n += 2
+	}
 	return n
 }
 
@@ -6675,6 +7168,30 @@ func (m *Response_ApplySnapshotChunk) Size() (n int) {
 	}
 	return n
 }
+func (m *Response_QueryPart) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.QueryPart != nil {
+		l = m.QueryPart.Size()
+		n += 2 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+func (m *Response_LoadSnapshotChunkPart) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.LoadSnapshotChunkPart != nil {
+		l = m.LoadSnapshotChunkPart.Size()
+		n += 2 + l + sovTypes(uint64(l))
+	}
+	return n
+}
 func (m *ResponseException) Size() (n int) {
 	if m == nil {
 		return 0
@@ -6802,6 +7319,30 @@ func (m *ResponseQuery) Size() (n int) {
 	return n
 }
 
+func (m *ResponseQueryPart) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Header != nil {
+		l = m.Header.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.Last {
+		n += 2
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func (m *ResponseBeginBlock) Size() (n int) {
 	if m == nil {
 		return 0
@@ -6989,6 +7530,26 @@ func (m *ResponseLoadSnapshotChunk) Size() (n int) {
 	return n
 }
 
+func (m *ResponseLoadSnapshotChunkPart) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Chunk)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.Last {
+		n += 2
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func (m *ResponseApplySnapshotChunk) Size() (n int) {
 	if m == nil {
 		return 0
@@ -8386,6 +8947,26 @@ func (m *RequestQuery) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.Prove = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stream", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Stream = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -9153,6 +9734,26 @@ func (m *RequestLoadSnapshotChunk) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stream", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Stream = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -9863,61 +10464,11 @@ func (m *Response) Unmarshal(dAtA []byte) error {
 			}
 			m.Value = &Response_ApplySnapshotChunk{v}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTypes(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthTypes
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *ResponseException) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTypes
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: ResponseException: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ResponseException: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 16:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryPart", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTypes
@@ -9927,13 +10478,133 @@ func (m *ResponseException) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &ResponseQueryPart{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Value = &Response_QueryPart{v}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LoadSnapshotChunkPart", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &ResponseLoadSnapshotChunkPart{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Value = &Response_LoadSnapshotChunkPart{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResponseException) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResponseException: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResponseException: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTypes
 			}
 			postIndex := iNdEx + intStringLen
@@ -10745,6 +11416,178 @@ func (m *ResponseQuery) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ResponseQueryPart) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResponseQueryPart: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResponseQueryPart: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Header == nil {
+				m.Header = &ResponseQuery{}
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Last", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Last = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *ResponseBeginBlock) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -11948,6 +12791,142 @@ func (m *ResponseLoadSnapshotChunk) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ResponseLoadSnapshotChunkPart) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResponseLoadSnapshotChunkPart: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResponseLoadSnapshotChunkPart: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunk", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Chunk = append(m.Chunk[:0], dAtA[iNdEx:postIndex]...)
+			if m.Chunk == nil {
+				m.Chunk = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Last", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Last = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *ResponseApplySnapshotChunk) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
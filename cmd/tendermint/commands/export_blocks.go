@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/internal/blocksync"
+)
+
+var (
+	exportFromHeight int64
+	exportToHeight   int64
+)
+
+// ExportBlocksCmd writes blocks from the block store to a block archive.
+var ExportBlocksCmd = &cobra.Command{
+	Use:   "export-blocks <file>",
+	Short: "export blocks and their commits to a block archive",
+	Long: `
+export-blocks writes blocks and their commits from the block store to a block archive,
+which another node can apply without fetching the blocks from peers, using import-blocks
+or the block-archive option during block sync. The default from-height is 0, meaning
+the base of the block store, and the default to-height is 0, meaning the latest height.
+The node must not be running.
+`,
+	Example: `
+	tendermint export-blocks blocks.bin
+	tendermint export-blocks blocks.bin --from-height 2 --to-height 10
+	`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		blockStore, stateStore, err := loadStateAndBlockStore(config)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = blockStore.Close()
+			_ = stateStore.Close()
+		}()
+
+		from, to := exportFromHeight, exportToHeight
+		if from == 0 {
+			from = blockStore.Base()
+		}
+		if to == 0 {
+			to = blockStore.Height()
+		}
+
+		file, err := os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if _, err := blocksync.ExportBlocks(file, blockStore, from, to); err != nil {
+			return fmt.Errorf("failed to export blocks: %w", err)
+		}
+		if err := file.Sync(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Exported blocks %d-%d to %s\n", from, to, args[0])
+		return nil
+	},
+}
+
+func init() {
+	ExportBlocksCmd.Flags().Int64Var(&exportFromHeight, "from-height", 0, "the lowest height to export")
+	ExportBlocksCmd.Flags().Int64Var(&exportToHeight, "to-height", 0, "the highest height to export")
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/blocksync"
+	"github.com/tendermint/tendermint/internal/consensus"
+	"github.com/tendermint/tendermint/internal/eventbus"
+	"github.com/tendermint/tendermint/internal/mempool/mock"
+	"github.com/tendermint/tendermint/internal/proxy"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/store"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// ImportBlocksCmd applies blocks from a block archive.
+var ImportBlocksCmd = &cobra.Command{
+	Use:   "import-blocks <file>",
+	Short: "apply blocks from a block archive",
+	Long: `
+import-blocks applies blocks from a block archive written by export-blocks, on top of the
+latest state, verifying each commit and executing each block against the application just
+like blocks fetched from peers during block sync. Blocks at or below the latest height are
+skipped, so an interrupted import can be resumed with the same archive.
+
+The application must be running, while the node must not be. Events are not indexed, run
+reindex-event afterwards if needed.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		height, applied, err := ImportBlocks(cmd.Context(), config, logger, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to import blocks (imported %d blocks up to height %d): %w",
+				applied, height, err)
+		}
+
+		fmt.Printf("Imported %d blocks, the latest height is %d\n", applied, height)
+		return nil
+	},
+}
+
+// ImportBlocks applies the blocks in the block archive at path on top of the
+// latest state. It returns the resulting height and the number of blocks
+// applied, also if an error occurred.
+func ImportBlocks(ctx context.Context, config *cfg.Config, logger log.Logger, path string) (int64, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	blockStoreDB, err := cfg.DefaultDBProvider(&cfg.DBContext{ID: "blockstore", Config: config})
+	if err != nil {
+		return 0, 0, err
+	}
+	blockStore := store.NewBlockStore(blockStoreDB)
+	defer blockStore.Close()
+
+	stateDB, err := cfg.DefaultDBProvider(&cfg.DBContext{ID: "state", Config: config})
+	if err != nil {
+		return 0, 0, err
+	}
+	stateStore := sm.NewStore(stateDB)
+	defer stateStore.Close()
+
+	genDoc, err := sm.MakeGenesisDocFromFile(config.GenesisFile())
+	if err != nil {
+		return 0, 0, err
+	}
+	state, err := stateStore.Load()
+	if err != nil {
+		return 0, 0, err
+	}
+	if state.IsEmpty() {
+		state, err = sm.MakeGenesisState(genDoc)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clientCreator, closer := proxy.DefaultClientCreator(logger, config.ProxyApp, config.ABCI, config.DBDir())
+	defer closer.Close()
+	proxyApp := proxy.NewAppConns(clientCreator, logger, proxy.NopMetrics())
+	if err := proxyApp.Start(ctx); err != nil {
+		return 0, 0, fmt.Errorf("starting proxy app conns: %w", err)
+	}
+
+	eventBus := eventbus.NewDefault(logger)
+	if err := eventBus.Start(ctx); err != nil {
+		return 0, 0, fmt.Errorf("failed to start event bus: %w", err)
+	}
+
+	// Sync the application with the latest state, as on node startup.
+	if err := consensus.NewHandshaker(
+		logger.With("module", "handshaker"),
+		stateStore, state, blockStore, eventBus, genDoc,
+	).Handshake(ctx, proxyApp); err != nil {
+		return 0, 0, err
+	}
+	state, err = stateStore.Load()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	blockExec := sm.NewBlockExecutor(
+		stateStore, logger, proxyApp.Consensus(), mock.Mempool{}, sm.EmptyEvidencePool{}, blockStore)
+	blockExec.SetEventBus(eventBus)
+
+	state, applied, err := blocksync.ImportBlocks(ctx, state, blockExec, blockStore, blocksync.NewArchiveReader(file))
+	return state.LastBlockHeight, applied, err
+}
@@ -29,6 +29,8 @@ func main() {
 		cmd.VersionCmd,
 		cmd.InspectCmd,
 		cmd.RollbackStateCmd,
+		cmd.ExportBlocksCmd,
+		cmd.ImportBlocksCmd,
 		cmd.MakeKeyMigrateCommand(),
 		debug.DebugCmd,
 		cli.NewCompletionCmd(rootCmd, true),
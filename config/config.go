@@ -5,13 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/tendermint/tendermint/crypto/tmhash"
 	tmjson "github.com/tendermint/tendermint/libs/json"
 	"github.com/tendermint/tendermint/libs/log"
 	tmos "github.com/tendermint/tendermint/libs/os"
+	tmstrings "github.com/tendermint/tendermint/libs/strings"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -64,6 +69,7 @@ type Config struct {
 
 	// Options for services
 	RPC             *RPCConfig             `mapstructure:"rpc"`
+	ABCIConn        *ABCIConnConfig        `mapstructure:"abci-conn"`
 	P2P             *P2PConfig             `mapstructure:"p2p"`
 	Mempool         *MempoolConfig         `mapstructure:"mempool"`
 	StateSync       *StateSyncConfig       `mapstructure:"statesync"`
@@ -78,6 +84,7 @@ func DefaultConfig() *Config {
 	return &Config{
 		BaseConfig:      DefaultBaseConfig(),
 		RPC:             DefaultRPCConfig(),
+		ABCIConn:        DefaultABCIConnConfig(),
 		P2P:             DefaultP2PConfig(),
 		Mempool:         DefaultMempoolConfig(),
 		StateSync:       DefaultStateSyncConfig(),
@@ -100,6 +107,7 @@ func TestConfig() *Config {
 	return &Config{
 		BaseConfig:      TestBaseConfig(),
 		RPC:             TestRPCConfig(),
+		ABCIConn:        TestABCIConnConfig(),
 		P2P:             TestP2PConfig(),
 		Mempool:         TestMempoolConfig(),
 		StateSync:       TestStateSyncConfig(),
@@ -130,6 +138,9 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.RPC.ValidateBasic(); err != nil {
 		return fmt.Errorf("error in [rpc] section: %w", err)
 	}
+	if err := cfg.ABCIConn.ValidateBasic(); err != nil {
+		return fmt.Errorf("error in [abci-conn] section: %w", err)
+	}
 	if err := cfg.Mempool.ValidateBasic(); err != nil {
 		return fmt.Errorf("error in [mempool] section: %w", err)
 	}
@@ -212,13 +223,23 @@ type BaseConfig struct { //nolint: maligned
 	// A JSON file containing the private key to use for p2p authenticated encryption
 	NodeKey string `mapstructure:"node-key-file"`
 
-	// Mechanism to connect to the ABCI application: socket | grpc
+	// Mechanism to connect to the ABCI application: socket | shm | grpc
 	ABCI string `mapstructure:"abci"`
 
 	// If true, query the ABCI app on connecting to a new peer
 	// so the app can decide if we should keep the connection or not
 	FilterPeers bool `mapstructure:"filter-peers"` // false
 
+	// Path to a block archive written by the export-blocks command. If set,
+	// block sync applies the blocks in the archive before fetching the
+	// remaining blocks from peers.
+	BlockArchive string `mapstructure:"block-archive"`
+
+	// A trusted block hash and its height, as "height:hash". If set, block
+	// sync verifies the blocks below this height by their hash chain from the
+	// trusted block, instead of verifying the signatures of each commit.
+	TrustedCheckpoint string `mapstructure:"trusted-checkpoint"`
+
 	Other map[string]interface{} `mapstructure:",remain"`
 }
 
@@ -303,6 +324,39 @@ func (cfg BaseConfig) DBDir() string {
 	return rootify(cfg.DBPath, cfg.RootDir)
 }
 
+// BlockArchiveFile returns the full path to the block archive, if any.
+func (cfg BaseConfig) BlockArchiveFile() string {
+	if cfg.BlockArchive == "" {
+		return ""
+	}
+	return rootify(cfg.BlockArchive, cfg.RootDir)
+}
+
+// TrustedCheckpointParams returns the height and hash of the trusted
+// checkpoint, or a zero height if none is set.
+func (cfg BaseConfig) TrustedCheckpointParams() (int64, []byte, error) {
+	if cfg.TrustedCheckpoint == "" {
+		return 0, nil, nil
+	}
+
+	parts := strings.Split(cfg.TrustedCheckpoint, ":")
+	if len(parts) != 2 {
+		return 0, nil, errors.New("trusted-checkpoint must be formatted as height:hash")
+	}
+	height, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || height <= 0 {
+		return 0, nil, fmt.Errorf("invalid trusted-checkpoint height %q", parts[0])
+	}
+	hash, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid trusted-checkpoint hash: %w", err)
+	}
+	if len(hash) != tmhash.Size {
+		return 0, nil, fmt.Errorf("trusted-checkpoint hash must be %d bytes, got %d", tmhash.Size, len(hash))
+	}
+	return height, hash, nil
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg BaseConfig) ValidateBasic() error {
@@ -321,6 +375,10 @@ func (cfg BaseConfig) ValidateBasic() error {
 		return fmt.Errorf("unknown mode: %v", cfg.Mode)
 	}
 
+	if _, _, err := cfg.TrustedCheckpointParams(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -566,9 +624,23 @@ type P2PConfig struct { //nolint: maligned
 	// Address to listen for incoming connections
 	ListenAddress string `mapstructure:"laddr"`
 
+	// Address to listen for incoming QUIC connections, in addition to
+	// ListenAddress. Empty disables the QUIC transport.
+	QUICListenAddress string `mapstructure:"quic-laddr"`
+
 	// Address to advertise to peers for them to dial
 	ExternalAddress string `mapstructure:"external-address"`
 
+	// URL of a SOCKS5 proxy to dial peers through, e.g.
+	// "socks5://127.0.0.1:9050" for a local Tor daemon. Required to dial
+	// .onion addresses. Empty dials peers directly.
+	Proxy string `mapstructure:"proxy"`
+
+	// Only dial peers through Proxy. Hostnames are resolved by the proxy
+	// rather than locally, and the QUIC transport, which can't be proxied,
+	// can't be enabled.
+	ProxyOnly bool `mapstructure:"proxy-only"`
+
 	// Comma separated list of seed nodes to connect to
 	// We only use these if we can’t connect to peers in the addrbook
 	// NOTE: not used by the new PEX reactor. Please use BootstrapPeers instead.
@@ -598,6 +670,10 @@ type P2PConfig struct { //nolint: maligned
 	// Set true to enable the peer-exchange reactor
 	PexReactor bool `mapstructure:"pex"`
 
+	// Interval between network crawls on seed nodes, which are served by the
+	// net_crawl RPC route. 0 disables crawling.
+	CrawlInterval time.Duration `mapstructure:"crawl-interval"`
+
 	// Comma separated list of peer IDs to keep private (will not be gossiped to
 	// other peers)
 	PrivatePeerIDs string `mapstructure:"private-peer-ids"`
@@ -617,10 +693,46 @@ type P2PConfig struct { //nolint: maligned
 	// Rate at which packets can be received, in bytes/second
 	RecvRate int64 `mapstructure:"recv-rate"`
 
+	// Comma separated list of <channel ID>=<rate> pairs limiting the rate at
+	// which messages are sent to each peer on a channel, in bytes/second.
+	ChannelSendRates string `mapstructure:"channel-send-rates"`
+
+	// Comma separated list of <channel ID>=<rate> pairs limiting the rate at
+	// which messages are accepted from each peer on a channel, in
+	// bytes/second. Messages over the limit are dropped.
+	ChannelRecvRates string `mapstructure:"channel-recv-rates"`
+
+	// Time for a peer's reputation to decay to half its value. Reactors lower
+	// the reputation of misbehaving peers, and raise it for useful ones.
+	ReputationHalfLife time.Duration `mapstructure:"reputation-half-life"`
+
+	// Negative reputation at which a peer is banned for reputation-ban-duration.
+	// 0 disables reputation bans.
+	ReputationBanThreshold int64 `mapstructure:"reputation-ban-threshold"`
+
+	// Duration of reputation bans.
+	ReputationBanDuration time.Duration `mapstructure:"reputation-ban-duration"`
+
 	// Peer connection configuration.
 	HandshakeTimeout time.Duration `mapstructure:"handshake-timeout"`
 	DialTimeout      time.Duration `mapstructure:"dial-timeout"`
 
+	// Handshake used to secure dialed connections, "sts" or "noise".
+	// Accepted connections use whichever handshake the peer dials with.
+	HandshakeProtocol string `mapstructure:"handshake-protocol"`
+
+	// Path to a file to capture messages sent and received by the router to,
+	// for debugging. Files are rotated like the consensus WAL. Empty disables
+	// capturing.
+	CapturePath string `mapstructure:"capture-file"`
+
+	// Comma separated list of channel IDs to capture. Empty captures all
+	// channels.
+	CaptureChannels string `mapstructure:"capture-channels"`
+
+	// Comma separated list of peer IDs to capture. Empty captures all peers.
+	CapturePeers string `mapstructure:"capture-peers"`
+
 	// Testing params.
 	// Force dial to fail
 	TestDialFail bool `mapstructure:"test-dial-fail"`
@@ -635,7 +747,10 @@ type P2PConfig struct { //nolint: maligned
 func DefaultP2PConfig() *P2PConfig {
 	return &P2PConfig{
 		ListenAddress:                 "tcp://0.0.0.0:26656",
+		QUICListenAddress:             "",
 		ExternalAddress:               "",
+		Proxy:                         "",
+		ProxyOnly:                     false,
 		UPNP:                          false,
 		MaxConnections:                64,
 		MaxIncomingConnectionAttempts: 100,
@@ -649,9 +764,17 @@ func DefaultP2PConfig() *P2PConfig {
 		SendRate:                5120000, // 5 mB/s
 		RecvRate:                5120000, // 5 mB/s
 		PexReactor:              true,
+		CrawlInterval:           10 * time.Minute,
 		AllowDuplicateIP:        false,
 		HandshakeTimeout:        20 * time.Second,
 		DialTimeout:             3 * time.Second,
+		HandshakeProtocol:       "sts",
+		CapturePath:             "",
+		CaptureChannels:         "",
+		CapturePeers:            "",
+		ReputationHalfLife:      24 * time.Hour,
+		ReputationBanThreshold:  -100,
+		ReputationBanDuration:   24 * time.Hour,
 		TestDialFail:            false,
 		QueueType:               "priority",
 	}
@@ -666,15 +789,110 @@ func (cfg *P2PConfig) ValidateBasic() error {
 	if cfg.MaxPacketMsgPayloadSize < 0 {
 		return errors.New("max-packet-msg-payload-size can't be negative")
 	}
+	if cfg.CrawlInterval < 0 {
+		return errors.New("crawl-interval can't be negative")
+	}
 	if cfg.SendRate < 0 {
 		return errors.New("send-rate can't be negative")
 	}
 	if cfg.RecvRate < 0 {
 		return errors.New("recv-rate can't be negative")
 	}
+	if _, err := ParseChannelRates(cfg.ChannelSendRates); err != nil {
+		return fmt.Errorf("invalid channel-send-rates: %w", err)
+	}
+	if _, err := ParseChannelRates(cfg.ChannelRecvRates); err != nil {
+		return fmt.Errorf("invalid channel-recv-rates: %w", err)
+	}
+	if cfg.Proxy != "" {
+		u, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy: %w", err)
+		}
+		if (u.Scheme != "socks5" && u.Scheme != "socks5h") || u.Host == "" {
+			return fmt.Errorf("proxy must be a SOCKS5 URL, e.g. socks5://127.0.0.1:9050 (got %q)", cfg.Proxy)
+		}
+	}
+	if cfg.ProxyOnly && cfg.Proxy == "" {
+		return errors.New("proxy-only requires a proxy")
+	}
+	if cfg.ProxyOnly && cfg.QUICListenAddress != "" {
+		return errors.New("proxy-only can't be used with quic-laddr, since QUIC can't be proxied")
+	}
+	switch cfg.HandshakeProtocol {
+	case "sts", "noise":
+	default:
+		return fmt.Errorf("unknown handshake-protocol %q, must be \"sts\" or \"noise\"", cfg.HandshakeProtocol)
+	}
+	if _, err := ParseChannelIDs(cfg.CaptureChannels); err != nil {
+		return fmt.Errorf("invalid capture-channels: %w", err)
+	}
+	for _, id := range tmstrings.SplitAndTrimEmpty(cfg.CapturePeers, ",", " ") {
+		if err := types.NodeID(id).Validate(); err != nil {
+			return fmt.Errorf("invalid capture-peers: %w", err)
+		}
+	}
+	if cfg.ReputationHalfLife < 0 {
+		return errors.New("reputation-half-life can't be negative")
+	}
+	if cfg.ReputationBanThreshold > 0 {
+		return errors.New("reputation-ban-threshold can't be positive")
+	}
+	if cfg.ReputationBanThreshold < 0 && cfg.ReputationBanDuration <= 0 {
+		return errors.New("reputation-ban-duration must be positive when reputation-ban-threshold is set")
+	}
 	return nil
 }
 
+// ParseChannelRates parses a comma separated list of <channel ID>=<rate>
+// pairs, such as "0x30=1048576". Channel IDs may be given in decimal or
+// hexadecimal, and rates are in bytes/second.
+func ParseChannelRates(s string) (map[uint16]int64, error) {
+	rates := map[uint16]int64{}
+	for _, pair := range tmstrings.SplitAndTrimEmpty(s, ",", " ") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%q is not a <channel ID>=<rate> pair", pair)
+		}
+		chID, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel ID in %q: %w", pair, err)
+		}
+		rate, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate in %q: %w", pair, err)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("rate in %q must be positive", pair)
+		}
+		rates[uint16(chID)] = rate
+	}
+	return rates, nil
+}
+
+// ParseChannelIDs parses a comma separated list of channel IDs, such as
+// "0x30,0x40". Channel IDs may be given in decimal or hexadecimal.
+func ParseChannelIDs(s string) ([]uint16, error) {
+	ids := []uint16{}
+	for _, id := range tmstrings.SplitAndTrimEmpty(s, ",", " ") {
+		chID, err := strconv.ParseUint(id, 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel ID %q: %w", id, err)
+		}
+		ids = append(ids, uint16(chID))
+	}
+	return ids, nil
+}
+
+// CaptureFile returns the full path to the message capture file, or an empty
+// string if capturing is disabled.
+func (cfg *P2PConfig) CaptureFile() string {
+	if cfg.CapturePath == "" {
+		return ""
+	}
+	return rootify(cfg.CapturePath, cfg.RootDir)
+}
+
 // TestP2PConfig returns a configuration for testing the peer-to-peer layer
 func TestP2PConfig() *P2PConfig {
 	cfg := DefaultP2PConfig()
@@ -685,6 +903,106 @@ func TestP2PConfig() *P2PConfig {
 	return cfg
 }
 
+//-----------------------------------------------------------------------------
+// ABCIConnConfig
+
+// ABCIConnConfig defines the call policies Tendermint applies to the
+// connections it holds to the ABCI application.
+type ABCIConnConfig struct {
+	// Number of connections used for Echo, Info and Query calls. Calls are
+	// spread over the connections, so that a slow query does not hold up the
+	// others. The application must support concurrent query connections.
+	// Builtin applications always use a single query connection, since their
+	// calls are serialized by one lock, except for Query calls to the
+	// merklestore application, which run concurrently anyway.
+	QueryConnections int `mapstructure:"query-connections"`
+
+	// Run Query calls to a builtin application without the lock serializing
+	// its other calls, so that queries are served concurrently with each
+	// other and with block execution. Only enable this if the application is
+	// safe for such concurrent use.
+	ConcurrentLocalQueries bool `mapstructure:"concurrent-local-queries"`
+
+	// Maximum duration of an Echo, Info or Query call on the query connection.
+	// 0 disables the timeout.
+	QueryTimeout time.Duration `mapstructure:"query-timeout"`
+
+	// Maximum duration of a synchronous CheckTx call on the mempool connection
+	// (e.g. from the check_tx RPC endpoint). 0 disables the timeout.
+	CheckTxTimeout time.Duration `mapstructure:"check-tx-timeout"`
+
+	// Maximum duration of a call on the snapshot connection. 0 disables the
+	// timeout, which is the default since loading and applying a large
+	// snapshot chunk may take arbitrarily long.
+	SnapshotTimeout time.Duration `mapstructure:"snapshot-timeout"`
+
+	// Maximum number of timed out calls a connection waits on in the
+	// background, since a timed out call keeps running until the application
+	// returns. Further calls fail immediately until one of them returns. This
+	// should not be below BreakerThreshold, otherwise the application may be
+	// considered unresponsive before the breaker opens.
+	MaxAbandonedCalls int `mapstructure:"max-abandoned-calls"`
+
+	// Number of consecutive timed out calls after which a connection is
+	// considered unresponsive and further calls fail immediately. Only the
+	// query, mempool and snapshot connections are affected. Since only timed
+	// out calls count, the breaker never opens on a connection whose timeout
+	// is disabled. 0 disables the circuit breaker.
+	BreakerThreshold int `mapstructure:"breaker-threshold"`
+
+	// Time an unresponsive connection rejects calls before a single probe call
+	// is let through to check whether the application recovered.
+	BreakerCooldown time.Duration `mapstructure:"breaker-cooldown"`
+}
+
+// DefaultABCIConnConfig returns a default configuration for the ABCI
+// application connections.
+func DefaultABCIConnConfig() *ABCIConnConfig {
+	return &ABCIConnConfig{
+		QueryConnections:       1,
+		ConcurrentLocalQueries: false,
+		QueryTimeout:           30 * time.Second,
+		CheckTxTimeout:         10 * time.Second,
+		SnapshotTimeout:        0,
+		MaxAbandonedCalls:      5,
+		BreakerThreshold:       5,
+		BreakerCooldown:        10 * time.Second,
+	}
+}
+
+// TestABCIConnConfig returns a configuration for testing the ABCI application
+// connections.
+func TestABCIConnConfig() *ABCIConnConfig {
+	return DefaultABCIConnConfig()
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *ABCIConnConfig) ValidateBasic() error {
+	if cfg.QueryConnections <= 0 {
+		return errors.New("query-connections must be positive")
+	}
+	if cfg.QueryTimeout < 0 {
+		return errors.New("query-timeout can't be negative")
+	}
+	if cfg.CheckTxTimeout < 0 {
+		return errors.New("check-tx-timeout can't be negative")
+	}
+	if cfg.SnapshotTimeout < 0 {
+		return errors.New("snapshot-timeout can't be negative")
+	}
+	if cfg.MaxAbandonedCalls <= 0 {
+		return errors.New("max-abandoned-calls must be positive")
+	}
+	if cfg.BreakerThreshold < 0 {
+		return errors.New("breaker-threshold can't be negative")
+	}
+	if cfg.BreakerThreshold > 0 && cfg.BreakerCooldown <= 0 {
+		return errors.New("breaker-cooldown must be positive when breaker-threshold is set")
+	}
+	return nil
+}
+
 //-----------------------------------------------------------------------------
 // MempoolConfig
 
@@ -818,17 +1136,44 @@ type StateSyncConfig struct {
 	// Time to spend discovering snapshots before initiating a restore.
 	DiscoveryTime time.Duration `mapstructure:"discovery-time"`
 
-	// Temporary directory for state sync snapshot chunks, defaults to os.TempDir().
-	// The synchronizer will create a new, randomly named directory within this directory
-	// and remove it when the sync is complete.
+	// Directory for state sync snapshot chunks, defaults to the data directory.
+	// The synchronizer will store chunks and restoration progress in a tm-statesync
+	// directory within this directory, such that an interrupted state sync can be
+	// resumed after a restart, and remove it when the sync is complete.
 	TempDir string `mapstructure:"temp-dir"`
 
 	// The timeout duration before re-requesting a chunk, possibly from a different
-	// peer (default: 15 seconds).
+	// peer (default: 15 seconds). Chunks are also requested from a second peer if
+	// the first is slow to respond, after at most half this timeout.
 	ChunkRequestTimeout time.Duration `mapstructure:"chunk-request-timeout"`
 
 	// The number of concurrent chunk and block fetchers to run (default: 4).
 	Fetchers int32 `mapstructure:"fetchers"`
+
+	// The height to backfill full blocks and ABCI results to once the node is
+	// live, e.g. after state sync: either "genesis", a height, or empty to
+	// disable. Both are fetched from peers, and the results are indexed.
+	BackfillTarget string `mapstructure:"backfill-target"`
+
+	// Directory with a local snapshot to restore instead of discovering
+	// snapshots from peers, e.g. for air-gapped nodes. The snapshot is verified
+	// against the trust height and hash using the light blocks in the
+	// directory, and no network access is needed.
+	SnapshotDir string `mapstructure:"snapshot-dir"`
+
+	// Take a snapshot of the last block-snapshot-blocks blocks in the block
+	// store every block-snapshot-interval heights, and serve the most recent
+	// ones to peers, such that state synced nodes can download recent block
+	// history in large chunks. 0 (default) disables block snapshots.
+	BlockSnapshotInterval int64 `mapstructure:"block-snapshot-interval"`
+
+	// The number of blocks in each block snapshot (default: 1000).
+	BlockSnapshotBlocks int64 `mapstructure:"block-snapshot-blocks"`
+
+	// After state sync, download the blocks below the restored height from a
+	// block snapshot served by peers, if any, and verify them against the
+	// restored block ID.
+	FetchBlockSnapshot bool `mapstructure:"fetch-block-snapshot"`
 }
 
 func (cfg *StateSyncConfig) TrustHashBytes() []byte {
@@ -840,6 +1185,23 @@ func (cfg *StateSyncConfig) TrustHashBytes() []byte {
 	return bytes
 }
 
+// BackfillHeight returns the height to backfill full blocks to, given the
+// initial height of the chain, or 0 if backfilling is disabled.
+func (cfg *StateSyncConfig) BackfillHeight(initialHeight int64) int64 {
+	switch cfg.BackfillTarget {
+	case "":
+		return 0
+	case "genesis":
+		return initialHeight
+	}
+	// validated in ValidateBasic, so we can safely ignore the error here
+	height, _ := strconv.ParseInt(cfg.BackfillTarget, 10, 64)
+	if height < initialHeight {
+		return initialHeight
+	}
+	return height
+}
+
 // DefaultStateSyncConfig returns a default configuration for the state sync service
 func DefaultStateSyncConfig() *StateSyncConfig {
 	return &StateSyncConfig{
@@ -847,6 +1209,7 @@ func DefaultStateSyncConfig() *StateSyncConfig {
 		DiscoveryTime:       15 * time.Second,
 		ChunkRequestTimeout: 15 * time.Second,
 		Fetchers:            4,
+		BlockSnapshotBlocks: 1000,
 	}
 }
 
@@ -857,13 +1220,28 @@ func TestStateSyncConfig() *StateSyncConfig {
 
 // ValidateBasic performs basic validation.
 func (cfg *StateSyncConfig) ValidateBasic() error {
+	if cfg.BackfillTarget != "" && cfg.BackfillTarget != "genesis" {
+		height, err := strconv.ParseInt(cfg.BackfillTarget, 10, 64)
+		if err != nil || height <= 0 {
+			return errors.New("backfill-target must be empty, genesis, or a positive height")
+		}
+	}
+
+	if cfg.BlockSnapshotInterval < 0 {
+		return errors.New("block-snapshot-interval can't be negative")
+	}
+
+	if cfg.BlockSnapshotInterval > 0 && cfg.BlockSnapshotBlocks <= 0 {
+		return errors.New("block-snapshot-blocks must be positive")
+	}
+
 	if !cfg.Enable {
 		return nil
 	}
 
-	// If we're not using the P2P stack then we need to validate the
-	// RPCServers
-	if !cfg.UseP2P {
+	// If we're neither restoring a local snapshot nor using the P2P stack
+	// then we need to validate the RPCServers
+	if cfg.SnapshotDir == "" && !cfg.UseP2P {
 		if len(cfg.RPCServers) < 2 {
 			return errors.New("at least two rpc-servers must be specified")
 		}
@@ -61,6 +61,31 @@ func TestBaseConfigValidateBasic(t *testing.T) {
 	assert.Error(t, cfg.ValidateBasic())
 }
 
+func TestBaseConfigTrustedCheckpoint(t *testing.T) {
+	cfg := TestBaseConfig()
+	height, hash, err := cfg.TrustedCheckpointParams()
+	require.NoError(t, err)
+	assert.Zero(t, height)
+	assert.Nil(t, hash)
+
+	cfg.TrustedCheckpoint = "1000:8A7B40E2B6AD4A18B3B3E3E3D8B2B2BAF6C2E6E2D4AD2E6A3B6F0E6C8C5F3A21"
+	height, hash, err = cfg.TrustedCheckpointParams()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1000, height)
+	assert.Len(t, hash, 32)
+	assert.NoError(t, cfg.ValidateBasic())
+
+	for _, checkpoint := range []string{
+		"1000",
+		"0:8A7B40E2B6AD4A18B3B3E3E3D8B2B2BAF6C2E6E2D4AD2E6A3B6F0E6C8C5F3A21",
+		"1000:8A7B40",
+		"1000:not-hex",
+	} {
+		cfg.TrustedCheckpoint = checkpoint
+		assert.Error(t, cfg.ValidateBasic(), checkpoint)
+	}
+}
+
 func TestRPCConfigValidateBasic(t *testing.T) {
 	cfg := TestRPCConfig()
 	assert.NoError(t, cfg.ValidateBasic())
@@ -81,6 +106,37 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 	}
 }
 
+func TestABCIConnConfigValidateBasic(t *testing.T) {
+	cfg := TestABCIConnConfig()
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.QueryConnections = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.QueryConnections = 1
+
+	cfg.MaxAbandonedCalls = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxAbandonedCalls = 1
+
+	fieldsToTest := []string{
+		"QueryTimeout",
+		"CheckTxTimeout",
+		"SnapshotTimeout",
+		"BreakerThreshold",
+	}
+
+	for _, fieldName := range fieldsToTest {
+		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(-1)
+		assert.Error(t, cfg.ValidateBasic())
+		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
+	}
+
+	// an enabled breaker needs a cooldown
+	cfg.BreakerThreshold = 3
+	cfg.BreakerCooldown = 0
+	assert.Error(t, cfg.ValidateBasic())
+}
+
 func TestMempoolConfigValidateBasic(t *testing.T) {
 	cfg := TestMempoolConfig()
 	assert.NoError(t, cfg.ValidateBasic())
@@ -102,6 +158,33 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 func TestStateSyncConfigValidateBasic(t *testing.T) {
 	cfg := TestStateSyncConfig()
 	require.NoError(t, cfg.ValidateBasic())
+
+	for target, valid := range map[string]bool{"": true, "genesis": true, "100": true, "0": false, "first": false} {
+		cfg.BackfillTarget = target
+		require.Equal(t, valid, cfg.ValidateBasic() == nil, target)
+	}
+	cfg.BackfillTarget = "genesis"
+	require.EqualValues(t, 5, cfg.BackfillHeight(5))
+	cfg.BackfillTarget = "100"
+	require.EqualValues(t, 100, cfg.BackfillHeight(5))
+	cfg.BackfillTarget = ""
+	require.EqualValues(t, 0, cfg.BackfillHeight(5))
+
+	// RPC servers aren't needed to restore a local snapshot.
+	cfg.Enable = true
+	cfg.TrustHeight = 1
+	cfg.TrustHash = "0A0B"
+	cfg.RPCServers = nil
+	require.Error(t, cfg.ValidateBasic())
+	cfg.SnapshotDir = "snapshot"
+	require.NoError(t, cfg.ValidateBasic())
+
+	cfg.BlockSnapshotInterval = 100
+	require.NoError(t, cfg.ValidateBasic())
+	cfg.BlockSnapshotBlocks = 0
+	require.Error(t, cfg.ValidateBasic())
+	cfg.BlockSnapshotInterval = -1
+	require.Error(t, cfg.ValidateBasic())
 }
 
 func TestConsensusConfig_ValidateBasic(t *testing.T) {
@@ -163,6 +246,7 @@ func TestP2PConfigValidateBasic(t *testing.T) {
 		"MaxPacketMsgPayloadSize",
 		"SendRate",
 		"RecvRate",
+		"ReputationHalfLife",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -170,4 +254,63 @@ func TestP2PConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.HandshakeProtocol = "tls"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.HandshakeProtocol = "sts"
+
+	cfg.ProxyOnly = true
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Proxy = "127.0.0.1:9050"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Proxy = "socks5://127.0.0.1:9050"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.QUICListenAddress = "0.0.0.0:26656"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.QUICListenAddress = ""
+
+	cfg.CaptureChannels = "0x30,foo"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CaptureChannels = "0x30, 64"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.CapturePeers = "foo"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.CapturePeers = ""
+
+	cfg.CrawlInterval = -1
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestParseChannelRates(t *testing.T) {
+	rates, err := ParseChannelRates("")
+	require.NoError(t, err)
+	assert.Empty(t, rates)
+
+	rates, err = ParseChannelRates("0x30=1048576, 32=100")
+	require.NoError(t, err)
+	assert.Equal(t, map[uint16]int64{0x30: 1048576, 32: 100}, rates)
+
+	for _, s := range []string{"0x30", "0x30=0", "0x30=-1", "foo=1", "0x30=1MB", "0x10000=1"} {
+		_, err := ParseChannelRates(s)
+		assert.Error(t, err, s)
+	}
+
+	cfg := TestP2PConfig()
+	cfg.ChannelSendRates = "0x30"
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestParseChannelIDs(t *testing.T) {
+	ids, err := ParseChannelIDs("")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	ids, err = ParseChannelIDs("0x30, 32")
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{0x30, 32}, ids)
+
+	for _, s := range []string{"foo", "0x10000", "-1"} {
+		_, err := ParseChannelIDs(s)
+		assert.Error(t, err, s)
+	}
 }
@@ -140,13 +140,25 @@ genesis-file = "{{ js .BaseConfig.Genesis }}"
 # Path to the JSON file containing the private key to use for node authentication in the p2p protocol
 node-key-file = "{{ js .BaseConfig.NodeKey }}"
 
-# Mechanism to connect to the ABCI application: socket | grpc
+# Mechanism to connect to the ABCI application: socket | shm | grpc
 abci = "{{ .BaseConfig.ABCI }}"
 
 # If true, query the ABCI app on connecting to a new peer
 # so the app can decide if we should keep the connection or not
 filter-peers = {{ .BaseConfig.FilterPeers }}
 
+# Path to a block archive written by the export-blocks command. If set, block sync
+# applies the blocks in the archive before fetching the remaining blocks from peers,
+# e.g. to rebuild a node from cold storage.
+block-archive = "{{ js .BaseConfig.BlockArchive }}"
+
+# A trusted block hash and its height, formatted as "height:hash". If set, block
+# sync verifies the blocks below this height by their hash chain from the trusted
+# block, which it walks backwards from peers before syncing, instead of verifying
+# the signatures of each commit. This speeds up replaying a long history, but the
+# checkpoint must be obtained from a trusted source.
+trusted-checkpoint = "{{ js .BaseConfig.TrustedCheckpoint }}"
+
 
 #######################################################
 ###       Priv Validator Configuration              ###
@@ -249,6 +261,56 @@ tls-key-file = "{{ .RPC.TLSKeyFile }}"
 # pprof listen address (https://golang.org/pkg/net/http/pprof)
 pprof-laddr = "{{ .RPC.PprofListenAddress }}"
 
+#######################################################
+###     ABCI Connection Configuration Options       ###
+#######################################################
+[abci-conn]
+
+# Number of connections used for Echo, Info and Query calls. Calls are spread
+# over the connections, so that a slow query does not hold up the others.
+# The application must support concurrent query connections.
+# Builtin applications always use a single query connection, since their
+# calls are serialized by one lock, except for Query calls to the
+# merklestore application, which run concurrently anyway.
+query-connections = {{ .ABCIConn.QueryConnections }}
+
+# Run Query calls to a builtin application without the lock serializing its
+# other calls, so that queries are served concurrently with each other and
+# with block execution. Only enable this if the application is safe for such
+# concurrent use.
+concurrent-local-queries = {{ .ABCIConn.ConcurrentLocalQueries }}
+
+# Maximum duration of an Echo, Info or Query call on the query connection.
+# 0 disables the timeout.
+query-timeout = "{{ .ABCIConn.QueryTimeout }}"
+
+# Maximum duration of a synchronous CheckTx call on the mempool connection
+# (e.g. from the /check_tx RPC endpoint). 0 disables the timeout.
+check-tx-timeout = "{{ .ABCIConn.CheckTxTimeout }}"
+
+# Maximum duration of a call on the snapshot connection. 0 disables the
+# timeout, which is the default since loading and applying a large snapshot
+# chunk may take arbitrarily long.
+snapshot-timeout = "{{ .ABCIConn.SnapshotTimeout }}"
+
+# Maximum number of timed out calls a connection waits on in the background,
+# since a timed out call keeps running until the application returns. Further
+# calls fail immediately until one of them returns. This should not be below
+# breaker-threshold, otherwise the application may be considered unresponsive
+# before the breaker opens.
+max-abandoned-calls = {{ .ABCIConn.MaxAbandonedCalls }}
+
+# Number of consecutive timed out calls after which a connection is considered
+# unresponsive and further calls fail immediately instead of waiting on the
+# application. Only the query, mempool and snapshot connections are affected.
+# Since only timed out calls count, the breaker never opens on a connection
+# whose timeout is disabled. 0 disables the circuit breaker.
+breaker-threshold = {{ .ABCIConn.BreakerThreshold }}
+
+# Time an unresponsive connection rejects calls before a single probe call is
+# let through to check whether the application recovered.
+breaker-cooldown = "{{ .ABCIConn.BreakerCooldown }}"
+
 #######################################################
 ###           P2P Configuration Options             ###
 #######################################################
@@ -260,6 +322,14 @@ queue-type = "{{ .P2P.QueueType }}"
 # Address to listen for incoming connections
 laddr = "{{ .P2P.ListenAddress }}"
 
+# Address to listen for incoming QUIC connections, in addition to laddr.
+# QUIC carries each channel on its own stream, so that e.g. mempool traffic
+# does not delay consensus messages. Peers are dialed over QUIC if their
+# address uses the quic:// scheme, e.g. quic://<id>@10.0.0.1:26656.
+# Leave empty to disable QUIC.
+# example: "0.0.0.0:26656"
+quic-laddr = "{{ .P2P.QUICListenAddress }}"
+
 # Address to advertise to peers for them to dial
 # If empty, will use the same port as the laddr,
 # and will introspect on the listener or use UPnP
@@ -267,6 +337,15 @@ laddr = "{{ .P2P.ListenAddress }}"
 # example: 159.89.10.97:26656
 external-address = "{{ .P2P.ExternalAddress }}"
 
+# SOCKS5 proxy to dial peers through, e.g. "socks5://127.0.0.1:9050" for a
+# local Tor daemon. A proxy is required to dial .onion addresses, which can be
+# used in peer lists and are exchanged via PEX. Leave empty to dial directly.
+proxy = "{{ .P2P.Proxy }}"
+
+# Only dial peers through the proxy. Hostnames are then resolved by the proxy
+# rather than locally, and quic-laddr, which can't be proxied, must be empty.
+proxy-only = {{ .P2P.ProxyOnly }}
+
 # Comma separated list of seed nodes to connect to
 # We only use these if we can’t connect to peers in the addrbook
 # NOTE: not used by the new PEX reactor. Please use BootstrapPeers instead.
@@ -294,6 +373,10 @@ max-incoming-connection-attempts = {{ .P2P.MaxIncomingConnectionAttempts }}
 # Set true to enable the peer-exchange reactor
 pex = {{ .P2P.PexReactor }}
 
+# Interval between network crawls on seed nodes. The last crawl is served by
+# the net_crawl RPC route. 0 disables crawling.
+crawl-interval = "{{ .P2P.CrawlInterval }}"
+
 # Comma separated list of peer IDs to keep private (will not be gossiped to other peers)
 # Warning: IPs will be exposed at /net_info, for more information https://github.com/tendermint/tendermint/issues/3055
 private-peer-ids = "{{ .P2P.PrivatePeerIDs }}"
@@ -305,6 +388,13 @@ allow-duplicate-ip = {{ .P2P.AllowDuplicateIP }}
 handshake-timeout = "{{ .P2P.HandshakeTimeout }}"
 dial-timeout = "{{ .P2P.DialTimeout }}"
 
+# Handshake used to secure connections dialed by this node:
+# 1) "sts" (default) - the station-to-station secret connection
+# 2) "noise" - the Noise XX handshake, with the static key signed by the node key
+# Inbound connections use whichever handshake the dialing peer selects, so
+# nodes using different handshakes can connect to each other.
+handshake-protocol = "{{ .P2P.HandshakeProtocol }}"
+
 # Time to wait before flushing messages out on the connection
 # TODO: Remove once MConnConnection is removed.
 flush-throttle-timeout = "{{ .P2P.FlushThrottleTimeout }}"
@@ -321,6 +411,35 @@ send-rate = {{ .P2P.SendRate }}
 # TODO: Remove once MConnConnection is removed.
 recv-rate = {{ .P2P.RecvRate }}
 
+# Per-channel rate limits for each peer, in bytes/second, as a comma separated
+# list of <channel ID>=<rate> pairs. For example, "0x30=1048576" limits mempool
+# gossip to 1MB/s per peer. Messages over the send rate are held back without
+# delaying other channels, and messages over the receive rate are dropped, so
+# receive limits should only be set for channels that tolerate lost messages.
+channel-send-rates = "{{ .P2P.ChannelSendRates }}"
+channel-recv-rates = "{{ .P2P.ChannelRecvRates }}"
+
+# Reactors lower the reputation of misbehaving peers (e.g. ones sending invalid
+# blocks or votes), and raise it for useful ones. Peers with a low reputation
+# are dialed last and redialed with a backoff, and are banned for
+# reputation-ban-duration once their reputation drops to
+# reputation-ban-threshold (0 disables bans). Reputations are persisted, and
+# decay to half their value every reputation-half-life.
+reputation-half-life = "{{ .P2P.ReputationHalfLife }}"
+reputation-ban-threshold = {{ .P2P.ReputationBanThreshold }}
+reputation-ban-duration = "{{ .P2P.ReputationBanDuration }}"
+
+# Capture messages sent and received by the router to a file, for debugging
+# problems caused by peer traffic. Captures can be inspected with
+# scripts/capture2json and replayed into a reactor with p2p.Replayer. Files
+# are rotated like the consensus WAL. Empty disables capturing.
+capture-file = "{{ js .P2P.CapturePath }}"
+
+# Comma separated lists of channel IDs (e.g. "0x30,0x40") and peer IDs to
+# capture. Empty captures all channels or peers.
+capture-channels = "{{ .P2P.CaptureChannels }}"
+capture-peers = "{{ .P2P.CapturePeers }}"
+
 
 #######################################################
 ###          Mempool Configuration Option          ###
@@ -403,18 +522,45 @@ trust-period = "{{ .StateSync.TrustPeriod }}"
 # Time to spend discovering snapshots before initiating a restore.
 discovery-time = "{{ .StateSync.DiscoveryTime }}"
 
-# Temporary directory for state sync snapshot chunks, defaults to os.TempDir().
-# The synchronizer will create a new, randomly named directory within this directory
-# and remove it when the sync is complete.
+# Directory for state sync snapshot chunks, defaults to the data directory.
+# The synchronizer will store chunks and restoration progress in a tm-statesync
+# directory within this directory, such that an interrupted state sync can be
+# resumed after a restart, and remove it when the sync is complete.
 temp-dir = "{{ .StateSync.TempDir }}"
 
 # The timeout duration before re-requesting a chunk, possibly from a different
-# peer (default: 15 seconds).
+# peer (default: 15 seconds). Chunks are also requested from a second peer if
+# the first is slow to respond, after at most half this timeout.
 chunk-request-timeout = "{{ .StateSync.ChunkRequestTimeout }}"
 
 # The number of concurrent chunk and block fetchers to run (default: 4).
 fetchers = "{{ .StateSync.Fetchers }}"
 
+# The height to backfill full blocks and ABCI results to in the background once
+# the node is live, e.g. after state sync: either "genesis", a height, or empty
+# to disable. Both are fetched from peers and verified against the stored
+# headers, and the results are indexed.
+backfill-target = "{{ .StateSync.BackfillTarget }}"
+
+# Directory with a local snapshot to restore instead of discovering snapshots
+# from peers, e.g. for air-gapped nodes. The snapshot is verified against the
+# trust height and hash using the light blocks in the directory, and the
+# rpc-servers are not needed.
+snapshot-dir = "{{ js .StateSync.SnapshotDir }}"
+
+# Take a snapshot of the last block-snapshot-blocks blocks in the block store
+# every block-snapshot-interval heights, and serve the most recent ones to
+# peers, such that state synced nodes can download recent block history in
+# large chunks. 0 disables block snapshots.
+block-snapshot-interval = {{ .StateSync.BlockSnapshotInterval }}
+
+# The number of blocks in each block snapshot.
+block-snapshot-blocks = {{ .StateSync.BlockSnapshotBlocks }}
+
+# After state sync, download the blocks below the restored height from a block
+# snapshot served by peers, if any, and verify them against the restored block.
+fetch-block-snapshot = {{ .StateSync.FetchBlockSnapshot }}
+
 #######################################################
 ###         Consensus Configuration Options         ###
 #######################################################
@@ -0,0 +1,153 @@
+package blocksync
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	abciclient "github.com/tendermint/tendermint/abci/client"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/consensus"
+	"github.com/tendermint/tendermint/internal/libs/protoio"
+	"github.com/tendermint/tendermint/internal/mempool/mock"
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/internal/proxy"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/store"
+	"github.com/tendermint/tendermint/internal/test/factory"
+	"github.com/tendermint/tendermint/libs/log"
+	bcproto "github.com/tendermint/tendermint/proto/tendermint/blocksync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// makeArchiveTarget returns the genesis state, block executor and empty block
+// store of a node to import blocks into.
+func makeArchiveTarget(
+	ctx context.Context,
+	t *testing.T,
+	genDoc *types.GenesisDoc,
+) (sm.State, *sm.BlockExecutor, *store.BlockStore) {
+	t.Helper()
+
+	app := proxy.NewAppConns(abciclient.NewLocalCreator(&abci.BaseApplication{}), log.TestingLogger(), proxy.NopMetrics())
+	require.NoError(t, app.Start(ctx))
+
+	stateStore := sm.NewStore(dbm.NewMemDB())
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	state, err := sm.MakeGenesisState(genDoc)
+	require.NoError(t, err)
+	require.NoError(t, stateStore.Save(state))
+
+	blockExec := sm.NewBlockExecutor(
+		stateStore, log.TestingLogger(), app.Consensus(), mock.Mempool{}, sm.EmptyEvidencePool{}, blockStore)
+	return state, blockExec, blockStore
+}
+
+func TestImportBlocks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_archive_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{20}, 0)
+	source := rts.reactors[rts.nodes[0]].store
+
+	// The commit of the latest block isn't in the source store, since the test
+	// setup saves the last commit as the seen commit.
+	_, err = ExportBlocks(&bytes.Buffer{}, source, 1, 20)
+	require.Error(t, err)
+
+	archive := &bytes.Buffer{}
+	n, err := ExportBlocks(archive, source, 1, 19)
+	require.NoError(t, err)
+	require.EqualValues(t, 19, n)
+	bz := archive.Bytes()
+
+	state, blockExec, blockStore := makeArchiveTarget(ctx, t, genDoc)
+
+	// An archive starting above the next height can't be applied.
+	partial := &bytes.Buffer{}
+	_, err = ExportBlocks(partial, source, 5, 10)
+	require.NoError(t, err)
+	_, applied, err := ImportBlocks(ctx, state, blockExec, blockStore, NewArchiveReader(partial))
+	require.Error(t, err)
+	require.Zero(t, applied)
+
+	// An archive with a forged commit is applied up to the forged commit.
+	forged := &bytes.Buffer{}
+	reader := NewArchiveReader(bytes.NewReader(bz))
+	for height := int64(1); height <= 19; height++ {
+		block, commit, err := reader.Next()
+		require.NoError(t, err)
+		if height == 10 {
+			commit.Signatures[0].Signature = make([]byte, len(commit.Signatures[0].Signature))
+		}
+		blockProto, err := block.ToProto()
+		require.NoError(t, err)
+		writer := protoio.NewDelimitedWriter(forged)
+		_, err = writer.WriteMsg(blockProto)
+		require.NoError(t, err)
+		_, err = writer.WriteMsg(commit.ToProto())
+		require.NoError(t, err)
+	}
+	state, applied, err = ImportBlocks(ctx, state, blockExec, blockStore, NewArchiveReader(forged))
+	require.Error(t, err)
+	require.EqualValues(t, 9, applied)
+	require.EqualValues(t, 9, state.LastBlockHeight)
+	require.EqualValues(t, 9, blockStore.Height())
+
+	// The import is resumed from the valid archive, skipping applied blocks.
+	state, applied, err = ImportBlocks(ctx, state, blockExec, blockStore, NewArchiveReader(bytes.NewReader(bz)))
+	require.NoError(t, err)
+	require.EqualValues(t, 10, applied)
+	require.EqualValues(t, 19, state.LastBlockHeight)
+	for height := int64(1); height <= 19; height++ {
+		require.Equal(t, source.LoadBlockMeta(height).BlockID, blockStore.LoadBlockMeta(height).BlockID)
+	}
+}
+
+func TestReactor_Archive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_archive_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{20}, 0)
+
+	path := filepath.Join(cfg.RootDir, "blocks.bin")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	_, err = ExportBlocks(file, rts.reactors[rts.nodes[0]].store, 1, 19)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	// Without any peers, the blocks in the archive are applied before
+	// fetching the remaining blocks from peers.
+	state, blockExec, blockStore := makeArchiveTarget(ctx, t, genDoc)
+	ch := p2p.NewChannel(BlockSyncChannel, new(bcproto.Message),
+		make(chan p2p.Envelope), make(chan p2p.Envelope, 100), make(chan p2p.PeerError, 100))
+	reactor, err := NewReactor(log.TestingLogger(), state, blockExec, blockStore, nil,
+		ch, p2p.NewPeerUpdates(make(chan p2p.PeerUpdate), 1), true, consensus.NopMetrics())
+	require.NoError(t, err)
+	reactor.SetArchive(path)
+	require.NoError(t, reactor.Start(ctx))
+
+	require.Eventually(t, func() bool {
+		height, _, _ := reactor.pool.GetStatus()
+		return height == 20
+	}, 10*time.Second, 10*time.Millisecond)
+	require.EqualValues(t, 19, blockStore.Height())
+}
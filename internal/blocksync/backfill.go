@@ -0,0 +1,575 @@
+package blocksync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/internal/p2p"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/state/indexer"
+	"github.com/tendermint/tendermint/internal/store"
+	"github.com/tendermint/tendermint/libs/log"
+	bcproto "github.com/tendermint/tendermint/proto/tendermint/blocksync"
+	tmstate "github.com/tendermint/tendermint/proto/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// number of blocks requested concurrently while backfilling
+	backfillWindow = 20
+
+	// re-request a block or its results from another peer after this duration
+	backfillRequestTimeout = 15 * time.Second
+
+	// number of peers asked for the results of a block before it's
+	// backfilled without them
+	backfillResultsAttempts = 3
+
+	// check for timed out requests and new peers this often
+	backfillTickInterval = time.Second
+)
+
+// BackfillOptions configures backfilling of historical blocks, see
+// Reactor.SetBackfill.
+type BackfillOptions struct {
+	// TargetHeight is the lowest height to backfill, e.g. the initial height
+	// to backfill to genesis.
+	TargetHeight int64
+
+	// StateStore stores the ABCI results of backfilled blocks, which are
+	// fetched from the peers that send the blocks. If nil, only blocks are
+	// backfilled.
+	StateStore sm.Store
+
+	// EventSinks index the transactions of backfilled blocks, if results are
+	// available.
+	EventSinks []indexer.EventSink
+}
+
+// backfillPeer is the block range reported by a peer, and whether it serves
+// block results and headers.
+type backfillPeer struct {
+	base, height int64
+	results      bool
+	headers      bool
+}
+
+// backfillRequest is an outstanding block request, along with the request
+// for its results if they are backfilled. The results are requested
+// separately, since they may come from another peer than the block.
+type backfillRequest struct {
+	peerID types.NodeID
+	sent   time.Time    // zero if the request must be resent
+	block  *types.Block // nil until received
+
+	resultsPeerID   types.NodeID
+	resultsSent     time.Time
+	resultsAttempts int
+	results         *tmstate.ABCIResponses // nil until received
+	resultsDone     bool                   // the results were received, or given up on
+}
+
+// backfiller fetches full blocks from peers in reverse order, below the
+// lowest full block in the block store, and verifies each of them against the
+// stored header at its height (as saved by state sync backfill) or the last
+// block ID of the block above it. It runs in the background once the node is
+// live, until the target height has been reached, such that a state synced
+// node eventually serves the full block history.
+//
+// The ABCI results of each block are fetched from peers that serve them. The
+// DeliverTx responses are verified against the results hash of the header
+// above, which only covers their deterministic fields, and are saved in full
+// along with the events of BeginBlock and EndBlock, so that the block and its
+// transactions are indexed as if the block had been executed. The remaining
+// BeginBlock and EndBlock fields, i.e. validator and consensus parameter
+// updates, are not fetched.
+type backfiller struct {
+	logger  log.Logger
+	store   *store.BlockStore
+	options BackfillOptions
+
+	// start returns the height to fetch first, and process verifies and
+	// saves a fetched block and its results, if any. They are nextHeight and
+	// backfillBlock, except when walking the hash chain of a trusted
+	// checkpoint.
+	start   func() int64
+	process func(context.Context, *types.Block, *tmstate.ABCIResponses) error
+
+	// headers makes the backfiller fetch headers instead of full blocks from
+	// the peers which serve them, when walking a hash chain. The blocks
+	// passed to process then only carry their header.
+	headers bool
+
+	mtx      sync.Mutex
+	peers    map[types.NodeID]backfillPeer
+	requests map[int64]*backfillRequest
+	wakeCh   chan struct{} // signals that a block has arrived or a request must be resent
+}
+
+func newBackfiller(logger log.Logger, store *store.BlockStore, options BackfillOptions) *backfiller {
+	b := &backfiller{
+		logger:   logger,
+		store:    store,
+		options:  options,
+		peers:    make(map[types.NodeID]backfillPeer),
+		requests: make(map[int64]*backfillRequest),
+		wakeCh:   make(chan struct{}, 1),
+	}
+	b.start = b.nextHeight
+	b.process = b.backfillBlock
+	return b
+}
+
+// setPeerStatus records the block range reported by a peer, and whether it
+// serves block results and headers.
+func (b *backfiller) setPeerStatus(peerID types.NodeID, status *bcproto.StatusResponse) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.peers[peerID] = backfillPeer{
+		base:    status.Base,
+		height:  status.Height,
+		results: status.BlockResults,
+		headers: status.Headers,
+	}
+}
+
+// removePeer removes a peer, rescheduling its outstanding block and results
+// requests.
+func (b *backfiller) removePeer(peerID types.NodeID) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	delete(b.peers, peerID)
+	for _, request := range b.requests {
+		if request.peerID == peerID && request.block == nil {
+			request.sent = time.Time{}
+		}
+		if request.resultsPeerID == peerID && !request.resultsDone {
+			request.resultsSent = time.Time{}
+		}
+	}
+	b.wake()
+}
+
+// addBlock adds a block received from a peer. It returns false if the block
+// wasn't requested by the backfiller, in which case it's for the block pool.
+func (b *backfiller) addBlock(peerID types.NodeID, block *types.Block) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	request, ok := b.requests[block.Height]
+	if !ok || request.peerID != peerID || request.block != nil {
+		return false
+	}
+	request.block = block
+
+	b.wake()
+	return true
+}
+
+// addHeader adds a header received from a peer, as a block without data, see
+// headers. It returns false if the header wasn't requested by the backfiller.
+func (b *backfiller) addHeader(peerID types.NodeID, header *types.Header) bool {
+	return b.addBlock(peerID, &types.Block{Header: *header})
+}
+
+// addResults adds the results of a block received from a peer. It returns
+// false if the results weren't requested by the backfiller.
+func (b *backfiller) addResults(peerID types.NodeID, height int64, results *tmstate.ABCIResponses) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	request, ok := b.requests[height]
+	if !ok || request.resultsPeerID != peerID || request.resultsDone {
+		return false
+	}
+	request.results = results
+	request.resultsDone = true
+
+	b.wake()
+	return true
+}
+
+// noResults handles a peer not having the results of a requested block, e.g.
+// because it discards them. The results are then requested from another peer,
+// until backfillResultsAttempts peers have been asked.
+func (b *backfiller) noResults(peerID types.NodeID, height int64) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if request, ok := b.requests[height]; ok && request.resultsPeerID == peerID && !request.resultsDone {
+		request.resultsSent = time.Time{}
+		b.wake()
+	}
+}
+
+// wake wakes up run, e.g. to process an arrived block or resend a request.
+func (b *backfiller) wake() {
+	select {
+	case b.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// noBlock handles a peer not having a requested block. The peer's base is
+// raised above the height, so that the block is requested from another peer.
+func (b *backfiller) noBlock(peerID types.NodeID, height int64) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if request, ok := b.requests[height]; ok && request.peerID == peerID && request.block == nil {
+		request.sent = time.Time{}
+		b.wake()
+	}
+	if peer, ok := b.peers[peerID]; ok && peer.base <= height {
+		peer.base = height + 1
+		b.peers[peerID] = peer
+	}
+}
+
+// nextHeight returns the height of the next block to backfill, i.e. the
+// highest height below which all blocks are full blocks, or 0 if the block
+// store is empty.
+func (b *backfiller) nextHeight() int64 {
+	base := b.store.Base()
+	if base == 0 {
+		return 0
+	}
+	if meta := b.store.LoadBlockMeta(base); meta.BlockSize >= 0 {
+		return base - 1
+	}
+
+	// State sync backfill saves headers without blocks (with a negative size)
+	// below the restored height, which we fill in from the top.
+	height := base
+	for {
+		meta := b.store.LoadBlockMeta(height + 1)
+		if meta == nil || meta.BlockSize >= 0 {
+			return height
+		}
+		height++
+	}
+}
+
+// run backfills blocks until reaching the target height. Block requests are
+// passed to send, and peers that send invalid blocks to sendError.
+func (b *backfiller) run(
+	ctx context.Context,
+	send func(context.Context, p2p.Envelope) error,
+	sendError func(context.Context, p2p.PeerError) error,
+) {
+	height := b.start()
+	if height < b.options.TargetHeight {
+		b.logger.Debug("no blocks to backfill", "height", height, "target", b.options.TargetHeight)
+		return
+	}
+	b.logger.Info("backfilling blocks", "height", height, "target", b.options.TargetHeight)
+
+	var (
+		ticker       = time.NewTicker(backfillTickInterval)
+		statusTicker = time.NewTicker(statusUpdateIntervalSeconds * time.Second)
+		backfilled   = 0
+		lastHundred  = time.Now()
+	)
+	defer ticker.Stop()
+	defer statusTicker.Stop()
+
+	for height >= b.options.TargetHeight {
+		// Block sync only polls peer ranges while syncing, so we keep them
+		// up to date ourselves.
+		select {
+		case <-statusTicker.C:
+			if err := send(ctx, p2p.Envelope{Broadcast: true, Message: &bcproto.StatusRequest{}}); err != nil {
+				return
+			}
+		default:
+		}
+
+		for _, envelope := range b.schedule(height) {
+			if err := send(ctx, envelope); err != nil {
+				return
+			}
+		}
+
+		block, results, peerID := b.popBlock(height)
+		if block == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.wakeCh:
+			case <-ticker.C:
+			}
+			continue
+		}
+
+		err := b.process(ctx, block, results)
+		switch {
+		case errors.Is(err, errInvalidBackfillBlock):
+			b.logger.Error("received invalid block while backfilling", "height", height, "peer", peerID, "err", err)
+			if serr := sendError(ctx, p2p.PeerError{
+				NodeID:      peerID,
+				Err:         err,
+				Misbehavior: p2p.MisbehaviorInvalidBlock,
+			}); serr != nil {
+				return
+			}
+			continue
+
+		case errors.Is(err, errInvalidBackfillResults):
+			b.logger.Error("received invalid results while backfilling", "height", height, "peer", peerID, "err", err)
+			if serr := sendError(ctx, p2p.PeerError{
+				NodeID:      peerID,
+				Err:         err,
+				Misbehavior: p2p.MisbehaviorInvalidMessage,
+			}); serr != nil {
+				return
+			}
+			continue
+
+		case errors.Is(err, errNoBackfillHeader):
+			// Retrying won't help, e.g. if the store was pruned meanwhile.
+			b.logger.Error("stopping backfill", "height", height, "err", err)
+			return
+
+		case err != nil:
+			b.logger.Error("failed to backfill block", "height", height, "err", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			continue
+		}
+
+		height--
+		backfilled++
+		if backfilled%100 == 0 {
+			b.logger.Info("backfill rate", "height", height,
+				"blocks/s", 100/time.Since(lastHundred).Seconds())
+			lastHundred = time.Now()
+		}
+	}
+
+	b.logger.Info("backfill complete", "height", b.options.TargetHeight)
+}
+
+// schedule returns block and results requests for the heights in the backfill
+// window below height that are not already requested, or whose requests timed
+// out. Each request is sent to a random peer that has reported having the
+// block, and results requests only to peers that serve them. If headers is
+// set, headers are requested instead of blocks from peers that serve them.
+func (b *backfiller) schedule(height int64) []p2p.Envelope {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	envelopes := []p2p.Envelope{}
+	for h := height; h > height-backfillWindow && h >= b.options.TargetHeight; h-- {
+		request, exists := b.requests[h]
+		if !exists {
+			request = &backfillRequest{}
+		}
+
+		if request.block == nil && time.Since(request.sent) >= backfillRequestTimeout {
+			// Peers which serve headers are preferred when fetching them.
+			headers := func(peer backfillPeer) bool { return b.headers && peer.headers }
+			peerID, ok := b.pickPeer(h, headers, request.peerID)
+			if !ok {
+				peerID, ok = b.pickPeer(h, nil, request.peerID)
+			}
+			if ok {
+				request.peerID = peerID
+				request.sent = time.Now()
+				var msg proto.Message = &bcproto.BlockRequest{Height: h}
+				if headers(b.peers[peerID]) {
+					msg = &bcproto.HeaderRequest{Height: h}
+				}
+				envelopes = append(envelopes, p2p.Envelope{To: peerID, Message: msg})
+			}
+		}
+
+		if b.options.StateStore != nil && !request.resultsDone &&
+			time.Since(request.resultsSent) >= backfillRequestTimeout {
+			peerID, ok := b.pickPeer(h, func(peer backfillPeer) bool { return peer.results }, request.resultsPeerID)
+			switch {
+			case request.resultsAttempts >= backfillResultsAttempts || (!ok && request.block != nil):
+				// Don't hold up the backfill indefinitely, the block is
+				// still useful without its results.
+				b.logger.Error("failed to fetch block results, backfilling block without them",
+					"height", h, "attempts", request.resultsAttempts)
+				request.resultsDone = true
+
+			case ok:
+				request.resultsPeerID = peerID
+				request.resultsSent = time.Now()
+				request.resultsAttempts++
+				envelopes = append(envelopes, p2p.Envelope{
+					To:      peerID,
+					Message: &bcproto.BlockResultsRequest{Height: h},
+				})
+			}
+		}
+
+		if exists || request.peerID != "" || request.resultsPeerID != "" {
+			b.requests[h] = request
+		}
+	}
+	return envelopes
+}
+
+// pickPeer returns a random peer that has reported having the block at the
+// given height and, if filter is given, passes it. The previous peer is
+// avoided if there are others.
+func (b *backfiller) pickPeer(
+	height int64,
+	filter func(backfillPeer) bool,
+	previous types.NodeID,
+) (types.NodeID, bool) {
+	candidates := []types.NodeID{}
+	for peerID, peer := range b.peers {
+		if peer.base <= height && height <= peer.height && (filter == nil || filter(peer)) {
+			candidates = append(candidates, peerID)
+		}
+	}
+	if len(candidates) > 1 {
+		for i, peerID := range candidates {
+			if peerID == previous {
+				candidates = append(candidates[:i], candidates[i+1:]...)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	return candidates[rand.Intn(len(candidates))], true // nolint:gosec // G404: Use of weak random number generator
+}
+
+// popBlock removes and returns the block at the given height, its results and
+// the peer that sent them, once they have arrived. The results are nil if they
+// aren't backfilled or the peer doesn't have them.
+func (b *backfiller) popBlock(height int64) (*types.Block, *tmstate.ABCIResponses, types.NodeID) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	request, ok := b.requests[height]
+	if !ok || request.block == nil || (b.options.StateStore != nil && !request.resultsDone) {
+		return nil, nil, ""
+	}
+	delete(b.requests, height)
+	return request.block, request.results, request.peerID
+}
+
+var (
+	// errInvalidBackfillBlock is returned by backfillBlock when the block
+	// fails verification.
+	errInvalidBackfillBlock = errors.New("invalid block")
+
+	// errInvalidBackfillResults is returned by backfillBlock when the
+	// results of the block fail verification.
+	errInvalidBackfillResults = errors.New("invalid results")
+
+	// errNoBackfillHeader is returned by backfillBlock when there is no
+	// stored header to verify the block against.
+	errNoBackfillHeader = errors.New("no stored header")
+)
+
+// backfillBlock verifies a block and its results, if any, and saves both.
+// Results are only saved if the block above is stored, since they are
+// verified against its header.
+func (b *backfiller) backfillBlock(_ context.Context, block *types.Block, results *tmstate.ABCIResponses) error {
+	if err := block.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidBackfillBlock, err)
+	}
+
+	// The block must match the stored header at its height, if any, or the
+	// last block ID of the stored block above it.
+	var expectID types.BlockID
+	next := b.store.LoadBlockMeta(block.Height + 1)
+	if meta := b.store.LoadBlockMeta(block.Height); meta != nil {
+		expectID = meta.BlockID
+	} else if next != nil {
+		expectID = next.Header.LastBlockID
+	} else {
+		return fmt.Errorf("%w to verify block %d against", errNoBackfillHeader, block.Height)
+	}
+	parts := block.MakePartSet(types.BlockPartSizeBytes)
+	blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+	if !blockID.Equals(expectID) {
+		return fmt.Errorf("%w: expected block ID %v, got %v", errInvalidBackfillBlock, expectID, blockID)
+	}
+
+	switch {
+	case b.options.StateStore == nil || results == nil:
+	case next == nil:
+		b.logger.Error("no header to verify block results against, backfilling block without them",
+			"height", block.Height)
+	default:
+		if len(results.DeliverTxs) != len(block.Txs) {
+			return fmt.Errorf("%w: expected %d tx results, got %d",
+				errInvalidBackfillResults, len(block.Txs), len(results.DeliverTxs))
+		}
+		if hash := sm.ABCIResponsesResultsHash(results); !bytes.Equal(hash, next.Header.LastResultsHash) {
+			return fmt.Errorf("%w: expected results hash %X, got %X",
+				errInvalidBackfillResults, next.Header.LastResultsHash, hash)
+		}
+		if err := b.options.StateStore.SaveABCIResponses(block.Height, results); err != nil {
+			return fmt.Errorf("failed to save results: %w", err)
+		}
+		if err := b.index(block, results); err != nil {
+			return err
+		}
+	}
+
+	return b.store.SaveHistoricalBlock(block, parts)
+}
+
+// index indexes the events of a block and its transactions in all event
+// sinks, like the reindex-event command.
+func (b *backfiller) index(block *types.Block, results *tmstate.ABCIResponses) error {
+	if len(b.options.EventSinks) == 0 {
+		return nil
+	}
+
+	header := types.EventDataNewBlockHeader{
+		Header: block.Header,
+		NumTxs: int64(len(block.Txs)),
+	}
+	if results.BeginBlock != nil {
+		header.ResultBeginBlock = *results.BeginBlock
+	}
+	if results.EndBlock != nil {
+		header.ResultEndBlock = *results.EndBlock
+	}
+
+	var batch *indexer.Batch
+	if len(block.Txs) > 0 {
+		batch = indexer.NewBatch(int64(len(block.Txs)))
+		for i, tx := range block.Txs {
+			_ = batch.Add(&abci.TxResult{
+				Height: block.Height,
+				Index:  uint32(i),
+				Tx:     tx,
+				Result: *results.DeliverTxs[i],
+			})
+		}
+	}
+
+	for _, sink := range b.options.EventSinks {
+		if err := sink.IndexBlockEvents(header); err != nil {
+			return fmt.Errorf("failed to index block events: %w", err)
+		}
+		if batch != nil {
+			if err := sink.IndexTxEvents(batch.Ops); err != nil {
+				return fmt.Errorf("failed to index tx events: %w", err)
+			}
+		}
+	}
+	return nil
+}
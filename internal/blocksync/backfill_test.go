@@ -0,0 +1,266 @@
+package blocksync
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/p2p"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/state/indexer"
+	"github.com/tendermint/tendermint/internal/state/indexer/sink/kv"
+	"github.com/tendermint/tendermint/internal/store"
+	"github.com/tendermint/tendermint/internal/test/factory"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+	bcproto "github.com/tendermint/tendermint/proto/tendermint/blocksync"
+	tmstate "github.com/tendermint/tendermint/proto/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestBackfiller(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_backfill_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{10}, 0)
+	source := rts.reactors[rts.nodes[0]].store
+
+	// As after state sync, the store has full blocks above height 5 and
+	// headers for heights 3 to 5.
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	for height := int64(6); height <= 10; height++ {
+		block := source.LoadBlock(height)
+		blockStore.SaveBlock(block, block.MakePartSet(types.BlockPartSizeBytes), source.LoadSeenCommit())
+	}
+	for height := int64(5); height >= 3; height-- {
+		meta := source.LoadBlockMeta(height)
+		require.NoError(t, blockStore.SaveSignedHeader(&types.SignedHeader{
+			Header: &meta.Header,
+			Commit: source.LoadBlockCommit(height),
+		}, meta.BlockID))
+	}
+	stateStore := sm.NewStore(dbm.NewMemDB())
+	eventSink := kv.NewEventSink(dbm.NewMemDB())
+
+	backfill := newBackfiller(log.TestingLogger(), blockStore, BackfillOptions{
+		TargetHeight: 2,
+		StateStore:   stateStore,
+		EventSinks:   []indexer.EventSink{eventSink},
+	})
+	require.EqualValues(t, 5, backfill.nextHeight())
+
+	// The peer first sends a block from another height in response to the
+	// request for height 4, and then the right one. It first sends tampered
+	// results for height 3, and doesn't have the results for height 2. The
+	// deterministic fields of the results are empty, as returned by
+	// abci.BaseApplication, but they carry a log and events.
+	peerID := types.NodeID("aa")
+	backfill.setPeerStatus(peerID, &bcproto.StatusResponse{Base: 1, Height: 10, BlockResults: true})
+	tamperedBlock, tamperedResults := false, false
+	noResults := 0
+	send := func(ctx context.Context, envelope p2p.Envelope) error {
+		switch msg := envelope.Message.(type) {
+		case *bcproto.BlockRequest:
+			require.Equal(t, peerID, envelope.To)
+			require.GreaterOrEqual(t, msg.Height, int64(2))
+			block := source.LoadBlock(msg.Height)
+			if msg.Height == 4 && !tamperedBlock {
+				tamperedBlock = true
+				block = source.LoadBlock(7)
+				block.Height = 4
+			}
+			require.True(t, backfill.addBlock(peerID, block))
+
+		case *bcproto.BlockResultsRequest:
+			require.Equal(t, peerID, envelope.To)
+			if msg.Height == 2 {
+				noResults++
+				backfill.noResults(peerID, msg.Height)
+				return nil
+			}
+			results := backfillResults(source.LoadBlock(msg.Height))
+			if msg.Height == 3 && !tamperedResults {
+				tamperedResults = true
+				results.DeliverTxs[0].Code = 1
+			}
+			require.True(t, backfill.addResults(peerID, msg.Height, results))
+		}
+		return nil
+	}
+	peerErrors := []p2p.PeerError{}
+	sendError := func(ctx context.Context, peerError p2p.PeerError) error {
+		peerErrors = append(peerErrors, peerError)
+		return nil
+	}
+	backfill.run(ctx, send, sendError)
+
+	require.Len(t, peerErrors, 2)
+	require.Equal(t, backfillResultsAttempts, noResults)
+	require.Equal(t, peerID, peerErrors[0].NodeID)
+	require.Equal(t, p2p.MisbehaviorInvalidBlock, peerErrors[0].Misbehavior)
+	require.Equal(t, peerID, peerErrors[1].NodeID)
+	require.Equal(t, p2p.MisbehaviorInvalidMessage, peerErrors[1].Misbehavior)
+
+	// Blocks 2 to 10 are now full blocks, with results above height 2.
+	require.EqualValues(t, 2, blockStore.Base())
+	require.EqualValues(t, 1, backfill.nextHeight())
+	for height := int64(2); height <= 10; height++ {
+		require.Equal(t, source.LoadBlock(height), blockStore.LoadBlock(height))
+		require.Equal(t, source.LoadBlockMeta(height), blockStore.LoadBlockMeta(height))
+		if height > 5 {
+			continue
+		}
+		results, err := stateStore.LoadABCIResponses(height)
+		if height == 2 {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, backfillResults(source.LoadBlock(height)), results)
+
+		hasBlock, err := eventSink.HasBlock(height)
+		require.NoError(t, err)
+		require.True(t, hasBlock)
+		for i, tx := range source.LoadBlock(height).Txs {
+			txResult, err := eventSink.GetTxByHash(tx.Hash())
+			require.NoError(t, err)
+			require.EqualValues(t, i, txResult.Index)
+			require.Equal(t, *results.DeliverTxs[i], txResult.Result)
+		}
+	}
+	require.Nil(t, blockStore.LoadBlock(1))
+
+	txResults, err := eventSink.SearchTxEvents(ctx, query.MustCompile("transfer.sender = 'alice'"))
+	require.NoError(t, err)
+	require.NotEmpty(t, txResults)
+}
+
+// backfillResults returns the results of a block with a log and events, as
+// sent by a peer.
+func backfillResults(block *types.Block) *tmstate.ABCIResponses {
+	events := []abci.Event{{
+		Type:       "transfer",
+		Attributes: []abci.EventAttribute{{Key: "sender", Value: "alice", Index: true}},
+	}}
+	results := &tmstate.ABCIResponses{
+		BeginBlock: &abci.ResponseBeginBlock{Events: events},
+		EndBlock:   &abci.ResponseEndBlock{Events: events},
+	}
+	for range block.Txs {
+		results.DeliverTxs = append(results.DeliverTxs, &abci.ResponseDeliverTx{Log: "ok", Events: events})
+	}
+	return results
+}
+
+func TestBackfiller_ResultsPeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_backfill_results_peer_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{6}, 0)
+	source := rts.reactors[rts.nodes[0]].store
+
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	block := source.LoadBlock(6)
+	blockStore.SaveBlock(block, block.MakePartSet(types.BlockPartSizeBytes), source.LoadSeenCommit())
+	stateStore := sm.NewStore(dbm.NewMemDB())
+	backfill := newBackfiller(log.TestingLogger(), blockStore, BackfillOptions{
+		TargetHeight: 2,
+		StateStore:   stateStore,
+	})
+
+	// Peer bb sends blocks but never responds to results requests, and
+	// disconnects as peer aa, which serves results, connects. Peer cc doesn't
+	// serve results.
+	backfill.setPeerStatus("bb", &bcproto.StatusResponse{Base: 1, Height: 6, BlockResults: true})
+	backfill.setPeerStatus("cc", &bcproto.StatusResponse{Base: 1, Height: 6})
+	dropped := 0
+	send := func(ctx context.Context, envelope p2p.Envelope) error {
+		switch msg := envelope.Message.(type) {
+		case *bcproto.BlockRequest:
+			require.True(t, backfill.addBlock(envelope.To, source.LoadBlock(msg.Height)))
+
+		case *bcproto.BlockResultsRequest:
+			switch envelope.To {
+			case "bb":
+				dropped++
+				backfill.removePeer("bb")
+				backfill.setPeerStatus("aa", &bcproto.StatusResponse{Base: 1, Height: 6, BlockResults: true})
+			case "cc":
+				require.Fail(t, "results requested from peer without results")
+			default:
+				results := backfillResults(source.LoadBlock(msg.Height))
+				require.True(t, backfill.addResults(envelope.To, msg.Height, results))
+			}
+		}
+		return nil
+	}
+	sendError := func(ctx context.Context, peerError p2p.PeerError) error {
+		require.Fail(t, "unexpected peer error", peerError.Err)
+		return nil
+	}
+	backfill.run(ctx, send, sendError)
+
+	// The results requested from bb are fetched from aa instead.
+	require.Equal(t, 4, dropped)
+	for height := int64(2); height <= 5; height++ {
+		require.Equal(t, source.LoadBlock(height), blockStore.LoadBlock(height))
+		_, err := stateStore.LoadABCIResponses(height)
+		require.NoError(t, err)
+	}
+}
+
+func TestBackfiller_NoHeader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_backfill_no_header_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{5}, 0)
+	source := rts.reactors[rts.nodes[0]].store
+
+	// The store has a single full block, so the block below can't be
+	// verified once the store has been pruned.
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	block := source.LoadBlock(5)
+	blockStore.SaveBlock(block, block.MakePartSet(types.BlockPartSizeBytes), source.LoadSeenCommit())
+	backfill := newBackfiller(log.TestingLogger(), blockStore, BackfillOptions{TargetHeight: 1})
+	backfill.start = func() int64 { return 3 }
+
+	peerID := types.NodeID("aa")
+	backfill.setPeerStatus(peerID, &bcproto.StatusResponse{Base: 1, Height: 5})
+	requests := 0
+	send := func(ctx context.Context, envelope p2p.Envelope) error {
+		if msg, ok := envelope.Message.(*bcproto.BlockRequest); ok {
+			requests++
+			require.True(t, backfill.addBlock(peerID, source.LoadBlock(msg.Height)))
+		}
+		return nil
+	}
+	sendError := func(ctx context.Context, peerError p2p.PeerError) error {
+		require.Fail(t, "unexpected peer error", peerError.Err)
+		return nil
+	}
+
+	// The backfill stops instead of requesting the block again.
+	backfill.run(ctx, send, sendError)
+	require.Equal(t, 3, requests)
+	require.Nil(t, blockStore.LoadBlock(3))
+}
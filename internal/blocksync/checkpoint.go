@@ -0,0 +1,205 @@
+package blocksync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/libs/log"
+	tmstate "github.com/tendermint/tendermint/proto/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// number of heights between the hashes of the checkpoint's hash chain
+	// kept in memory. Blocks are verified in segments between these anchors,
+	// so it must not exceed verifyWindow, the number of blocks peeked from the
+	// pool.
+	checkpointAnchorInterval = verifyWindow
+)
+
+// checkpoint verifies the blocks below a trusted checkpoint by their hash
+// chain instead of the signatures of their commits, which is much cheaper when
+// replaying a long history.
+//
+// Before syncing, the hash chain is walked backwards from the trusted block
+// down to the height above the initial state, fetching each header from peers
+// and following its LastBlockID. Only headers are fetched, since the blocks
+// themselves are fetched by the pool while syncing; peers which don't serve
+// headers are asked for full blocks instead. Only the hashes of every
+// checkpointAnchorInterval-th block (the anchors) are kept. While syncing, the
+// blocks up to the next anchor are then verified by their hash chain, and each
+// block's last commit by its header, which allows applying them without
+// verifying any signatures. The block at the checkpoint height and above are
+// verified as usual.
+type checkpoint struct {
+	height int64
+	hash   []byte
+
+	// fetcher fetches the headers of the hash chain from peers, in reverse
+	// order.
+	fetcher *backfiller
+
+	mtx     sync.Mutex
+	target  int64            // the lowest height to walk to
+	walked  int64            // the lowest height walked so far
+	expect  []byte           // the hash of the block below walked
+	anchors map[int64][]byte // by height
+	trusted map[int64][]byte // hashes of verified blocks not yet applied, by height
+}
+
+func newCheckpoint(logger log.Logger, height int64, hash []byte) *checkpoint {
+	c := &checkpoint{
+		height:  height,
+		hash:    hash,
+		walked:  height + 1,
+		expect:  hash,
+		anchors: make(map[int64][]byte),
+		trusted: make(map[int64][]byte),
+	}
+	c.fetcher = newBackfiller(logger, nil, BackfillOptions{})
+	c.fetcher.start = func() int64 { return c.height }
+	c.fetcher.process = c.walkBlock
+	c.fetcher.headers = true
+	return c
+}
+
+// walk walks the hash chain from the checkpoint down to the target height,
+// until done or the context ends. Block requests are passed to send, and peers
+// that send invalid blocks to sendError. It returns true if the hash chain has
+// been walked.
+func (c *checkpoint) walk(
+	ctx context.Context,
+	target int64,
+	send func(context.Context, p2p.Envelope) error,
+	sendError func(context.Context, p2p.PeerError) error,
+) bool {
+	c.mtx.Lock()
+	c.target = target
+	c.mtx.Unlock()
+
+	// The target is only read by run, after it's set here.
+	c.fetcher.options.TargetHeight = target
+	c.fetcher.run(ctx, send, sendError)
+	return c.complete()
+}
+
+// walkBlock verifies the header of the next block of the hash chain, and
+// records its hash if it's an anchor. The block only carries its header if
+// it was fetched as such.
+func (c *checkpoint) walkBlock(_ context.Context, block *types.Block, _ *tmstate.ABCIResponses) error {
+	if err := block.Header.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidBackfillBlock, err)
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if block.Height != c.walked-1 {
+		return fmt.Errorf("expected block at height %v, got %v", c.walked-1, block.Height)
+	}
+	if hash := block.Header.Hash(); !bytes.Equal(hash, c.expect) {
+		return fmt.Errorf("%w: expected block hash %X, got %X", errInvalidBackfillBlock, c.expect, hash)
+	}
+	if (c.height-block.Height)%checkpointAnchorInterval == 0 {
+		c.anchors[block.Height] = c.expect
+	}
+	c.walked = block.Height
+	c.expect = block.LastBlockID.Hash
+	return nil
+}
+
+// complete returns true if the hash chain has been walked down to the target
+// height.
+func (c *checkpoint) complete() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.walked <= c.target
+}
+
+// trusts returns true if the block at the given height is verified by the
+// hash chain, i.e. it's below the checkpoint and the hash chain has been
+// walked down to it.
+func (c *checkpoint) trusts(height int64) bool {
+	if c == nil || height >= c.height {
+		return false
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return height >= c.walked
+}
+
+// anchor returns the height of the lowest anchor at or above the given height.
+func (c *checkpoint) anchor(height int64) int64 {
+	return c.height - (c.height-height)/checkpointAnchorInterval*checkpointAnchorInterval
+}
+
+// untrusted returns the given contiguous blocks from the first block that is
+// not verified by the hash chain, whose commits must be verified.
+func (c *checkpoint) untrusted(blocks []*types.Block) []*types.Block {
+	for len(blocks) > 0 && c.trusts(blocks[0].Height) {
+		blocks = blocks[1:]
+	}
+	return blocks
+}
+
+// ready returns true if the first of the given contiguous blocks can be
+// verified, i.e. it's not verified by the hash chain, or the blocks up to the
+// anchor above the second block are available.
+func (c *checkpoint) ready(blocks []*types.Block) bool {
+	first := blocks[0]
+	if !c.trusts(first.Height) {
+		return true
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.trusted[first.Height] != nil && c.trusted[first.Height+1] != nil {
+		return true
+	}
+	return int64(len(blocks)) > c.anchor(first.Height+1)-first.Height
+}
+
+// verify verifies the first and second of the given contiguous blocks by the
+// hash chain, and returns the first block's part set and block ID. Since the
+// second block's header is verified, so is its last commit, i.e. the commit of
+// the first block. The first block must be trusted, and the given blocks
+// ready. If a block doesn't match the hash chain, it returns its height and an
+// error.
+func (c *checkpoint) verify(blocks []*types.Block) (*types.PartSet, types.BlockID, int64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	first, second := blocks[0], blocks[1]
+	if c.trusted[first.Height] == nil || c.trusted[second.Height] == nil {
+		// Verify the segment of the hash chain from the anchor above the
+		// second block down to the first block.
+		anchor := c.anchor(second.Height)
+		expect := c.anchors[anchor]
+		for i := anchor - first.Height; i >= 0; i-- {
+			block := blocks[i]
+			if hash := block.Hash(); !bytes.Equal(hash, expect) {
+				return nil, types.BlockID{}, block.Height,
+					fmt.Errorf("expected block hash %X by trusted checkpoint, got %X", expect, hash)
+			}
+			c.trusted[block.Height] = expect
+			expect = block.LastBlockID.Hash
+		}
+	}
+
+	// The blocks may have been fetched again since their segment was
+	// verified.
+	for _, block := range []*types.Block{first, second} {
+		if hash := block.Hash(); !bytes.Equal(hash, c.trusted[block.Height]) {
+			return nil, types.BlockID{}, block.Height,
+				fmt.Errorf("expected block hash %X by trusted checkpoint, got %X", c.trusted[block.Height], hash)
+		}
+	}
+	delete(c.trusted, first.Height)
+	delete(c.anchors, first.Height)
+
+	parts := first.MakePartSet(types.BlockPartSizeBytes)
+	return parts, types.BlockID{Hash: first.Hash(), PartSetHeader: parts.Header()}, 0, nil
+}
@@ -0,0 +1,201 @@
+package blocksync
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/consensus"
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/internal/test/factory"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/log"
+	bcproto "github.com/tendermint/tendermint/proto/tendermint/blocksync"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_checkpoint_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{150}, 0)
+	source := rts.reactors[rts.nodes[0]].store
+
+	c := newCheckpoint(log.TestingLogger(), 140, source.LoadBlockMeta(140).BlockID.Hash)
+	require.False(t, c.trusts(100), "the hash chain hasn't been walked yet")
+
+	// Peer aa serves headers above height 60, and first sends a header from
+	// another height in response to the request for height 100, and then the
+	// right one. The headers below are fetched as full blocks from peer bb,
+	// which doesn't serve headers.
+	c.fetcher.setPeerStatus("aa", &bcproto.StatusResponse{Base: 60, Height: 150, Headers: true})
+	c.fetcher.setPeerStatus("bb", &bcproto.StatusResponse{Base: 1, Height: 150})
+	tampered := false
+	send := func(ctx context.Context, envelope p2p.Envelope) error {
+		switch msg := envelope.Message.(type) {
+		case *bcproto.HeaderRequest:
+			require.Equal(t, types.NodeID("aa"), envelope.To)
+			require.GreaterOrEqual(t, msg.Height, int64(60))
+			require.LessOrEqual(t, msg.Height, int64(140))
+			header := source.LoadBlockMeta(msg.Height).Header
+			if msg.Height == 100 && !tampered {
+				tampered = true
+				header = source.LoadBlockMeta(101).Header
+				header.Height = 100
+			}
+			require.True(t, c.fetcher.addHeader("aa", &header))
+
+		case *bcproto.BlockRequest:
+			require.Equal(t, types.NodeID("bb"), envelope.To)
+			require.GreaterOrEqual(t, msg.Height, int64(10))
+			require.Less(t, msg.Height, int64(60))
+			require.True(t, c.fetcher.addBlock("bb", source.LoadBlock(msg.Height)))
+		}
+		return nil
+	}
+	peerErrors := []p2p.PeerError{}
+	sendError := func(ctx context.Context, peerError p2p.PeerError) error {
+		peerErrors = append(peerErrors, peerError)
+		return nil
+	}
+	require.True(t, c.walk(ctx, 10, send, sendError))
+	require.Len(t, peerErrors, 1)
+	require.Equal(t, p2p.MisbehaviorInvalidBlock, peerErrors[0].Misbehavior)
+
+	// Only the hashes of every checkpointAnchorInterval-th block are kept.
+	require.Len(t, c.anchors, 3)
+	for _, height := range []int64{140, 76, 12} {
+		require.Equal(t, source.LoadBlockMeta(height).BlockID.Hash, tmbytes.HexBytes(c.anchors[height]))
+	}
+	require.False(t, c.trusts(9))
+	require.True(t, c.trusts(10))
+	require.True(t, c.trusts(139))
+	require.False(t, c.trusts(140), "the commit of the checkpoint block must be verified")
+
+	blocks := []*types.Block{}
+	for height := int64(10); height <= 150; height++ {
+		blocks = append(blocks, source.LoadBlock(height))
+	}
+	require.Equal(t, int64(140), c.untrusted(blocks)[0].Height)
+
+	// The blocks up to the anchor above the second block are needed.
+	require.True(t, c.ready(blocks[:3]))
+	require.False(t, c.ready(blocks[:2]))
+	require.False(t, c.ready(blocks[2:66]))
+	require.True(t, c.ready(blocks[2:67]))
+
+	// A block that doesn't match the hash chain is rejected.
+	forgedProto, err := blocks[1].ToProto()
+	require.NoError(t, err)
+	forged, err := types.BlockFromProto(forgedProto)
+	require.NoError(t, err)
+	forged.Time = forged.Time.Add(time.Second)
+	_, _, invalid, err := c.verify([]*types.Block{blocks[0], forged, blocks[2]})
+	require.Error(t, err)
+	require.EqualValues(t, 11, invalid)
+
+	for i := 0; i < 130; i++ {
+		parts, blockID, _, err := c.verify(blocks[i:])
+		require.NoError(t, err, "height %v", blocks[i].Height)
+		require.Equal(t, source.LoadBlockMeta(blocks[i].Height).BlockID, blockID)
+		require.Equal(t, blockID.PartSetHeader, parts.Header())
+	}
+
+	// The hashes of applied blocks are dropped, up to the checkpoint block.
+	require.Len(t, c.anchors, 1)
+	require.Len(t, c.trusted, 1)
+}
+
+func TestReactor_Checkpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_checkpoint_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{150}, 0)
+	source := rts.reactors[rts.nodes[0]].store
+
+	state, blockExec, blockStore := makeArchiveTarget(ctx, t, genDoc)
+	inCh := make(chan p2p.Envelope, 100)
+	outCh := make(chan p2p.Envelope, 100)
+	ch := p2p.NewChannel(BlockSyncChannel, new(bcproto.Message), inCh, outCh, make(chan p2p.PeerError, 100))
+	reactor, err := NewReactor(log.TestingLogger(), state, blockExec, blockStore, nil,
+		ch, p2p.NewPeerUpdates(make(chan p2p.PeerUpdate), 1), true, consensus.NopMetrics())
+	require.NoError(t, err)
+	reactor.SetCheckpoint(100, source.LoadBlockMeta(100).BlockID.Hash)
+	require.NoError(t, reactor.Start(ctx))
+
+	// A peer serves the blocks and headers in the source store. The hash
+	// chain is walked by headers, so each block is only fetched once.
+	peerID := types.NodeID("aa")
+	blockRequests := make(map[int64]int)
+	respond := func(message bcproto.Message) {
+		msg, err := message.Unwrap()
+		require.NoError(t, err)
+		select {
+		case inCh <- p2p.Envelope{From: peerID, Message: msg}:
+		case <-ctx.Done():
+		}
+	}
+	sendBlocks := func(from, to int64) {
+		for height := from; height <= to; height++ {
+			blockRequests[height]++
+			blockProto, err := source.LoadBlock(height).ToProto()
+			require.NoError(t, err)
+			respond(bcproto.Message{Sum: &bcproto.Message_BlockResponse{
+				BlockResponse: &bcproto.BlockResponse{Block: blockProto}}})
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		status := bcproto.Message{Sum: &bcproto.Message_StatusResponse{
+			StatusResponse: &bcproto.StatusResponse{Base: 1, Height: 150, RangeRequests: true, Headers: true}}}
+		respond(status)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case envelope := <-outCh:
+				switch msg := envelope.Message.(type) {
+				case *bcproto.BlockRequest:
+					sendBlocks(msg.Height, msg.Height)
+				case *bcproto.BlockRangeRequest:
+					sendBlocks(msg.StartHeight, msg.EndHeight)
+				case *bcproto.HeaderRequest:
+					respond(bcproto.Message{Sum: &bcproto.Message_HeaderResponse{
+						HeaderResponse: &bcproto.HeaderResponse{Header: source.LoadBlockMeta(msg.Height).Header.ToProto()}}})
+				case *bcproto.StatusRequest:
+					respond(status)
+				}
+			}
+		}
+	}()
+
+	// The blocks below the checkpoint are applied after walking the hash
+	// chain, and the blocks above it are verified by their commits.
+	require.Eventually(t, func() bool {
+		return blockStore.Height() == 149
+	}, 30*time.Second, 10*time.Millisecond)
+	require.True(t, reactor.checkpoint.complete())
+	for height := int64(1); height <= 149; height++ {
+		require.Equal(t, source.LoadBlockMeta(height).BlockID, blockStore.LoadBlockMeta(height).BlockID)
+	}
+	cancel()
+	<-done
+	for height := int64(2); height <= 100; height++ {
+		require.Equal(t, 1, blockRequests[height], "height %v", height)
+	}
+}
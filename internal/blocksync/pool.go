@@ -28,11 +28,22 @@ eg, L = latency = 0.1s
 */
 
 const (
-	requestIntervalMS         = 2
-	maxTotalRequesters        = 600
-	maxPeerErrBuffer          = 1000
-	maxPendingRequests        = maxTotalRequesters
-	maxPendingRequestsPerPeer = 20
+	requestIntervalMS  = 2
+	maxTotalRequesters = 600
+	maxPeerErrBuffer   = 1000
+	maxPendingRequests = maxTotalRequesters
+
+	// Blocks are requested from a peer in ranges of up to maxRequestRange
+	// heights. The number of blocks pending per peer is limited by the peer's
+	// window, which adapts to the peer's measured throughput such that it
+	// holds about peerWindowDuration worth of blocks.
+	maxRequestRange    = 20
+	minPeerWindow      = 4
+	maxPeerWindow      = 100
+	peerWindowDuration = 2 * time.Second
+
+	// The throughput of a peer is sampled at most this often.
+	peerRateInterval = time.Second
 
 	// Minimum recv rate to ensure we're receiving blocks from a peer fast
 	// enough. If a peer is not sending us data at at least that rate, we
@@ -51,7 +62,8 @@ var peerTimeout = 15 * time.Second // not const so we can override with tests
 /*
 	Peers self report their heights when we join the block pool.
 	Starting from our latest pool.height, we request blocks
-	in sequence from peers that reported higher heights than ours.
+	in sequence from peers that reported higher heights than ours,
+	in contiguous ranges from the peers with the most room in their window.
 	Every so often we ask peers what height they're on so we can keep going.
 
 	Requests are continuously made for blocks of higher heights until
@@ -59,11 +71,12 @@ var peerTimeout = 15 * time.Second // not const so we can override with tests
 	are not at peer limits, we can probably switch to consensus reactor
 */
 
-// BlockRequest stores a block request identified by the block Height and the
-// PeerID responsible for delivering the block.
+// BlockRequest stores a request for the blocks from Height to EndHeight
+// (inclusive) and the PeerID responsible for delivering the blocks.
 type BlockRequest struct {
-	Height int64
-	PeerID types.NodeID
+	Height    int64
+	EndHeight int64
+	PeerID    types.NodeID
 }
 
 // BlockPool keeps track of the block sync peers, block requests and block responses.
@@ -228,6 +241,27 @@ func (pool *BlockPool) PeekTwoBlocks() (first *types.Block, second *types.Block)
 	return
 }
 
+// PeekBlocks returns up to max contiguous blocks from pool.height, stopping at
+// the first height whose block hasn't been received yet.
+func (pool *BlockPool) PeekBlocks(max int) []*types.Block {
+	pool.mtx.RLock()
+	defer pool.mtx.RUnlock()
+
+	blocks := []*types.Block{}
+	for height := pool.height; len(blocks) < max; height++ {
+		r := pool.requesters[height]
+		if r == nil {
+			break
+		}
+		block := r.getBlock()
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
 // PopRequest pops the first block at pool.height.
 // It must have been validated by 'second'.Commit from PeekTwoBlocks().
 func (pool *BlockPool) PopRequest() {
@@ -323,8 +357,9 @@ func (pool *BlockPool) LastAdvance() time.Time {
 	return pool.lastAdvance
 }
 
-// SetPeerRange sets the peer's alleged blockchain base and height.
-func (pool *BlockPool) SetPeerRange(peerID types.NodeID, base int64, height int64) {
+// SetPeerRange sets the peer's alleged blockchain base and height, and
+// whether it serves range requests.
+func (pool *BlockPool) SetPeerRange(peerID types.NodeID, base int64, height int64, rangeRequests bool) {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
@@ -337,6 +372,7 @@ func (pool *BlockPool) SetPeerRange(peerID types.NodeID, base int64, height int6
 		peer.logger = pool.logger.With("peer", peerID)
 		pool.peers[peerID] = peer
 	}
+	peer.rangeRequests = rangeRequests
 
 	if height > pool.maxPeerHeight {
 		pool.maxPeerHeight = height
@@ -386,27 +422,74 @@ func (pool *BlockPool) updateMaxPeerHeight() {
 	pool.maxPeerHeight = max
 }
 
-// Pick an available peer with the given height available.
-// If no peers are available, returns nil.
-func (pool *BlockPool) pickIncrAvailablePeer(height int64) *bpPeer {
+// assignRange assigns the requester at the given height, along with the
+// requesters of the contiguous heights above it that don't have a peer yet,
+// to the available peer with the most room in its window. It returns the
+// request for their blocks, or false if no peer is available. If the
+// requester already has a peer, it returns an empty request. Peers that don't
+// serve range requests are only assigned a single height.
+func (pool *BlockPool) assignRange(height int64) (BlockRequest, bool) {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
+	requester := pool.requesters[height]
+	if requester == nil || !requester.assignable() {
+		return BlockRequest{}, true
+	}
+
+	peer := pool.pickAvailablePeer(height)
+	if peer == nil {
+		return BlockRequest{}, false
+	}
+
+	maxHeight := height + maxRequestRange - 1
+	if !peer.rangeRequests {
+		maxHeight = height
+	}
+	if free := height + peer.freeWindow() - 1; free < maxHeight {
+		maxHeight = free
+	}
+	if peer.height < maxHeight {
+		maxHeight = peer.height
+	}
+
+	request := BlockRequest{Height: height, EndHeight: height, PeerID: peer.id}
+	requester.setPeerID(peer.id)
+	peer.incrPending()
+	for h := height + 1; h <= maxHeight; h++ {
+		r := pool.requesters[h]
+		if r == nil || !r.assignable() {
+			break
+		}
+		r.setPeerID(peer.id)
+		peer.incrPending()
+		request.EndHeight = h
+	}
+	return request, true
+}
+
+// pickAvailablePeer returns the peer with the given height available that has
+// the most room in its window, preferring faster peers. If no peers are
+// available, it returns nil.
+func (pool *BlockPool) pickAvailablePeer(height int64) *bpPeer {
+	var best *bpPeer
 	for _, peer := range pool.peers {
 		if peer.didTimeout {
 			pool.removePeer(peer.id)
 			continue
 		}
-		if peer.numPending >= maxPendingRequestsPerPeer {
+		if peer.freeWindow() <= 0 {
 			continue
 		}
 		if height < peer.base || height > peer.height {
 			continue
 		}
-		peer.incrPending()
-		return peer
+		if best == nil || peer.freeWindow() > best.freeWindow() ||
+			(peer.freeWindow() == best.freeWindow() && peer.blockRate > best.blockRate) {
+			best = peer
+		}
 	}
-	return nil
+	return best
 }
 
 func (pool *BlockPool) makeNextRequester(ctx context.Context) {
@@ -433,11 +516,11 @@ func (pool *BlockPool) requestersLen() int64 {
 	return int64(len(pool.requesters))
 }
 
-func (pool *BlockPool) sendRequest(height int64, peerID types.NodeID) {
+func (pool *BlockPool) sendRequest(request BlockRequest) {
 	if !pool.IsRunning() {
 		return
 	}
-	pool.requestsCh <- BlockRequest{height, peerID}
+	pool.requestsCh <- request
 }
 
 func (pool *BlockPool) sendError(err error, peerID types.NodeID) {
@@ -491,6 +574,17 @@ type bpPeer struct {
 	id          types.NodeID
 	recvMonitor *flowrate.Monitor
 
+	// rangeRequests is true if the peer serves BlockRangeRequest.
+	rangeRequests bool
+
+	// window is the maximum number of pending blocks, adapted to blockRate,
+	// the moving average of the blocks received per second. The blocks
+	// received since rateStart are counted in rateBlocks.
+	window     int32
+	blockRate  float64
+	rateBlocks int
+	rateStart  time.Time
+
 	timeout *time.Timer
 
 	logger log.Logger
@@ -503,6 +597,7 @@ func newBPPeer(pool *BlockPool, peerID types.NodeID, base int64, height int64) *
 		base:       base,
 		height:     height,
 		numPending: 0,
+		window:     minPeerWindow,
 		logger:     log.NewNopLogger(),
 	}
 	return peer
@@ -526,12 +621,15 @@ func (peer *bpPeer) incrPending() {
 	if peer.numPending == 0 {
 		peer.resetMonitor()
 		peer.resetTimeout()
+		peer.rateStart = time.Now()
+		peer.rateBlocks = 0
 	}
 	peer.numPending++
 }
 
 func (peer *bpPeer) decrPending(recvSize int) {
 	peer.numPending--
+	peer.updateRate()
 	if peer.numPending == 0 {
 		peer.timeout.Stop()
 	} else {
@@ -540,6 +638,40 @@ func (peer *bpPeer) decrPending(recvSize int) {
 	}
 }
 
+// freeWindow returns the number of blocks that can still be requested from
+// the peer.
+func (peer *bpPeer) freeWindow() int64 {
+	return int64(peer.window - peer.numPending)
+}
+
+// updateRate counts a received block, and adapts the window to the peer's
+// throughput once per peerRateInterval.
+func (peer *bpPeer) updateRate() {
+	peer.rateBlocks++
+	elapsed := time.Since(peer.rateStart)
+	if elapsed < peerRateInterval {
+		return
+	}
+
+	rate := float64(peer.rateBlocks) / elapsed.Seconds()
+	if peer.blockRate == 0 {
+		peer.blockRate = rate
+	} else {
+		peer.blockRate = 0.7*peer.blockRate + 0.3*rate
+	}
+	peer.rateStart = time.Now()
+	peer.rateBlocks = 0
+
+	window := int32(math.Ceil(peer.blockRate * peerWindowDuration.Seconds()))
+	switch {
+	case window < minPeerWindow:
+		window = minPeerWindow
+	case window > maxPeerWindow:
+		window = maxPeerWindow
+	}
+	peer.window = window
+}
+
 func (peer *bpPeer) onTimeout() {
 	peer.pool.mtx.Lock()
 	defer peer.pool.mtx.Unlock()
@@ -616,6 +748,19 @@ func (bpr *bpRequester) getPeerID() types.NodeID {
 	return bpr.peerID
 }
 
+func (bpr *bpRequester) setPeerID(peerID types.NodeID) {
+	bpr.mtx.Lock()
+	defer bpr.mtx.Unlock()
+	bpr.peerID = peerID
+}
+
+// assignable returns true if the requester has neither a peer nor a block.
+func (bpr *bpRequester) assignable() bool {
+	bpr.mtx.Lock()
+	defer bpr.mtx.Unlock()
+	return bpr.peerID == "" && bpr.block == nil
+}
+
 // This is called from the requestRoutine, upon redo().
 func (bpr *bpRequester) reset() {
 	bpr.mtx.Lock()
@@ -650,26 +795,25 @@ func (bpr *bpRequester) requestRoutine(ctx context.Context) {
 
 OUTER_LOOP:
 	for {
-		// Pick a peer to send request to.
-		var peer *bpPeer
+		// Pick a peer to send a request to, for this height and the heights
+		// above it, unless the request for a lower height already covers it.
 	PICK_PEER_LOOP:
 		for {
 			if !bpr.IsRunning() || !bpr.pool.IsRunning() {
 				return
 			}
-			peer = bpr.pool.pickIncrAvailablePeer(bpr.height)
-			if peer == nil {
+			request, ok := bpr.pool.assignRange(bpr.height)
+			if !ok {
 				time.Sleep(requestIntervalMS * time.Millisecond)
 				continue PICK_PEER_LOOP
 			}
+
+			// Send request and wait.
+			if request.PeerID != "" {
+				bpr.pool.sendRequest(request)
+			}
 			break PICK_PEER_LOOP
 		}
-		bpr.mtx.Lock()
-		bpr.peerID = peer.id
-		bpr.mtx.Unlock()
-
-		// Send request and wait.
-		bpr.pool.sendRequest(bpr.height, peer.id)
 	WAIT_LOOP:
 		for {
 			select {
@@ -681,7 +825,7 @@ OUTER_LOOP:
 				}
 				return
 			case peerID := <-bpr.redoCh:
-				if peerID == bpr.peerID {
+				if peerID == bpr.getPeerID() {
 					bpr.reset()
 					continue OUTER_LOOP
 				} else {
@@ -40,10 +40,12 @@ func (p testPeer) runInputRoutine() {
 	}()
 }
 
-// Request desired, pretend like we got the block immediately.
+// Request desired, pretend like we got the blocks immediately.
 func (p testPeer) simulateInput(input inputData) {
-	block := &types.Block{Header: types.Header{Height: input.request.Height}}
-	input.pool.AddBlock(input.request.PeerID, block, 123)
+	for height := input.request.Height; height <= input.request.EndHeight; height++ {
+		block := &types.Block{Header: types.Header{Height: height}}
+		input.pool.AddBlock(input.request.PeerID, block, 123)
+	}
 	// TODO: uncommenting this creates a race which is detected by:
 	// https://github.com/golang/go/blob/2bd767b1022dd3254bcec469f0ee164024726486/src/testing/testing.go#L854-L856
 	// see: https://github.com/tendermint/tendermint/issues/3390#issue-418379890
@@ -100,7 +102,7 @@ func TestBlockPoolBasic(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerRange(peer.id, peer.base, peer.height)
+			pool.SetPeerRange(peer.id, peer.base, peer.height, true)
 		}
 	}()
 
@@ -126,7 +128,7 @@ func TestBlockPoolBasic(t *testing.T) {
 			t.Error(err)
 		case request := <-requestsCh:
 			t.Logf("Pulled new BlockRequest %v", request)
-			if request.Height == 300 {
+			if request.Height <= 300 && request.EndHeight >= 300 {
 				return // Done!
 			}
 
@@ -157,7 +159,7 @@ func TestBlockPoolTimeout(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerRange(peer.id, peer.base, peer.height)
+			pool.SetPeerRange(peer.id, peer.base, peer.height, true)
 		}
 	}()
 
@@ -216,7 +218,7 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	// add peers
 	for peerID, peer := range peers {
-		pool.SetPeerRange(peerID, peer.base, peer.height)
+		pool.SetPeerRange(peerID, peer.base, peer.height, true)
 	}
 	assert.EqualValues(t, 10, pool.MaxPeerHeight())
 
@@ -234,3 +236,90 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	assert.EqualValues(t, 0, pool.MaxPeerHeight())
 }
+
+func TestBlockPoolAssignRange(t *testing.T) {
+	requestsCh := make(chan BlockRequest, 10)
+	errorsCh := make(chan peerError, 10)
+	pool := NewBlockPool(log.TestingLogger(), 1, requestsCh, errorsCh)
+	for height := int64(1); height <= 50; height++ {
+		pool.requesters[height] = newBPRequester(pool, height)
+	}
+
+	// Without peers, no range can be assigned.
+	_, ok := pool.assignRange(1)
+	require.False(t, ok)
+
+	pool.SetPeerRange("a", 1, 30, true)
+	pool.SetPeerRange("b", 1, 6, true)
+	peerA, peerB := pool.peers["a"], pool.peers["b"]
+	peerA.window = 10
+	peerB.window = 5
+
+	// The peer with the most room in its window gets a range up to its window.
+	request, ok := pool.assignRange(1)
+	require.True(t, ok)
+	require.Equal(t, BlockRequest{Height: 1, EndHeight: 10, PeerID: "a"}, request)
+	require.EqualValues(t, 10, peerA.numPending)
+	for height := int64(1); height <= 10; height++ {
+		require.EqualValues(t, "a", pool.requesters[height].getPeerID())
+	}
+
+	// Requesters covered by a range don't request again.
+	request, ok = pool.assignRange(5)
+	require.True(t, ok)
+	require.Equal(t, BlockRequest{}, request)
+
+	// Peers with a full window or without the height aren't available.
+	_, ok = pool.assignRange(11)
+	require.False(t, ok)
+
+	// The range is limited by the peer's height.
+	peerA.numPending = 0
+	pool.SetPeerRange("a", 1, 14, true)
+	request, ok = pool.assignRange(11)
+	require.True(t, ok)
+	require.Equal(t, BlockRequest{Height: 11, EndHeight: 14, PeerID: "a"}, request)
+
+	// Once a peer's window is full, other peers are used. The range ends at
+	// the next assigned requester.
+	peerA.numPending = peerA.window
+	pool.requesters[3].setPeerID("")
+	request, ok = pool.assignRange(3)
+	require.True(t, ok)
+	require.Equal(t, BlockRequest{Height: 3, EndHeight: 3, PeerID: "b"}, request)
+
+	// Peers that don't serve range requests are assigned a single height.
+	pool.SetPeerRange("b", 1, 30, false)
+	request, ok = pool.assignRange(15)
+	require.True(t, ok)
+	require.Equal(t, BlockRequest{Height: 15, EndHeight: 15, PeerID: "b"}, request)
+
+	peerA.timeout.Stop()
+	peerB.timeout.Stop()
+}
+
+func TestBPPeerWindow(t *testing.T) {
+	pool := NewBlockPool(log.TestingLogger(), 1, make(chan BlockRequest), make(chan peerError, 10))
+	peer := newBPPeer(pool, "a", 1, 1000)
+	require.EqualValues(t, minPeerWindow, peer.window)
+
+	for i := 0; i < 50; i++ {
+		peer.incrPending()
+	}
+	defer peer.timeout.Stop()
+
+	// A fast peer gets a larger window.
+	peer.rateStart = time.Now().Add(-peerRateInterval)
+	peer.rateBlocks = 39
+	peer.decrPending(123)
+	require.InDelta(t, 40, peer.blockRate, 1)
+	require.EqualValues(t, 80, peer.window)
+
+	// The window shrinks as the peer slows down, but not below the minimum.
+	for i := 0; i < 10; i++ {
+		peer.rateStart = time.Now().Add(-10 * peerRateInterval)
+		peer.decrPending(123)
+	}
+	require.Less(t, peer.blockRate, 2.0)
+	require.EqualValues(t, minPeerWindow, peer.window)
+}
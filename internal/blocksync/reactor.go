@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"runtime"
 	"runtime/debug"
 	"sync"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
+
+	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/internal/consensus"
 	"github.com/tendermint/tendermint/internal/p2p"
 	sm "github.com/tendermint/tendermint/internal/state"
@@ -16,6 +21,7 @@ import (
 	"github.com/tendermint/tendermint/libs/service"
 	tmsync "github.com/tendermint/tendermint/libs/sync"
 	bcproto "github.com/tendermint/tendermint/proto/tendermint/blocksync"
+	tmstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -35,6 +41,12 @@ const (
 
 	// switch to consensus after this duration of inactivity
 	syncTimeout = 60 * time.Second
+
+	// buffer for peer block ranges reported to the peer manager
+	peerRangeBufferSize = 100
+
+	// maximum number of blocks served for a single block range request
+	maxBlockRangeRequest = 100
 )
 
 func GetChannelDescriptor() *p2p.ChannelDescriptor {
@@ -88,6 +100,11 @@ type Reactor struct {
 	blockSyncOutBridgeCh chan p2p.Envelope
 	peerUpdates          *p2p.PeerUpdates
 
+	// peerRangeCh buffers the block ranges reported by peers, which are
+	// passed on to the peer manager by processPeerRanges without blocking
+	// message processing.
+	peerRangeCh chan p2p.PeerUpdate
+
 	requestsCh <-chan BlockRequest
 	errorsCh   <-chan peerError
 
@@ -99,6 +116,20 @@ type Reactor struct {
 	metrics *consensus.Metrics
 
 	syncStartTime time.Time
+
+	// backfill is set via SetBackfill, and started once the node is live.
+	backfill       *backfiller
+	backfillOnce   sync.Once
+	backfillCancel context.CancelFunc
+	backfillWG     sync.WaitGroup
+
+	// archivePath is set via SetArchive, and imported before syncing from
+	// peers.
+	archivePath string
+
+	// checkpoint is set via SetCheckpoint, and its hash chain is walked
+	// before syncing from peers.
+	checkpoint *checkpoint
 }
 
 // NewReactor returns new reactor instance.
@@ -138,6 +169,7 @@ func NewReactor(
 		blockSyncCh:          blockSyncCh,
 		blockSyncOutBridgeCh: make(chan p2p.Envelope),
 		peerUpdates:          peerUpdates,
+		peerRangeCh:          make(chan p2p.PeerUpdate, peerRangeBufferSize),
 		metrics:              metrics,
 		syncStartTime:        time.Time{},
 	}
@@ -155,18 +187,20 @@ func NewReactor(
 // goroutine. If the pool fails to start, an error is returned.
 func (r *Reactor) OnStart(ctx context.Context) error {
 	if r.blockSync.IsSet() {
-		if err := r.pool.Start(ctx); err != nil {
+		if err := r.startSync(ctx, false); err != nil {
 			return err
 		}
-		r.poolWG.Add(1)
-		go r.requestRoutine(ctx)
-
-		r.poolWG.Add(1)
-		go r.poolRoutine(ctx, false)
 	}
 
 	go r.processBlockSyncCh(ctx)
 	go r.processPeerUpdates(ctx)
+	go r.processPeerRanges(ctx)
+
+	// If we're neither block syncing nor waiting for state sync (which starts
+	// with an empty store), the node is already live.
+	if !r.blockSync.IsSet() && r.initialState.LastBlockHeight > 0 {
+		r.startBackfill(ctx)
+	}
 
 	return nil
 }
@@ -182,12 +216,179 @@ func (r *Reactor) OnStop() {
 
 	// wait for the poolRoutine and requestRoutine goroutines to gracefully exit
 	r.poolWG.Wait()
+
+	if r.backfillCancel != nil {
+		r.backfillCancel()
+	}
+	r.backfillWG.Wait()
+}
+
+// SetBackfill enables backfilling of historical blocks down to the target
+// height, in the background once the node is live, e.g. so that a state
+// synced node eventually has the full block history. It must be called before
+// the reactor is started.
+func (r *Reactor) SetBackfill(options BackfillOptions) {
+	r.backfill = newBackfiller(r.logger.With("backfill", true), r.store, options)
+}
+
+// SetArchive sets a block archive, as written by ExportBlocks, to apply blocks
+// from before fetching the remaining blocks from peers, e.g. to rebuild a node
+// from cold storage. It must be called before the reactor is started.
+func (r *Reactor) SetArchive(path string) {
+	r.archivePath = path
+}
+
+// SetCheckpoint sets a trusted checkpoint, i.e. the hash of the block at the
+// given height. Blocks below the checkpoint are verified by their hash chain
+// from the trusted block, instead of verifying the signatures of each commit.
+// It must be called before the reactor is started.
+func (r *Reactor) SetCheckpoint(height int64, hash []byte) {
+	r.checkpoint = newCheckpoint(r.logger.With("checkpoint", height), height, hash)
+}
+
+// startSync imports the block archive and walks the hash chain of the trusted
+// checkpoint, if any, in the background and then starts the pool to fetch the
+// remaining blocks from peers.
+func (r *Reactor) startSync(ctx context.Context, stateSynced bool) error {
+	if r.archivePath == "" && r.checkpoint == nil {
+		return r.startPool(ctx, stateSynced)
+	}
+
+	r.poolWG.Add(1)
+	go func() {
+		defer r.poolWG.Done()
+
+		// Like after state sync, consensus must skip the WAL of the
+		// imported heights.
+		if r.archivePath != "" && r.importArchive(ctx) > 0 {
+			stateSynced = true
+		}
+		if r.checkpoint != nil {
+			r.walkCheckpoint(ctx)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err := r.startPool(ctx, stateSynced); err != nil {
+			r.logger.Error("failed to start block pool", "err", err)
+		}
+	}()
+	return nil
+}
+
+// startPool starts fetching blocks from peers, from the height above the
+// initial state.
+func (r *Reactor) startPool(ctx context.Context, stateSynced bool) error {
+	if err := r.pool.Start(ctx); err != nil {
+		return err
+	}
+
+	r.poolWG.Add(1)
+	go r.requestRoutine(ctx)
+
+	r.poolWG.Add(1)
+	go r.poolRoutine(ctx, stateSynced)
+
+	return nil
+}
+
+// importArchive applies the blocks in the block archive on top of the initial
+// state, and returns the number of blocks applied. If the archive is invalid,
+// the blocks applied so far are kept and the remaining blocks are fetched from
+// peers.
+func (r *Reactor) importArchive(ctx context.Context) int64 {
+	file, err := os.Open(r.archivePath)
+	if err != nil {
+		r.logger.Error("failed to open block archive", "path", r.archivePath, "err", err)
+		return 0
+	}
+	defer file.Close()
+
+	r.logger.Info("importing blocks from archive", "path", r.archivePath, "height", r.initialState.LastBlockHeight)
+	state, applied, err := ImportBlocks(ctx, r.initialState, r.blockExec, r.store, NewArchiveReader(file))
+	if err != nil && ctx.Err() == nil {
+		r.logger.Error("failed to import blocks from archive", "height", state.LastBlockHeight, "err", err)
+	} else {
+		r.logger.Info("imported blocks from archive", "height", state.LastBlockHeight, "blocks", applied)
+	}
+
+	if applied > 0 {
+		r.initialState = state
+		r.pool.mtx.Lock()
+		r.pool.height = state.LastBlockHeight + 1
+		r.pool.mtx.Unlock()
+	}
+	return applied
+}
+
+// walkCheckpoint walks the hash chain of the trusted checkpoint down to the
+// height above the initial state, fetching the blocks from peers.
+func (r *Reactor) walkCheckpoint(ctx context.Context) {
+	next := r.initialState.LastBlockHeight + 1
+	if r.initialState.LastBlockHeight == 0 {
+		next = r.initialState.InitialHeight
+	}
+	if next >= r.checkpoint.height {
+		r.logger.Info("already synced past trusted checkpoint", "height", r.checkpoint.height)
+		return
+	}
+
+	r.logger.Info("walking hash chain of trusted checkpoint",
+		"height", r.checkpoint.height, "hash", fmt.Sprintf("%X", r.checkpoint.hash), "target", next)
+	if r.checkpoint.walk(ctx, next, r.sendBridged, r.blockSyncCh.SendError) {
+		r.logger.Info("walked hash chain of trusted checkpoint", "height", r.checkpoint.height)
+	}
+}
+
+// sendBridged sends an envelope via the blockSyncOutBridgeCh.
+func (r *Reactor) sendBridged(ctx context.Context, envelope p2p.Envelope) error {
+	select {
+	case r.blockSyncOutBridgeCh <- envelope:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startBackfill starts backfilling blocks, if enabled and not already started.
+func (r *Reactor) startBackfill(ctx context.Context) {
+	if r.backfill == nil {
+		return
+	}
+	r.backfillOnce.Do(func() {
+		ctx, r.backfillCancel = context.WithCancel(ctx)
+		r.backfillWG.Add(1)
+		go func() {
+			defer r.backfillWG.Done()
+			r.backfill.run(ctx, r.sendBridged, r.blockSyncCh.SendError)
+		}()
+	})
 }
 
 // respondToPeer loads a block and sends it to the requesting peer, if we have it.
 // Otherwise, we'll respond saying we do not have it.
 func (r *Reactor) respondToPeer(ctx context.Context, msg *bcproto.BlockRequest, peerID types.NodeID) error {
-	block := r.store.LoadBlock(msg.Height)
+	return r.sendBlock(ctx, msg.Height, peerID)
+}
+
+// respondToRange sends each block in the requested range to the requesting
+// peer, in ascending order, or a NoBlockResponse for the blocks we do not have.
+func (r *Reactor) respondToRange(ctx context.Context, msg *bcproto.BlockRangeRequest, peerID types.NodeID) error {
+	if msg.EndHeight-msg.StartHeight >= maxBlockRangeRequest {
+		return fmt.Errorf("block range %v-%v exceeds the maximum of %v blocks",
+			msg.StartHeight, msg.EndHeight, maxBlockRangeRequest)
+	}
+
+	for height := msg.StartHeight; height <= msg.EndHeight; height++ {
+		if err := r.sendBlock(ctx, height, peerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reactor) sendBlock(ctx context.Context, height int64, peerID types.NodeID) error {
+	block := r.store.LoadBlock(height)
 	if block != nil {
 		blockProto, err := block.ToProto()
 		if err != nil {
@@ -201,11 +402,49 @@ func (r *Reactor) respondToPeer(ctx context.Context, msg *bcproto.BlockRequest,
 		})
 	}
 
-	r.logger.Info("peer requesting a block we do not have", "peer", peerID, "height", msg.Height)
+	r.logger.Info("peer requesting a block we do not have", "peer", peerID, "height", height)
 
 	return r.blockSyncCh.Send(ctx, p2p.Envelope{
 		To:      peerID,
-		Message: &bcproto.NoBlockResponse{Height: msg.Height},
+		Message: &bcproto.NoBlockResponse{Height: height},
+	})
+}
+
+// respondToResults sends the DeliverTx responses and the BeginBlock and
+// EndBlock events of the requested block to the peer, or a
+// NoBlockResultsResponse if we do not have them.
+func (r *Reactor) respondToResults(ctx context.Context, msg *bcproto.BlockResultsRequest, peerID types.NodeID) error {
+	var response proto.Message = &bcproto.NoBlockResultsResponse{Height: msg.Height}
+	if results, err := r.blockExec.Store().LoadABCIResponses(msg.Height); err == nil {
+		resultsResponse := &bcproto.BlockResultsResponse{Height: msg.Height, DeliverTxs: results.DeliverTxs}
+		if results.BeginBlock != nil {
+			resultsResponse.BeginBlockEvents = results.BeginBlock.Events
+		}
+		if results.EndBlock != nil {
+			resultsResponse.EndBlockEvents = results.EndBlock.Events
+		}
+		if resultsResponse.Size() < MaxMsgSize {
+			response = resultsResponse
+		}
+	}
+
+	return r.blockSyncCh.Send(ctx, p2p.Envelope{
+		To:      peerID,
+		Message: response,
+	})
+}
+
+// respondToHeader sends the header of the requested block to the peer, or a
+// NoBlockResponse if we do not have it.
+func (r *Reactor) respondToHeader(ctx context.Context, msg *bcproto.HeaderRequest, peerID types.NodeID) error {
+	var response proto.Message = &bcproto.NoBlockResponse{Height: msg.Height}
+	if meta := r.store.LoadBlockMeta(msg.Height); meta != nil {
+		response = &bcproto.HeaderResponse{Header: meta.Header.ToProto()}
+	}
+
+	return r.blockSyncCh.Send(ctx, p2p.Envelope{
+		To:      peerID,
+		Message: response,
 	})
 }
 
@@ -218,6 +457,21 @@ func (r *Reactor) handleBlockSyncMessage(ctx context.Context, envelope p2p.Envel
 	switch msg := envelope.Message.(type) {
 	case *bcproto.BlockRequest:
 		return r.respondToPeer(ctx, msg, envelope.From)
+	case *bcproto.BlockRangeRequest:
+		return r.respondToRange(ctx, msg, envelope.From)
+	case *bcproto.BlockResultsRequest:
+		return r.respondToResults(ctx, msg, envelope.From)
+	case *bcproto.HeaderRequest:
+		return r.respondToHeader(ctx, msg, envelope.From)
+	case *bcproto.HeaderResponse:
+		header, err := types.HeaderFromProto(msg.Header)
+		if err != nil {
+			logger.Error("failed to convert header from proto", "err", err)
+			return err
+		}
+		if r.checkpoint != nil {
+			r.checkpoint.fetcher.addHeader(envelope.From, &header)
+		}
 	case *bcproto.BlockResponse:
 		block, err := types.BlockFromProto(msg.Block)
 		if err != nil {
@@ -225,21 +479,69 @@ func (r *Reactor) handleBlockSyncMessage(ctx context.Context, envelope p2p.Envel
 			return err
 		}
 
+		if r.backfill != nil && r.backfill.addBlock(envelope.From, block) {
+			return nil
+		}
+		if r.checkpoint != nil && r.checkpoint.fetcher.addBlock(envelope.From, block) {
+			return nil
+		}
 		r.pool.AddBlock(envelope.From, block, block.Size())
 
 	case *bcproto.StatusRequest:
 		return r.blockSyncCh.Send(ctx, p2p.Envelope{
 			To: envelope.From,
 			Message: &bcproto.StatusResponse{
-				Height: r.store.Height(),
-				Base:   r.store.Base(),
+				Height:        r.store.Height(),
+				Base:          r.store.Base(),
+				RangeRequests: true,
+				BlockResults:  true,
+				Headers:       true,
 			},
 		})
 	case *bcproto.StatusResponse:
-		r.pool.SetPeerRange(envelope.From, msg.Base, msg.Height)
+		r.pool.SetPeerRange(envelope.From, msg.Base, msg.Height, msg.RangeRequests)
+		if r.backfill != nil {
+			r.backfill.setPeerStatus(envelope.From, msg)
+		}
+		if r.checkpoint != nil {
+			r.checkpoint.fetcher.setPeerStatus(envelope.From, msg)
+		}
+
+		// Peers send their status regularly, so the range is dropped rather
+		// than blocking if the buffer is full.
+		select {
+		case r.peerRangeCh <- p2p.PeerUpdate{
+			NodeID: envelope.From,
+			Status: p2p.PeerStatusHeight,
+			Base:   msg.Base,
+			Height: msg.Height,
+		}:
+		default:
+		}
 
 	case *bcproto.NoBlockResponse:
 		logger.Debug("peer does not have the requested block", "height", msg.Height)
+		if r.backfill != nil {
+			r.backfill.noBlock(envelope.From, msg.Height)
+		}
+		if r.checkpoint != nil {
+			r.checkpoint.fetcher.noBlock(envelope.From, msg.Height)
+		}
+
+	case *bcproto.BlockResultsResponse:
+		if r.backfill != nil {
+			r.backfill.addResults(envelope.From, msg.Height, &tmstate.ABCIResponses{
+				DeliverTxs: msg.DeliverTxs,
+				BeginBlock: &abci.ResponseBeginBlock{Events: msg.BeginBlockEvents},
+				EndBlock:   &abci.ResponseEndBlock{Events: msg.EndBlockEvents},
+			})
+		}
+
+	case *bcproto.NoBlockResultsResponse:
+		logger.Debug("peer does not have the requested block results", "height", msg.Height)
+		if r.backfill != nil {
+			r.backfill.noResults(envelope.From, msg.Height)
+		}
 
 	default:
 		return fmt.Errorf("received unknown message: %T", msg)
@@ -324,13 +626,22 @@ func (r *Reactor) processPeerUpdate(peerUpdate p2p.PeerUpdate) {
 		r.blockSyncOutBridgeCh <- p2p.Envelope{
 			To: peerUpdate.NodeID,
 			Message: &bcproto.StatusResponse{
-				Base:   r.store.Base(),
-				Height: r.store.Height(),
+				Base:          r.store.Base(),
+				Height:        r.store.Height(),
+				RangeRequests: true,
+				BlockResults:  true,
+				Headers:       true,
 			},
 		}
 
 	case p2p.PeerStatusDown:
 		r.pool.RemovePeer(peerUpdate.NodeID)
+		if r.backfill != nil {
+			r.backfill.removePeer(peerUpdate.NodeID)
+		}
+		if r.checkpoint != nil {
+			r.checkpoint.fetcher.removePeer(peerUpdate.NodeID)
+		}
 	}
 }
 
@@ -349,6 +660,19 @@ func (r *Reactor) processPeerUpdates(ctx context.Context) {
 	}
 }
 
+// processPeerRanges reports the block ranges of peers to the peer manager, so
+// that it can prefer peers that are able to serve syncing nodes.
+func (r *Reactor) processPeerRanges(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case peerUpdate := <-r.peerRangeCh:
+			r.peerUpdates.SendUpdate(ctx, peerUpdate)
+		}
+	}
+}
+
 // SwitchToBlockSync is called by the state sync reactor when switching to fast
 // sync.
 func (r *Reactor) SwitchToBlockSync(ctx context.Context, state sm.State) error {
@@ -356,19 +680,9 @@ func (r *Reactor) SwitchToBlockSync(ctx context.Context, state sm.State) error {
 	r.initialState = state
 	r.pool.height = state.LastBlockHeight + 1
 
-	if err := r.pool.Start(ctx); err != nil {
-		return err
-	}
-
 	r.syncStartTime = time.Now()
 
-	r.poolWG.Add(1)
-	go r.requestRoutine(ctx)
-
-	r.poolWG.Add(1)
-	go r.poolRoutine(ctx, true)
-
-	return nil
+	return r.startSync(ctx, true)
 }
 
 func (r *Reactor) requestRoutine(ctx context.Context) {
@@ -382,9 +696,13 @@ func (r *Reactor) requestRoutine(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case request := <-r.requestsCh:
+			var msg proto.Message = &bcproto.BlockRequest{Height: request.Height}
+			if request.EndHeight > request.Height {
+				msg = &bcproto.BlockRangeRequest{StartHeight: request.Height, EndHeight: request.EndHeight}
+			}
 			r.blockSyncOutBridgeCh <- p2p.Envelope{
 				To:      request.PeerID,
-				Message: &bcproto.BlockRequest{Height: request.Height},
+				Message: msg,
 			}
 		case pErr := <-r.errorsCh:
 			if err := r.blockSyncCh.SendError(ctx, p2p.PeerError{
@@ -429,6 +747,8 @@ func (r *Reactor) poolRoutine(ctx context.Context, stateSynced bool) {
 		lastRate    = 0.0
 
 		didProcessCh = make(chan struct{}, 1)
+
+		verifier = newBlockVerifier(chainID, r.metrics)
 	)
 
 	defer trySyncTicker.Stop()
@@ -436,6 +756,16 @@ func (r *Reactor) poolRoutine(ctx context.Context, stateSynced bool) {
 
 	defer r.poolWG.Done()
 
+	verifyCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	verifier.setState(state)
+	verifier.run(verifyCtx, runtime.NumCPU())
+
+	// Prefer peers that have the blocks we need, until we're done syncing.
+	startHeight, _, _ := r.pool.GetStatus()
+	r.peerUpdates.SetSyncTarget(p2p.SyncTarget{Height: startHeight, RequireBase: true})
+	defer r.peerUpdates.SetSyncTarget(p2p.SyncTarget{})
+
 FOR_LOOP:
 	for {
 		select {
@@ -466,6 +796,7 @@ FOR_LOOP:
 					"max_peer_height", r.pool.MaxPeerHeight(),
 					"timeout_in", syncTimeout-time.Since(lastAdvance),
 				)
+				r.peerUpdates.SetSyncTarget(p2p.SyncTarget{Height: height, RequireBase: true})
 				continue
 			}
 
@@ -479,6 +810,8 @@ FOR_LOOP:
 				r.consReactor.SwitchToConsensus(ctx, state, blocksSynced > 0 || stateSynced)
 			}
 
+			r.startBackfill(ctx)
+
 			break FOR_LOOP
 
 		case <-trySyncTicker.C:
@@ -497,28 +830,56 @@ FOR_LOOP:
 			//
 			// TODO: Uncouple from request routine.
 
-			// see if there are any blocks to sync
-			first, second := r.pool.PeekTwoBlocks()
-			if first == nil || second == nil {
-				// we need both to sync the first block
+			// see if there are any blocks to sync, and verify the commits of
+			// upcoming blocks ahead of execution
+			blocks := r.pool.PeekBlocks(verifyWindow + 1)
+			if len(blocks) < 2 || !r.checkpoint.ready(blocks) {
+				// we need both to sync the first block, and below the trusted
+				// checkpoint the blocks up to the next anchor of its hash chain
 				continue FOR_LOOP
 			} else {
 				// try again quickly next loop
 				didProcessCh <- struct{}{}
 			}
+			verifier.schedule(r.checkpoint.untrusted(blocks))
+			first, second := blocks[0], blocks[1]
 
 			var (
-				firstParts         = first.MakePartSet(types.BlockPartSizeBytes)
-				firstPartSetHeader = firstParts.Header()
-				firstID            = types.BlockID{Hash: first.Hash(), PartSetHeader: firstPartSetHeader}
+				firstParts *types.PartSet
+				firstID    types.BlockID
+				err        error
+				trusted    = r.checkpoint.trusts(first.Height)
 			)
+			if trusted {
+				// Below the trusted checkpoint, the first block and its
+				// commit are verified by the hash chain instead.
+				var invalid int64
+				firstParts, firstID, invalid, err = r.checkpoint.verify(blocks)
+				if err != nil {
+					r.logger.Error("invalid block below trusted checkpoint", "height", invalid, "err", err)
 
-			// Finally, verify the first block using the second's commit.
-			//
-			// NOTE: We can probably make this more efficient, but note that calling
-			// first.Hash() doesn't verify the tx contents, so MakePartSet() is
-			// currently necessary.
-			err := state.Validators.VerifyCommitLight(chainID, firstID, first.Height, second.LastCommit)
+					peerID := r.pool.RedoRequest(invalid)
+					if serr := r.blockSyncCh.SendError(ctx, p2p.PeerError{
+						NodeID:      peerID,
+						Err:         err,
+						Misbehavior: p2p.MisbehaviorInvalidBlock,
+					}); serr != nil {
+						break FOR_LOOP
+					}
+
+					continue FOR_LOOP
+				}
+			} else {
+				// Finally, verify the first block using the second's commit.
+				//
+				// NOTE: We can probably make this more efficient, but note that calling
+				// first.Hash() doesn't verify the tx contents, so MakePartSet() is
+				// currently necessary.
+				firstParts, firstID, err = verifier.verify(ctx, state, first, second)
+			}
+			if ctx.Err() != nil {
+				break FOR_LOOP
+			}
 			if err != nil {
 				err = fmt.Errorf("invalid last commit: %w", err)
 				r.logger.Error(
@@ -528,43 +889,45 @@ FOR_LOOP:
 					"height", first.Height,
 				)
 
-				// NOTE: We've already removed the peer's request, but we still need
-				// to clean up the rest.
-				peerID := r.pool.RedoRequest(first.Height)
+				// Only the peer of the block at fault is removed and penalized,
+				// the other block is kept.
+				peerID := r.pool.RedoRequest(faultyHeight(state, first, second))
 				if serr := r.blockSyncCh.SendError(ctx, p2p.PeerError{
-					NodeID: peerID,
-					Err:    err,
+					NodeID:      peerID,
+					Err:         err,
+					Misbehavior: p2p.MisbehaviorInvalidBlock,
 				}); serr != nil {
 					break FOR_LOOP
 				}
 
-				peerID2 := r.pool.RedoRequest(second.Height)
-				if peerID2 != peerID {
-					if serr := r.blockSyncCh.SendError(ctx, p2p.PeerError{
-						NodeID: peerID2,
-						Err:    err,
-					}); serr != nil {
-						break FOR_LOOP
-					}
-				}
-
 				continue FOR_LOOP
 			} else {
 				r.pool.PopRequest()
 
+				start := time.Now()
+
 				// TODO: batch saves so we do not persist to disk every block
 				r.store.SaveBlock(first, firstParts, second.LastCommit)
 
-				var err error
+				if trusted {
+					// The block's last commit is covered by the hash chain, so
+					// its signatures aren't verified when applying the block.
+					err = r.blockExec.ValidateTrustedBlock(state, first)
+				}
 
 				// TODO: Same thing for app - but we would need a way to get the hash
 				// without persisting the state.
-				state, err = r.blockExec.ApplyBlock(ctx, state, firstID, first)
+				if err == nil {
+					state, err = r.blockExec.ApplyBlock(ctx, state, firstID, first)
+				}
 				if err != nil {
 					// TODO: This is bad, are we zombie?
 					panic(fmt.Sprintf("failed to process committed block (%d:%X): %v", first.Height, first.Hash(), err))
 				}
 
+				r.metrics.BlockSyncExecuteSeconds.Observe(time.Since(start).Seconds())
+				verifier.setState(state)
+
 				r.metrics.RecordConsMetrics(first)
 
 				blocksSynced++
@@ -303,6 +303,82 @@ func TestReactor_NoBlockResponse(t *testing.T) {
 	}
 }
 
+func TestReactor_LegacyPeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_reactor_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	maxBlockHeight := int64(30)
+	source := setup(ctx, t, genDoc, privVals[0], []int64{maxBlockHeight}, 0)
+	sourceStore := source.reactors[source.nodes[0]].store
+
+	// The legacy peer serves the blocks of the source store, but doesn't
+	// advertise range requests, like a node running an older version.
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{0}, 0)
+	legacy := rts.network.MakeNode(ctx, t, p2ptest.NodeOptions{MaxPeers: 1, MaxConnected: 1})
+	rts.network.Nodes[legacy.NodeID] = legacy
+	legacyCh := legacy.MakeChannelNoCleanup(ctx, t, &p2p.ChannelDescriptor{
+		ID:          BlockSyncChannel,
+		MessageType: new(bcproto.Message),
+	})
+	rts.network.Start(ctx, t)
+
+	status := &bcproto.StatusResponse{Base: sourceStore.Base(), Height: sourceStore.Height()}
+	p2ptest.RequireSend(ctx, t, legacyCh, p2p.Envelope{To: rts.nodes[0], Message: status})
+
+	rangeRequests := make(chan *bcproto.BlockRangeRequest, 1)
+	go func() {
+		for {
+			var envelope p2p.Envelope
+			select {
+			case <-ctx.Done():
+				return
+			case envelope = <-legacyCh.In:
+			}
+
+			response := p2p.Envelope{To: envelope.From}
+			switch msg := envelope.Message.(type) {
+			case *bcproto.StatusRequest:
+				response.Message = status
+			case *bcproto.BlockRequest:
+				block, err := sourceStore.LoadBlock(msg.Height).ToProto()
+				require.NoError(t, err)
+				response.Message = &bcproto.BlockResponse{Block: block}
+			case *bcproto.BlockRangeRequest:
+				select {
+				case rangeRequests <- msg:
+				default:
+				}
+				continue
+			default:
+				continue
+			}
+			if err := legacyCh.Send(ctx, response); err != nil {
+				return
+			}
+		}
+	}()
+
+	// The blocks are requested one by one, and the node syncs up to the
+	// last block, which can't be verified without the next one.
+	require.Eventually(
+		t,
+		func() bool { return rts.reactors[rts.nodes[0]].store.Height() == maxBlockHeight-1 },
+		10*time.Second,
+		10*time.Millisecond,
+		"expected node to sync from the legacy peer",
+	)
+	select {
+	case msg := <-rangeRequests:
+		require.Fail(t, "legacy peer received a range request", "%v", msg)
+	default:
+	}
+}
+
 func TestReactor_BadBlockStopsPeer(t *testing.T) {
 	// Ultimately, this should be refactored to be less integration test oriented
 	// and more unit test oriented by simply testing channel sends and receives.
@@ -359,7 +435,7 @@ func TestReactor_BadBlockStopsPeer(t *testing.T) {
 	rts.addNode(ctx, t, newNode.NodeID, otherGenDoc, otherPrivVals[0], maxBlockHeight)
 
 	// add a fake peer just so we do not wait for the consensus ticker to timeout
-	rts.reactors[newNode.NodeID].pool.SetPeerRange("00ff", 10, 10)
+	rts.reactors[newNode.NodeID].pool.SetPeerRange("00ff", 10, 10, true)
 
 	// wait for the new peer to catch up and become fully synced
 	require.Eventually(
@@ -382,3 +458,50 @@ func TestReactor_BadBlockStopsPeer(t *testing.T) {
 		len(rts.reactors[newNode.NodeID].pool.peers),
 	)
 }
+
+func TestReactor_BlockResults(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_reactor_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{5}, 0)
+	source := rts.reactors[rts.nodes[0]]
+
+	outCh := make(chan p2p.Envelope, 1)
+	r := &Reactor{
+		logger:      log.TestingLogger(),
+		blockExec:   source.blockExec,
+		store:       source.store,
+		blockSyncCh: p2p.NewChannel(BlockSyncChannel, new(bcproto.Message), nil, outCh, nil),
+	}
+
+	// The results of stored blocks are served, and a NoBlockResultsResponse
+	// is sent for other heights.
+	peerID := types.NodeID("aa")
+	require.NoError(t, r.handleBlockSyncMessage(ctx, p2p.Envelope{
+		From:    peerID,
+		Message: &bcproto.BlockResultsRequest{Height: 3},
+	}))
+	results, err := source.blockExec.Store().LoadABCIResponses(3)
+	require.NoError(t, err)
+	require.NotEmpty(t, results.DeliverTxs)
+	envelope := <-outCh
+	require.Equal(t, peerID, envelope.To)
+	require.Equal(t, &bcproto.BlockResultsResponse{
+		Height:           3,
+		DeliverTxs:       results.DeliverTxs,
+		BeginBlockEvents: results.BeginBlock.Events,
+		EndBlockEvents:   results.EndBlock.Events,
+	}, envelope.Message)
+
+	require.NoError(t, r.handleBlockSyncMessage(ctx, p2p.Envelope{
+		From:    peerID,
+		Message: &bcproto.BlockResultsRequest{Height: 10},
+	}))
+	envelope = <-outCh
+	require.Equal(t, &bcproto.NoBlockResultsResponse{Height: 10}, envelope.Message)
+}
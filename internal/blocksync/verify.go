@@ -0,0 +1,197 @@
+package blocksync
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/internal/consensus"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// maximum number of heights above the next height to verify ahead of
+	// execution
+	verifyWindow = 64
+)
+
+// verifyJob verifies the commit of a block, using the last commit of the
+// block above it.
+type verifyJob struct {
+	first, second *types.Block
+	vals          *types.ValidatorSet
+	valsHash      []byte
+
+	done    chan struct{}
+	parts   *types.PartSet
+	blockID types.BlockID
+	err     error
+}
+
+// blockVerifier verifies the commits of upcoming blocks in worker goroutines,
+// ahead of their sequential execution, since signature verification is
+// independent for each height.
+//
+// The validator set of a height is only known once the block below it has
+// been executed, so commits are verified ahead with the validator set of the
+// current state (or the next one) if its hash matches the header. A result is
+// then only used if the state's validator set has that same hash when the
+// block is executed, and the commit is verified again otherwise.
+type blockVerifier struct {
+	chainID string
+	metrics *consensus.Metrics
+	jobsCh  chan *verifyJob
+
+	mtx  sync.Mutex
+	jobs map[int64]*verifyJob // by height
+
+	// the validator sets of the current state, and their hashes
+	vals, nextVals         *types.ValidatorSet
+	valsHash, nextValsHash []byte
+}
+
+func newBlockVerifier(chainID string, metrics *consensus.Metrics) *blockVerifier {
+	return &blockVerifier{
+		chainID: chainID,
+		metrics: metrics,
+		jobsCh:  make(chan *verifyJob, verifyWindow),
+		jobs:    make(map[int64]*verifyJob),
+	}
+}
+
+// run starts the given number of workers, until the context ends.
+func (v *blockVerifier) run(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-v.jobsCh:
+					v.process(job)
+				}
+			}
+		}()
+	}
+}
+
+func (v *blockVerifier) process(job *verifyJob) {
+	defer close(job.done)
+
+	start := time.Now()
+	job.parts = job.first.MakePartSet(types.BlockPartSizeBytes)
+	job.blockID = types.BlockID{Hash: job.first.Hash(), PartSetHeader: job.parts.Header()}
+	job.err = job.vals.VerifyCommitLight(v.chainID, job.blockID, job.first.Height, job.second.LastCommit)
+	v.metrics.BlockSyncVerifySeconds.Observe(time.Since(start).Seconds())
+}
+
+// setState sets the state that upcoming blocks are applied on.
+func (v *blockVerifier) setState(state sm.State) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	// The validator sets are copied, since workers use them concurrently.
+	if v.vals == nil || !bytes.Equal(state.Validators.Hash(), v.valsHash) {
+		v.vals = state.Validators.Copy()
+		v.vals.TotalVotingPower()
+		v.valsHash = v.vals.Hash()
+	}
+	if v.nextVals == nil || !bytes.Equal(state.NextValidators.Hash(), v.nextValsHash) {
+		v.nextVals = state.NextValidators.Copy()
+		v.nextVals.TotalVotingPower()
+		v.nextValsHash = v.nextVals.Hash()
+	}
+}
+
+// schedule verifies the commits of the given contiguous blocks ahead of
+// execution, except for the last one. Blocks that are already scheduled or
+// whose validator set isn't known yet are skipped.
+func (v *blockVerifier) schedule(blocks []*types.Block) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	for i := 0; i+1 < len(blocks); i++ {
+		first, second := blocks[i], blocks[i+1]
+		if job := v.jobs[first.Height]; job != nil && job.first == first && job.second == second {
+			continue
+		}
+
+		var vals *types.ValidatorSet
+		var valsHash []byte
+		switch {
+		case bytes.Equal(first.ValidatorsHash, v.valsHash):
+			vals, valsHash = v.vals, v.valsHash
+		case bytes.Equal(first.ValidatorsHash, v.nextValsHash):
+			vals, valsHash = v.nextVals, v.nextValsHash
+		default:
+			continue
+		}
+
+		job := &verifyJob{
+			first:    first,
+			second:   second,
+			vals:     vals,
+			valsHash: valsHash,
+			done:     make(chan struct{}),
+		}
+		select {
+		case v.jobsCh <- job:
+			v.jobs[first.Height] = job
+		default:
+			// The workers are busy, try again later.
+			return
+		}
+	}
+}
+
+// verify verifies the commit of the first block against the validator set
+// of the given state, using the second block's last commit. It waits for the
+// result of the verification ahead of execution, if there is a matching one,
+// and verifies the commit itself otherwise. It returns the first block's part
+// set and block ID, also if verification failed.
+func (v *blockVerifier) verify(
+	ctx context.Context,
+	state sm.State,
+	first, second *types.Block,
+) (*types.PartSet, types.BlockID, error) {
+	v.mtx.Lock()
+	job := v.jobs[first.Height]
+	delete(v.jobs, first.Height)
+	valsHash := v.valsHash
+	v.mtx.Unlock()
+
+	if job != nil && job.first == first && job.second == second && bytes.Equal(job.valsHash, valsHash) {
+		select {
+		case <-job.done:
+			return job.parts, job.blockID, job.err
+		case <-ctx.Done():
+			return nil, types.BlockID{}, ctx.Err()
+		}
+	}
+
+	start := time.Now()
+	parts := first.MakePartSet(types.BlockPartSizeBytes)
+	blockID := types.BlockID{Hash: first.Hash(), PartSetHeader: parts.Header()}
+	err := state.Validators.VerifyCommitLight(state.ChainID, blockID, first.Height, second.LastCommit)
+	v.metrics.BlockSyncVerifySeconds.Observe(time.Since(start).Seconds())
+	return parts, blockID, err
+}
+
+// faultyHeight returns the height of the block whose peer is at fault when the
+// first block fails verification against the second block's last commit. A
+// commit that isn't validly signed for its own block ID is the fault of the
+// second block's peer. A valid commit for another block ID shows that the first
+// block is not the committed one, which is the fault of the first block's peer.
+func faultyHeight(state sm.State, first, second *types.Block) int64 {
+	commit := second.LastCommit
+	if commit == nil {
+		return second.Height
+	}
+	err := state.Validators.VerifyCommitLight(state.ChainID, commit.BlockID, first.Height, commit)
+	if err != nil {
+		return second.Height
+	}
+	return first.Height
+}
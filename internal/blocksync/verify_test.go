@@ -0,0 +1,112 @@
+package blocksync
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/consensus"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/test/factory"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestBlockVerifier(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_verify_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{10}, 0)
+	source := rts.reactors[rts.nodes[0]].store
+
+	state, err := sm.MakeGenesisState(genDoc)
+	require.NoError(t, err)
+	blocks := []*types.Block{}
+	for height := int64(1); height <= 10; height++ {
+		blocks = append(blocks, source.LoadBlock(height))
+	}
+
+	verifier := newBlockVerifier(state.ChainID, consensus.NopMetrics())
+	verifier.setState(state)
+	verifier.run(ctx, 2)
+
+	// The commits of all blocks but the last are verified ahead.
+	verifier.schedule(blocks)
+	require.Len(t, verifier.jobs, 9)
+	for i := 0; i < 3; i++ {
+		parts, blockID, err := verifier.verify(ctx, state, blocks[i], blocks[i+1])
+		require.NoError(t, err)
+		require.Equal(t, source.LoadBlockMeta(blocks[i].Height).BlockID, blockID)
+		require.Equal(t, blockID.PartSetHeader, parts.Header())
+	}
+	require.Len(t, verifier.jobs, 6)
+
+	// A block received again from another peer replaces the verification
+	// ahead, and a forged commit fails verification.
+	forgedProto, err := blocks[4].ToProto()
+	require.NoError(t, err)
+	forged, err := types.BlockFromProto(forgedProto)
+	require.NoError(t, err)
+	sig := forged.LastCommit.Signatures[0].Signature
+	forged.LastCommit.Signatures[0].Signature = make([]byte, len(sig))
+	verifier.schedule([]*types.Block{blocks[3], forged})
+	require.True(t, verifier.jobs[blocks[3].Height].second == forged)
+	_, _, err = verifier.verify(ctx, state, blocks[3], forged)
+	require.Error(t, err)
+
+	// Without a matching verification ahead, the commit is verified directly.
+	_, _, err = verifier.verify(ctx, state, blocks[3], blocks[4])
+	require.NoError(t, err)
+
+	// Verifications ahead with another validator set aren't used.
+	vals, _ := factory.RandValidatorSet(1, 10)
+	other := state.Copy()
+	other.Validators = vals
+	verifier.setState(other)
+	_, _, err = verifier.verify(ctx, other, blocks[4], blocks[5])
+	require.Error(t, err)
+}
+
+func TestFaultyHeight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := config.ResetTestRoot("block_sync_faulty_height_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(cfg.RootDir)
+
+	genDoc, privVals := factory.RandGenesisDoc(cfg, 1, false, 30)
+	rts := setup(ctx, t, genDoc, privVals[0], []int64{3}, 0)
+	source := rts.reactors[rts.nodes[0]].store
+
+	state, err := sm.MakeGenesisState(genDoc)
+	require.NoError(t, err)
+	first, second := source.LoadBlock(1), source.LoadBlock(2)
+
+	copyBlock := func(block *types.Block) *types.Block {
+		pb, err := block.ToProto()
+		require.NoError(t, err)
+		block, err = types.BlockFromProto(pb)
+		require.NoError(t, err)
+		return block
+	}
+
+	// A block which doesn't match a validly signed commit is at fault.
+	forgedFirst := copyBlock(first)
+	forgedFirst.Data.Txs = types.Txs{types.Tx("forged")}
+	forgedFirst.DataHash = forgedFirst.Data.Hash()
+	require.Equal(t, first.Height, faultyHeight(state, forgedFirst, second))
+
+	// A commit with invalid signatures is at fault.
+	forgedSecond := copyBlock(second)
+	sig := forgedSecond.LastCommit.Signatures[0].Signature
+	forgedSecond.LastCommit.Signatures[0].Signature = make([]byte, len(sig))
+	require.Equal(t, second.Height, faultyHeight(state, first, forgedSecond))
+}
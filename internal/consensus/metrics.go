@@ -58,6 +58,10 @@ type Metrics struct {
 	BlockSyncing metrics.Gauge
 	// Whether or not a node is state syncing. 1 if yes, 0 if no.
 	StateSyncing metrics.Gauge
+	// Time spent verifying the commit of a block during block sync.
+	BlockSyncVerifySeconds metrics.Histogram
+	// Time spent saving and executing a block during block sync.
+	BlockSyncExecuteSeconds metrics.Histogram
 
 	// Number of blockparts transmitted by peer.
 	BlockParts metrics.Counter
@@ -184,6 +188,20 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "state_syncing",
 			Help:      "Whether or not a node is state syncing. 1 if yes, 0 if no.",
 		}, labels).With(labelsAndValues...),
+		BlockSyncVerifySeconds: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_sync_verify_seconds",
+			Help:      "Time spent verifying the commit of a block during block sync.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0001, 2, 14),
+		}, labels).With(labelsAndValues...),
+		BlockSyncExecuteSeconds: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_sync_execute_seconds",
+			Help:      "Time spent saving and executing a block during block sync.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0001, 2, 14),
+		}, labels).With(labelsAndValues...),
 		BlockParts: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -226,6 +244,9 @@ func NopMetrics() *Metrics {
 		BlockSyncing:    discard.NewGauge(),
 		StateSyncing:    discard.NewGauge(),
 		BlockParts:      discard.NewCounter(),
+
+		BlockSyncVerifySeconds:  discard.NewHistogram(),
+		BlockSyncExecuteSeconds: discard.NewHistogram(),
 	}
 }
 
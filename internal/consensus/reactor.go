@@ -191,6 +191,7 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 	go r.processVoteCh(ctx)
 	go r.processVoteSetBitsCh(ctx)
 	go r.processPeerUpdates(ctx)
+	go r.processPeerErrors(ctx)
 
 	return nil
 }
@@ -1328,6 +1329,12 @@ func (r *Reactor) handleMessage(ctx context.Context, chID p2p.ChannelID, envelop
 
 	msgI, err := MsgFromProto(protoMsg)
 	if err != nil {
+		switch protoMsg.Sum.(type) {
+		case *tmcons.Message_Vote:
+			return misbehaviorError{p2p.MisbehaviorInvalidVote, err}
+		case *tmcons.Message_BlockPart:
+			return misbehaviorError{p2p.MisbehaviorInvalidBlockPart, err}
+		}
 		return err
 	}
 
@@ -1368,8 +1375,9 @@ func (r *Reactor) processStateCh(ctx context.Context) {
 			if err := r.handleMessage(ctx, r.stateCh.ID, envelope); err != nil {
 				r.logger.Error("failed to process message", "ch_id", r.stateCh.ID, "envelope", envelope, "err", err)
 				if serr := r.stateCh.SendError(ctx, p2p.PeerError{
-					NodeID: envelope.From,
-					Err:    err,
+					NodeID:      envelope.From,
+					Err:         err,
+					Misbehavior: misbehavior(err),
 				}); serr != nil {
 					return
 				}
@@ -1393,8 +1401,9 @@ func (r *Reactor) processDataCh(ctx context.Context) {
 			if err := r.handleMessage(ctx, r.dataCh.ID, envelope); err != nil {
 				r.logger.Error("failed to process message", "ch_id", r.dataCh.ID, "envelope", envelope, "err", err)
 				if serr := r.dataCh.SendError(ctx, p2p.PeerError{
-					NodeID: envelope.From,
-					Err:    err,
+					NodeID:      envelope.From,
+					Err:         err,
+					Misbehavior: misbehavior(err),
 				}); serr != nil {
 					return
 				}
@@ -1418,8 +1427,9 @@ func (r *Reactor) processVoteCh(ctx context.Context) {
 			if err := r.handleMessage(ctx, r.voteCh.ID, envelope); err != nil {
 				r.logger.Error("failed to process message", "ch_id", r.voteCh.ID, "envelope", envelope, "err", err)
 				if serr := r.voteCh.SendError(ctx, p2p.PeerError{
-					NodeID: envelope.From,
-					Err:    err,
+					NodeID:      envelope.From,
+					Err:         err,
+					Misbehavior: misbehavior(err),
 				}); serr != nil {
 					return
 				}
@@ -1447,8 +1457,9 @@ func (r *Reactor) processVoteSetBitsCh(ctx context.Context) {
 
 				r.logger.Error("failed to process message", "ch_id", r.voteSetBitsCh.ID, "envelope", envelope, "err", err)
 				if serr := r.voteSetBitsCh.SendError(ctx, p2p.PeerError{
-					NodeID: envelope.From,
-					Err:    err,
+					NodeID:      envelope.From,
+					Err:         err,
+					Misbehavior: misbehavior(err),
 				}); serr != nil {
 					return
 				}
@@ -1457,6 +1468,46 @@ func (r *Reactor) processVoteSetBitsCh(ctx context.Context) {
 	}
 }
 
+// processPeerErrors reports peers whose votes or block parts fail verification
+// by the consensus state. When the reactor is stopped, we will catch the signal
+// and return.
+func (r *Reactor) processPeerErrors(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case peerErr := <-r.state.peerErrQueue:
+			ch := r.dataCh
+			if peerErr.Misbehavior == p2p.MisbehaviorInvalidVote {
+				ch = r.voteCh
+			}
+			if err := ch.SendError(ctx, peerErr); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// misbehaviorError is an error caused by a peer's message, along with the kind
+// of misbehavior the peer is penalized for.
+type misbehaviorError struct {
+	misbehavior p2p.Misbehavior
+	err         error
+}
+
+func (e misbehaviorError) Error() string { return e.err.Error() }
+func (e misbehaviorError) Unwrap() error { return e.err }
+
+// misbehavior returns the kind of misbehavior of an error returned by a
+// message handler, which is an invalid message unless classified otherwise.
+func misbehavior(err error) p2p.Misbehavior {
+	var merr misbehaviorError
+	if errors.As(err, &merr) {
+		return merr.misbehavior
+	}
+	return p2p.MisbehaviorInvalidMessage
+}
+
 // processPeerUpdates initiates a blocking process where we listen for and handle
 // PeerUpdate messages. When the reactor is stopped, we will catch the signal and
 // close the p2p PeerUpdatesCh gracefully.
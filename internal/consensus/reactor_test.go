@@ -32,6 +32,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	tmcons "github.com/tendermint/tendermint/proto/tendermint/consensus"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -834,3 +835,34 @@ func TestReactorValidatorSetChanges(t *testing.T) {
 
 	waitForBlockWithUpdatedValsAndValidateIt(ctx, t, nPeers, activeVals, blocksSubs, states)
 }
+
+func TestReactorClassifiesInvalidMessages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := &Reactor{logger: log.TestingLogger()}
+
+	err := r.handleMessage(ctx, VoteChannel, p2p.Envelope{
+		From:    "peer",
+		Message: &tmcons.Vote{Vote: &tmproto.Vote{Height: -1}},
+	})
+	require.Error(t, err)
+	require.Equal(t, p2p.MisbehaviorInvalidVote, misbehavior(err))
+
+	err = r.handleMessage(ctx, DataChannel, p2p.Envelope{
+		From: "peer",
+		Message: &tmcons.BlockPart{
+			Height: 1,
+			Part:   tmproto.Part{Bytes: make([]byte, types.BlockPartSizeBytes+1)},
+		},
+	})
+	require.Error(t, err)
+	require.Equal(t, p2p.MisbehaviorInvalidBlockPart, misbehavior(err))
+
+	err = r.handleMessage(ctx, StateChannel, p2p.Envelope{
+		From:    "peer",
+		Message: &tmcons.NewRoundStep{Height: 1, Step: 0},
+	})
+	require.Error(t, err)
+	require.Equal(t, p2p.MisbehaviorInvalidMessage, misbehavior(err))
+}
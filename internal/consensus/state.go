@@ -18,6 +18,7 @@ import (
 	"github.com/tendermint/tendermint/internal/eventbus"
 	"github.com/tendermint/tendermint/internal/libs/fail"
 	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+	"github.com/tendermint/tendermint/internal/p2p"
 	sm "github.com/tendermint/tendermint/internal/state"
 	tmevents "github.com/tendermint/tendermint/libs/events"
 	tmjson "github.com/tendermint/tendermint/libs/json"
@@ -117,6 +118,10 @@ type State struct {
 	// so statistics can be computed by reactor
 	statsMsgQueue chan msgInfo
 
+	// votes and block parts from peers which fail verification are reported
+	// on this channel, so that the reactor can penalize the peers
+	peerErrQueue chan p2p.PeerError
+
 	// we use eventBus to trigger msg broadcasts in the reactor,
 	// and to notify external subscribers, eg. through a websocket
 	eventBus *eventbus.EventBus
@@ -174,6 +179,7 @@ func NewState(
 		internalMsgQueue: make(chan msgInfo, msgQueueSize),
 		timeoutTicker:    NewTimeoutTicker(logger),
 		statsMsgQueue:    make(chan msgInfo, msgQueueSize),
+		peerErrQueue:     make(chan p2p.PeerError, msgQueueSize),
 		done:             make(chan struct{}),
 		doWALCatchup:     true,
 		wal:              nilWAL{},
@@ -923,6 +929,9 @@ func (cs *State) handleMsg(ctx context.Context, mi msgInfo) {
 				return
 			}
 		}
+		if errors.Is(err, types.ErrPartSetInvalidProof) {
+			cs.reportPeerError(peerID, err, p2p.MisbehaviorInvalidBlockPart)
+		}
 
 		if err != nil && msg.Round != cs.Round {
 			cs.logger.Debug(
@@ -978,6 +987,19 @@ func (cs *State) handleMsg(ctx context.Context, mi msgInfo) {
 	}
 }
 
+// reportPeerError passes a verification failure of a peer's message on to the
+// reactor. It is dropped if the reactor falls behind, since the consensus
+// state must not block on it.
+func (cs *State) reportPeerError(peerID types.NodeID, err error, misbehavior p2p.Misbehavior) {
+	if peerID == "" {
+		return // our own message
+	}
+	select {
+	case cs.peerErrQueue <- p2p.PeerError{NodeID: peerID, Err: err, Misbehavior: misbehavior}:
+	default:
+	}
+}
+
 func (cs *State) handleTimeout(
 	ctx context.Context,
 	ti timeoutInfo,
@@ -2056,6 +2078,14 @@ func (cs *State) tryAddVote(ctx context.Context, vote *types.Vote, peerID types.
 			return added, err
 		} else if errors.Is(err, types.ErrVoteNonDeterministicSignature) {
 			cs.logger.Debug("vote has non-deterministic signature", "err", err)
+		} else if errors.Is(err, types.ErrVoteInvalidSignature) ||
+			errors.Is(err, types.ErrVoteInvalidValidatorAddress) ||
+			errors.Is(err, types.ErrVoteInvalidValidatorIndex) {
+			// the vote can't have been signed by the validator, so the peer
+			// relaying it is at fault
+			cs.logger.Info("failed attempting to add vote", "err", err)
+			cs.reportPeerError(peerID, err, p2p.MisbehaviorInvalidVote)
+			return added, ErrAddingVote
 		} else {
 			// Either
 			// 1) bad peer OR
@@ -14,6 +14,7 @@ import (
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	cstypes "github.com/tendermint/tendermint/internal/consensus/types"
 	"github.com/tendermint/tendermint/internal/eventbus"
+	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/libs/log"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	tmrand "github.com/tendermint/tendermint/libs/rand"
@@ -2054,6 +2055,38 @@ func TestStateOutputVoteStats(t *testing.T) {
 
 }
 
+func TestStateReportsInvalidVotes(t *testing.T) {
+	config := configSetup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cs, vss, err := randState(ctx, config, log.TestingLogger(), 2)
+	require.NoError(t, err)
+	peerID, err := types.NewNodeID("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+	require.NoError(t, err)
+
+	vote := signVote(ctx, vss[1], config, tmproto.PrecommitType, tmrand.Bytes(tmhash.Size), types.PartSetHeader{})
+	vote.Signature = tmrand.Bytes(len(vote.Signature))
+	cs.handleMsg(ctx, msgInfo{&VoteMessage{vote}, peerID})
+
+	peerErr := <-cs.peerErrQueue
+	require.Equal(t, peerID, peerErr.NodeID)
+	require.Equal(t, p2p.MisbehaviorInvalidVote, peerErr.Misbehavior)
+	require.ErrorIs(t, peerErr.Err, types.ErrVoteInvalidSignature)
+
+	// votes for other heights can't be verified, and are not reported
+	incrementHeight(vss[1])
+	vote = signVote(ctx, vss[1], config, tmproto.PrecommitType, tmrand.Bytes(tmhash.Size), types.PartSetHeader{})
+	vote.Signature = tmrand.Bytes(len(vote.Signature))
+	cs.handleMsg(ctx, msgInfo{&VoteMessage{vote}, peerID})
+
+	select {
+	case peerErr := <-cs.peerErrQueue:
+		t.Errorf("unexpected peer error %v", peerErr.Err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 func TestSignSameVoteTwice(t *testing.T) {
 	config := configSetup(t)
 	ctx, cancel := context.WithCancel(context.Background())
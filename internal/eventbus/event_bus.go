@@ -127,6 +127,10 @@ func (b *EventBus) PublishEventStateSyncStatus(ctx context.Context, data types.E
 	return b.Publish(ctx, types.EventStateSyncStatusValue, data)
 }
 
+func (b *EventBus) PublishEventStateSyncProgress(ctx context.Context, data types.EventDataStateSyncProgress) error {
+	return b.Publish(ctx, types.EventStateSyncProgressValue, data)
+}
+
 // PublishEventTx publishes tx event with events from Result. Note it will add
 // predefined keys (EventTypeKey, TxHashKey). Existing events with the same keys
 // will be overwritten.
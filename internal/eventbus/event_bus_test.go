@@ -388,6 +388,7 @@ func TestEventBusPublish(t *testing.T) {
 	require.NoError(t, eventBus.PublishEventValidatorSetUpdates(ctx, types.EventDataValidatorSetUpdates{}))
 	require.NoError(t, eventBus.PublishEventBlockSyncStatus(ctx, types.EventDataBlockSyncStatus{}))
 	require.NoError(t, eventBus.PublishEventStateSyncStatus(ctx, types.EventDataStateSyncStatus{}))
+	require.NoError(t, eventBus.PublishEventStateSyncProgress(ctx, types.EventDataStateSyncProgress{}))
 
 	require.GreaterOrEqual(t, <-count, numEventsExpected)
 }
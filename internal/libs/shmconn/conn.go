@@ -0,0 +1,374 @@
+// Package shmconn implements a connection for processes on the same host that
+// keeps the control stream on a unix socket, but moves large payloads through
+// shared memory regions instead of copying them through the kernel's socket
+// buffers.
+//
+// Each side writes into its own region and only reads from the other side's.
+// The socket carries a stream of frames: small writes are
+// sent inline, large writes are copied into the ring and announced by their
+// offset and length. The reader acknowledges every shared frame once it has
+// copied it out, which lets the writer reuse the space. If a write does not
+// fit in the free part of the ring it is sent inline, so a slow reader never
+// blocks the writer on shared memory.
+//
+// Acknowledgements are sent by a separate goroutine, so that the reader never
+// waits on the socket being writable.
+//
+// The byte stream seen by Read and Write is identical to that of a plain
+// socket, so protocols framed on top of a net.Conn work unchanged.
+package shmconn
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+)
+
+const (
+	frameInline byte = iota
+	frameShared
+	frameAck
+)
+
+const (
+	// DefaultRingSize is the default size of each of the two shared memory
+	// regions of a connection.
+	DefaultRingSize = 64 * 1024 * 1024 // 64MB
+
+	// DefaultMinSharedSize is the default size from which writes go through
+	// shared memory. Smaller writes are cheaper to send inline.
+	DefaultMinSharedSize = 16 * 1024 // 16KB
+)
+
+// ErrClosed is returned by operations on a closed connection.
+var ErrClosed = errors.New("shmconn: use of closed connection")
+
+// Conn is a net.Conn whose large writes are transferred through shared memory.
+type Conn struct {
+	net.Conn
+
+	minShared int
+
+	br *bufio.Reader
+
+	wMtx tmsync.Mutex // serializes frames written to the socket
+	wbuf []byte       // buffer for small inline frames, guarded by wMtx
+	tx   *ring
+
+	// read state, only accessed by the reading goroutine
+	rx     []byte
+	inline int    // bytes left in the current inline frame
+	shared []byte // unread part of the current shared frame
+
+	ackMtx     tmsync.Mutex
+	pendingAck uint64        // shared frames read but not acknowledged yet
+	ackCh      chan struct{} // signals pending acknowledgements
+	done       chan struct{} // closed on Close
+
+	onRelease func() error // releases the regions on Close
+
+	// mapMtx guards the regions: Close unmaps them, so copies in and out of
+	// them must not overlap with it.
+	mapMtx tmsync.RWMutex
+	closed bool
+}
+
+var _ net.Conn = (*Conn)(nil)
+
+// newConn wraps conn. Writes go through tx, reads come from rx. release, if
+// set, is called once on Close after the regions are no longer accessed.
+func newConn(conn net.Conn, tx, rx []byte, minShared int, release func() error) *Conn {
+	if minShared <= 0 {
+		minShared = DefaultMinSharedSize
+	}
+	c := &Conn{
+		Conn:      conn,
+		minShared: minShared,
+		br:        bufio.NewReader(conn),
+		tx:        newRing(tx),
+		rx:        rx,
+		ackCh:     make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		onRelease: release,
+	}
+	go c.ackRoutine()
+	return c
+}
+
+// Write implements net.Conn.
+func (c *Conn) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	c.wMtx.Lock()
+	defer c.wMtx.Unlock()
+
+	if len(p) >= c.minShared {
+		if off, ok := c.tx.alloc(len(p)); ok {
+			if err := c.copyIn(off, p); err != nil {
+				return 0, err
+			}
+			if err := c.writeHeader(frameShared, uint64(off), uint64(len(p))); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+
+	if len(p) < c.minShared {
+		// send header and payload with a single write
+		c.wbuf = appendHeader(c.wbuf[:0], frameInline, uint64(len(p)))
+		c.wbuf = append(c.wbuf, p...)
+		if _, err := c.Conn.Write(c.wbuf); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	if err := c.writeHeader(frameInline, uint64(len(p))); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(p)
+}
+
+func (c *Conn) copyIn(off int, p []byte) error {
+	c.mapMtx.RLock()
+	defer c.mapMtx.RUnlock()
+	if c.closed {
+		return ErrClosed
+	}
+	copy(c.tx.buf[off:], p)
+	return nil
+}
+
+// writeHeader writes a frame header. The caller must hold wMtx.
+func (c *Conn) writeHeader(kind byte, values ...uint64) error {
+	var hdr [1 + 2*binary.MaxVarintLen64]byte
+	_, err := c.Conn.Write(appendHeader(hdr[:0], kind, values...))
+	return err
+}
+
+func appendHeader(buf []byte, kind byte, values ...uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	buf = append(buf, kind)
+	for _, v := range values {
+		n := binary.PutUvarint(tmp[:], v)
+		buf = append(buf, tmp[:n]...)
+	}
+	return buf
+}
+
+// Read implements net.Conn.
+func (c *Conn) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	for c.inline == 0 && len(c.shared) == 0 {
+		if err := c.readFrame(); err != nil {
+			return 0, err
+		}
+	}
+
+	if c.inline > 0 {
+		if len(p) > c.inline {
+			p = p[:c.inline]
+		}
+		n, err := c.br.Read(p)
+		c.inline -= n
+		return n, err
+	}
+
+	c.mapMtx.RLock()
+	if c.closed {
+		c.mapMtx.RUnlock()
+		return 0, ErrClosed
+	}
+	n := copy(p, c.shared)
+	c.mapMtx.RUnlock()
+
+	c.shared = c.shared[n:]
+	if len(c.shared) == 0 {
+		c.ackMtx.Lock()
+		c.pendingAck++
+		c.ackMtx.Unlock()
+		select {
+		case c.ackCh <- struct{}{}:
+		default:
+		}
+	}
+	return n, nil
+}
+
+// ackRoutine acknowledges the shared frames consumed by Read.
+func (c *Conn) ackRoutine() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.ackCh:
+		}
+
+		c.ackMtx.Lock()
+		count := c.pendingAck
+		c.pendingAck = 0
+		c.ackMtx.Unlock()
+		if count == 0 {
+			continue
+		}
+
+		c.wMtx.Lock()
+		err := c.writeHeader(frameAck, count)
+		c.wMtx.Unlock()
+		if err != nil {
+			// the connection is broken, the next read or write will fail
+			return
+		}
+	}
+}
+
+// readFrame reads the next frame header, handling acknowledgements directly.
+func (c *Conn) readFrame() error {
+	kind, err := c.br.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case frameAck:
+		count, err := binary.ReadUvarint(c.br)
+		if err != nil {
+			return err
+		}
+		return c.tx.release(count)
+
+	case frameInline:
+		n, err := binary.ReadUvarint(c.br)
+		if err != nil {
+			return err
+		}
+		if n > uint64(maxInt) {
+			return fmt.Errorf("shmconn: inline frame of %d bytes", n)
+		}
+		c.inline = int(n)
+		return nil
+
+	case frameShared:
+		off, err := binary.ReadUvarint(c.br)
+		if err != nil {
+			return err
+		}
+		n, err := binary.ReadUvarint(c.br)
+		if err != nil {
+			return err
+		}
+		if off > uint64(len(c.rx)) || n > uint64(len(c.rx))-off {
+			return fmt.Errorf("shmconn: shared frame [%d, %d) out of bounds of %d byte region",
+				off, off+n, len(c.rx))
+		}
+		c.shared = c.rx[off : off+n]
+		return nil
+
+	default:
+		return fmt.Errorf("shmconn: unknown frame type %d", kind)
+	}
+}
+
+// Close implements net.Conn. It closes the socket and releases the shared
+// memory regions.
+func (c *Conn) Close() error {
+	c.mapMtx.Lock()
+	if c.closed {
+		c.mapMtx.Unlock()
+		return ErrClosed
+	}
+	c.closed = true
+	c.mapMtx.Unlock()
+	close(c.done)
+
+	err := c.Conn.Close()
+	if c.onRelease != nil {
+		if rerr := c.onRelease(); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// ring allocates space for shared frames in a region. Frames are released in
+// the order they were allocated, since the reader consumes them in order.
+type ring struct {
+	mtx  tmsync.Mutex
+	buf  []byte
+	head int      // offset of the next allocation
+	used [][2]int // offset and length of unreleased frames, oldest first
+}
+
+func newRing(buf []byte) *ring {
+	return &ring{buf: buf}
+}
+
+// alloc reserves n contiguous bytes and returns their offset, or false if
+// there is not enough free space.
+func (r *ring) alloc(n int) (int, bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	size := len(r.buf)
+	if n > size {
+		return 0, false
+	}
+	if len(r.used) == 0 {
+		r.head = 0
+	}
+
+	off := -1
+	switch {
+	case len(r.used) == 0:
+		off = 0
+	default:
+		tail := r.used[0][0]
+		switch {
+		case r.head == tail:
+			// full
+		case r.head > tail:
+			// free space is [head, size) and [0, tail)
+			if size-r.head >= n {
+				off = r.head
+			} else if tail >= n {
+				off = 0
+			}
+		default:
+			// free space is [head, tail)
+			if tail-r.head >= n {
+				off = r.head
+			}
+		}
+	}
+	if off < 0 {
+		return 0, false
+	}
+
+	r.used = append(r.used, [2]int{off, n})
+	r.head = off + n
+	return off, true
+}
+
+// release frees the count oldest allocated frames.
+func (r *ring) release(count uint64) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if count > uint64(len(r.used)) {
+		return fmt.Errorf("shmconn: %d acknowledgements for %d shared frames", count, len(r.used))
+	}
+	r.used = r.used[count:]
+	return nil
+}
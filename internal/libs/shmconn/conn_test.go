@@ -0,0 +1,119 @@
+package shmconn
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newPipe returns two connected Conns backed by heap allocated regions.
+func newPipe(t *testing.T, size, minShared int) (*Conn, *Conn) {
+	t.Helper()
+
+	a, b := net.Pipe()
+	ab, ba := make([]byte, size), make([]byte, size)
+	ca := newConn(a, ab, ba, minShared, nil)
+	cb := newConn(b, ba, ab, minShared, nil)
+	t.Cleanup(func() {
+		_ = ca.Close()
+		_ = cb.Close()
+	})
+	return ca, cb
+}
+
+func TestConn_ByteStream(t *testing.T) {
+	// a small region forces both shared frames and inline fallbacks
+	client, server := newPipe(t, 4096, 512)
+
+	r := rand.New(rand.NewSource(1))
+	var sent bytes.Buffer
+	for i := 0; i < 200; i++ {
+		p := make([]byte, r.Intn(3000))
+		r.Read(p)
+		sent.Write(p)
+	}
+	expected := sent.Bytes()
+
+	errCh := make(chan error, 1)
+	go func() {
+		data := expected
+		for len(data) > 0 {
+			n := r.Intn(3000) + 1
+			if n > len(data) {
+				n = len(data)
+			}
+			if _, err := client.Write(data[:n]); err != nil {
+				errCh <- err
+				return
+			}
+			data = data[n:]
+		}
+		errCh <- nil
+	}()
+
+	received := make([]byte, len(expected))
+	_, err := io.ReadFull(server, received)
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+	require.Equal(t, expected, received)
+}
+
+func TestConn_SharedFrameBounds(t *testing.T) {
+	a, b := net.Pipe()
+	defer a.Close()
+	conn := newConn(b, make([]byte, 16), make([]byte, 16), 0, nil)
+	defer conn.Close()
+
+	go func() {
+		// announce a frame reaching past the end of the region
+		_, _ = a.Write(appendHeader(nil, frameShared, 8, 9))
+	}()
+
+	_, err := conn.Read(make([]byte, 16))
+	require.Error(t, err)
+}
+
+func TestRing(t *testing.T) {
+	r := newRing(make([]byte, 100))
+
+	off, ok := r.alloc(101)
+	require.False(t, ok)
+
+	off, ok = r.alloc(60)
+	require.True(t, ok)
+	require.Equal(t, 0, off)
+
+	off, ok = r.alloc(40)
+	require.True(t, ok)
+	require.Equal(t, 60, off)
+
+	// full
+	_, ok = r.alloc(1)
+	require.False(t, ok)
+
+	// releasing the first frame frees [0, 60)
+	require.NoError(t, r.release(1))
+	off, ok = r.alloc(50)
+	require.True(t, ok)
+	require.Equal(t, 0, off)
+
+	// [50, 60) is free but too small
+	_, ok = r.alloc(20)
+	require.False(t, ok)
+	off, ok = r.alloc(10)
+	require.True(t, ok)
+	require.Equal(t, 50, off)
+
+	require.Error(t, r.release(4))
+	require.NoError(t, r.release(2))
+
+	// once everything is released, allocation restarts at the beginning
+	require.NoError(t, r.release(1))
+	off, ok = r.alloc(100)
+	require.True(t, ok)
+	require.Equal(t, 0, off)
+}
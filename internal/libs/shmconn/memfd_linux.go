@@ -0,0 +1,198 @@
+//go:build linux
+// +build linux
+
+package shmconn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// handshakeMagic starts the setup message sent by the client.
+var handshakeMagic = []byte("abcishm1")
+
+// Client sets up the shared memory regions of a connection on conn, an
+// established unix socket connection whose other end calls Server. It
+// creates two regions of size bytes each (DefaultRingSize if size is not
+// positive) and passes their file descriptors to the server.
+func Client(conn *net.UnixConn, size int) (*Conn, error) {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+
+	tx, txFd, err := createRegion("abci-shm-client", size)
+	if err != nil {
+		return nil, err
+	}
+	rx, rxFd, err := createRegion("abci-shm-server", size)
+	if err != nil {
+		_ = unix.Munmap(tx)
+		_ = unix.Close(txFd)
+		return nil, err
+	}
+	release := func() error {
+		return firstError(unix.Munmap(tx), unix.Munmap(rx))
+	}
+
+	msg := make([]byte, len(handshakeMagic)+8)
+	copy(msg, handshakeMagic)
+	binary.BigEndian.PutUint64(msg[len(handshakeMagic):], uint64(size))
+
+	_, _, err = conn.WriteMsgUnix(msg, unix.UnixRights(txFd, rxFd), nil)
+	// the mappings remain valid after the descriptors are closed
+	err = firstError(err, unix.Close(txFd), unix.Close(rxFd))
+	if err != nil {
+		_ = release()
+		return nil, fmt.Errorf("shmconn: sending regions: %w", err)
+	}
+
+	var ack [1]byte
+	if _, err := io.ReadFull(conn, ack[:]); err != nil {
+		_ = release()
+		return nil, fmt.Errorf("shmconn: waiting for server: %w", err)
+	}
+	if ack[0] != 1 {
+		_ = release()
+		return nil, errors.New("shmconn: server rejected shared memory regions")
+	}
+
+	return newConn(conn, tx, rx, 0, release), nil
+}
+
+// Server completes the setup of a connection started by Client on the other
+// end of conn, mapping the regions created by the client.
+func Server(conn *net.UnixConn) (*Conn, error) {
+	msg := make([]byte, len(handshakeMagic)+8)
+	oob := make([]byte, unix.CmsgSpace(2*4))
+
+	n, oobn, _, _, err := conn.ReadMsgUnix(msg, oob)
+	if err != nil {
+		return nil, fmt.Errorf("shmconn: receiving regions: %w", err)
+	}
+
+	fds, err := parseRights(oob[:oobn])
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, fd := range fds {
+			_ = unix.Close(fd)
+		}
+	}()
+
+	if n != len(msg) || !bytes.Equal(msg[:len(handshakeMagic)], handshakeMagic) {
+		return nil, errors.New("shmconn: invalid setup message")
+	}
+	if len(fds) != 2 {
+		return nil, fmt.Errorf("shmconn: expected 2 regions, got %d", len(fds))
+	}
+	size := binary.BigEndian.Uint64(msg[len(handshakeMagic):])
+	if size == 0 || size > uint64(maxInt) {
+		return nil, fmt.Errorf("shmconn: invalid region size %d", size)
+	}
+
+	// the client's write region is our read region and vice versa
+	rx, err := mapRegion(fds[0], int(size))
+	if err != nil {
+		return nil, err
+	}
+	tx, err := mapRegion(fds[1], int(size))
+	if err != nil {
+		_ = unix.Munmap(rx)
+		return nil, err
+	}
+	release := func() error {
+		return firstError(unix.Munmap(tx), unix.Munmap(rx))
+	}
+
+	if _, err := conn.Write([]byte{1}); err != nil {
+		_ = release()
+		return nil, fmt.Errorf("shmconn: confirming setup: %w", err)
+	}
+
+	return newConn(conn, tx, rx, 0, release), nil
+}
+
+// regionSeals are applied to every region before it is mapped. They fix the
+// size of the file, so that neither side can make the other's mapping extend
+// beyond the end of the file, which would raise SIGBUS on access.
+const regionSeals = unix.F_SEAL_SHRINK | unix.F_SEAL_GROW
+
+// createRegion creates an anonymous shared memory file of the given size,
+// seals its size and maps it.
+func createRegion(name string, size int) ([]byte, int, error) {
+	fd, err := unix.MemfdCreate(name, unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING)
+	if err != nil {
+		return nil, 0, fmt.Errorf("shmconn: creating region: %w", err)
+	}
+	if err := unix.Ftruncate(fd, int64(size)); err != nil {
+		_ = unix.Close(fd)
+		return nil, 0, fmt.Errorf("shmconn: sizing region: %w", err)
+	}
+	if _, err := unix.FcntlInt(uintptr(fd), unix.F_ADD_SEALS, regionSeals); err != nil {
+		_ = unix.Close(fd)
+		return nil, 0, fmt.Errorf("shmconn: sealing region: %w", err)
+	}
+	buf, err := unix.Mmap(fd, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+	if err != nil {
+		_ = unix.Close(fd)
+		return nil, 0, fmt.Errorf("shmconn: mapping region: %w", err)
+	}
+	return buf, fd, nil
+}
+
+// mapRegion maps a region received from the client, after checking that the
+// file is large enough and that its size is sealed, since accessing a mapping
+// beyond the end of its file raises SIGBUS.
+func mapRegion(fd, size int) ([]byte, error) {
+	seals, err := unix.FcntlInt(uintptr(fd), unix.F_GET_SEALS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("shmconn: inspecting region seals: %w", err)
+	}
+	if seals&regionSeals != regionSeals {
+		return nil, errors.New("shmconn: region size is not sealed")
+	}
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return nil, fmt.Errorf("shmconn: inspecting region: %w", err)
+	}
+	if stat.Size < int64(size) {
+		return nil, fmt.Errorf("shmconn: region of %d bytes is smaller than announced %d bytes", stat.Size, size)
+	}
+	buf, err := unix.Mmap(fd, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("shmconn: mapping region: %w", err)
+	}
+	return buf, nil
+}
+
+func parseRights(oob []byte) ([]int, error) {
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, fmt.Errorf("shmconn: parsing control message: %w", err)
+	}
+	var fds []int
+	for i := range msgs {
+		rights, err := unix.ParseUnixRights(&msgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("shmconn: parsing control message: %w", err)
+		}
+		fds = append(fds, rights...)
+	}
+	return fds, nil
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build linux
+// +build linux
+
+package shmconn
+
+import (
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestClientServer(t *testing.T) {
+	addr := &net.UnixAddr{Name: filepath.Join(t.TempDir(), "shm.sock"), Net: "unix"}
+	ln, err := net.ListenUnix("unix", addr)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	serverCh := make(chan *Conn, 1)
+	go func() {
+		conn, err := ln.AcceptUnix()
+		if err != nil {
+			serverCh <- nil
+			return
+		}
+		sconn, err := Server(conn)
+		if err != nil {
+			conn.Close()
+		}
+		serverCh <- sconn
+	}()
+
+	conn, err := net.DialUnix("unix", nil, addr)
+	require.NoError(t, err)
+	client, err := Client(conn, 1024*1024)
+	require.NoError(t, err)
+	defer client.Close()
+
+	server := <-serverCh
+	require.NotNil(t, server)
+	defer server.Close()
+
+	// large enough to go through shared memory in both directions
+	msg := make([]byte, 256*1024)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+
+	go func() { _, _ = client.Write(msg) }()
+	received := make([]byte, len(msg))
+	_, err = io.ReadFull(server, received)
+	require.NoError(t, err)
+	require.Equal(t, msg, received)
+
+	go func() { _, _ = server.Write(received) }()
+	_, err = io.ReadFull(client, received)
+	require.NoError(t, err)
+	require.Equal(t, msg, received)
+}
+
+func TestMapRegionRequiresSeals(t *testing.T) {
+	fd, err := unix.MemfdCreate("abci-shm-test", unix.MFD_CLOEXEC)
+	require.NoError(t, err)
+	defer unix.Close(fd)
+	require.NoError(t, unix.Ftruncate(fd, 4096))
+
+	// the client could shrink an unsealed region while the server uses it
+	_, err = mapRegion(fd, 4096)
+	require.Error(t, err)
+
+	buf, sealedFd, err := createRegion("abci-shm-test", 4096)
+	require.NoError(t, err)
+	defer unix.Close(sealedFd)
+	defer unix.Munmap(buf) //nolint:errcheck
+
+	require.Error(t, unix.Ftruncate(sealedFd, 0))
+	mapped, err := mapRegion(sealedFd, 4096)
+	require.NoError(t, err)
+	require.NoError(t, unix.Munmap(mapped))
+}
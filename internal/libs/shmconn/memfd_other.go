@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+package shmconn
+
+import (
+	"errors"
+	"net"
+)
+
+var errUnsupported = errors.New("shmconn: shared memory connections are only supported on linux")
+
+// Client is only supported on linux.
+func Client(conn *net.UnixConn, size int) (*Conn, error) {
+	return nil, errUnsupported
+}
+
+// Server is only supported on linux.
+func Server(conn *net.UnixConn) (*Conn, error) {
+	return nil, errUnsupported
+}
@@ -22,6 +22,10 @@ var (
 	// reSchemeIsHost tries to detect URLs where the scheme part is instead a
 	// hostname, i.e. of the form "host:80/path" where host: is a hostname.
 	reSchemeIsHost = regexp.MustCompile(`^[^/:]+:\d+(/|$)`)
+
+	// reOnionHostname matches Tor v3 onion service hostnames, which encode the
+	// service's public key, checksum and version in 56 base32 characters.
+	reOnionHostname = regexp.MustCompile(`^[a-z2-7]{56}\.onion$`)
 )
 
 // NodeAddress is a node address URL. It differs from a transport Endpoint in
@@ -115,6 +119,12 @@ func (a NodeAddress) Resolve(ctx context.Context) ([]Endpoint, error) {
 		}}, nil
 	}
 
+	// Onion addresses can only be resolved by the Tor proxy the transport dials
+	// through.
+	if isOnionHostname(a.Hostname) {
+		return []Endpoint{a.hostnameEndpoint()}, nil
+	}
+
 	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", a.Hostname)
 	if err != nil {
 		return nil, err
@@ -131,6 +141,17 @@ func (a NodeAddress) Resolve(ctx context.Context) ([]Endpoint, error) {
 	return endpoints, nil
 }
 
+// hostnameEndpoint returns an endpoint for the address with its hostname left
+// unresolved.
+func (a NodeAddress) hostnameEndpoint() Endpoint {
+	return Endpoint{
+		Protocol: a.Protocol,
+		Hostname: a.Hostname,
+		Port:     a.Port,
+		Path:     a.Path,
+	}
+}
+
 // String formats the address as a URL string.
 func (a NodeAddress) String() string {
 	u := url.URL{Scheme: string(a.Protocol)}
@@ -172,5 +193,13 @@ func (a NodeAddress) Validate() error {
 	if a.Port > 0 && a.Hostname == "" {
 		return errors.New("cannot specify port without hostname")
 	}
+	if isOnionHostname(a.Hostname) && !reOnionHostname.MatchString(a.Hostname) {
+		return fmt.Errorf("invalid onion address %q", a.Hostname)
+	}
 	return nil
 }
+
+// isOnionHostname returns true if the hostname is a Tor onion address.
+func isOnionHostname(hostname string) bool {
+	return strings.HasSuffix(hostname, ".onion")
+}
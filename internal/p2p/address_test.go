@@ -174,6 +174,11 @@ func TestParseNodeAddress(t *testing.T) {
 			p2p.NodeAddress{Protocol: "mconn", NodeID: id, Hostname: "fd80:b10c::2", Port: 26657},
 			true,
 		},
+		{
+			user + "@" + strings.Repeat("A", 56) + ".onion:26656",
+			p2p.NodeAddress{Protocol: "mconn", NodeID: id, Hostname: strings.Repeat("a", 56) + ".onion", Port: 26656},
+			true,
+		},
 
 		// Invalid addresses.
 		{"", p2p.NodeAddress{}, false},
@@ -187,6 +192,8 @@ func TestParseNodeAddress(t *testing.T) {
 		{"mconn://foo@127.0.0.1", p2p.NodeAddress{}, false},
 		{"mconn://" + user + "@127.0.0.1:65536", p2p.NodeAddress{}, false},
 		{"mconn://" + user + "@:80", p2p.NodeAddress{}, false},
+		{"mconn://" + user + "@foo.onion:80", p2p.NodeAddress{}, false},
+		{"mconn://" + user + "@" + strings.Repeat("1", 56) + ".onion:80", p2p.NodeAddress{}, false},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -256,6 +263,13 @@ func TestNodeAddress_Resolve(t *testing.T) {
 			false,
 		},
 
+		// Onion addresses are left for the proxy to resolve.
+		{
+			p2p.NodeAddress{Protocol: "tcp", Hostname: strings.Repeat("a", 56) + ".onion", Port: 80},
+			p2p.Endpoint{Protocol: "tcp", Hostname: strings.Repeat("a", 56) + ".onion", Port: 80},
+			true,
+		},
+
 		// Valid non-networked addresses.
 		{
 			p2p.NodeAddress{Protocol: "memory", NodeID: id},
@@ -0,0 +1,274 @@
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"path/filepath"
+	"time"
+
+	auto "github.com/tendermint/tendermint/internal/libs/autofile"
+	"github.com/tendermint/tendermint/libs/log"
+	tmos "github.com/tendermint/tendermint/libs/os"
+	"github.com/tendermint/tendermint/libs/service"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// captureFlushInterval is how often captured messages are flushed to disk.
+	captureFlushInterval = time.Second
+
+	// captureHeaderSize is the size of the fixed fields of an encoded
+	// CapturedMessage: time, direction, channel ID and peer ID length.
+	captureHeaderSize = 8 + 1 + 2 + 1
+
+	// maxCaptureRecordSize bounds the size of decoded capture records.
+	maxCaptureRecordSize = 64 * 1024 * 1024
+)
+
+var captureCRC32c = crc32.MakeTable(crc32.Castagnoli)
+
+// CaptureDirection is the direction of a captured message.
+type CaptureDirection uint8
+
+const (
+	CaptureInbound  CaptureDirection = 1 // received from the peer
+	CaptureOutbound CaptureDirection = 2 // sent to the peer
+)
+
+// String implements fmt.Stringer.
+func (d CaptureDirection) String() string {
+	switch d {
+	case CaptureInbound:
+		return "inbound"
+	case CaptureOutbound:
+		return "outbound"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(d))
+	}
+}
+
+// CapturedMessage is a message sent or received by the router, as recorded by
+// a Capture.
+type CapturedMessage struct {
+	Time      time.Time
+	Direction CaptureDirection
+	PeerID    types.NodeID
+	ChannelID ChannelID
+	Payload   []byte // Protobuf-encoded message, as sent on the wire
+}
+
+// CaptureEncoder writes captured messages to an output stream. Each record is
+// prefixed by the CRC32c checksum and length of its data, both 4 bytes big
+// endian, as with the consensus WAL. The data is the time in Unix nanoseconds
+// (8 bytes), direction (1 byte), channel ID (2 bytes), length of the peer ID
+// (1 byte), the peer ID and the payload.
+type CaptureEncoder struct {
+	wr io.Writer
+}
+
+// NewCaptureEncoder returns a new encoder that writes to wr.
+func NewCaptureEncoder(wr io.Writer) *CaptureEncoder {
+	return &CaptureEncoder{wr: wr}
+}
+
+// Encode writes a captured message to the stream in a single write.
+func (enc *CaptureEncoder) Encode(msg *CapturedMessage) error {
+	if len(msg.PeerID) > 255 {
+		return fmt.Errorf("peer ID %q is too long", msg.PeerID)
+	}
+	length := captureHeaderSize + len(msg.PeerID) + len(msg.Payload)
+	if length > maxCaptureRecordSize {
+		return fmt.Errorf("message is too big: %d bytes, max: %d bytes", length, maxCaptureRecordSize)
+	}
+
+	bz := make([]byte, 8+length)
+	data := bz[8:]
+	binary.BigEndian.PutUint64(data[0:8], uint64(msg.Time.UnixNano()))
+	data[8] = byte(msg.Direction)
+	binary.BigEndian.PutUint16(data[9:11], uint16(msg.ChannelID))
+	data[11] = byte(len(msg.PeerID))
+	copy(data[captureHeaderSize:], msg.PeerID)
+	copy(data[captureHeaderSize+len(msg.PeerID):], msg.Payload)
+
+	binary.BigEndian.PutUint32(bz[0:4], crc32.Checksum(data, captureCRC32c))
+	binary.BigEndian.PutUint32(bz[4:8], uint32(length))
+	_, err := enc.wr.Write(bz)
+	return err
+}
+
+// CaptureDecoder reads captured messages written by a CaptureEncoder.
+type CaptureDecoder struct {
+	rd io.Reader
+}
+
+// NewCaptureDecoder returns a new decoder that reads from rd.
+func NewCaptureDecoder(rd io.Reader) *CaptureDecoder {
+	return &CaptureDecoder{rd: rd}
+}
+
+// Decode reads the next captured message. It returns io.EOF at the end of the
+// stream, and io.ErrUnexpectedEOF for a truncated record, e.g. when the node
+// crashed while writing it.
+func (dec *CaptureDecoder) Decode() (*CapturedMessage, error) {
+	prefix := make([]byte, 8)
+	if _, err := io.ReadFull(dec.rd, prefix); err != nil {
+		return nil, err
+	}
+	crc := binary.BigEndian.Uint32(prefix[0:4])
+	length := binary.BigEndian.Uint32(prefix[4:8])
+	if length < captureHeaderSize || length > maxCaptureRecordSize {
+		return nil, fmt.Errorf("invalid capture record length %d", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(dec.rd, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if actual := crc32.Checksum(data, captureCRC32c); actual != crc {
+		return nil, fmt.Errorf("capture record checksums do not match: read %v, actual %v", crc, actual)
+	}
+
+	idLength := int(data[11])
+	if captureHeaderSize+idLength > len(data) {
+		return nil, fmt.Errorf("invalid capture record peer ID length %d", idLength)
+	}
+	return &CapturedMessage{
+		Time:      time.Unix(0, int64(binary.BigEndian.Uint64(data[0:8]))).UTC(),
+		Direction: CaptureDirection(data[8]),
+		ChannelID: ChannelID(binary.BigEndian.Uint16(data[9:11])),
+		PeerID:    types.NodeID(data[captureHeaderSize : captureHeaderSize+idLength]),
+		Payload:   data[captureHeaderSize+idLength:],
+	}, nil
+}
+
+// CaptureOptions filters the messages recorded by a Capture.
+type CaptureOptions struct {
+	// Channels to capture. Empty captures all channels.
+	Channels []ChannelID
+
+	// Peers to capture. Empty captures all peers.
+	Peers []types.NodeID
+}
+
+// Capture records messages sent and received by the router to rotating files
+// via autofile.Group, for offline inspection and replay with Replayer. It is
+// enabled via RouterOptions.Capture, and the router starts and stops it.
+type Capture struct {
+	service.BaseService
+	logger log.Logger
+
+	group    *auto.Group
+	enc      *CaptureEncoder
+	channels map[ChannelID]bool
+	peers    map[types.NodeID]bool
+}
+
+// NewCapture creates a capture writing to the given head file path, rotating
+// the files according to the group options.
+func NewCapture(
+	logger log.Logger,
+	path string,
+	options CaptureOptions,
+	groupOptions ...func(*auto.Group),
+) (*Capture, error) {
+	if err := tmos.EnsureDir(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to ensure capture directory: %w", err)
+	}
+	group, err := auto.OpenGroup(logger, path, groupOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Capture{
+		logger:   logger,
+		group:    group,
+		enc:      NewCaptureEncoder(group),
+		channels: make(map[ChannelID]bool, len(options.Channels)),
+		peers:    make(map[types.NodeID]bool, len(options.Peers)),
+	}
+	for _, chID := range options.Channels {
+		c.channels[chID] = true
+	}
+	for _, peerID := range options.Peers {
+		c.peers[peerID] = true
+	}
+	c.BaseService = *service.NewBaseService(logger, "Capture", c)
+	return c, nil
+}
+
+// OnStart implements service.Service.
+func (c *Capture) OnStart(ctx context.Context) error {
+	if err := c.group.Start(ctx); err != nil {
+		return err
+	}
+	go c.flushRoutine(ctx)
+	return nil
+}
+
+// OnStop implements service.Service.
+func (c *Capture) OnStop() {
+	if err := c.group.FlushAndSync(); err != nil {
+		c.logger.Error("failed to flush capture", "err", err)
+	}
+	if err := c.group.Stop(); err != nil {
+		c.logger.Error("failed to stop capture group", "err", err)
+	}
+	c.group.Wait()
+	c.group.Close()
+}
+
+// Group returns the underlying autofile group, e.g. to read the capture.
+func (c *Capture) Group() *auto.Group {
+	return c.group
+}
+
+// FlushAndSync flushes captured messages to disk.
+func (c *Capture) FlushAndSync() error {
+	return c.group.FlushAndSync()
+}
+
+func (c *Capture) flushRoutine(ctx context.Context) {
+	ticker := time.NewTicker(captureFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.group.FlushAndSync(); err != nil {
+				c.logger.Error("failed to flush capture", "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// record records a message if it passes the filters. Errors are logged, since
+// they shouldn't affect message routing.
+func (c *Capture) record(direction CaptureDirection, peerID types.NodeID, chID ChannelID, payload []byte) {
+	if !c.IsRunning() {
+		return
+	}
+	if len(c.channels) > 0 && !c.channels[chID] {
+		return
+	}
+	if len(c.peers) > 0 && !c.peers[peerID] {
+		return
+	}
+	err := c.enc.Encode(&CapturedMessage{
+		Time:      time.Now().UTC(),
+		Direction: direction,
+		PeerID:    peerID,
+		ChannelID: chID,
+		Payload:   payload,
+	})
+	if err != nil {
+		c.logger.Error("failed to capture message", "peer", peerID, "channel", chID, "err", err)
+	}
+}
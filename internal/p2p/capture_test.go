@@ -0,0 +1,213 @@
+package p2p_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/internal/p2p/p2ptest"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestCaptureEncoder(t *testing.T) {
+	msgs := []*p2p.CapturedMessage{
+		{
+			Time:      time.Unix(1, 2).UTC(),
+			Direction: p2p.CaptureInbound,
+			PeerID:    types.NodeID(strings.Repeat("a", 40)),
+			ChannelID: 0x30,
+			Payload:   []byte{1, 2, 3},
+		},
+		{
+			Time:      time.Unix(3, 4).UTC(),
+			Direction: p2p.CaptureOutbound,
+			PeerID:    types.NodeID(strings.Repeat("b", 40)),
+			ChannelID: 0xffff,
+			Payload:   []byte{},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	enc := p2p.NewCaptureEncoder(buf)
+	for _, msg := range msgs {
+		require.NoError(t, enc.Encode(msg))
+	}
+	encoded := buf.Bytes()
+
+	dec := p2p.NewCaptureDecoder(bytes.NewReader(encoded))
+	for _, msg := range msgs {
+		decoded, err := dec.Decode()
+		require.NoError(t, err)
+		require.Equal(t, msg, decoded)
+	}
+	_, err := dec.Decode()
+	require.Equal(t, io.EOF, err)
+
+	// A truncated record is an unexpected EOF.
+	dec = p2p.NewCaptureDecoder(bytes.NewReader(encoded[:len(encoded)-1]))
+	_, err = dec.Decode()
+	require.NoError(t, err)
+	_, err = dec.Decode()
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// A corrupted record fails the checksum.
+	corrupted := append([]byte{}, encoded...)
+	corrupted[len(corrupted)-1] ^= 1
+	dec = p2p.NewCaptureDecoder(bytes.NewReader(corrupted))
+	_, err = dec.Decode()
+	require.NoError(t, err)
+	_, err = dec.Decode()
+	require.Error(t, err)
+	require.False(t, errors.Is(err, io.EOF))
+}
+
+func TestRouter_Capture(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "capture")
+	capture, err := p2p.NewCapture(log.TestingLogger(), path, p2p.CaptureOptions{
+		Channels: []p2p.ChannelID{chID},
+	})
+	require.NoError(t, err)
+
+	// Node b captures its traffic on chID, but not on other channels.
+	network := p2ptest.MakeNetwork(ctx, t, p2ptest.NetworkOptions{NumNodes: 1})
+	aID := network.NodeIDs()[0]
+	b := network.MakeNode(ctx, t, p2ptest.NodeOptions{Capture: capture})
+	network.Nodes[b.NodeID] = b
+	bID := b.NodeID
+	channels := network.MakeChannels(ctx, t, chDesc)
+	otherChannels := network.MakeChannels(ctx, t, p2ptest.MakeChannelDesc(9))
+	network.Start(ctx, t)
+
+	p2ptest.RequireSend(ctx, t, otherChannels[aID], p2p.Envelope{To: bID, Message: &p2ptest.Message{Value: "other"}})
+	p2ptest.RequireReceive(t, otherChannels[bID], p2p.Envelope{From: aID, Message: &p2ptest.Message{Value: "other"}})
+	p2ptest.RequireSend(ctx, t, channels[aID], p2p.Envelope{To: bID, Message: &p2ptest.Message{Value: "ping"}})
+	p2ptest.RequireReceive(t, channels[bID], p2p.Envelope{From: aID, Message: &p2ptest.Message{Value: "ping"}})
+	p2ptest.RequireSend(ctx, t, channels[bID], p2p.Envelope{To: aID, Message: &p2ptest.Message{Value: "pong"}})
+	p2ptest.RequireReceive(t, channels[aID], p2p.Envelope{From: bID, Message: &p2ptest.Message{Value: "pong"}})
+
+	var captured []*p2p.CapturedMessage
+	require.Eventually(t, func() bool {
+		require.NoError(t, capture.FlushAndSync())
+		captured = readCapture(t, path)
+		return len(captured) >= 2
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Len(t, captured, 2)
+
+	for i, expect := range []struct {
+		direction p2p.CaptureDirection
+		value     string
+	}{{p2p.CaptureInbound, "ping"}, {p2p.CaptureOutbound, "pong"}} {
+		require.Equal(t, expect.direction, captured[i].Direction)
+		require.Equal(t, aID, captured[i].PeerID)
+		require.Equal(t, chID, captured[i].ChannelID)
+		msg := &p2ptest.Message{}
+		require.NoError(t, proto.Unmarshal(captured[i].Payload, msg))
+		require.Equal(t, expect.value, msg.Value)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerA := types.NodeID(strings.Repeat("a", 40))
+	peerB := types.NodeID(strings.Repeat("b", 40))
+	buf := new(bytes.Buffer)
+	enc := p2p.NewCaptureEncoder(buf)
+	for _, msg := range []struct {
+		direction p2p.CaptureDirection
+		peerID    types.NodeID
+		chID      p2p.ChannelID
+		value     string
+	}{
+		{p2p.CaptureInbound, peerA, chID, "foo"},
+		{p2p.CaptureOutbound, peerA, chID, "sent"},
+		{p2p.CaptureInbound, peerB, 9, "other"},
+		{p2p.CaptureInbound, peerB, chID, "bad"},
+		{p2p.CaptureInbound, peerA, chID, "bar"},
+	} {
+		bz, err := proto.Marshal(&p2ptest.Message{Value: msg.value})
+		require.NoError(t, err)
+		require.NoError(t, enc.Encode(&p2p.CapturedMessage{
+			Time:      time.Now().UTC(),
+			Direction: msg.direction,
+			PeerID:    msg.peerID,
+			ChannelID: msg.chID,
+			Payload:   bz,
+		}))
+	}
+
+	replayer := p2p.NewReplayer(log.TestingLogger(), p2p.NewCaptureDecoder(buf), chDesc, p2p.ReplayerOptions{})
+
+	// A stand-in reactor, which echoes messages from peers it has seen come
+	// up, and reports an error for bad messages.
+	channel, peerUpdates := replayer.Channel(), replayer.PeerUpdates()
+	go func() {
+		peers := map[types.NodeID]bool{}
+		for {
+			select {
+			case update := <-peerUpdates.Updates():
+				peers[update.NodeID] = update.Status == p2p.PeerStatusUp
+			case envelope := <-channel.In:
+				msg := envelope.Message.(*p2ptest.Message)
+				switch {
+				case !peers[envelope.From]:
+					_ = channel.SendError(ctx, p2p.PeerError{NodeID: envelope.From, Err: errors.New("unknown peer")})
+				case msg.Value == "bad":
+					_ = channel.SendError(ctx, p2p.PeerError{NodeID: envelope.From, Err: errors.New("bad")})
+				default:
+					_ = channel.Send(ctx, p2p.Envelope{To: envelope.From, Message: msg})
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	require.NoError(t, replayer.Run(ctx))
+	require.Eventually(t, func() bool {
+		return len(replayer.Sent())+len(replayer.Errors()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, []p2p.Envelope{
+		{To: peerA, Message: &p2ptest.Message{Value: "foo"}},
+		{To: peerA, Message: &p2ptest.Message{Value: "bar"}},
+	}, replayer.Sent())
+	require.Len(t, replayer.Errors(), 1)
+	require.Equal(t, peerB, replayer.Errors()[0].NodeID)
+	require.Equal(t, "bad", replayer.Errors()[0].Err.Error())
+}
+
+// readCapture reads all messages from a capture head file.
+func readCapture(t *testing.T, path string) []*p2p.CapturedMessage {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	msgs := []*p2p.CapturedMessage{}
+	dec := p2p.NewCaptureDecoder(f)
+	for {
+		msg, err := dec.Decode()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return msgs
+		}
+		require.NoError(t, err)
+		msgs = append(msgs, msg)
+	}
+}
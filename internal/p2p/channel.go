@@ -40,19 +40,14 @@ type Wrapper interface {
 	Unwrap() (proto.Message, error)
 }
 
-// PeerError is a peer error reported via Channel.Error.
-//
-// FIXME: This currently just disconnects the peer, which is too simplistic.
-// For example, some errors should be logged, some should cause disconnects,
-// and some should ban the peer.
-//
-// FIXME: This should probably be replaced by a more general PeerBehavior
-// concept that can mark good and bad behavior and contributes to peer scoring.
-// It should possibly also allow reactors to request explicit actions, e.g.
-// disconnection or banning, in addition to doing this based on aggregates.
+// PeerError is a peer error reported via Channel.Error. The peer is
+// disconnected, and its reputation is penalized according to the misbehavior
+// (see Misbehavior). Peers whose reputation drops below the configured
+// threshold are temporarily banned.
 type PeerError struct {
-	NodeID types.NodeID
-	Err    error
+	NodeID      types.NodeID
+	Err         error
+	Misbehavior Misbehavior // MisbehaviorUnknown if not given
 }
 
 func (pe PeerError) Error() string { return fmt.Sprintf("peer=%q: %s", pe.NodeID, pe.Err.Error()) }
@@ -0,0 +1,294 @@
+package conn
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/flynn/noise"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/encoding"
+	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+	tmp2p "github.com/tendermint/tendermint/proto/tendermint/p2p"
+)
+
+const (
+	noisePrologue = "TENDERMINT_NOISE_CONNECTION"
+
+	// noiseStaticKeyLabel prefixes the Noise static key signed by the node key,
+	// which binds the node key to the static key.
+	noiseStaticKeyLabel = "TENDERMINT_NOISE_STATIC_KEY:"
+
+	noiseLenSize        = 2               // big-endian message length prefix
+	noiseMaxMessageSize = noise.MaxMsgLen // Noise limit for any message
+	noiseMaxPayloadSize = noiseMaxMessageSize - aeadSizeOverhead
+)
+
+// noiseCipherSuite is the Noise_XX_25519_ChaChaPoly_SHA256 cipher suite.
+var noiseCipherSuite = noise.NewCipherSuite(noise.DH25519, noise.CipherChaChaPoly, noise.HashSHA256)
+
+// NoiseConnection implements net.Conn. It is an alternative to
+// SecretConnection that uses the Noise protocol framework's XX handshake
+// pattern (Noise_XX_25519_ChaChaPoly_SHA256), see
+// https://noiseprotocol.org/noise.html. The protocol is implemented by
+// github.com/flynn/noise.
+//
+// Each side generates a Noise static key for the connection, and sends it
+// along with a signature of it by the node key in its handshake payload.
+//
+// Consumers of the NoiseConnection are responsible for authenticating the
+// remote peer's pubkey against known information, like a nodeID.
+type NoiseConnection struct {
+	conn      io.ReadWriteCloser
+	remPubKey crypto.PubKey
+
+	// Send and receive are independent, see SecretConnection.
+	recvMtx    tmsync.Mutex
+	recvCipher *noise.CipherState
+	recvBuffer []byte
+
+	sendMtx    tmsync.Mutex
+	sendCipher *noise.CipherState
+}
+
+// MakeNoiseConnection performs a Noise XX handshake and returns a new
+// authenticated NoiseConnection. Unlike MakeSecretConnection, the handshake is
+// asymmetric: exactly one side must be the initiator, typically the dialer.
+// Caller should call conn.Close()
+func MakeNoiseConnection(conn io.ReadWriteCloser, locPrivKey crypto.PrivKey, initiator bool) (*NoiseConnection, error) {
+	pbpk, err := encoding.PubKeyToProto(locPrivKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+
+	static, err := noiseCipherSuite.GenerateKeypair(crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := locPrivKey.Sign(noiseStaticKeySignBytes(static.Public))
+	if err != nil {
+		return nil, err
+	}
+	payload, err := (&tmp2p.AuthSigMessage{PubKey: pbpk, Sig: signature}).Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	hs, err := newNoiseHandshake(initiator, static, crand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// -> e
+	// <- e, ee, s, es
+	// -> s, se
+	var (
+		remPayload []byte
+		cs1, cs2   *noise.CipherState
+		sendCipher *noise.CipherState
+		recvCipher *noise.CipherState
+	)
+	if initiator {
+		if _, _, err = writeNoiseHandshake(conn, hs, nil); err != nil {
+			return nil, err
+		}
+		if remPayload, _, _, err = readNoiseHandshake(conn, hs); err != nil {
+			return nil, err
+		}
+		if cs1, cs2, err = writeNoiseHandshake(conn, hs, payload); err != nil {
+			return nil, err
+		}
+		sendCipher, recvCipher = cs1, cs2
+	} else {
+		if _, _, _, err = readNoiseHandshake(conn, hs); err != nil {
+			return nil, err
+		}
+		if _, _, err = writeNoiseHandshake(conn, hs, payload); err != nil {
+			return nil, err
+		}
+		if remPayload, cs1, cs2, err = readNoiseHandshake(conn, hs); err != nil {
+			return nil, err
+		}
+		sendCipher, recvCipher = cs2, cs1
+	}
+	if sendCipher == nil || recvCipher == nil {
+		return nil, errors.New("noise handshake did not complete")
+	}
+
+	remPubKey, err := verifyNoisePayload(remPayload, hs.PeerStatic())
+	if err != nil {
+		return nil, err
+	}
+
+	return &NoiseConnection{
+		conn:       conn,
+		remPubKey:  remPubKey,
+		recvCipher: recvCipher,
+		sendCipher: sendCipher,
+	}, nil
+}
+
+// RemotePubKey returns authenticated remote pubkey
+func (nc *NoiseConnection) RemotePubKey() crypto.PubKey {
+	return nc.remPubKey
+}
+
+// Write writes data as Noise transport messages of up to noiseMaxMessageSize
+// bytes, each prefixed by its length.
+// CONTRACT: data smaller than noiseMaxPayloadSize is written atomically.
+func (nc *NoiseConnection) Write(data []byte) (n int, err error) {
+	nc.sendMtx.Lock()
+	defer nc.sendMtx.Unlock()
+
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > noiseMaxPayloadSize {
+			chunk = chunk[:noiseMaxPayloadSize]
+		}
+		data = data[len(chunk):]
+
+		msg := make([]byte, noiseLenSize, noiseLenSize+len(chunk)+aeadSizeOverhead)
+		if msg, err = nc.sendCipher.Encrypt(msg, nil, chunk); err != nil {
+			return n, err
+		}
+		binary.BigEndian.PutUint16(msg, uint16(len(msg)-noiseLenSize))
+
+		if _, err = nc.conn.Write(msg); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+	}
+	return n, nil
+}
+
+// Read reads and decrypts a Noise transport message, buffering any data that
+// doesn't fit in data.
+func (nc *NoiseConnection) Read(data []byte) (n int, err error) {
+	nc.recvMtx.Lock()
+	defer nc.recvMtx.Unlock()
+
+	if len(nc.recvBuffer) > 0 {
+		n = copy(data, nc.recvBuffer)
+		nc.recvBuffer = nc.recvBuffer[n:]
+		return n, nil
+	}
+
+	msg, err := readNoiseMessage(nc.conn)
+	if err != nil {
+		return 0, err
+	}
+	chunk, err := nc.recvCipher.Decrypt(msg[:0], nil, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt NoiseConnection: %w", err)
+	}
+
+	n = copy(data, chunk)
+	nc.recvBuffer = chunk[n:]
+	return n, nil
+}
+
+// Implements net.Conn
+func (nc *NoiseConnection) Close() error                  { return nc.conn.Close() }
+func (nc *NoiseConnection) LocalAddr() net.Addr           { return nc.conn.(net.Conn).LocalAddr() }
+func (nc *NoiseConnection) RemoteAddr() net.Addr          { return nc.conn.(net.Conn).RemoteAddr() }
+func (nc *NoiseConnection) SetDeadline(t time.Time) error { return nc.conn.(net.Conn).SetDeadline(t) }
+func (nc *NoiseConnection) SetReadDeadline(t time.Time) error {
+	return nc.conn.(net.Conn).SetReadDeadline(t)
+}
+func (nc *NoiseConnection) SetWriteDeadline(t time.Time) error {
+	return nc.conn.(net.Conn).SetWriteDeadline(t)
+}
+
+// noiseStaticKeySignBytes returns the bytes signed by the node key to bind it
+// to a Noise static key.
+func noiseStaticKeySignBytes(staticPub []byte) []byte {
+	return append([]byte(noiseStaticKeyLabel), staticPub...)
+}
+
+// verifyNoisePayload decodes a remote handshake payload and verifies that its
+// node key signed the remote static key.
+func verifyNoisePayload(payload []byte, remStaticPub []byte) (crypto.PubKey, error) {
+	var msg tmp2p.AuthSigMessage
+	if err := msg.Unmarshal(payload); err != nil {
+		return nil, fmt.Errorf("invalid handshake payload: %w", err)
+	}
+	remPubKey, err := encoding.PubKeyFromProto(msg.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := remPubKey.(ed25519.PubKey); !ok {
+		return nil, fmt.Errorf("expected ed25519 pubkey, got %T", remPubKey)
+	}
+	if !remPubKey.VerifySignature(noiseStaticKeySignBytes(remStaticPub), msg.Sig) {
+		return nil, errors.New("static key signature verification failed")
+	}
+	return remPubKey, nil
+}
+
+// readNoiseMessage reads a length-prefixed Noise message.
+func readNoiseMessage(r io.Reader) ([]byte, error) {
+	var lenBuf [noiseLenSize]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(lenBuf[:]))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// newNoiseHandshake starts a Noise XX handshake with the given static key,
+// reading the ephemeral key from rand.
+func newNoiseHandshake(initiator bool, static noise.DHKey, rand io.Reader) (*noise.HandshakeState, error) {
+	return noise.NewHandshakeState(noise.Config{
+		CipherSuite:   noiseCipherSuite,
+		Random:        rand,
+		Pattern:       noise.HandshakeXX,
+		Initiator:     initiator,
+		Prologue:      []byte(noisePrologue),
+		StaticKeypair: static,
+	})
+}
+
+// writeNoiseHandshake writes the next handshake message, with the given
+// payload. It returns the transport cipher states once the handshake has
+// completed, for the initiator and the responder in this order.
+func writeNoiseHandshake(
+	w io.Writer,
+	hs *noise.HandshakeState,
+	payload []byte,
+) (cs1, cs2 *noise.CipherState, err error) {
+	msg, cs1, cs2, err := hs.WriteMessage(make([]byte, noiseLenSize), payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(msg)-noiseLenSize > noiseMaxMessageSize {
+		return nil, nil, errors.New("handshake message too large")
+	}
+	binary.BigEndian.PutUint16(msg, uint16(len(msg)-noiseLenSize))
+	if _, err = w.Write(msg); err != nil {
+		return nil, nil, err
+	}
+	return cs1, cs2, nil
+}
+
+// readNoiseHandshake reads the next handshake message, returning its payload.
+// It returns the transport cipher states once the handshake has completed, for
+// the initiator and the responder in this order.
+func readNoiseHandshake(
+	r io.Reader,
+	hs *noise.HandshakeState,
+) (payload []byte, cs1, cs2 *noise.CipherState, err error) {
+	msg, err := readNoiseMessage(r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return hs.ReadMessage(nil, msg)
+}
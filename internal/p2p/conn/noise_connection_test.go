@@ -0,0 +1,224 @@
+package conn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/flynn/noise"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/sr25519"
+	"github.com/tendermint/tendermint/libs/async"
+	tmrand "github.com/tendermint/tendermint/libs/rand"
+)
+
+func TestNoiseConnectionHandshake(t *testing.T) {
+	fooNoiseConn, barNoiseConn := makeNoiseConnPair(t)
+	require.NoError(t, fooNoiseConn.Close())
+	require.NoError(t, barNoiseConn.Close())
+}
+
+func TestNoiseConnectionReadWrite(t *testing.T) {
+	fooNoiseConn, barNoiseConn := makeNoiseConnPair(t)
+	t.Cleanup(closeAll(t, fooNoiseConn, barNoiseConn))
+
+	// Messages larger than a Noise message are split, and reads smaller than
+	// a message are buffered.
+	for _, size := range []int{1, 1000, noiseMaxPayloadSize, 3*noiseMaxPayloadSize + 7} {
+		data := tmrand.Bytes(size)
+		go func() {
+			_, err := fooNoiseConn.Write(data)
+			assert.NoError(t, err)
+		}()
+
+		read := make([]byte, 0, size)
+		buf := make([]byte, 1000)
+		for len(read) < size {
+			n, err := barNoiseConn.Read(buf)
+			require.NoError(t, err)
+			read = append(read, buf[:n]...)
+		}
+		require.Equal(t, data, read)
+	}
+}
+
+func TestNoiseConnectionTampered(t *testing.T) {
+	fooConn, barConn := makeKVStoreConnPair()
+	t.Cleanup(closeAll(t, fooConn, barConn))
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := MakeNoiseConnection(fooConn, ed25519.GenPrivKey(), true)
+		errCh <- err
+	}()
+
+	// Flipping a bit in the responder's encrypted static key fails the
+	// initiator's handshake.
+	hs, err := newNoiseHandshake(false, makeNoiseKeypair(t, tmrand.Bytes(32)), nil)
+	require.NoError(t, err)
+	_, _, _, err = readNoiseHandshake(barConn, hs)
+	require.NoError(t, err)
+	msg, _, _, err := hs.WriteMessage(nil, nil)
+	require.NoError(t, err)
+	msg[40] ^= 1
+	_, err = barConn.Write(append([]byte{byte(len(msg) >> 8), byte(len(msg))}, msg...))
+	require.NoError(t, err)
+
+	require.Error(t, <-errCh)
+}
+
+func TestNoiseNilPubkey(t *testing.T) {
+	var fooConn, barConn = makeKVStoreConnPair()
+	t.Cleanup(closeAll(t, fooConn, barConn))
+
+	_, err := MakeNoiseConnection(barConn, privKeyWithNilPubKey{ed25519.GenPrivKey()}, false)
+	require.Error(t, err)
+	assert.Equal(t, "toproto: key type <nil> is not supported", err.Error())
+}
+
+func TestNoiseNonEd25519Pubkey(t *testing.T) {
+	var fooConn, barConn = makeKVStoreConnPair()
+	t.Cleanup(closeAll(t, fooConn, barConn))
+
+	go MakeNoiseConnection(barConn, sr25519.GenPrivKey(), false) //nolint:errcheck // ignore for tests
+
+	_, err := MakeNoiseConnection(fooConn, ed25519.GenPrivKey(), true)
+	require.Error(t, err)
+}
+
+func TestNoiseHandshakeGolden(t *testing.T) {
+	goldenFilepath := filepath.Join("testdata", t.Name()+".golden")
+	if *update {
+		t.Logf("Updating golden test vector file %s", goldenFilepath)
+		data := createNoiseGoldenTestVectors(t)
+		require.NoError(t, os.WriteFile(goldenFilepath, []byte(data), 0644))
+	}
+	f, err := os.Open(goldenFilepath)
+	require.NoError(t, err)
+	t.Cleanup(closeAll(t, f))
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		params := strings.Split(scanner.Text(), ",")
+		require.Len(t, params, 9)
+		keys := make([][]byte, 9)
+		for i, param := range params {
+			keys[i], err = hex.DecodeString(param)
+			require.NoError(t, err)
+		}
+
+		hash, initSend, initRecv, msgs := runNoiseHandshake(t, keys[0], keys[1], keys[2], keys[3])
+		require.Equal(t, keys[4], hash, "handshake hash")
+		require.Equal(t, keys[5], initSend, "initiator send key")
+		require.Equal(t, keys[6], initRecv, "initiator receive key")
+		require.Equal(t, keys[7], msgs[1], "responder message")
+		require.Equal(t, keys[8], msgs[2], "initiator message")
+	}
+	require.NoError(t, scanner.Err())
+}
+
+// Creates the data for a Noise test vector file.
+// The file format is:
+// Hex(initiator_static), Hex(initiator_ephemeral), Hex(responder_static),
+// Hex(responder_ephemeral), Hex(handshake_hash), Hex(initiator_send_key),
+// Hex(initiator_recv_key), Hex(message_2), Hex(message_3)
+func createNoiseGoldenTestVectors(t *testing.T) string {
+	data := ""
+	for i := 0; i < 32; i++ {
+		keys := [][]byte{tmrand.Bytes(32), tmrand.Bytes(32), tmrand.Bytes(32), tmrand.Bytes(32)}
+		hash, initSend, initRecv, msgs := runNoiseHandshake(t, keys[0], keys[1], keys[2], keys[3])
+		for _, b := range append(keys, hash, initSend, initRecv, msgs[1]) {
+			data += hex.EncodeToString(b) + ","
+		}
+		data += hex.EncodeToString(msgs[2]) + "\n"
+	}
+	return data
+}
+
+// runNoiseHandshake runs an in-memory handshake with the given private keys and
+// empty payloads, returning the handshake hash, the initiator's transport keys
+// and the handshake messages.
+func runNoiseHandshake(
+	t *testing.T,
+	initStatic, initEph, respStatic, respEph []byte,
+) (hash, initSend, initRecv []byte, msgs [3][]byte) {
+	initStaticKey, respStaticKey := makeNoiseKeypair(t, initStatic), makeNoiseKeypair(t, respStatic)
+	initiator, err := newNoiseHandshake(true, initStaticKey, bytes.NewReader(initEph))
+	require.NoError(t, err)
+	responder, err := newNoiseHandshake(false, respStaticKey, bytes.NewReader(respEph))
+	require.NoError(t, err)
+
+	var initCS, respCS [2]*noise.CipherState
+	for i := range msgs {
+		from, to := initiator, responder
+		if i == 1 {
+			from, to = responder, initiator
+		}
+		var (
+			payload      []byte
+			cs1, cs2     *noise.CipherState
+			peer1, peer2 *noise.CipherState
+		)
+		msgs[i], cs1, cs2, err = from.WriteMessage(nil, nil)
+		require.NoError(t, err)
+		payload, peer1, peer2, err = to.ReadMessage(nil, msgs[i])
+		require.NoError(t, err)
+		require.Empty(t, payload)
+		if i == 2 {
+			initCS = [2]*noise.CipherState{cs1, cs2}
+			respCS = [2]*noise.CipherState{peer1, peer2}
+		}
+	}
+	require.Equal(t, initiator.ChannelBinding(), responder.ChannelBinding())
+	require.Equal(t, respStaticKey.Public, initiator.PeerStatic())
+	require.Equal(t, initStaticKey.Public, responder.PeerStatic())
+
+	sendKey, recvKey := initCS[0].UnsafeKey(), initCS[1].UnsafeKey()
+	require.Equal(t, sendKey, respCS[0].UnsafeKey())
+	require.Equal(t, recvKey, respCS[1].UnsafeKey())
+
+	return initiator.ChannelBinding(), sendKey[:], recvKey[:], msgs
+}
+
+func makeNoiseKeypair(t *testing.T, priv []byte) noise.DHKey {
+	keypair, err := noiseCipherSuite.GenerateKeypair(bytes.NewReader(priv))
+	require.NoError(t, err)
+	return keypair
+}
+
+func makeNoiseConnPair(t *testing.T) (fooNoiseConn, barNoiseConn *NoiseConnection) {
+	var (
+		fooConn, barConn = makeKVStoreConnPair()
+		fooPrvKey        = ed25519.GenPrivKey()
+		barPrvKey        = ed25519.GenPrivKey()
+	)
+
+	checkRemote := func(nc *NoiseConnection, expect crypto.PubKey) {
+		require.True(t, bytes.Equal(expect.Bytes(), nc.RemotePubKey().Bytes()))
+	}
+
+	var trs, ok = async.Parallel(
+		func(_ int) (val interface{}, abort bool, err error) {
+			fooNoiseConn, err = MakeNoiseConnection(fooConn, fooPrvKey, true)
+			return nil, err != nil, err
+		},
+		func(_ int) (val interface{}, abort bool, err error) {
+			barNoiseConn, err = MakeNoiseConnection(barConn, barPrvKey, false)
+			return nil, err != nil, err
+		},
+	)
+	require.NoError(t, trs.FirstError())
+	require.True(t, ok, "Unexpected task abortion")
+
+	checkRemote(fooNoiseConn, barPrvKey.PubKey())
+	checkRemote(barNoiseConn, fooPrvKey.PubKey())
+	return fooNoiseConn, barNoiseConn
+}
@@ -35,6 +35,10 @@ type Metrics struct {
 	PeerSendBytesTotal metrics.Counter
 	// Pending bytes to be sent to a given peer.
 	PeerPendingSendBytes metrics.Gauge
+	// Number of messages received from a given peer.
+	PeerReceiveMessagesTotal metrics.Counter
+	// Number of messages sent to a given peer.
+	PeerSendMessagesTotal metrics.Counter
 
 	// RouterPeerQueueRecv defines the time taken to read off of a peer's queue
 	// before sending on the connection.
@@ -56,6 +60,10 @@ type Metrics struct {
 	// queue for a specific flow (i.e. Channel).
 	PeerQueueMsgSize metrics.Gauge
 
+	// RouterRateLimitDroppedMsgs defines the number of messages dropped
+	// because they exceeded the send or receive rate limit of a p2p Channel.
+	RouterRateLimitDroppedMsgs metrics.Counter
+
 	mtx               *sync.RWMutex
 	messageLabelNames map[reflect.Type]string
 }
@@ -97,6 +105,20 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Help:      "Number of pending bytes to be sent to a given peer.",
 		}, append(labels, "peer_id")).With(labelsAndValues...),
 
+		PeerReceiveMessagesTotal: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_receive_messages_total",
+			Help:      "Number of messages received from a given peer.",
+		}, append(labels, "peer_id", "chID")).With(labelsAndValues...),
+
+		PeerSendMessagesTotal: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_send_messages_total",
+			Help:      "Number of messages sent to a given peer.",
+		}, append(labels, "peer_id", "chID")).With(labelsAndValues...),
+
 		RouterPeerQueueRecv: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -132,6 +154,13 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Help:      "The size of messages sent over a peer's queue for a specific p2p Channel.",
 		}, append(labels, "ch_id")).With(labelsAndValues...),
 
+		RouterRateLimitDroppedMsgs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "router_rate_limit_dropped_msgs",
+			Help:      "The number of messages dropped for exceeding the send or receive rate limit of a p2p Channel.",
+		}, append(labels, "ch_id", "direction")).With(labelsAndValues...),
+
 		mtx:               &sync.RWMutex{},
 		messageLabelNames: map[reflect.Type]string{},
 	}
@@ -140,17 +169,20 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 // NopMetrics returns no-op Metrics.
 func NopMetrics() *Metrics {
 	return &Metrics{
-		Peers:                  discard.NewGauge(),
-		PeerReceiveBytesTotal:  discard.NewCounter(),
-		PeerSendBytesTotal:     discard.NewCounter(),
-		PeerPendingSendBytes:   discard.NewGauge(),
-		RouterPeerQueueRecv:    discard.NewHistogram(),
-		RouterPeerQueueSend:    discard.NewHistogram(),
-		RouterChannelQueueSend: discard.NewHistogram(),
-		PeerQueueDroppedMsgs:   discard.NewCounter(),
-		PeerQueueMsgSize:       discard.NewGauge(),
-		mtx:                    &sync.RWMutex{},
-		messageLabelNames:      map[reflect.Type]string{},
+		Peers:                      discard.NewGauge(),
+		PeerReceiveBytesTotal:      discard.NewCounter(),
+		PeerSendBytesTotal:         discard.NewCounter(),
+		PeerPendingSendBytes:       discard.NewGauge(),
+		PeerReceiveMessagesTotal:   discard.NewCounter(),
+		PeerSendMessagesTotal:      discard.NewCounter(),
+		RouterPeerQueueRecv:        discard.NewHistogram(),
+		RouterPeerQueueSend:        discard.NewHistogram(),
+		RouterChannelQueueSend:     discard.NewHistogram(),
+		PeerQueueDroppedMsgs:       discard.NewCounter(),
+		PeerQueueMsgSize:           discard.NewGauge(),
+		RouterRateLimitDroppedMsgs: discard.NewCounter(),
+		mtx:                        &sync.RWMutex{},
+		messageLabelNames:          map[reflect.Type]string{},
 	}
 }
 
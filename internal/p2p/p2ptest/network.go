@@ -33,11 +33,20 @@ type NetworkOptions struct {
 	NumNodes   int
 	BufferSize int
 	NodeOpts   NodeOptions
+
+	// LinkConditions are the default conditions of links between nodes,
+	// e.g. to add latency. Defaults to perfect links.
+	LinkConditions p2p.LinkConditions
 }
 
 type NodeOptions struct {
-	MaxPeers     uint16
-	MaxConnected uint16
+	MaxPeers         uint16
+	MaxConnected     uint16
+	ChannelSendRates map[p2p.ChannelID]int64
+	ChannelRecvRates map[p2p.ChannelID]int64
+
+	// Capture records the node's messages, see p2p.Capture.
+	Capture *p2p.Capture
 }
 
 func (opts *NetworkOptions) setDefaults() {
@@ -56,6 +65,7 @@ func MakeNetwork(ctx context.Context, t *testing.T, opts NetworkOptions) *Networ
 		logger:        logger,
 		memoryNetwork: p2p.NewMemoryNetwork(logger, opts.BufferSize),
 	}
+	require.NoError(t, network.memoryNetwork.SetDefaultLinkConditions(opts.LinkConditions))
 
 	for i := 0; i < opts.NumNodes; i++ {
 		node := network.MakeNode(ctx, t, opts.NodeOpts)
@@ -69,67 +79,88 @@ func MakeNetwork(ctx context.Context, t *testing.T, opts NetworkOptions) *Networ
 // addition to creating a peer update subscription for each node. Finally, all
 // nodes are connected to each other.
 func (n *Network) Start(ctx context.Context, t *testing.T) {
+	n.StartTopology(ctx, t, FullMesh(n.NodeIDs()...))
+}
+
+// StartTopology starts the network like Start, but only connects the nodes
+// linked by the given topology.
+func (n *Network) StartTopology(ctx context.Context, t *testing.T, topology Topology) {
 	ctx, n.cancel = context.WithCancel(ctx)
 	t.Cleanup(n.cancel)
 
-	// Set up a list of node addresses to dial, and a peer update subscription
-	// for each node.
-	dialQueue := []p2p.NodeAddress{}
-	subs := map[types.NodeID]*p2p.PeerUpdates{}
+	for _, link := range topology {
+		n.Connect(ctx, t, link[0], link[1])
+	}
+}
+
+// Connect makes the source node dial the target node, and waits for both of
+// them to confirm the connection. It can be used to connect nodes mid-test.
+func (n *Network) Connect(ctx context.Context, t *testing.T, sourceID, targetID types.NodeID) {
+	require.Contains(t, n.Nodes, sourceID)
+	require.Contains(t, n.Nodes, targetID)
+	sourceNode := n.Nodes[sourceID]
+	targetNode := n.Nodes[targetID]
+
 	subctx, subcancel := context.WithCancel(ctx)
 	defer subcancel()
-	for _, node := range n.Nodes {
-		dialQueue = append(dialQueue, node.NodeAddress)
-		subs[node.NodeID] = node.PeerManager.Subscribe(subctx)
+	sourceSub := sourceNode.PeerManager.Subscribe(subctx)
+	targetSub := targetNode.PeerManager.Subscribe(subctx)
+
+	added, err := sourceNode.PeerManager.Add(targetNode.NodeAddress)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	select {
+	case <-ctx.Done():
+		require.Fail(t, "operation canceled")
+	case peerUpdate := <-sourceSub.Updates():
+		require.Equal(t, p2p.PeerUpdate{
+			NodeID: targetNode.NodeID,
+			Status: p2p.PeerStatusUp,
+		}, peerUpdate)
+	case <-time.After(3 * time.Second):
+		require.Fail(t, "timed out waiting for peer", "%v dialing %v",
+			sourceNode.NodeID, targetNode.NodeID)
 	}
 
-	// For each node, dial the nodes that it still doesn't have a connection to
-	// (either inbound or outbound), and wait for both sides to confirm the
-	// connection via the subscriptions.
-	for i, sourceAddress := range dialQueue {
-		sourceNode := n.Nodes[sourceAddress.NodeID]
-		sourceSub := subs[sourceAddress.NodeID]
-
-		for _, targetAddress := range dialQueue[i+1:] { // nodes <i already connected
-			targetNode := n.Nodes[targetAddress.NodeID]
-			targetSub := subs[targetAddress.NodeID]
-			added, err := sourceNode.PeerManager.Add(targetAddress)
-			require.NoError(t, err)
-			require.True(t, added)
-
-			select {
-			case <-ctx.Done():
-				require.Fail(t, "operation canceled")
-			case peerUpdate := <-sourceSub.Updates():
-				require.Equal(t, p2p.PeerUpdate{
-					NodeID: targetNode.NodeID,
-					Status: p2p.PeerStatusUp,
-				}, peerUpdate)
-			case <-time.After(3 * time.Second):
-				require.Fail(t, "timed out waiting for peer", "%v dialing %v",
-					sourceNode.NodeID, targetNode.NodeID)
-			}
-
-			select {
-			case <-ctx.Done():
-				require.Fail(t, "operation canceled")
-			case peerUpdate := <-targetSub.Updates():
-				require.Equal(t, p2p.PeerUpdate{
-					NodeID: sourceNode.NodeID,
-					Status: p2p.PeerStatusUp,
-				}, peerUpdate)
-			case <-time.After(3 * time.Second):
-				require.Fail(t, "timed out waiting for peer", "%v accepting %v",
-					targetNode.NodeID, sourceNode.NodeID)
-			}
-
-			// Add the address to the target as well, so it's able to dial the
-			// source back if that's even necessary.
-			added, err = targetNode.PeerManager.Add(sourceAddress)
-			require.NoError(t, err)
-			require.True(t, added)
-		}
+	select {
+	case <-ctx.Done():
+		require.Fail(t, "operation canceled")
+	case peerUpdate := <-targetSub.Updates():
+		require.Equal(t, p2p.PeerUpdate{
+			NodeID: sourceNode.NodeID,
+			Status: p2p.PeerStatusUp,
+		}, peerUpdate)
+	case <-time.After(3 * time.Second):
+		require.Fail(t, "timed out waiting for peer", "%v accepting %v",
+			targetNode.NodeID, sourceNode.NodeID)
 	}
+
+	// Add the address to the target as well, so it's able to dial the
+	// source back if that's even necessary.
+	added, err = targetNode.PeerManager.Add(sourceNode.NodeAddress)
+	require.NoError(t, err)
+	require.True(t, added)
+}
+
+// SetLinkConditions sets the conditions of the links between two nodes, in
+// both directions.
+func (n *Network) SetLinkConditions(t *testing.T, a, b types.NodeID, conditions p2p.LinkConditions) {
+	require.NoError(t, n.memoryNetwork.SetLinkConditions(a, b, conditions))
+	require.NoError(t, n.memoryNetwork.SetLinkConditions(b, a, conditions))
+}
+
+// Partition splits the network into the given groups of nodes, which can't
+// communicate with each other until Heal is called. Nodes that aren't in any
+// group form a group of their own. Connections are not closed, but messages
+// across the partition are dropped.
+func (n *Network) Partition(groups ...[]types.NodeID) {
+	n.memoryNetwork.Partition(groups...)
+}
+
+// Heal removes a partition set via Partition.
+func (n *Network) Heal() {
+	n.memoryNetwork.Heal()
 }
 
 // NodeIDs returns the network's node IDs.
@@ -267,7 +298,12 @@ func (n *Network) MakeNode(ctx context.Context, t *testing.T, opts NodeOptions)
 		peerManager,
 		[]p2p.Transport{transport},
 		transport.Endpoints(),
-		p2p.RouterOptions{DialSleep: func(_ context.Context) {}},
+		p2p.RouterOptions{
+			DialSleep:        func(_ context.Context) {},
+			ChannelSendRates: opts.ChannelSendRates,
+			ChannelRecvRates: opts.ChannelRecvRates,
+			Capture:          opts.Capture,
+		},
 	)
 
 	require.NoError(t, err)
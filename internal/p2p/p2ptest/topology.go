@@ -0,0 +1,47 @@
+package p2ptest
+
+import (
+	"github.com/tendermint/tendermint/types"
+)
+
+// Topology is a list of links between nodes, each from the dialing node to the
+// dialed node, used to connect a Network via StartTopology.
+type Topology [][2]types.NodeID
+
+// FullMesh links every node to every other node.
+func FullMesh(ids ...types.NodeID) Topology {
+	topology := Topology{}
+	for i, source := range ids {
+		for _, target := range ids[i+1:] {
+			topology = append(topology, [2]types.NodeID{source, target})
+		}
+	}
+	return topology
+}
+
+// Line links each node to the next one.
+func Line(ids ...types.NodeID) Topology {
+	topology := Topology{}
+	for i := 1; i < len(ids); i++ {
+		topology = append(topology, [2]types.NodeID{ids[i-1], ids[i]})
+	}
+	return topology
+}
+
+// Ring links each node to the next one, and the last node to the first one.
+func Ring(ids ...types.NodeID) Topology {
+	topology := Line(ids...)
+	if len(ids) > 2 {
+		topology = append(topology, [2]types.NodeID{ids[len(ids)-1], ids[0]})
+	}
+	return topology
+}
+
+// Star links each leaf node to the center node.
+func Star(center types.NodeID, leaves ...types.NodeID) Topology {
+	topology := Topology{}
+	for _, leaf := range leaves {
+		topology = append(topology, [2]types.NodeID{leaf, center})
+	}
+	return topology
+}
@@ -0,0 +1,389 @@
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/google/orderedcode"
+	dbm "github.com/tendermint/tm-db"
+
+	p2pproto "github.com/tendermint/tendermint/proto/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+// PeerBan bans either a node ID or an IP network. The peer manager will not
+// dial or accept banned peers, and evicts them if they are connected.
+type PeerBan struct {
+	NodeID types.NodeID // set for node ID bans
+	IPNet  *net.IPNet   // set for IP bans
+	Until  time.Time    // the ban expires at this time, zero never expires
+	Reason string
+}
+
+// ParsePeerBan creates a ban for the given target, which is either a node ID,
+// an IP address or a CIDR network. A zero duration bans the target
+// indefinitely.
+func ParsePeerBan(target string, duration time.Duration, reason string) (PeerBan, error) {
+	if duration < 0 {
+		return PeerBan{}, errors.New("negative ban duration")
+	}
+	ban := PeerBan{Reason: reason}
+	if duration > 0 {
+		ban.Until = time.Now().Add(duration).UTC()
+	}
+
+	if _, ipNet, err := net.ParseCIDR(target); err == nil {
+		ban.IPNet = ipNet
+		return ban, nil
+	}
+	if ip := net.ParseIP(target); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		ban.IPNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		return ban, nil
+	}
+	nodeID, err := types.NewNodeID(target)
+	if err != nil {
+		return PeerBan{}, fmt.Errorf("ban target %q is not a node ID, IP address or CIDR network", target)
+	}
+	ban.NodeID = nodeID
+	return ban, nil
+}
+
+// Target returns the banned node ID or IP network.
+func (b PeerBan) Target() string {
+	if b.IPNet != nil {
+		return b.IPNet.String()
+	}
+	return string(b.NodeID)
+}
+
+// Validate validates the ban.
+func (b PeerBan) Validate() error {
+	switch {
+	case b.NodeID == "" && b.IPNet == nil:
+		return errors.New("no node ID or IP network given")
+	case b.NodeID != "" && b.IPNet != nil:
+		return errors.New("ban can't have both a node ID and an IP network")
+	case b.NodeID != "":
+		return b.NodeID.Validate()
+	}
+	return nil
+}
+
+// expired returns whether the ban has expired at the given time.
+func (b PeerBan) expired(now time.Time) bool {
+	return !b.Until.IsZero() && !now.Before(b.Until)
+}
+
+// peerBanFromProto converts a Protobuf PeerBan message to a PeerBan, erroring
+// if the data is invalid.
+func peerBanFromProto(msg *p2pproto.PeerBan) (PeerBan, error) {
+	ban, err := ParsePeerBan(msg.Target, 0, msg.Reason)
+	if err != nil {
+		return PeerBan{}, err
+	}
+	if msg.Until != nil {
+		ban.Until = *msg.Until
+	}
+	return ban, nil
+}
+
+// ToProto converts the ban to p2pproto.PeerBan for database storage.
+func (b PeerBan) ToProto() *p2pproto.PeerBan {
+	msg := &p2pproto.PeerBan{
+		Target: b.Target(),
+		Reason: b.Reason,
+	}
+	if !b.Until.IsZero() {
+		until := b.Until
+		msg.Until = &until
+	}
+	return msg
+}
+
+// loadPeerBans loads all unexpired bans from the database.
+func loadPeerBans(db dbm.DB) (map[string]PeerBan, error) {
+	bans := map[string]PeerBan{}
+
+	start, end := keyPeerBanRange()
+	iter, err := db.Iterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	now := time.Now()
+	for ; iter.Valid(); iter.Next() {
+		msg := new(p2pproto.PeerBan)
+		if err := proto.Unmarshal(iter.Value(), msg); err != nil {
+			return nil, fmt.Errorf("invalid peer ban Protobuf data: %w", err)
+		}
+		ban, err := peerBanFromProto(msg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer ban data: %w", err)
+		}
+		if !ban.expired(now) {
+			bans[ban.Target()] = ban
+		}
+	}
+	if iter.Error() != nil {
+		return nil, iter.Error()
+	}
+	return bans, nil
+}
+
+// Ban bans a node ID or IP network, persisting the ban in the peer database.
+// Any existing ban of the same target is replaced, and connected peers
+// matching the ban are evicted.
+func (m *PeerManager) Ban(ban PeerBan) error {
+	if err := ban.Validate(); err != nil {
+		return err
+	}
+	if ban.NodeID == m.selfID {
+		return fmt.Errorf("can't ban self (%v)", m.selfID)
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.ban(ban)
+}
+
+// ban persists and applies a validated ban. The caller must hold the mutex
+// lock.
+func (m *PeerManager) ban(ban PeerBan) error {
+	bz, err := ban.ToProto().Marshal()
+	if err != nil {
+		return err
+	}
+	if err := m.store.db.Set(keyPeerBan(ban.Target()), bz); err != nil {
+		return err
+	}
+	m.bans[ban.Target()] = ban
+
+	for peerID := range m.connected {
+		if m.isBanned(peerID) || m.isBannedIP(m.connectedIPs[peerID]) {
+			m.evict[peerID] = true
+		}
+	}
+	m.evictWaker.Wake()
+	return nil
+}
+
+// Unban removes the ban of a node ID, IP address or CIDR network.
+func (m *PeerManager) Unban(target string) error {
+	ban, err := ParsePeerBan(target, 0, "")
+	if err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if _, ok := m.bans[ban.Target()]; !ok {
+		return fmt.Errorf("%v is not banned", ban.Target())
+	}
+	if err := m.store.db.Delete(keyPeerBan(ban.Target())); err != nil {
+		return err
+	}
+	delete(m.bans, ban.Target())
+	m.dialWaker.Wake()
+	return nil
+}
+
+// Bans returns the current bans ordered by target, removing expired bans from
+// the peer database.
+func (m *PeerManager) Bans() ([]PeerBan, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	now := time.Now()
+	bans := make([]PeerBan, 0, len(m.bans))
+	for target, ban := range m.bans {
+		if ban.expired(now) {
+			if err := m.store.db.Delete(keyPeerBan(target)); err != nil {
+				return nil, err
+			}
+			delete(m.bans, target)
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].Target() < bans[j].Target() })
+	return bans, nil
+}
+
+// isBanned returns whether a node ID is banned. The caller must hold the mutex
+// lock.
+func (m *PeerManager) isBanned(peerID types.NodeID) bool {
+	ban, ok := m.bans[string(peerID)]
+	return ok && !ban.expired(time.Now())
+}
+
+// isBannedIP returns whether an IP address is within a banned network. The
+// caller must hold the mutex lock.
+func (m *PeerManager) isBannedIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	now := time.Now()
+	for _, ban := range m.bans {
+		if ban.IPNet != nil && ban.IPNet.Contains(ip) && !ban.expired(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBanned returns an error if either the node ID or the IP address is
+// banned. Empty values are not checked.
+func (m *PeerManager) checkBanned(peerID types.NodeID, ip net.IP) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if peerID != "" && m.isBanned(peerID) {
+		return fmt.Errorf("peer %v is banned", peerID)
+	}
+	if m.isBannedIP(ip) {
+		return fmt.Errorf("IP %v is banned", ip)
+	}
+	return nil
+}
+
+// setPeerIP records the remote IP address of a connected peer, so that it can
+// be evicted by IP bans. The peer is evicted right away if the IP is already
+// banned.
+func (m *PeerManager) setPeerIP(peerID types.NodeID, ip net.IP) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if !m.connected[peerID] {
+		return
+	}
+	m.connectedIPs[peerID] = ip
+	if m.isBannedIP(ip) {
+		m.evict[peerID] = true
+		m.evictWaker.Wake()
+	}
+}
+
+// DialNow dials an address as soon as possible, adding it to the peer store
+// if necessary. The dial ignores any retry backoff from earlier failed dials.
+// If all connection slots are in use, the lowest-scored non-persistent peer is
+// evicted once the dial succeeds, and an error is returned if there is no such
+// peer.
+func (m *PeerManager) DialNow(address NodeAddress) error {
+	if err := address.Validate(); err != nil {
+		return err
+	}
+	if address.NodeID == m.selfID {
+		return fmt.Errorf("can't dial self (%v)", m.selfID)
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.isBanned(address.NodeID) {
+		return fmt.Errorf("peer %v is banned", address.NodeID)
+	}
+	if m.connected[address.NodeID] {
+		return fmt.Errorf("peer %v is already connected", address.NodeID)
+	}
+	if m.dialing[address.NodeID] {
+		return fmt.Errorf("peer %v is already being dialed", address.NodeID)
+	}
+	if m.isFull() && m.findUpgradeCandidate(address.NodeID, PeerScorePersistent) == "" {
+		return errors.New("no connection slot available, all connected peers are persistent or being evicted")
+	}
+
+	peer, ok := m.store.Get(address.NodeID)
+	if !ok {
+		peer = m.newPeerInfo(address.NodeID)
+	}
+	// The peer store is not pruned here, since the new peer would likely be
+	// the first to go.
+	if addressInfo, ok := peer.AddressInfo[address]; ok {
+		addressInfo.DialFailures = 0
+		addressInfo.LastDialFailure = time.Time{}
+	} else {
+		peer.AddressInfo[address] = &peerAddressInfo{Address: address}
+	}
+	if err := m.store.Set(peer); err != nil {
+		return err
+	}
+	m.dialRequests[address.NodeID] = address
+	m.dialWaker.Wake()
+	return nil
+}
+
+// nextDialRequest returns an address requested via DialNow, marking it as
+// dialing. If all connection slots are in use, a lower-scored peer is claimed
+// for eviction as for upgrades. Requests which can no longer be served are
+// dropped, leaving the peer to regular dialing. The caller must hold the mutex
+// lock.
+func (m *PeerManager) nextDialRequest() (NodeAddress, bool) {
+	for id, address := range m.dialRequests {
+		delete(m.dialRequests, id)
+		if m.dialing[id] || m.connected[id] || m.isBanned(id) {
+			continue
+		}
+		if m.isFull() {
+			upgradeFromPeer := m.findUpgradeCandidate(id, PeerScorePersistent)
+			if upgradeFromPeer == "" {
+				continue
+			}
+			m.upgrading[upgradeFromPeer] = id
+		}
+		m.dialing[id] = true
+		m.requested[id] = true
+		return address, true
+	}
+	return NodeAddress{}, false
+}
+
+// isFull returns whether all connection slots are in use, not counting upgrade
+// capacity. The caller must hold the mutex lock.
+func (m *PeerManager) isFull() bool {
+	return m.options.MaxConnected > 0 && len(m.connected) >= int(m.options.MaxConnected)
+}
+
+// Disconnect evicts a connected peer. The peer may be dialed or accepted again
+// afterwards, unless it is also banned.
+func (m *PeerManager) Disconnect(peerID types.NodeID) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if !m.connected[peerID] {
+		return fmt.Errorf("peer %v is not connected", peerID)
+	}
+	m.evict[peerID] = true
+	m.evictWaker.Wake()
+	return nil
+}
+
+// keyPeerBan generates a PeerBan database key.
+func keyPeerBan(target string) []byte {
+	key, err := orderedcode.Append(nil, prefixPeerBan, target)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// keyPeerBanRange generates start/end keys for the entire PeerBan key range.
+func keyPeerBanRange() ([]byte, []byte) {
+	start, err := orderedcode.Append(nil, prefixPeerBan, "")
+	if err != nil {
+		panic(err)
+	}
+	end, err := orderedcode.Append(nil, prefixPeerBan, orderedcode.Infinity)
+	if err != nil {
+		panic(err)
+	}
+	return start, end
+}
@@ -0,0 +1,212 @@
+package p2p
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestParsePeerBan(t *testing.T) {
+	id := types.NodeID(strings.Repeat("a", 40))
+
+	testcases := map[string]struct {
+		target string
+		expect string
+		ok     bool
+	}{
+		"node ID":      {string(id), string(id), true},
+		"IPv4":         {"1.2.3.4", "1.2.3.4/32", true},
+		"IPv6":         {"::1", "::1/128", true},
+		"CIDR":         {"10.1.2.3/8", "10.0.0.0/8", true},
+		"invalid ID":   {"abc", "", false},
+		"invalid CIDR": {"10.0.0.0/33", "", false},
+		"empty":        {"", "", false},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ban, err := ParsePeerBan(tc.target, 0, "")
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, ban.Validate())
+			require.Equal(t, tc.expect, ban.Target())
+			require.True(t, ban.Until.IsZero())
+		})
+	}
+
+	_, err := ParsePeerBan(string(id), -time.Second, "")
+	require.Error(t, err)
+}
+
+func TestPeerManager_Ban(t *testing.T) {
+	selfID := types.NodeID(strings.Repeat("f", 40))
+	a := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+
+	db := dbm.NewMemDB()
+	peerManager, err := NewPeerManager(selfID, db, PeerManagerOptions{})
+	require.NoError(t, err)
+
+	_, err = peerManager.Add(a)
+	require.NoError(t, err)
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+
+	// Banning ourself isn't allowed.
+	require.Error(t, peerManager.Ban(PeerBan{NodeID: selfID}))
+
+	// Banning a connected peer evicts it, and it can't be dialed or
+	// accepted afterwards.
+	ban, err := ParsePeerBan(string(a.NodeID), 0, "misbehaving")
+	require.NoError(t, err)
+	require.NoError(t, peerManager.Ban(ban))
+
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, a.NodeID, evict)
+	peerManager.Disconnected(context.Background(), a.NodeID)
+
+	dial, err := peerManager.TryDialNext()
+	require.NoError(t, err)
+	require.Zero(t, dial)
+	require.Error(t, peerManager.Accepted(a.NodeID))
+	require.Error(t, peerManager.DialNow(a))
+
+	// The ban is persisted.
+	peerManager, err = NewPeerManager(selfID, db, PeerManagerOptions{})
+	require.NoError(t, err)
+	bans, err := peerManager.Bans()
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	require.Equal(t, a.NodeID, bans[0].NodeID)
+	require.Equal(t, "misbehaving", bans[0].Reason)
+	require.Error(t, peerManager.Accepted(a.NodeID))
+
+	// IP bans evict connected peers with a matching remote IP.
+	require.NoError(t, peerManager.Accepted(b.NodeID))
+	peerManager.setPeerIP(b.NodeID, net.IPv4(10, 1, 2, 3))
+	require.NoError(t, peerManager.checkBanned("", net.IPv4(10, 1, 2, 3)))
+	ipBan, err := ParsePeerBan("10.0.0.0/8", time.Hour, "")
+	require.NoError(t, err)
+	require.NoError(t, peerManager.Ban(ipBan))
+	evict, err = peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, b.NodeID, evict)
+	require.Error(t, peerManager.checkBanned("", net.IPv4(10, 1, 2, 3)))
+	require.NoError(t, peerManager.checkBanned("", net.IPv4(11, 1, 2, 3)))
+
+	// Unbanning accepts the same target forms as banning.
+	require.NoError(t, peerManager.Unban("10.0.0.0/8"))
+	require.Error(t, peerManager.Unban("10.0.0.0/8"))
+	require.NoError(t, peerManager.Unban(string(a.NodeID)))
+	bans, err = peerManager.Bans()
+	require.NoError(t, err)
+	require.Empty(t, bans)
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+}
+
+func TestPeerManager_Ban_Expiry(t *testing.T) {
+	selfID := types.NodeID(strings.Repeat("f", 40))
+	a := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	db := dbm.NewMemDB()
+	peerManager, err := NewPeerManager(selfID, db, PeerManagerOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, peerManager.Ban(PeerBan{NodeID: a.NodeID, Until: time.Now().Add(-time.Second)}))
+	require.NoError(t, peerManager.checkBanned(a.NodeID, nil))
+
+	bans, err := peerManager.Bans()
+	require.NoError(t, err)
+	require.Empty(t, bans)
+
+	peerManager, err = NewPeerManager(selfID, db, PeerManagerOptions{})
+	require.NoError(t, err)
+	require.Empty(t, peerManager.bans)
+}
+
+func TestPeerManager_DialNow(t *testing.T) {
+	selfID := types.NodeID(strings.Repeat("f", 40))
+	a := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	peerManager, err := NewPeerManager(selfID, dbm.NewMemDB(), PeerManagerOptions{
+		MinRetryTime: time.Hour,
+	})
+	require.NoError(t, err)
+
+	// A failed dial backs off for an hour, but DialNow clears the backoff.
+	require.NoError(t, peerManager.DialNow(a))
+	dial, err := peerManager.TryDialNext()
+	require.NoError(t, err)
+	require.Equal(t, a, dial)
+	require.NoError(t, peerManager.DialFailed(context.Background(), a))
+
+	dial, err = peerManager.TryDialNext()
+	require.NoError(t, err)
+	require.Zero(t, dial)
+
+	require.NoError(t, peerManager.DialNow(a))
+	dial, err = peerManager.TryDialNext()
+	require.NoError(t, err)
+	require.Equal(t, a, dial)
+
+	require.NoError(t, peerManager.Dialed(a))
+	require.Error(t, peerManager.DialNow(a))
+	require.Error(t, peerManager.DialNow(NodeAddress{Protocol: "memory", NodeID: selfID}))
+}
+
+func TestPeerManager_DialNow_Full(t *testing.T) {
+	selfID := types.NodeID(strings.Repeat("f", 40))
+	a := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+	c := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("c", 40))}
+
+	peerManager, err := NewPeerManager(selfID, dbm.NewMemDB(), PeerManagerOptions{
+		PersistentPeers: []types.NodeID{a.NodeID, c.NodeID},
+		MaxConnected:    2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	require.NoError(t, peerManager.Accepted(b.NodeID))
+
+	// The requested dial bypasses the connection limit and claims the
+	// lowest-scored peer for eviction.
+	require.NoError(t, peerManager.DialNow(c))
+	dial, err := peerManager.TryDialNext()
+	require.NoError(t, err)
+	require.Equal(t, c, dial)
+	require.NoError(t, peerManager.Dialed(c))
+
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, b.NodeID, evict)
+	peerManager.Disconnected(context.Background(), b.NodeID)
+
+	// Only persistent peers are connected now, so there is no slot.
+	require.Error(t, peerManager.DialNow(b))
+}
+
+func TestPeerManager_Disconnect(t *testing.T) {
+	selfID := types.NodeID(strings.Repeat("f", 40))
+	a := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	peerManager, err := NewPeerManager(selfID, dbm.NewMemDB(), PeerManagerOptions{})
+	require.NoError(t, err)
+
+	require.Error(t, peerManager.Disconnect(a.NodeID))
+
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	require.NoError(t, peerManager.Disconnect(a.NodeID))
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, a.NodeID, evict)
+}
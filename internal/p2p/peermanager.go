@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net"
 	"sort"
 	"sync"
 	"time"
@@ -32,10 +33,11 @@ const (
 type PeerStatus string
 
 const (
-	PeerStatusUp   PeerStatus = "up"   // connected and ready
-	PeerStatusDown PeerStatus = "down" // disconnected
-	PeerStatusGood PeerStatus = "good" // peer observed as good
-	PeerStatusBad  PeerStatus = "bad"  // peer observed as bad
+	PeerStatusUp     PeerStatus = "up"     // connected and ready
+	PeerStatusDown   PeerStatus = "down"   // disconnected
+	PeerStatusGood   PeerStatus = "good"   // peer observed as good
+	PeerStatusBad    PeerStatus = "bad"    // peer observed as bad
+	PeerStatusHeight PeerStatus = "height" // peer reported its block range
 )
 
 // PeerScore is a numeric score assigned to a peer (higher is better).
@@ -49,6 +51,10 @@ const (
 type PeerUpdate struct {
 	NodeID types.NodeID
 	Status PeerStatus
+
+	// Base and Height are the peer's block range, for PeerStatusHeight updates.
+	Base   int64
+	Height int64
 }
 
 // PeerUpdates is a peer update subscription with notifications about peer
@@ -56,6 +62,10 @@ type PeerUpdate struct {
 type PeerUpdates struct {
 	routerUpdatesCh  chan PeerUpdate
 	reactorUpdatesCh chan PeerUpdate
+
+	syncTargetMtx sync.Mutex
+	syncTarget    SyncTarget
+	syncTargetCh  chan struct{} // signals a new sync target
 }
 
 // NewPeerUpdates creates a new PeerUpdates subscription. It is primarily for
@@ -65,6 +75,7 @@ func NewPeerUpdates(updatesCh chan PeerUpdate, buf int) *PeerUpdates {
 	return &PeerUpdates{
 		reactorUpdatesCh: updatesCh,
 		routerUpdatesCh:  make(chan PeerUpdate, buf),
+		syncTargetCh:     make(chan struct{}, 1),
 	}
 }
 
@@ -82,6 +93,28 @@ func (pu *PeerUpdates) SendUpdate(ctx context.Context, update PeerUpdate) {
 	}
 }
 
+// SetSyncTarget sets the blocks the reactor needs from peers while syncing,
+// so that the peer manager can prefer peers that are able to serve them (see
+// SyncTarget). It never blocks, and only the latest target is kept. The zero
+// target must be set when done syncing.
+func (pu *PeerUpdates) SetSyncTarget(target SyncTarget) {
+	pu.syncTargetMtx.Lock()
+	pu.syncTarget = target
+	pu.syncTargetMtx.Unlock()
+
+	select {
+	case pu.syncTargetCh <- struct{}{}:
+	default:
+	}
+}
+
+// getSyncTarget returns the latest sync target.
+func (pu *PeerUpdates) getSyncTarget() SyncTarget {
+	pu.syncTargetMtx.Lock()
+	defer pu.syncTargetMtx.Unlock()
+	return pu.syncTarget
+}
+
 // PeerManagerOptions specifies options for a PeerManager.
 type PeerManagerOptions struct {
 	// PersistentPeers are peers that we want to maintain persistent connections
@@ -132,6 +165,18 @@ type PeerManagerOptions struct {
 	// for testing. A score of 0 is ignored.
 	PeerScores map[types.NodeID]PeerScore
 
+	// ReputationHalfLife is the time it takes for peer reputations to decay
+	// to half their value. 0 disables decay.
+	ReputationHalfLife time.Duration
+
+	// ReputationBanThreshold is the (negative) reputation at which a peer is
+	// temporarily banned, for ReputationBanDuration. 0 disables reputation
+	// bans.
+	ReputationBanThreshold float64
+
+	// ReputationBanDuration is the duration of reputation bans.
+	ReputationBanDuration time.Duration
+
 	// PrivatePeerIDs defines a set of NodeID objects which the PEX reactor will
 	// consider private and never gossip.
 	PrivatePeers map[types.NodeID]struct{}
@@ -187,7 +232,7 @@ func (o *PeerManagerOptions) Validate() error {
 		}
 	}
 
-	return nil
+	return o.validateReputation()
 }
 
 // isPersistentPeer checks if a peer is in PersistentPeers. It will panic
@@ -262,12 +307,18 @@ type PeerManager struct {
 	mtx           sync.Mutex
 	store         *peerStore
 	subscriptions map[*PeerUpdates]*PeerUpdates // keyed by struct identity (address)
+	syncTargets   map[*PeerUpdates]SyncTarget   // sync targets set by subscribers
 	dialing       map[types.NodeID]bool         // peers being dialed (DialNext → Dialed/DialFail)
 	upgrading     map[types.NodeID]types.NodeID // peers claimed for upgrade (DialNext → Dialed/DialFail)
 	connected     map[types.NodeID]bool         // connected peers (Dialed/Accepted → Disconnected)
 	ready         map[types.NodeID]bool         // ready peers (Ready → Disconnected)
 	evict         map[types.NodeID]bool         // peers scheduled for eviction (Connected → EvictNext)
 	evicting      map[types.NodeID]bool         // peers being evicted (EvictNext → Disconnected)
+	bans          map[string]PeerBan            // banned node IDs and IP networks, keyed by target
+	connectedIPs  map[types.NodeID]net.IP       // remote IPs of connected peers, for IP bans
+	dialRequests  map[types.NodeID]NodeAddress  // dials requested via DialNow (DialNow → DialNext)
+	requested     map[types.NodeID]bool         // requested dials in progress (DialNext → Dialed/DialFail)
+	lastDecay     time.Time                     // last time reputations were decayed
 }
 
 // NewPeerManager creates a new peer manager.
@@ -300,11 +351,19 @@ func NewPeerManager(selfID types.NodeID, peerDB dbm.DB, options PeerManagerOptio
 		ready:         map[types.NodeID]bool{},
 		evict:         map[types.NodeID]bool{},
 		evicting:      map[types.NodeID]bool{},
+		connectedIPs:  map[types.NodeID]net.IP{},
+		dialRequests:  map[types.NodeID]NodeAddress{},
+		requested:     map[types.NodeID]bool{},
 		subscriptions: map[*PeerUpdates]*PeerUpdates{},
+		syncTargets:   map[*PeerUpdates]SyncTarget{},
+	}
+	if peerManager.bans, err = loadPeerBans(peerDB); err != nil {
+		return nil, err
 	}
 	if err = peerManager.configurePeers(); err != nil {
 		return nil, err
 	}
+	peerManager.decayReputations(time.Now())
 	if err = peerManager.prunePeers(); err != nil {
 		return nil, err
 	}
@@ -448,6 +507,10 @@ func (m *PeerManager) TryDialNext() (NodeAddress, error) {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
+	if address, ok := m.nextDialRequest(); ok {
+		return address, nil
+	}
+
 	// We allow dialing MaxConnected+MaxConnectedUpgrade peers. Including
 	// MaxConnectedUpgrade allows us to probe additional peers that have a
 	// higher score than any other peers, and if successful evict it.
@@ -456,36 +519,50 @@ func (m *PeerManager) TryDialNext() (NodeAddress, error) {
 		return NodeAddress{}, nil
 	}
 
-	for _, peer := range m.store.Ranked() {
-		if m.dialing[peer.ID] || m.connected[peer.ID] {
+	m.decayReputations(time.Now())
+	for _, peer := range m.dialOrder() {
+		address, ok := m.dialableAddress(peer)
+		if !ok {
 			continue
 		}
 
-		for _, addressInfo := range peer.AddressInfo {
-			if time.Since(addressInfo.LastDialFailure) < m.retryDelay(addressInfo.DialFailures, peer.Persistent) {
-				continue
+		// We now have an eligible address to dial. If we're full but have
+		// upgrade capacity (as checked above), we find a lower-scored peer
+		// we can replace and mark it as upgrading so noone else claims it.
+		//
+		// If we don't find one, there is no point in trying additional
+		// peers, since they will all have the same or lower score than this
+		// peer (since they're ordered by score via peerStore.Ranked).
+		if m.options.MaxConnected > 0 && len(m.connected) >= int(m.options.MaxConnected) {
+			upgradeFromPeer := m.findUpgradeCandidate(peer.ID, peer.Score())
+			if upgradeFromPeer == "" {
+				return NodeAddress{}, nil
 			}
+			m.upgrading[upgradeFromPeer] = peer.ID
+		}
 
-			// We now have an eligible address to dial. If we're full but have
-			// upgrade capacity (as checked above), we find a lower-scored peer
-			// we can replace and mark it as upgrading so noone else claims it.
-			//
-			// If we don't find one, there is no point in trying additional
-			// peers, since they will all have the same or lower score than this
-			// peer (since they're ordered by score via peerStore.Ranked).
-			if m.options.MaxConnected > 0 && len(m.connected) >= int(m.options.MaxConnected) {
-				upgradeFromPeer := m.findUpgradeCandidate(peer.ID, peer.Score())
-				if upgradeFromPeer == "" {
-					return NodeAddress{}, nil
-				}
-				m.upgrading[upgradeFromPeer] = peer.ID
-			}
+		m.dialing[peer.ID] = true
+		return address, nil
+	}
+	return NodeAddress{}, nil
+}
 
-			m.dialing[peer.ID] = true
-			return addressInfo.Address, nil
+// dialableAddress returns an address of the peer that is eligible for dialing,
+// if any. The caller must hold the mutex lock.
+func (m *PeerManager) dialableAddress(peer *peerInfo) (NodeAddress, bool) {
+	if m.dialing[peer.ID] || m.connected[peer.ID] || m.isBanned(peer.ID) {
+		return NodeAddress{}, false
+	}
+	if time.Since(peer.ReputationUpdated) < m.reputationDelay(peer) {
+		return NodeAddress{}, false
+	}
+	for _, addressInfo := range peer.AddressInfo {
+		if time.Since(addressInfo.LastDialFailure) < m.retryDelay(addressInfo.DialFailures, peer.Persistent) {
+			continue
 		}
+		return addressInfo.Address, true
 	}
-	return NodeAddress{}, nil
+	return NodeAddress{}, false
 }
 
 // DialFailed reports a failed dial attempt. This will make the peer available
@@ -497,6 +574,7 @@ func (m *PeerManager) DialFailed(ctx context.Context, address NodeAddress) error
 	defer m.mtx.Unlock()
 
 	delete(m.dialing, address.NodeID)
+	delete(m.requested, address.NodeID)
 	for from, to := range m.upgrading {
 		if to == address.NodeID {
 			delete(m.upgrading, from) // Unmark failed upgrade attempt.
@@ -548,6 +626,8 @@ func (m *PeerManager) Dialed(address NodeAddress) error {
 	defer m.mtx.Unlock()
 
 	delete(m.dialing, address.NodeID)
+	requested := m.requested[address.NodeID]
+	delete(m.requested, address.NodeID)
 
 	var upgradeFromPeer types.NodeID
 	for from, to := range m.upgrading {
@@ -564,9 +644,14 @@ func (m *PeerManager) Dialed(address NodeAddress) error {
 	if m.connected[address.NodeID] {
 		return fmt.Errorf("peer %v is already connected", address.NodeID)
 	}
+	if m.isBanned(address.NodeID) {
+		return fmt.Errorf("peer %v is banned", address.NodeID)
+	}
 	if m.options.MaxConnected > 0 && len(m.connected) >= int(m.options.MaxConnected) {
-		if upgradeFromPeer == "" || len(m.connected) >=
-			int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade) {
+		// A requested dial has claimed a peer to evict, but may exceed the
+		// upgrade capacity.
+		if upgradeFromPeer == "" || (!requested && len(m.connected) >=
+			int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade)) {
 			return fmt.Errorf("already connected to maximum number of peers")
 		}
 	}
@@ -631,6 +716,9 @@ func (m *PeerManager) Accepted(peerID types.NodeID) error {
 	if m.connected[peerID] {
 		return fmt.Errorf("peer %q is already connected", peerID)
 	}
+	if m.isBanned(peerID) {
+		return fmt.Errorf("peer %q is banned", peerID)
+	}
 	if m.options.MaxConnected > 0 &&
 		len(m.connected) >= int(m.options.MaxConnected)+int(m.options.MaxConnectedUpgrade) {
 		return fmt.Errorf("already connected to maximum number of peers")
@@ -719,6 +807,13 @@ func (m *PeerManager) TryEvictNext() (types.NodeID, error) {
 		}
 	}
 
+	// While syncing, we make room for peers that can serve us by evicting
+	// peers that can't.
+	if peerID := m.findUselessPeer(); peerID != "" {
+		m.evicting[peerID] = true
+		return peerID, nil
+	}
+
 	// If we're below capacity, we don't need to evict anything.
 	if m.options.MaxConnected == 0 ||
 		len(m.connected)-len(m.evicting) <= int(m.options.MaxConnected) {
@@ -727,6 +822,7 @@ func (m *PeerManager) TryEvictNext() (types.NodeID, error) {
 
 	// If we're above capacity (shouldn't really happen), just pick the
 	// lowest-ranked peer to evict.
+	m.decayReputations(time.Now())
 	ranked := m.store.Ranked()
 	for i := len(ranked) - 1; i >= 0; i-- {
 		peer := ranked[i]
@@ -752,6 +848,7 @@ func (m *PeerManager) Disconnected(ctx context.Context, peerID types.NodeID) {
 	delete(m.evict, peerID)
 	delete(m.evicting, peerID)
 	delete(m.ready, peerID)
+	delete(m.connectedIPs, peerID)
 
 	if ready {
 		m.broadcast(ctx, PeerUpdate{
@@ -764,22 +861,24 @@ func (m *PeerManager) Disconnected(ctx context.Context, peerID types.NodeID) {
 }
 
 // Errored reports a peer error, causing the peer to be evicted if it's
-// currently connected.
-//
-// FIXME: This should probably be replaced with a peer behavior API, see
-// PeerError comments for more details.
-//
-// FIXME: This will cause the peer manager to immediately try to reconnect to
-// the peer, which is probably not always what we want.
-func (m *PeerManager) Errored(peerID types.NodeID, err error) {
+// currently connected. The peer's reputation is penalized according to the
+// error's Misbehavior, if it is a PeerError, which delays redialing it and may
+// cause it to be banned.
+func (m *PeerManager) Errored(peerID types.NodeID, err error) error {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
 	if m.connected[peerID] {
 		m.evict[peerID] = true
 	}
-
 	m.evictWaker.Wake()
+
+	misbehavior := MisbehaviorUnknown
+	var peerErr PeerError
+	if errors.As(err, &peerErr) {
+		misbehavior = peerErr.Misbehavior
+	}
+	return m.adjustReputation(peerID, -misbehavior.penalty(), misbehavior.String())
 }
 
 // Advertise returns a list of peer addresses to advertise to a peer.
@@ -844,7 +943,16 @@ func (m *PeerManager) Register(ctx context.Context, peerUpdates *PeerUpdates) {
 			select {
 			case <-ctx.Done():
 				return
+			case <-peerUpdates.syncTargetCh:
+				m.processSyncTarget(peerUpdates)
 			case pu := <-peerUpdates.routerUpdatesCh:
+				// Apply any pending sync target first, so that it takes
+				// effect before updates that were sent after it.
+				select {
+				case <-peerUpdates.syncTargetCh:
+					m.processSyncTarget(peerUpdates)
+				default:
+				}
 				m.processPeerEvent(ctx, pu)
 			}
 		}
@@ -855,6 +963,7 @@ func (m *PeerManager) Register(ctx context.Context, peerUpdates *PeerUpdates) {
 		m.mtx.Lock()
 		defer m.mtx.Unlock()
 		delete(m.subscriptions, peerUpdates)
+		delete(m.syncTargets, peerUpdates)
 	}()
 }
 
@@ -866,18 +975,25 @@ func (m *PeerManager) processPeerEvent(ctx context.Context, pu PeerUpdate) {
 		return
 	}
 
-	if _, ok := m.store.peers[pu.NodeID]; !ok {
-		m.store.peers[pu.NodeID] = &peerInfo{}
-	}
-
+	// There's nowhere to report errors to here, and they can only come from
+	// the database, so they're ignored.
 	switch pu.Status {
 	case PeerStatusBad:
-		m.store.peers[pu.NodeID].MutableScore--
+		_ = m.adjustReputation(pu.NodeID, -1, string(pu.Status))
 	case PeerStatusGood:
-		m.store.peers[pu.NodeID].MutableScore++
+		_ = m.adjustReputation(pu.NodeID, 1, string(pu.Status))
+	case PeerStatusHeight:
+		_ = m.setPeerRange(pu.NodeID, pu.Base, pu.Height)
 	}
 }
 
+// processSyncTarget applies the latest sync target of a subscription.
+func (m *PeerManager) processSyncTarget(peerUpdates *PeerUpdates) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.setSyncTarget(peerUpdates, peerUpdates.getSyncTarget())
+}
+
 // broadcast broadcasts a peer update to all subscriptions. The caller must
 // already hold the mutex lock, to make sure updates are sent in the same order
 // as the PeerManager processes them, but this means subscribers must be
@@ -913,6 +1029,32 @@ func (m *PeerManager) Addresses(peerID types.NodeID) []NodeAddress {
 	return addresses
 }
 
+// PeerAddressInfo describes the dial history of a peer address.
+type PeerAddressInfo struct {
+	Address         NodeAddress
+	LastDialSuccess time.Time
+	LastDialFailure time.Time
+	DialFailures    uint32 // since last successful dial
+}
+
+// AddressInfo returns the dial history of all known addresses for a peer, and
+// the last time the peer was connected (zero if never). The order of the
+// addresses is arbitrary.
+func (m *PeerManager) AddressInfo(peerID types.NodeID) ([]PeerAddressInfo, time.Time) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	peer, ok := m.store.Get(peerID)
+	if !ok {
+		return nil, time.Time{}
+	}
+	infos := make([]PeerAddressInfo, 0, len(peer.AddressInfo))
+	for _, addressInfo := range peer.AddressInfo {
+		infos = append(infos, PeerAddressInfo(*addressInfo))
+	}
+	return infos, peer.LastConnected
+}
+
 // Peers returns all known peers, primarily for testing. The order is arbitrary.
 func (m *PeerManager) Peers() []types.NodeID {
 	m.mtx.Lock()
@@ -1034,6 +1176,25 @@ func (m *PeerManager) SetHeight(peerID types.NodeID, height int64) error {
 	return m.store.Set(peer)
 }
 
+// setPeerRange stores the block range reported by a known peer. The caller
+// must hold the mutex lock.
+func (m *PeerManager) setPeerRange(peerID types.NodeID, base, height int64) error {
+	peer, ok := m.store.Get(peerID)
+	if !ok {
+		return nil
+	}
+	peer.Base = base
+	peer.Height = height
+	if err := m.store.Set(peer); err != nil {
+		return err
+	}
+	if len(m.syncTargets) > 0 {
+		m.dialWaker.Wake()
+		m.evictWaker.Wake()
+	}
+	return nil
+}
+
 // peerStore stores information about peers. It is not thread-safe, assuming it
 // is only used by PeerManager which handles concurrency control. This allows
 // the manager to execute multiple operations atomically via its own mutex.
@@ -1088,7 +1249,7 @@ func (s *peerStore) loadPeers() error {
 	}
 	s.peers = peers
 	s.ranked = nil // invalidate cache if populated
-	return nil
+	return s.loadReputations()
 }
 
 // Get fetches a peer. The boolean indicates whether the peer existed or not.
@@ -1115,9 +1276,14 @@ func (s *peerStore) Set(peer peerInfo) error {
 	if err = s.db.Set(keyPeerInfo(peer.ID), bz); err != nil {
 		return err
 	}
+	if err = s.setReputation(&peer); err != nil {
+		return err
+	}
 
-	if current, ok := s.peers[peer.ID]; !ok || current.Score() != peer.Score() {
-		// If the peer is new, or its score changes, we invalidate the Ranked() cache.
+	if current, ok := s.peers[peer.ID]; !ok || current.Score() != peer.Score() ||
+		current.Reputation != peer.Reputation {
+		// If the peer is new, or its score or reputation changes, we
+		// invalidate the Ranked() cache.
 		s.peers[peer.ID] = &peer
 		s.ranked = nil
 	} else {
@@ -1138,6 +1304,9 @@ func (s *peerStore) Delete(id types.NodeID) error {
 	if err := s.db.Delete(keyPeerInfo(id)); err != nil {
 		return err
 	}
+	if err := s.db.Delete(keyPeerReputation(id)); err != nil {
+		return err
+	}
 	delete(s.peers, id)
 	s.ranked = nil
 	return nil
@@ -1154,9 +1323,10 @@ func (s *peerStore) List() []peerInfo {
 }
 
 // Ranked returns a list of peers ordered by score (better peers first). Peers
-// with equal scores are returned in an arbitrary order. The returned list must
-// not be mutated or accessed concurrently by the caller, since it returns
-// pointers to internal peerStore data for performance.
+// with equal scores are ordered by reputation, so that misbehaving peers are
+// dialed last and evicted first. The returned list must not be mutated or
+// accessed concurrently by the caller, since it returns pointers to internal
+// peerStore data for performance.
 //
 // Ranked is used to determine both which peers to dial, which ones to evict,
 // and which ones to delete completely.
@@ -1164,8 +1334,6 @@ func (s *peerStore) List() []peerInfo {
 // FIXME: For now, we simply maintain a cache in s.ranked which is invalidated
 // by setting it to nil, but if necessary we should use a better data structure
 // for this (e.g. a heap or ordered map).
-//
-// FIXME: The scoring logic is currently very naïve, see peerInfo.Score().
 func (s *peerStore) Ranked() []*peerInfo {
 	if s.ranked != nil {
 		return s.ranked
@@ -1177,7 +1345,10 @@ func (s *peerStore) Ranked() []*peerInfo {
 	sort.Slice(s.ranked, func(i, j int) bool {
 		// FIXME: If necessary, consider precomputing scores before sorting,
 		// to reduce the number of Score() calls.
-		return s.ranked[i].Score() > s.ranked[j].Score()
+		if si, sj := s.ranked[i].Score(), s.ranked[j].Score(); si != sj {
+			return si > sj
+		}
+		return s.ranked[i].Reputation > s.ranked[j].Reputation
 	})
 	return s.ranked
 }
@@ -1193,12 +1364,16 @@ type peerInfo struct {
 	AddressInfo   map[NodeAddress]*peerAddressInfo
 	LastConnected time.Time
 
+	// The reputation is persisted separately from the Protobuf peer info,
+	// and decays towards 0 over time (see PeerManagerOptions.ReputationHalfLife).
+	Reputation        float64
+	ReputationUpdated time.Time
+
 	// These fields are ephemeral, i.e. not persisted to the database.
 	Persistent bool
+	Base       int64 // lowest block height the peer has, as last reported
 	Height     int64
 	FixedScore PeerScore // mainly for tests
-
-	MutableScore int64 // updated by router
 }
 
 // peerInfoFromProto converts a Protobuf PeerInfo message to a peerInfo,
@@ -1262,7 +1437,7 @@ func (p *peerInfo) Score() PeerScore {
 		return PeerScorePersistent
 	}
 
-	score := p.MutableScore
+	score := int64(math.Round(p.Reputation))
 
 	for _, addr := range p.AddressInfo {
 		// DialFailures is reset when dials succeed, so this
@@ -1346,7 +1521,9 @@ func (a *peerAddressInfo) Validate() error {
 
 // Database key prefixes.
 const (
-	prefixPeerInfo int64 = 1
+	prefixPeerInfo       int64 = 1
+	prefixPeerBan        int64 = 2
+	prefixPeerReputation int64 = 3
 )
 
 // keyPeerInfo generates a peerInfo database key.
@@ -0,0 +1,224 @@
+package pex
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/libs/service"
+	"github.com/tendermint/tendermint/types"
+)
+
+// CrawledPeer is what the crawler has learned about a peer.
+type CrawledPeer struct {
+	NodeID    types.NodeID
+	Addresses []p2p.PeerAddressInfo
+
+	// Connected is true if the peer was connected during the crawl.
+	Connected bool
+
+	// Reachable is true if the last dial to any of the peer's addresses
+	// succeeded.
+	Reachable bool
+
+	// LastSeen is the last time the peer was connected, or zero if never.
+	LastSeen time.Time
+
+	// NodeInfo is the information the peer sent when last connected, or nil
+	// if it has not been connected since the crawler started.
+	NodeInfo *types.NodeInfo
+
+	// Height is the peer's last known height, or 0 if unknown.
+	Height int64
+}
+
+// CrawlerOptions specifies options for a Crawler.
+type CrawlerOptions struct {
+	// Interval between crawls. Defaults to 10 minutes.
+	Interval time.Duration
+}
+
+// crawlerRouter is the part of the router used by the crawler.
+type crawlerRouter interface {
+	PeerNodeInfo(types.NodeID) (types.NodeInfo, bool)
+}
+
+// Crawler maps the network for seed nodes. Every crawl interval, it requests
+// addresses from all connected peers via the PEX reactor, and records the
+// reachability, advertised NodeInfo and last known height of every peer in
+// the peer store. Since the peer manager dials the discovered addresses,
+// successive crawls cover the network beyond the peers that are connected at
+// any given time. The latest crawl is available via Peers(), e.g. for the
+// net_crawl RPC route, and summarized in metrics.
+type Crawler struct {
+	service.BaseService
+	logger log.Logger
+
+	reactor     *Reactor
+	peerManager *p2p.PeerManager
+	router      crawlerRouter
+	peerUpdates *p2p.PeerUpdates
+	options     CrawlerOptions
+	metrics     *Metrics
+
+	mtx       sync.RWMutex
+	nodeInfos map[types.NodeID]types.NodeInfo // last seen NodeInfo of peers
+	peers     []CrawledPeer
+	lastCrawl time.Time
+	versions  map[string]bool // versions reported in metrics
+}
+
+// NewCrawler creates a new crawler, which crawls via the given PEX reactor.
+func NewCrawler(
+	logger log.Logger,
+	reactor *Reactor,
+	peerManager *p2p.PeerManager,
+	router crawlerRouter,
+	peerUpdates *p2p.PeerUpdates,
+	options CrawlerOptions,
+	metrics *Metrics,
+) *Crawler {
+	if options.Interval == 0 {
+		options.Interval = 10 * time.Minute
+	}
+	c := &Crawler{
+		logger:      logger,
+		reactor:     reactor,
+		peerManager: peerManager,
+		router:      router,
+		peerUpdates: peerUpdates,
+		options:     options,
+		metrics:     metrics,
+		nodeInfos:   map[types.NodeID]types.NodeInfo{},
+		versions:    map[string]bool{},
+	}
+	c.BaseService = *service.NewBaseService(logger, "Crawler", c)
+	return c
+}
+
+// OnStart implements service.Service.
+func (c *Crawler) OnStart(ctx context.Context) error {
+	go c.processPeerUpdates(ctx)
+	go c.crawlRoutine(ctx)
+	return nil
+}
+
+// OnStop implements service.Service.
+func (c *Crawler) OnStop() {}
+
+// Peers returns the peers recorded by the last crawl, ordered by node ID, and
+// the time of the crawl. The time is zero if no crawl has completed yet.
+func (c *Crawler) Peers() ([]CrawledPeer, time.Time) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return append([]CrawledPeer{}, c.peers...), c.lastCrawl
+}
+
+// Crawl requests addresses from all connected peers and records all known
+// peers. It is run every crawl interval, but can also be called directly.
+func (c *Crawler) Crawl(ctx context.Context) {
+	requests := c.reactor.requestAllPeers(ctx)
+
+	peers := []CrawledPeer{}
+	reachable := 0
+	versions := map[string]int{}
+	for _, peerID := range c.peerManager.Peers() {
+		peer := c.crawlPeer(peerID)
+		if peer.Reachable {
+			reachable++
+		}
+		if peer.NodeInfo != nil {
+			versions[peer.NodeInfo.Version]++
+		}
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].NodeID < peers[j].NodeID })
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.peers = peers
+	c.lastCrawl = time.Now().UTC()
+
+	c.metrics.Crawls.Add(1)
+	c.metrics.CrawlPeers.Set(float64(len(peers)))
+	c.metrics.CrawlReachablePeers.Set(float64(reachable))
+	for version := range c.versions {
+		if _, ok := versions[version]; !ok {
+			c.metrics.CrawlPeerVersions.With("version", version).Set(0)
+		}
+	}
+	c.versions = map[string]bool{}
+	for version, count := range versions {
+		c.metrics.CrawlPeerVersions.With("version", version).Set(float64(count))
+		c.versions[version] = true
+	}
+
+	c.logger.Info("crawled network", "peers", len(peers), "reachable", reachable, "requests", requests)
+}
+
+// crawlPeer records what is known about a peer.
+func (c *Crawler) crawlPeer(peerID types.NodeID) CrawledPeer {
+	addresses, lastConnected := c.peerManager.AddressInfo(peerID)
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Address.String() < addresses[j].Address.String()
+	})
+	peer := CrawledPeer{
+		NodeID:    peerID,
+		Addresses: addresses,
+		Connected: c.peerManager.Status(peerID) == p2p.PeerStatusUp,
+		LastSeen:  lastConnected,
+		Height:    c.peerManager.GetHeight(peerID),
+	}
+	for _, address := range addresses {
+		if !address.LastDialSuccess.IsZero() && address.LastDialSuccess.After(address.LastDialFailure) {
+			peer.Reachable = true
+		}
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if nodeInfo, ok := c.router.PeerNodeInfo(peerID); ok {
+		c.nodeInfos[peerID] = nodeInfo
+	}
+	if nodeInfo, ok := c.nodeInfos[peerID]; ok {
+		peer.NodeInfo = &nodeInfo
+	}
+	return peer
+}
+
+// crawlRoutine crawls the network every crawl interval.
+func (c *Crawler) crawlRoutine(ctx context.Context) {
+	ticker := time.NewTicker(c.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.Crawl(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processPeerUpdates records the NodeInfo of peers as they connect, since
+// they may disconnect before the next crawl.
+func (c *Crawler) processPeerUpdates(ctx context.Context) {
+	for {
+		select {
+		case peerUpdate := <-c.peerUpdates.Updates():
+			if peerUpdate.Status != p2p.PeerStatusUp {
+				continue
+			}
+			if nodeInfo, ok := c.router.PeerNodeInfo(peerUpdate.NodeID); ok {
+				c.mtx.Lock()
+				c.nodeInfos[peerUpdate.NodeID] = nodeInfo
+				c.mtx.Unlock()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package pex_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/internal/p2p/pex"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestCrawler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The crawling node is only connected to the second node, and learns
+	// about the third node via PEX.
+	testNet := setupNetwork(ctx, t, testOptions{TotalNodes: 3})
+	testNet.connectPeers(ctx, t, firstNode, secondNode)
+	testNet.connectPeers(ctx, t, secondNode, thirdNode)
+	testNet.start(ctx, t)
+
+	seedID := testNet.nodes[firstNode]
+	seed := testNet.network.Nodes[seedID]
+	crawler := pex.NewCrawler(
+		testNet.logger,
+		testNet.reactors[seedID],
+		seed.PeerManager,
+		seed.Router,
+		seed.PeerManager.Subscribe(ctx),
+		pex.CrawlerOptions{Interval: time.Hour},
+		pex.NopMetrics(),
+	)
+	require.NoError(t, crawler.Start(ctx))
+	t.Cleanup(crawler.Wait)
+
+	peers, lastCrawl := crawler.Peers()
+	require.Empty(t, peers)
+	require.True(t, lastCrawl.IsZero())
+
+	expect := map[types.NodeID]bool{testNet.nodes[secondNode]: true, testNet.nodes[thirdNode]: true}
+	require.Eventually(t, func() bool {
+		crawler.Crawl(ctx)
+		peers, _ := crawler.Peers()
+		crawled := 0
+		for _, peer := range peers {
+			if expect[peer.NodeID] && peer.Reachable && peer.Connected && peer.NodeInfo != nil {
+				crawled++
+			}
+		}
+		return crawled == len(expect)
+	}, longWait, checkFrequency)
+
+	peers, lastCrawl = crawler.Peers()
+	require.False(t, lastCrawl.IsZero())
+	for _, peer := range peers {
+		require.Equal(t, peer.NodeID, peer.NodeInfo.NodeID)
+		require.NotEmpty(t, peer.Addresses)
+		require.False(t, peer.LastSeen.IsZero())
+	}
+}
@@ -0,0 +1,71 @@
+package pex
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this package.
+	MetricsSubsystem = "pex"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of crawls completed by the crawler.
+	Crawls metrics.Counter
+	// Number of peers known to the crawler.
+	CrawlPeers metrics.Gauge
+	// Number of known peers that the crawler could dial.
+	CrawlReachablePeers metrics.Gauge
+	// Number of known peers per advertised software version.
+	CrawlPeerVersions metrics.Gauge
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Crawls: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "crawls",
+			Help:      "Number of network crawls completed.",
+		}, labels).With(labelsAndValues...),
+		CrawlPeers: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "crawl_peers",
+			Help:      "Number of peers known to the crawler.",
+		}, labels).With(labelsAndValues...),
+		CrawlReachablePeers: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "crawl_reachable_peers",
+			Help:      "Number of known peers that the crawler could dial.",
+		}, labels).With(labelsAndValues...),
+		CrawlPeerVersions: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "crawl_peer_versions",
+			Help:      "Number of known peers per advertised software version.",
+		}, append(labels, "version")).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		Crawls:              discard.NewCounter(),
+		CrawlPeers:          discard.NewGauge(),
+		CrawlReachablePeers: discard.NewGauge(),
+		CrawlPeerVersions:   discard.NewGauge(),
+	}
+}
@@ -95,6 +95,11 @@ type Reactor struct {
 	// peer down status update is sent
 	requestsSent map[types.NodeID]struct{}
 
+	// lastRequestSent keeps track of when requests were last sent to each
+	// peer, so that crawls (see Crawler) don't send requests more often than
+	// peers accept them.
+	lastRequestSent map[types.NodeID]time.Time
+
 	// lastReceivedRequests keeps track of when peers send a request to prevent
 	// peers from sending requests too often (as defined by
 	// minReceiveRequestInterval).
@@ -129,6 +134,7 @@ func NewReactor(
 		peerUpdates:          peerUpdates,
 		availablePeers:       make(map[types.NodeID]struct{}),
 		requestsSent:         make(map[types.NodeID]struct{}),
+		lastRequestSent:      make(map[types.NodeID]time.Time),
 		lastReceivedRequests: make(map[types.NodeID]time.Time),
 	}
 
@@ -305,6 +311,7 @@ func (r *Reactor) processPeerUpdate(peerUpdate p2p.PeerUpdate) {
 	case p2p.PeerStatusDown:
 		delete(r.availablePeers, peerUpdate.NodeID)
 		delete(r.requestsSent, peerUpdate.NodeID)
+		delete(r.lastRequestSent, peerUpdate.NodeID)
 		delete(r.lastReceivedRequests, peerUpdate.NodeID)
 	default:
 	}
@@ -342,11 +349,38 @@ func (r *Reactor) sendRequestForPeers(ctx context.Context) {
 	// remove the peer from the abvailable peers list and mark it in the requestsSent map
 	delete(r.availablePeers, peerID)
 	r.requestsSent[peerID] = struct{}{}
+	r.lastRequestSent[peerID] = time.Now()
 
 	r.calculateNextRequestTime()
 	r.logger.Debug("peer request sent", "next_request_time", r.nextRequestTime)
 }
 
+// requestAllPeers sends a request for addresses to every available peer,
+// except those that were sent a request too recently to accept another one.
+// It is used by the Crawler, and returns the number of requests sent.
+func (r *Reactor) requestAllPeers(ctx context.Context) int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	sent := 0
+	for peerID := range r.availablePeers {
+		if time.Since(r.lastRequestSent[peerID]) < 2*minReceiveRequestInterval {
+			continue
+		}
+		if err := r.pexCh.Send(ctx, p2p.Envelope{
+			To:      peerID,
+			Message: &protop2p.PexRequest{},
+		}); err != nil {
+			return sent
+		}
+		delete(r.availablePeers, peerID)
+		r.requestsSent[peerID] = struct{}{}
+		r.lastRequestSent[peerID] = time.Now()
+		sent++
+	}
+	return sent
+}
+
 // calculateNextRequestTime implements something of a proportional controller
 // to estimate how often the reactor should be requesting new peer addresses.
 // The dependent variable in this calculation is the ratio of new peers to
@@ -536,7 +536,9 @@ func (r *reactorTestSuite) listenForPeerUpdate(
 	waitPeriod time.Duration,
 ) {
 	on, with := r.checkNodePair(t, onNode, withNode)
-	sub := r.network.Nodes[on].PeerManager.Subscribe(ctx)
+	subctx, subcancel := context.WithCancel(ctx)
+	defer subcancel()
+	sub := r.network.Nodes[on].PeerManager.Subscribe(subctx)
 	timesUp := time.After(waitPeriod)
 	for {
 		select {
@@ -645,8 +647,10 @@ func (r *reactorTestSuite) connectPeers(ctx context.Context, t *testing.T, sourc
 		return
 	}
 
-	sourceSub := n1.PeerManager.Subscribe(ctx)
-	targetSub := n2.PeerManager.Subscribe(ctx)
+	subctx, subcancel := context.WithCancel(ctx)
+	defer subcancel()
+	sourceSub := n1.PeerManager.Subscribe(subctx)
+	targetSub := n2.PeerManager.Subscribe(subctx)
 
 	sourceAddress := n1.NodeAddress
 	r.logger.Debug("source address", "address", sourceAddress)
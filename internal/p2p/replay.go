@@ -0,0 +1,183 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ReplayerOptions specifies options for a Replayer.
+type ReplayerOptions struct {
+	// RealTime delays each message by the time that passed between it and
+	// the previous message in the capture. Otherwise, messages are replayed
+	// as fast as the reactor accepts them.
+	RealTime bool
+}
+
+// Replayer replays inbound messages from a capture (see Capture) into a single
+// reactor, to reproduce problems caused by peer traffic offline. It stands in
+// for the router: the reactor is constructed with the replayer's Channel() and
+// PeerUpdates(), and Run() feeds it the captured messages of its channel.
+// Peers are reported as up before their first message. Messages and errors
+// sent by the reactor are collected, and returned by Sent() and Errors().
+type Replayer struct {
+	logger  log.Logger
+	dec     *CaptureDecoder
+	chDesc  *ChannelDescriptor
+	options ReplayerOptions
+
+	channel       *Channel
+	inCh          chan Envelope
+	outCh         chan Envelope
+	errCh         chan PeerError
+	peerUpdates   *PeerUpdates
+	peerUpdatesCh chan PeerUpdate
+
+	mtx    sync.Mutex
+	sent   []Envelope
+	errors []PeerError
+}
+
+// NewReplayer creates a new replayer, which reads captured messages from dec
+// and replays those received on the given channel.
+func NewReplayer(
+	logger log.Logger,
+	dec *CaptureDecoder,
+	chDesc *ChannelDescriptor,
+	options ReplayerOptions,
+) *Replayer {
+	r := &Replayer{
+		logger:        logger,
+		dec:           dec,
+		chDesc:        chDesc,
+		options:       options,
+		inCh:          make(chan Envelope, chDesc.RecvBufferCapacity),
+		outCh:         make(chan Envelope, chDesc.RecvBufferCapacity),
+		errCh:         make(chan PeerError, chDesc.RecvBufferCapacity),
+		peerUpdatesCh: make(chan PeerUpdate),
+	}
+	r.channel = NewChannel(chDesc.ID, chDesc.MessageType, r.inCh, r.outCh, r.errCh)
+	r.peerUpdates = NewPeerUpdates(r.peerUpdatesCh, 1)
+	return r
+}
+
+// Channel returns the channel to pass to the reactor.
+func (r *Replayer) Channel() *Channel {
+	return r.channel
+}
+
+// PeerUpdates returns the peer updates to pass to the reactor.
+func (r *Replayer) PeerUpdates() *PeerUpdates {
+	return r.peerUpdates
+}
+
+// Sent returns the envelopes the reactor has sent so far.
+func (r *Replayer) Sent() []Envelope {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]Envelope{}, r.sent...)
+}
+
+// Errors returns the peer errors the reactor has reported so far.
+func (r *Replayer) Errors() []PeerError {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]PeerError{}, r.errors...)
+}
+
+// Run replays the capture into the reactor, returning once all messages have
+// been delivered to the channel or ctx ends. Messages and errors sent by the
+// reactor are collected until ctx ends, so that responses to the last
+// messages can still be inspected after Run returns.
+func (r *Replayer) Run(ctx context.Context) error {
+	go r.collect(ctx)
+
+	var (
+		peers    = map[types.NodeID]bool{}
+		last     time.Time
+		replayed int
+	)
+	for {
+		msg, err := r.dec.Decode()
+		if errors.Is(err, io.EOF) {
+			r.logger.Info("replayed capture", "channel", r.chDesc.ID, "messages", replayed)
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to decode captured message: %w", err)
+		}
+		if msg.Direction != CaptureInbound || msg.ChannelID != r.chDesc.ID {
+			continue
+		}
+
+		if r.options.RealTime && !last.IsZero() {
+			if wait := msg.Time.Sub(last); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				}
+			}
+		}
+		last = msg.Time
+
+		if !peers[msg.PeerID] {
+			select {
+			case r.peerUpdatesCh <- PeerUpdate{NodeID: msg.PeerID, Status: PeerStatusUp}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			peers[msg.PeerID] = true
+		}
+
+		// Messages that fail to decode are dropped, as the router does.
+		m := proto.Clone(r.chDesc.MessageType)
+		if err := proto.Unmarshal(msg.Payload, m); err != nil {
+			r.logger.Error("message decoding failed, dropping message", "peer", msg.PeerID, "err", err)
+			continue
+		}
+		if wrapper, ok := m.(Wrapper); ok {
+			m, err = wrapper.Unwrap()
+			if err != nil {
+				r.logger.Error("failed to unwrap message", "peer", msg.PeerID, "err", err)
+				continue
+			}
+		}
+
+		select {
+		case r.inCh <- Envelope{From: msg.PeerID, Message: m}:
+			replayed++
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// collect collects messages, errors and peer updates sent by the reactor.
+func (r *Replayer) collect(ctx context.Context) {
+	for {
+		select {
+		case envelope := <-r.outCh:
+			r.mtx.Lock()
+			r.sent = append(r.sent, envelope)
+			r.mtx.Unlock()
+		case peerError := <-r.errCh:
+			r.logger.Info("reactor reported peer error", "peer", peerError.NodeID, "err", peerError.Err)
+			r.mtx.Lock()
+			r.errors = append(r.errors, peerError)
+			r.mtx.Unlock()
+		case <-r.peerUpdates.routerUpdatesCh:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package p2p
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/google/orderedcode"
+
+	p2pproto "github.com/tendermint/tendermint/proto/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Misbehavior is a kind of peer misbehavior reported by reactors via
+// PeerError. It determines how much the peer's reputation is penalized.
+type Misbehavior uint8
+
+const (
+	MisbehaviorUnknown          Misbehavior = iota // unspecified error
+	MisbehaviorInvalidMessage                      // malformed or invalid message
+	MisbehaviorSpam                                // unsolicited or excessive messages
+	MisbehaviorInvalidBlockPart                    // block part that doesn't match the proposal
+	MisbehaviorInvalidVote                         // vote with an invalid signature
+	MisbehaviorInvalidBlock                        // block or light block failing verification
+)
+
+// String implements fmt.Stringer.
+func (m Misbehavior) String() string {
+	switch m {
+	case MisbehaviorInvalidMessage:
+		return "invalid message"
+	case MisbehaviorSpam:
+		return "spam"
+	case MisbehaviorInvalidBlockPart:
+		return "invalid block part"
+	case MisbehaviorInvalidVote:
+		return "invalid vote"
+	case MisbehaviorInvalidBlock:
+		return "invalid block"
+	default:
+		return "error"
+	}
+}
+
+// penalty returns the reputation penalty for the misbehavior. A
+// PeerStatusBad update has a penalty of 1.
+func (m Misbehavior) penalty() float64 {
+	switch m {
+	case MisbehaviorInvalidMessage:
+		return 10
+	case MisbehaviorSpam:
+		return 5
+	case MisbehaviorInvalidBlockPart, MisbehaviorInvalidVote:
+		return 20
+	case MisbehaviorInvalidBlock:
+		return 50
+	default:
+		return 1
+	}
+}
+
+const (
+	// maxReputation bounds the magnitude of peer reputations.
+	maxReputation = math.MaxUint8
+
+	// reputationDecayInterval is the minimum interval between decaying the
+	// reputations of all peers.
+	reputationDecayInterval = time.Minute
+)
+
+// decayReputation decays a reputation last updated at the given time towards
+// 0, halving it every halfLife. A zero halfLife disables decay.
+func decayReputation(reputation float64, updated, now time.Time, halfLife time.Duration) float64 {
+	if halfLife <= 0 || updated.IsZero() || !now.After(updated) {
+		return reputation
+	}
+	return reputation * math.Exp2(-float64(now.Sub(updated))/float64(halfLife))
+}
+
+// adjustReputation adds delta to a peer's reputation, after decaying it. If the
+// reputation drops to ReputationBanThreshold the peer is banned for
+// ReputationBanDuration. Peers that are not in the peer store are ignored. The
+// caller must hold the mutex lock.
+func (m *PeerManager) adjustReputation(peerID types.NodeID, delta float64, reason string) error {
+	peer, ok := m.store.Get(peerID)
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	reputation := decayReputation(peer.Reputation, peer.ReputationUpdated, now, m.options.ReputationHalfLife)
+	reputation = math.Max(-maxReputation, math.Min(maxReputation, reputation+delta))
+	peer.Reputation = reputation
+	peer.ReputationUpdated = now
+	if err := m.store.Set(peer); err != nil {
+		return err
+	}
+
+	threshold := m.options.ReputationBanThreshold
+	if threshold < 0 && reputation <= threshold && !peer.Persistent && !m.isBanned(peerID) {
+		return m.ban(PeerBan{
+			NodeID: peerID,
+			Until:  now.Add(m.options.ReputationBanDuration).UTC(),
+			Reason: fmt.Sprintf("reputation %.0f reached threshold %.0f (%v)", reputation, threshold, reason),
+		})
+	}
+	return nil
+}
+
+// decayReputations decays the reputations of all peers, at most once per
+// reputationDecayInterval. The decayed reputations are not written to the
+// database, since the persisted reputations decay to the same values when
+// loaded. The caller must hold the mutex lock.
+func (m *PeerManager) decayReputations(now time.Time) {
+	if m.options.ReputationHalfLife <= 0 || now.Sub(m.lastDecay) < reputationDecayInterval {
+		return
+	}
+	m.lastDecay = now
+	for _, peer := range m.store.peers {
+		if peer.Reputation == 0 {
+			continue
+		}
+		peer.Reputation = decayReputation(peer.Reputation, peer.ReputationUpdated, now, m.options.ReputationHalfLife)
+		peer.ReputationUpdated = now
+	}
+	m.store.ranked = nil
+}
+
+// reputationDelay returns how long to wait before dialing a peer with a
+// negative reputation, counted from its last reputation update. It uses the
+// dial retry backoff, taking the reputation deficit as the number of failures,
+// so there is no delay if retries are disabled. The caller must hold the mutex
+// lock.
+func (m *PeerManager) reputationDelay(peer *peerInfo) time.Duration {
+	if peer.Reputation > -1 || peer.Persistent || m.options.MinRetryTime == 0 {
+		return 0
+	}
+	return m.retryDelay(uint32(-peer.Reputation), false)
+}
+
+// Reputations returns the reputations of all known peers with a non-zero
+// reputation, primarily for testing.
+func (m *PeerManager) Reputations() map[types.NodeID]float64 {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	reputations := map[types.NodeID]float64{}
+	for _, peer := range m.store.peers {
+		if peer.Reputation != 0 {
+			reputations[peer.ID] = peer.Reputation
+		}
+	}
+	return reputations
+}
+
+// loadReputations loads persisted reputations into the peers in the store.
+// Reputations of unknown peers are ignored.
+func (s *peerStore) loadReputations() error {
+	start, end := keyPeerReputationRange()
+	iter, err := s.db.Iterator(start, end)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		msg := new(p2pproto.PeerReputation)
+		if err := proto.Unmarshal(iter.Value(), msg); err != nil {
+			return fmt.Errorf("invalid peer reputation Protobuf data: %w", err)
+		}
+		if peer, ok := s.peers[types.NodeID(msg.ID)]; ok {
+			peer.Reputation = msg.Reputation
+			if msg.Updated != nil {
+				peer.ReputationUpdated = *msg.Updated
+			}
+		}
+	}
+	return iter.Error()
+}
+
+// setReputation persists a peer's reputation, deleting it if it is zero.
+func (s *peerStore) setReputation(peer *peerInfo) error {
+	if peer.Reputation == 0 {
+		return s.db.Delete(keyPeerReputation(peer.ID))
+	}
+	msg := &p2pproto.PeerReputation{
+		ID:         string(peer.ID),
+		Reputation: peer.Reputation,
+	}
+	if !peer.ReputationUpdated.IsZero() {
+		updated := peer.ReputationUpdated
+		msg.Updated = &updated
+	}
+	bz, err := msg.Marshal()
+	if err != nil {
+		return err
+	}
+	return s.db.Set(keyPeerReputation(peer.ID), bz)
+}
+
+// validateReputation validates the reputation options.
+func (o *PeerManagerOptions) validateReputation() error {
+	switch {
+	case o.ReputationHalfLife < 0:
+		return errors.New("ReputationHalfLife can't be negative")
+	case o.ReputationBanThreshold > 0:
+		return errors.New("ReputationBanThreshold can't be positive")
+	case o.ReputationBanThreshold < 0 && o.ReputationBanDuration <= 0:
+		return errors.New("ReputationBanDuration must be positive when ReputationBanThreshold is set")
+	}
+	return nil
+}
+
+// keyPeerReputation generates a peer reputation database key.
+func keyPeerReputation(id types.NodeID) []byte {
+	key, err := orderedcode.Append(nil, prefixPeerReputation, string(id))
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// keyPeerReputationRange generates start/end keys for the entire peer
+// reputation key range.
+func keyPeerReputationRange() ([]byte, []byte) {
+	start, err := orderedcode.Append(nil, prefixPeerReputation, "")
+	if err != nil {
+		panic(err)
+	}
+	end, err := orderedcode.Append(nil, prefixPeerReputation, orderedcode.Infinity)
+	if err != nil {
+		panic(err)
+	}
+	return start, end
+}
@@ -0,0 +1,120 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestDecayReputation(t *testing.T) {
+	now := time.Now()
+
+	require.Equal(t, -50.0, decayReputation(-100, now.Add(-time.Hour), now, time.Hour))
+	require.Equal(t, 25.0, decayReputation(100, now.Add(-2*time.Hour), now, time.Hour))
+	require.Equal(t, -100.0, decayReputation(-100, now.Add(-time.Hour), now, 0))
+	require.Equal(t, -100.0, decayReputation(-100, time.Time{}, now, time.Hour))
+}
+
+func TestPeerManager_Reputation(t *testing.T) {
+	selfID := types.NodeID(strings.Repeat("f", 40))
+	a := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+
+	db := dbm.NewMemDB()
+	peerManager, err := NewPeerManager(selfID, db, PeerManagerOptions{
+		MinRetryTime: time.Second,
+		MaxRetryTime: time.Hour,
+	})
+	require.NoError(t, err)
+
+	for _, addr := range []NodeAddress{a, b} {
+		added, err := peerManager.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// Plain errors and misbehavior are penalized differently. Errors for
+	// unknown peers are ignored.
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	require.NoError(t, peerManager.Errored(a.NodeID, errors.New("boom")))
+	require.NoError(t, peerManager.Errored(a.NodeID, PeerError{
+		NodeID:      a.NodeID,
+		Err:         errors.New("invalid block"),
+		Misbehavior: MisbehaviorInvalidBlock,
+	}))
+	require.NoError(t, peerManager.Errored(types.NodeID(strings.Repeat("c", 40)), errors.New("boom")))
+	require.Equal(t, map[types.NodeID]float64{a.NodeID: -51}, peerManager.Reputations())
+
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, a.NodeID, evict)
+	peerManager.Disconnected(context.Background(), a.NodeID)
+
+	// The misbehaving peer is ranked last, and isn't redialed until its
+	// reputation backoff expires.
+	require.Equal(t, []types.NodeID{b.NodeID, a.NodeID}, peerManager.Peers())
+	dial, err := peerManager.TryDialNext()
+	require.NoError(t, err)
+	require.Equal(t, b, dial)
+	dial, err = peerManager.TryDialNext()
+	require.NoError(t, err)
+	require.Zero(t, dial)
+
+	// Reputations are persisted.
+	peerManager, err = NewPeerManager(selfID, db, PeerManagerOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[types.NodeID]float64{a.NodeID: -51}, peerManager.Reputations())
+}
+
+func TestPeerManager_Reputation_Ban(t *testing.T) {
+	selfID := types.NodeID(strings.Repeat("f", 40))
+	a := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+
+	peerManager, err := NewPeerManager(selfID, dbm.NewMemDB(), PeerManagerOptions{
+		ReputationBanThreshold: -60,
+		ReputationBanDuration:  time.Hour,
+	})
+	require.NoError(t, err)
+
+	added, err := peerManager.Add(a)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	misbehavior := PeerError{NodeID: a.NodeID, Err: errors.New("bad vote"), Misbehavior: MisbehaviorInvalidVote}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, peerManager.Errored(a.NodeID, misbehavior))
+	}
+	bans, err := peerManager.Bans()
+	require.NoError(t, err)
+	require.Empty(t, bans)
+
+	// Crossing the threshold bans the peer temporarily.
+	require.NoError(t, peerManager.Errored(a.NodeID, misbehavior))
+	bans, err = peerManager.Bans()
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	require.Equal(t, a.NodeID, bans[0].NodeID)
+	require.WithinDuration(t, time.Now().Add(time.Hour), bans[0].Until, time.Minute)
+	require.Contains(t, bans[0].Reason, "invalid vote")
+	require.Error(t, peerManager.Accepted(a.NodeID))
+}
+
+func TestPeerManagerOptions_Reputation(t *testing.T) {
+	selfID := types.NodeID(strings.Repeat("f", 40))
+
+	for _, options := range []PeerManagerOptions{
+		{ReputationHalfLife: -time.Second},
+		{ReputationBanThreshold: 1},
+		{ReputationBanThreshold: -1},
+	} {
+		_, err := NewPeerManager(selfID, dbm.NewMemDB(), options)
+		require.Error(t, err)
+	}
+}
@@ -27,6 +27,12 @@ type RouterOptions struct {
 	// 0 means no timeout.
 	ResolveTimeout time.Duration
 
+	// ResolveViaProxy passes hostnames in NodeAddress URLs to the transports
+	// unresolved, for the proxy they dial through to resolve, rather than
+	// looking them up locally. This keeps the node's DNS lookups from
+	// revealing which peers it dials.
+	ResolveViaProxy bool
+
 	// DialTimeout is the timeout for dialing a peer. 0 means no timeout.
 	DialTimeout time.Duration
 
@@ -72,6 +78,23 @@ type RouterOptions struct {
 	// are used to dial peers. This defaults to the value of
 	// runtime.NumCPU.
 	NumConcurrentDials func() int
+
+	// ChannelSendRates limits the rate at which messages are sent to
+	// each peer on the given channels, in bytes/second. Messages over
+	// the limit are held back without delaying other channels, and
+	// dropped if the channel's send queue capacity is exceeded.
+	ChannelSendRates map[ChannelID]int64
+
+	// ChannelRecvRates limits the rate at which messages are accepted
+	// from each peer on the given channels, in bytes/second. Messages
+	// over the limit are dropped, so this should only be used for
+	// channels that tolerate lost messages, such as the mempool.
+	ChannelRecvRates map[ChannelID]int64
+
+	// Capture records the messages sent and received by the router, for
+	// offline inspection and replay. The router starts and stops it. nil
+	// disables capturing.
+	Capture *Capture
 }
 
 const (
@@ -102,6 +125,17 @@ func (o *RouterOptions) Validate() error {
 		o.MaxIncomingConnectionAttempts = 100
 	}
 
+	for chID, rate := range o.ChannelSendRates {
+		if rate < 0 {
+			return fmt.Errorf("send rate for channel %#x can't be negative", chID)
+		}
+	}
+	for chID, rate := range o.ChannelRecvRates {
+		if rate < 0 {
+			return fmt.Errorf("receive rate for channel %#x can't be negative", chID)
+		}
+	}
+
 	return nil
 }
 
@@ -163,6 +197,8 @@ type Router struct {
 	peerQueues map[types.NodeID]queue // outbound messages per peer for all channels
 	// the channels that the peer queue has open
 	peerChannels map[types.NodeID]channelIDs
+	peerTraffic  map[types.NodeID]*peerTraffic
+	peerInfos    map[types.NodeID]types.NodeInfo // NodeInfo of connected peers
 	queueFactory func(int) queue
 
 	// FIXME: We don't strictly need to use a mutex for this if we seal the
@@ -171,6 +207,7 @@ type Router struct {
 	channelMtx      sync.RWMutex
 	channelQueues   map[ChannelID]queue // inbound messages from all peers to a single channel
 	channelMessages map[ChannelID]proto.Message
+	channelCapacity map[ChannelID]int // send queue capacity, see sendQueueCapacity
 }
 
 // NewRouter creates a new Router. The given Transports must already be
@@ -209,8 +246,11 @@ func NewRouter(
 		options:            options,
 		channelQueues:      map[ChannelID]queue{},
 		channelMessages:    map[ChannelID]proto.Message{},
+		channelCapacity:    map[ChannelID]int{},
 		peerQueues:         map[types.NodeID]queue{},
 		peerChannels:       make(map[types.NodeID]channelIDs),
+		peerTraffic:        map[types.NodeID]*peerTraffic{},
+		peerInfos:          map[types.NodeID]types.NodeInfo{},
 	}
 
 	router.BaseService = service.NewBaseService(logger, "router", router)
@@ -284,6 +324,10 @@ func (r *Router) OpenChannel(ctx context.Context, chDesc *ChannelDescriptor) (*C
 
 	r.channelQueues[id] = queue
 	r.channelMessages[id] = messageType
+	r.channelCapacity[id] = queueBufferDefault
+	if chDesc.SendQueueCapacity > 0 {
+		r.channelCapacity[id] = chDesc.SendQueueCapacity
+	}
 
 	// add the channel to the nodeInfo if it's not already there.
 	r.nodeInfo.AddChannel(uint16(chDesc.ID))
@@ -297,6 +341,7 @@ func (r *Router) OpenChannel(ctx context.Context, chDesc *ChannelDescriptor) (*C
 			r.channelMtx.Lock()
 			delete(r.channelQueues, id)
 			delete(r.channelMessages, id)
+			delete(r.channelCapacity, id)
 			r.channelMtx.Unlock()
 			queue.close()
 		}()
@@ -407,9 +452,12 @@ func (r *Router) routeChannel(
 				return
 			}
 
-			r.logger.Error("peer error, evicting", "peer", peerError.NodeID, "err", peerError.Err)
+			r.logger.Error("peer error, evicting", "peer", peerError.NodeID, "err", peerError.Err,
+				"misbehavior", peerError.Misbehavior)
 
-			r.peerManager.Errored(peerError.NodeID, peerError.Err)
+			if err := r.peerManager.Errored(peerError.NodeID, peerError); err != nil {
+				r.logger.Error("failed to report peer error", "peer", peerError.NodeID, "err", err)
+			}
 		case <-ctx.Done():
 			return
 		}
@@ -425,6 +473,11 @@ func (r *Router) numConccurentDials() int {
 }
 
 func (r *Router) filterPeersIP(ctx context.Context, ip net.IP, port uint16) error {
+	if r.peerManager != nil {
+		if err := r.peerManager.checkBanned("", ip); err != nil {
+			return err
+		}
+	}
 	if r.options.FilterPeerByIP == nil {
 		return nil
 	}
@@ -433,6 +486,11 @@ func (r *Router) filterPeersIP(ctx context.Context, ip net.IP, port uint16) erro
 }
 
 func (r *Router) filterPeersID(ctx context.Context, id types.NodeID) error {
+	if r.peerManager != nil {
+		if err := r.peerManager.checkBanned(id, nil); err != nil {
+			return err
+		}
+	}
 	if r.options.FilterPeerByID == nil {
 		return nil
 	}
@@ -544,8 +602,9 @@ func (r *Router) openConnection(ctx context.Context, conn Connection) {
 			"op", "incoming/accepted", "peer", peerInfo.NodeID, "err", err)
 		return
 	}
+	r.peerManager.setPeerIP(peerInfo.NodeID, incomingIP)
 
-	r.routePeer(ctx, peerInfo.NodeID, conn, toChannelIDs(peerInfo.Channels))
+	r.routePeer(ctx, peerInfo, conn)
 }
 
 // dialPeers maintains outbound connections to peers by dialing them.
@@ -639,9 +698,10 @@ func (r *Router) connectPeer(ctx context.Context, address NodeAddress) {
 		conn.Close()
 		return
 	}
+	r.peerManager.setPeerIP(address.NodeID, conn.RemoteEndpoint().IP)
 
 	// routePeer (also) calls connection close
-	go r.routePeer(ctx, address.NodeID, conn, toChannelIDs(peerInfo.Channels))
+	go r.routePeer(ctx, peerInfo, conn)
 }
 
 func (r *Router) getOrMakeQueue(peerID types.NodeID, channels channelIDs) queue {
@@ -667,8 +727,16 @@ func (r *Router) dialPeer(ctx context.Context, address NodeAddress) (Connection,
 		defer cancel()
 	}
 
-	r.logger.Debug("resolving peer address", "peer", address)
-	endpoints, err := address.Resolve(resolveCtx)
+	var (
+		endpoints []Endpoint
+		err       error
+	)
+	if r.options.ResolveViaProxy && address.Hostname != "" && net.ParseIP(address.Hostname) == nil {
+		endpoints = []Endpoint{address.hostnameEndpoint()}
+	} else {
+		r.logger.Debug("resolving peer address", "peer", address)
+		endpoints, err = address.Resolve(resolveCtx)
+	}
 	switch {
 	case err != nil:
 		return nil, fmt.Errorf("failed to resolve address %q: %w", address, err)
@@ -682,6 +750,10 @@ func (r *Router) dialPeer(ctx context.Context, address NodeAddress) (Connection,
 			r.logger.Error("no transport found for protocol", "endpoint", endpoint)
 			continue
 		}
+		if err := r.peerManager.checkBanned("", endpoint.IP); err != nil {
+			r.logger.Debug("skipping banned endpoint", "peer", address.NodeID, "endpoint", endpoint)
+			continue
+		}
 
 		dialCtx := ctx
 		if r.options.DialTimeout > 0 {
@@ -756,15 +828,25 @@ func (r *Router) runWithPeerMutex(fn func() error) error {
 // routePeer routes inbound and outbound messages between a peer and the reactor
 // channels. It will close the given connection and send queue when done, or if
 // they are closed elsewhere it will cause this method to shut down and return.
-func (r *Router) routePeer(ctx context.Context, peerID types.NodeID, conn Connection, channels channelIDs) {
+func (r *Router) routePeer(ctx context.Context, peerInfo types.NodeInfo, conn Connection) {
+	peerID := peerInfo.NodeID
 	r.metrics.Peers.Add(1)
-	r.peerManager.Ready(ctx, peerID)
 
-	sendQueue := r.getOrMakeQueue(peerID, channels)
+	// The peer's info must be available before subscribers learn that it is
+	// up, e.g. for the PEX crawler to record it.
+	sendQueue := r.getOrMakeQueue(peerID, toChannelIDs(peerInfo.Channels))
+	traffic := newPeerTraffic(r.options.ChannelSendRates, r.options.ChannelRecvRates)
+	r.peerMtx.Lock()
+	r.peerTraffic[peerID] = traffic
+	r.peerInfos[peerID] = peerInfo
+	r.peerMtx.Unlock()
+	r.peerManager.Ready(ctx, peerID)
 	defer func() {
 		r.peerMtx.Lock()
 		delete(r.peerQueues, peerID)
 		delete(r.peerChannels, peerID)
+		delete(r.peerTraffic, peerID)
+		delete(r.peerInfos, peerID)
 		r.peerMtx.Unlock()
 
 		sendQueue.close()
@@ -779,14 +861,14 @@ func (r *Router) routePeer(ctx context.Context, peerID types.NodeID, conn Connec
 
 	go func() {
 		select {
-		case errCh <- r.receivePeer(ctx, peerID, conn):
+		case errCh <- r.receivePeer(ctx, peerID, conn, traffic):
 		case <-ctx.Done():
 		}
 	}()
 
 	go func() {
 		select {
-		case errCh <- r.sendPeer(ctx, peerID, conn, sendQueue):
+		case errCh <- r.sendPeer(ctx, peerID, conn, sendQueue, traffic):
 		case <-ctx.Done():
 		}
 	}()
@@ -825,12 +907,21 @@ func (r *Router) routePeer(ctx context.Context, peerID types.NodeID, conn Connec
 
 // receivePeer receives inbound messages from a peer, deserializes them and
 // passes them on to the appropriate channel.
-func (r *Router) receivePeer(ctx context.Context, peerID types.NodeID, conn Connection) error {
+func (r *Router) receivePeer(ctx context.Context, peerID types.NodeID, conn Connection, traffic *peerTraffic) error {
 	for {
 		chID, bz, err := conn.ReceiveMessage(ctx)
 		if err != nil {
 			return err
 		}
+		if r.options.Capture != nil {
+			r.options.Capture.record(CaptureInbound, peerID, chID, bz)
+		}
+
+		if !traffic.received(chID, len(bz)) {
+			r.metrics.RouterRateLimitDroppedMsgs.With("ch_id", fmt.Sprint(chID), "direction", "recv").Add(1)
+			r.logger.Debug("receive rate exceeded, dropping message", "peer", peerID, "channel", chID)
+			continue
+		}
 
 		r.channelMtx.RLock()
 		queue, ok := r.channelQueues[chID]
@@ -864,6 +955,9 @@ func (r *Router) receivePeer(ctx context.Context, peerID types.NodeID, conn Conn
 				"chID", fmt.Sprint(chID),
 				"peer_id", string(peerID),
 				"message_type", r.metrics.ValueToMetricLabel(msg)).Add(float64(proto.Size(msg)))
+			r.metrics.PeerReceiveMessagesTotal.With(
+				"chID", fmt.Sprint(chID),
+				"peer_id", string(peerID)).Add(1)
 			r.metrics.RouterChannelQueueSend.Observe(time.Since(start).Seconds())
 			r.logger.Debug("received message", "peer", peerID, "message", msg)
 
@@ -876,8 +970,21 @@ func (r *Router) receivePeer(ctx context.Context, peerID types.NodeID, conn Conn
 	}
 }
 
-// sendPeer sends queued messages to a peer.
-func (r *Router) sendPeer(ctx context.Context, peerID types.NodeID, conn Connection, peerQueue queue) error {
+// sendPeer sends queued messages to a peer. Messages on channels that exceed
+// their send rate are held back in a per-channel backlog until the rate
+// allows, so that they don't delay messages on other channels.
+func (r *Router) sendPeer(
+	ctx context.Context,
+	peerID types.NodeID,
+	conn Connection,
+	peerQueue queue,
+	traffic *peerTraffic,
+) error {
+	backlog := map[ChannelID][]Envelope{}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
 	for {
 		start := time.Now().UTC()
 
@@ -889,17 +996,25 @@ func (r *Router) sendPeer(ctx context.Context, peerID types.NodeID, conn Connect
 				continue
 			}
 
-			bz, err := proto.Marshal(envelope.Message)
-			if err != nil {
-				r.logger.Error("failed to marshal message", "peer", peerID, "err", err)
+			chID := envelope.channelID
+			switch {
+			case len(backlog[chID]) == 0 && traffic.sendDelay(chID) == 0:
+				if err := r.sendMessage(ctx, peerID, conn, traffic, envelope); err != nil {
+					return err
+				}
 				continue
-			}
 
-			if err = conn.SendMessage(ctx, envelope.channelID, bz); err != nil {
-				return err
+			case len(backlog[chID]) >= r.sendQueueCapacity(chID):
+				traffic.drop(chID)
+				r.metrics.RouterRateLimitDroppedMsgs.With("ch_id", fmt.Sprint(chID), "direction", "send").Add(1)
+				r.logger.Debug("send rate exceeded, dropping message", "peer", peerID, "channel", chID)
+				continue
+
+			default:
+				backlog[chID] = append(backlog[chID], envelope)
 			}
 
-			r.logger.Debug("sent message", "peer", envelope.To, "message", envelope.Message)
+		case <-timer.C:
 
 		case <-peerQueue.closed():
 			return nil
@@ -907,7 +1022,107 @@ func (r *Router) sendPeer(ctx context.Context, peerID types.NodeID, conn Connect
 		case <-ctx.Done():
 			return nil
 		}
+
+		// Send what the rate limits allow from the backlog, and wake up
+		// again when the next held back message can be sent.
+		var wait time.Duration
+		for chID, envelopes := range backlog {
+			for len(envelopes) > 0 {
+				if delay := traffic.sendDelay(chID); delay > 0 {
+					if wait == 0 || delay < wait {
+						wait = delay
+					}
+					break
+				}
+				if err := r.sendMessage(ctx, peerID, conn, traffic, envelopes[0]); err != nil {
+					return err
+				}
+				envelopes = envelopes[1:]
+			}
+			if len(envelopes) == 0 {
+				delete(backlog, chID)
+			} else {
+				backlog[chID] = envelopes
+			}
+		}
+		if wait > 0 {
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(wait)
+		}
+	}
+}
+
+// sendMessage marshals a message and sends it to a peer, recording its
+// traffic.
+func (r *Router) sendMessage(
+	ctx context.Context,
+	peerID types.NodeID,
+	conn Connection,
+	traffic *peerTraffic,
+	envelope Envelope,
+) error {
+	bz, err := proto.Marshal(envelope.Message)
+	if err != nil {
+		r.logger.Error("failed to marshal message", "peer", peerID, "err", err)
+		return nil
+	}
+
+	if err = conn.SendMessage(ctx, envelope.channelID, bz); err != nil {
+		return err
+	}
+	if r.options.Capture != nil {
+		r.options.Capture.record(CaptureOutbound, peerID, envelope.channelID, bz)
+	}
+
+	traffic.sent(envelope.channelID, len(bz))
+	r.metrics.PeerSendBytesTotal.With(
+		"chID", fmt.Sprint(envelope.channelID),
+		"peer_id", string(peerID),
+		"message_type", r.metrics.ValueToMetricLabel(envelope.Message)).Add(float64(len(bz)))
+	r.metrics.PeerSendMessagesTotal.With(
+		"chID", fmt.Sprint(envelope.channelID),
+		"peer_id", string(peerID)).Add(1)
+	r.logger.Debug("sent message", "peer", envelope.To, "message", envelope.Message)
+	return nil
+}
+
+// sendQueueCapacity returns the send queue capacity of a channel, which
+// bounds the number of messages held back by its send rate limit.
+func (r *Router) sendQueueCapacity(chID ChannelID) int {
+	r.channelMtx.RLock()
+	defer r.channelMtx.RUnlock()
+
+	if capacity, ok := r.channelCapacity[chID]; ok {
+		return capacity
 	}
+	return queueBufferDefault
+}
+
+// PeerTraffic returns the traffic exchanged with a connected peer on each
+// channel, or nil if the peer is not connected.
+func (r *Router) PeerTraffic(peerID types.NodeID) []ChannelTraffic {
+	r.peerMtx.RLock()
+	traffic, ok := r.peerTraffic[peerID]
+	r.peerMtx.RUnlock()
+
+	if !ok {
+		return nil
+	}
+	return traffic.traffic()
+}
+
+// PeerNodeInfo returns the NodeInfo a connected peer sent in its handshake,
+// or false if the peer is not connected.
+func (r *Router) PeerNodeInfo(peerID types.NodeID) (types.NodeInfo, bool) {
+	r.peerMtx.RLock()
+	defer r.peerMtx.RUnlock()
+	peerInfo, ok := r.peerInfos[peerID]
+	return peerInfo, ok
 }
 
 // evictPeers evicts connected peers as requested by the peer manager.
@@ -946,8 +1161,19 @@ func (r *Router) NodeInfo() types.NodeInfo {
 
 // OnStart implements service.Service.
 func (r *Router) OnStart(ctx context.Context) error {
+	if r.options.Capture != nil {
+		if err := r.options.Capture.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start capture: %w", err)
+		}
+	}
+
 	for _, transport := range r.transports {
 		for _, endpoint := range r.endpoints {
+			// With several transports, each listens on the endpoints of
+			// the protocols it supports.
+			if len(r.transports) > 1 && !supportsProtocol(transport, endpoint.Protocol) {
+				continue
+			}
 			if err := transport.Listen(endpoint); err != nil {
 				return err
 			}
@@ -972,6 +1198,16 @@ func (r *Router) OnStart(ctx context.Context) error {
 	return nil
 }
 
+// supportsProtocol returns true if the transport supports the protocol.
+func supportsProtocol(transport Transport, protocol Protocol) bool {
+	for _, p := range transport.Protocols() {
+		if p == protocol {
+			return true
+		}
+	}
+	return false
+}
+
 // OnStop implements service.Service.
 //
 // All channels must be closed by OpenChannel() callers before stopping the
@@ -1005,6 +1241,12 @@ func (r *Router) OnStop() {
 		q.close()
 		<-q.closed()
 	}
+
+	if r.options.Capture != nil {
+		if err := r.options.Capture.Stop(); err != nil {
+			r.logger.Error("failed to stop capture", "err", err)
+		}
+	}
 }
 
 type channelIDs map[ChannelID]struct{}
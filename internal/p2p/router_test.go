@@ -228,6 +228,57 @@ func TestRouter_Channel_SendReceive(t *testing.T) {
 	}
 }
 
+func TestRouter_Channel_SendRate(t *testing.T) {
+	t.Cleanup(leaktest.Check(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Limit channel 1 to 100 bytes/s, i.e. about 20 test messages.
+	network := p2ptest.MakeNetwork(ctx, t, p2ptest.NetworkOptions{
+		NumNodes: 2,
+		NodeOpts: p2ptest.NodeOptions{
+			ChannelSendRates: map[p2p.ChannelID]int64{chID: 100},
+		},
+	})
+
+	ids := network.NodeIDs()
+	aID, bID := ids[0], ids[1]
+	channels := network.MakeChannelsNoCleanup(ctx, t, chDesc)
+	otherChannels := network.MakeChannels(ctx, t, p2ptest.MakeChannelDesc(9))
+
+	network.Start(ctx, t)
+
+	go func() {
+		for {
+			select {
+			case <-channels[bID].In:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Flooding channel 1 must not delay channel 9.
+	for i := 0; i < 100; i++ {
+		p2ptest.RequireSend(ctx, t, channels[aID], p2p.Envelope{To: bID, Message: &p2ptest.Message{Value: "foo"}})
+	}
+	p2ptest.RequireSend(ctx, t, otherChannels[aID], p2p.Envelope{To: bID, Message: &p2ptest.Message{Value: "bar"}})
+	p2ptest.RequireReceive(t, otherChannels[bID], p2p.Envelope{From: aID, Message: &p2ptest.Message{Value: "bar"}})
+
+	// Messages over the rate are held back up to the channel's send queue
+	// capacity, and the rest are dropped.
+	require.Eventually(t, func() bool {
+		traffic := network.Nodes[aID].Router.PeerTraffic(bID)
+		return len(traffic) == 2 && traffic[0].ChannelID == chID && traffic[0].Dropped > 0
+	}, time.Second, 10*time.Millisecond)
+
+	traffic := network.Nodes[aID].Router.PeerTraffic(bID)
+	require.Less(t, traffic[0].SendMessages, int64(50))
+	require.EqualValues(t, 9, traffic[1].ChannelID)
+	require.EqualValues(t, 1, traffic[1].SendMessages)
+}
+
 func TestRouter_Channel_Broadcast(t *testing.T) {
 	t.Cleanup(leaktest.Check(t))
 
@@ -631,6 +682,7 @@ func TestRouter_DialPeers(t *testing.T) {
 				mockConnection.On("Close").Run(func(_ mock.Arguments) { closer.Close() }).Return(nil).Maybe()
 			}
 			if tc.ok {
+				mockConnection.On("RemoteEndpoint").Return(p2p.Endpoint{})
 				mockConnection.On("ReceiveMessage", mock.Anything).Return(chID, nil, io.EOF).Maybe()
 			}
 
@@ -696,6 +748,57 @@ func TestRouter_DialPeers(t *testing.T) {
 	}
 }
 
+func TestRouter_DialPeers_ResolveViaProxy(t *testing.T) {
+	t.Cleanup(leaktest.Check(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The .invalid hostname can't be resolved locally, so it must be passed
+	// on to the transport as is.
+	address := p2p.NodeAddress{Protocol: "mock", NodeID: peerID, Hostname: "peer.invalid", Port: 26656}
+	endpoint := p2p.Endpoint{Protocol: "mock", Hostname: "peer.invalid", Port: 26656}
+
+	closer := tmsync.NewCloser()
+	mockTransport := &mocks.Transport{}
+	mockTransport.On("String").Maybe().Return("mock")
+	mockTransport.On("Protocols").Return([]p2p.Protocol{"mock"})
+	mockTransport.On("Close").Return(nil).Maybe()
+	mockTransport.On("Accept", mock.Anything).Maybe().Return(nil, io.EOF)
+	mockTransport.On("Dial", mock.Anything, endpoint).
+		Run(func(_ mock.Arguments) { closer.Close() }).
+		Return(nil, errors.New("boom"))
+
+	peerManager, err := p2p.NewPeerManager(selfID, dbm.NewMemDB(), p2p.PeerManagerOptions{})
+	require.NoError(t, err)
+	added, err := peerManager.Add(address)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	router, err := p2p.NewRouter(
+		ctx,
+		log.TestingLogger(),
+		p2p.NopMetrics(),
+		selfInfo,
+		selfKey,
+		peerManager,
+		[]p2p.Transport{mockTransport},
+		nil,
+		p2p.RouterOptions{ResolveViaProxy: true},
+	)
+	require.NoError(t, err)
+	require.NoError(t, router.Start(ctx))
+
+	select {
+	case <-closer.Done():
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "hostname endpoint not dialed")
+	}
+
+	require.NoError(t, router.Stop())
+	mockTransport.AssertExpectations(t)
+}
+
 func TestRouter_DialPeers_Parallel(t *testing.T) {
 	t.Cleanup(leaktest.Check(t))
 
@@ -0,0 +1,119 @@
+package p2p
+
+import (
+	"github.com/tendermint/tendermint/types"
+)
+
+// SyncTarget describes the blocks that a syncing reactor needs from peers, as
+// set via PeerUpdates.SetSyncTarget. While any reactor has a sync target, the
+// peer manager prefers to dial peers that can serve it, and evicts connected
+// peers that can't when all connection slots are taken and a better peer is
+// available. Whether a peer can serve the target is based on the block range
+// it last reported via a PeerStatusHeight update. Peers that haven't reported
+// a range are assumed to be useful.
+//
+// The zero value means that the reactor is not syncing.
+type SyncTarget struct {
+	// Height is the lowest height that useful peers must have reached. Block
+	// sync needs blocks above our height, while state sync needs snapshots
+	// above the trusted height.
+	Height int64
+
+	// RequireBase requires useful peers to still have the block at Height,
+	// i.e. not to have pruned it, as needed by block sync.
+	RequireBase bool
+}
+
+// IsZero returns true if the target is the zero value, i.e. not syncing.
+func (t SyncTarget) IsZero() bool {
+	return t == SyncTarget{}
+}
+
+// useful returns whether a peer can serve the target, based on its last
+// reported block range.
+func (t SyncTarget) useful(peer *peerInfo) bool {
+	if t.Height == 0 || peer.Height == 0 {
+		return true
+	}
+	if peer.Height < t.Height {
+		return false
+	}
+	return !t.RequireBase || peer.Base <= t.Height
+}
+
+// setSyncTarget sets the sync target of a subscription, replacing any previous
+// one. The caller must hold the mutex lock.
+func (m *PeerManager) setSyncTarget(peerUpdates *PeerUpdates, target SyncTarget) {
+	if target.IsZero() {
+		delete(m.syncTargets, peerUpdates)
+	} else {
+		m.syncTargets[peerUpdates] = target
+	}
+	m.dialWaker.Wake()
+	m.evictWaker.Wake()
+}
+
+// isUseful returns whether a peer can serve all sync targets. The caller must
+// hold the mutex lock.
+func (m *PeerManager) isUseful(peer *peerInfo) bool {
+	for _, target := range m.syncTargets {
+		if !target.useful(peer) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialOrder returns the peers in the order they should be dialed: ranked by
+// score, but with peers that can't serve our sync targets last. The returned
+// list must not be mutated, like peerStore.Ranked(). The caller must hold the
+// mutex lock.
+func (m *PeerManager) dialOrder() []*peerInfo {
+	ranked := m.store.Ranked()
+	if len(m.syncTargets) == 0 {
+		return ranked
+	}
+	peers := make([]*peerInfo, 0, len(ranked))
+	useless := []*peerInfo{}
+	for _, peer := range ranked {
+		if m.isUseful(peer) {
+			peers = append(peers, peer)
+		} else {
+			useless = append(useless, peer)
+		}
+	}
+	return append(peers, useless...)
+}
+
+// findUselessPeer returns the lowest-ranked connected peer that can't serve
+// our sync targets, if all connection slots are taken and there is a useful
+// peer to dial instead. Persistent peers are never returned. The caller must
+// hold the mutex lock.
+func (m *PeerManager) findUselessPeer() types.NodeID {
+	if len(m.syncTargets) == 0 || m.options.MaxConnected == 0 ||
+		len(m.connected)-len(m.evicting) < int(m.options.MaxConnected) {
+		return ""
+	}
+
+	ranked := m.store.Ranked()
+	candidate := false
+	for _, peer := range ranked {
+		if !m.connected[peer.ID] && m.isUseful(peer) {
+			if _, ok := m.dialableAddress(peer); ok {
+				candidate = true
+				break
+			}
+		}
+	}
+	if !candidate {
+		return ""
+	}
+
+	for i := len(ranked) - 1; i >= 0; i-- {
+		peer := ranked[i]
+		if m.connected[peer.ID] && !m.evicting[peer.ID] && !peer.Persistent && !m.isUseful(peer) {
+			return peer.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package p2p
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestSyncTarget_Useful(t *testing.T) {
+	testcases := []struct {
+		target       SyncTarget
+		base, height int64
+		expect       bool
+	}{
+		{SyncTarget{}, 1, 5, true},
+		{SyncTarget{Height: 10}, 0, 0, true},
+		{SyncTarget{Height: 10}, 1, 5, false},
+		{SyncTarget{Height: 10}, 8, 10, true},
+		{SyncTarget{Height: 10}, 20, 30, true},
+		{SyncTarget{Height: 10, RequireBase: true}, 20, 30, false},
+		{SyncTarget{Height: 10, RequireBase: true}, 10, 30, true},
+	}
+	for _, tc := range testcases {
+		peer := &peerInfo{Base: tc.base, Height: tc.height}
+		require.Equal(t, tc.expect, tc.target.useful(peer), "%+v %v-%v", tc.target, tc.base, tc.height)
+	}
+}
+
+func TestPeerManager_SyncTarget(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	selfID := types.NodeID(strings.Repeat("f", 40))
+	a := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("a", 40))}
+	b := NodeAddress{Protocol: "memory", NodeID: types.NodeID(strings.Repeat("b", 40))}
+
+	peerManager, err := NewPeerManager(selfID, dbm.NewMemDB(), PeerManagerOptions{
+		MaxConnected: 1,
+	})
+	require.NoError(t, err)
+	for _, addr := range []NodeAddress{a, b} {
+		added, err := peerManager.Add(addr)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	// a is connected, and reports that it is behind us.
+	sub := peerManager.Subscribe(ctx)
+	require.NoError(t, peerManager.Accepted(a.NodeID))
+	peerManager.processPeerEvent(ctx, PeerUpdate{NodeID: a.NodeID, Status: PeerStatusHeight, Base: 1, Height: 5})
+	require.EqualValues(t, 5, peerManager.GetHeight(a.NodeID))
+
+	// a is not evicted while we're not syncing.
+	evict, err := peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Empty(t, evict)
+
+	// While block syncing above a's height, a is evicted to make room for b,
+	// which may have the blocks we need.
+	sub.SetSyncTarget(SyncTarget{Height: 10, RequireBase: true})
+	peerManager.processSyncTarget(sub)
+	evict, err = peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Equal(t, a.NodeID, evict)
+	peerManager.Disconnected(ctx, a.NodeID)
+
+	// b is dialed before a.
+	dial, err := peerManager.TryDialNext()
+	require.NoError(t, err)
+	require.Equal(t, b, dial)
+	require.NoError(t, peerManager.Dialed(b))
+
+	// If b has pruned the blocks we need, it's not evicted since there is no
+	// better peer to dial.
+	peerManager.processPeerEvent(ctx, PeerUpdate{NodeID: b.NodeID, Status: PeerStatusHeight, Base: 20, Height: 30})
+	evict, err = peerManager.TryEvictNext()
+	require.NoError(t, err)
+	require.Empty(t, evict)
+
+	// Once done syncing, heights are ignored again.
+	sub.SetSyncTarget(SyncTarget{})
+	peerManager.processSyncTarget(sub)
+	require.Empty(t, peerManager.syncTargets)
+}
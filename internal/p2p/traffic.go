@@ -0,0 +1,199 @@
+package p2p
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/internal/libs/flowrate"
+)
+
+// ChannelTraffic is the traffic exchanged with a peer on a single channel.
+// Rates are moving averages in bytes/second. Messages dropped due to the rate
+// limits are only counted in Dropped.
+type ChannelTraffic struct {
+	ChannelID    ChannelID
+	SendBytes    int64
+	SendMessages int64
+	SendRate     int64
+	RecvBytes    int64
+	RecvMessages int64
+	RecvRate     int64
+	// Dropped is the number of messages dropped due to the channel rate
+	// limits, in either direction.
+	Dropped int64
+}
+
+// channelTraffic tracks the traffic of a single channel with a peer.
+type channelTraffic struct {
+	send      *flowrate.Monitor // for rates, its byte counts lag behind
+	recv      *flowrate.Monitor
+	sendBytes int64
+	recvBytes int64
+	sendMsgs  int64
+	recvMsgs  int64
+	dropped   int64
+	sendLimit *tokenBucket // nil if unlimited
+	recvLimit *tokenBucket // nil if unlimited
+}
+
+// peerTraffic tracks the traffic with a peer per channel, and enforces the
+// per-channel rate limits given in RouterOptions.
+type peerTraffic struct {
+	mtx       sync.Mutex
+	sendRates map[ChannelID]int64
+	recvRates map[ChannelID]int64
+	channels  map[ChannelID]*channelTraffic
+}
+
+func newPeerTraffic(sendRates, recvRates map[ChannelID]int64) *peerTraffic {
+	return &peerTraffic{
+		sendRates: sendRates,
+		recvRates: recvRates,
+		channels:  map[ChannelID]*channelTraffic{},
+	}
+}
+
+// channel returns the traffic of a channel, creating it on first use. The
+// caller must hold the mutex lock.
+func (p *peerTraffic) channel(chID ChannelID) *channelTraffic {
+	if c, ok := p.channels[chID]; ok {
+		return c
+	}
+	now := time.Now()
+	c := &channelTraffic{
+		send: flowrate.New(0, 0),
+		recv: flowrate.New(0, 0),
+	}
+	if rate := p.sendRates[chID]; rate > 0 {
+		c.sendLimit = newTokenBucket(rate, now)
+	}
+	if rate := p.recvRates[chID]; rate > 0 {
+		c.recvLimit = newTokenBucket(rate, now)
+	}
+	p.channels[chID] = c
+	return c
+}
+
+// sendDelay returns how long to wait before the next message can be sent on
+// the channel without exceeding its send rate limit.
+func (p *peerTraffic) sendDelay(chID ChannelID) time.Duration {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	c := p.channel(chID)
+	if c.sendLimit == nil {
+		return 0
+	}
+	return c.sendLimit.delay(time.Now())
+}
+
+// sent records a message of n bytes sent on the channel.
+func (p *peerTraffic) sent(chID ChannelID, n int) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	c := p.channel(chID)
+	c.send.Update(n)
+	c.sendBytes += int64(n)
+	c.sendMsgs++
+	if c.sendLimit != nil {
+		c.sendLimit.take(time.Now(), n)
+	}
+}
+
+// received records a message of n bytes received on the channel. It returns
+// false if the message exceeds the channel's receive rate limit and must be
+// dropped, in which case it is recorded as dropped instead.
+func (p *peerTraffic) received(chID ChannelID, n int) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	c := p.channel(chID)
+	if c.recvLimit != nil {
+		now := time.Now()
+		if c.recvLimit.delay(now) > 0 {
+			c.dropped++
+			return false
+		}
+		c.recvLimit.take(now, n)
+	}
+	c.recv.Update(n)
+	c.recvBytes += int64(n)
+	c.recvMsgs++
+	return true
+}
+
+// drop records a message dropped on the channel.
+func (p *peerTraffic) drop(chID ChannelID) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.channel(chID).dropped++
+}
+
+// traffic returns the traffic of all channels used with the peer, ordered by
+// channel ID.
+func (p *peerTraffic) traffic() []ChannelTraffic {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	traffic := make([]ChannelTraffic, 0, len(p.channels))
+	for chID, c := range p.channels {
+		traffic = append(traffic, ChannelTraffic{
+			ChannelID:    chID,
+			SendBytes:    c.sendBytes,
+			SendMessages: c.sendMsgs,
+			SendRate:     c.send.Status().CurRate,
+			RecvBytes:    c.recvBytes,
+			RecvMessages: c.recvMsgs,
+			RecvRate:     c.recv.Status().CurRate,
+			Dropped:      c.dropped,
+		})
+	}
+	sort.Slice(traffic, func(i, j int) bool { return traffic[i].ChannelID < traffic[j].ChannelID })
+	return traffic
+}
+
+// tokenBucket limits a byte rate. It holds up to one second worth of tokens,
+// and lets a message through whenever it is not in debt, so that messages
+// larger than the bucket still pass and are paid for afterwards.
+type tokenBucket struct {
+	rate   float64 // tokens per second
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate int64, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   now,
+	}
+}
+
+// refill adds the tokens accrued since the last refill.
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+		b.last = now
+	}
+}
+
+// delay returns how long until the bucket is out of debt.
+func (b *tokenBucket) delay(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens/b.rate*float64(time.Second)) + 1
+}
+
+// take removes n tokens from the bucket.
+func (b *tokenBucket) take(now time.Time, n int) {
+	b.refill(now)
+	b.tokens -= float64(n)
+}
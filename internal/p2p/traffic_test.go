@@ -0,0 +1,47 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(100, now)
+
+	// A full bucket lets a message through even if it's larger than the
+	// bucket, and is then in debt until refilled.
+	require.Zero(t, b.delay(now))
+	b.take(now, 150)
+	require.Equal(t, 500*time.Millisecond+1, b.delay(now))
+	require.Zero(t, b.delay(now.Add(500*time.Millisecond)))
+
+	// The bucket never holds more than one second worth of tokens.
+	now = now.Add(time.Hour)
+	b.take(now, 100)
+	require.Zero(t, b.delay(now))
+	b.take(now, 1)
+	require.NotZero(t, b.delay(now))
+}
+
+func TestPeerTraffic(t *testing.T) {
+	traffic := newPeerTraffic(nil, map[ChannelID]int64{1: 10})
+
+	// Channel 1 drops messages received while over its rate, channel 2 is
+	// unlimited.
+	require.True(t, traffic.received(1, 20))
+	require.False(t, traffic.received(1, 20))
+	for i := 0; i < 10; i++ {
+		require.True(t, traffic.received(2, 20))
+	}
+	require.Zero(t, traffic.sendDelay(2))
+	traffic.sent(2, 5)
+
+	require.Equal(t, []ChannelTraffic{
+		// dropped messages are not counted as received
+		{ChannelID: 1, RecvBytes: 20, RecvMessages: 1, Dropped: 1},
+		{ChannelID: 2, RecvBytes: 200, RecvMessages: 10, SendBytes: 5, SendMessages: 1},
+	}, traffic.traffic())
+}
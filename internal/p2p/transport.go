@@ -120,6 +120,11 @@ type Endpoint struct {
 	// endpoint as a networked endpoint.
 	IP net.IP
 
+	// Hostname is a hostname to connect to instead of an IP address, for hosts
+	// that can't or shouldn't be resolved locally, such as Tor .onion
+	// addresses. Transports that dial through a proxy let the proxy resolve it.
+	Hostname string
+
 	// Port is a network port (either TCP or UDP). If 0, a default port may be
 	// used depending on the protocol.
 	Port uint16
@@ -149,9 +154,13 @@ func (e Endpoint) NodeAddress(nodeID types.NodeID) NodeAddress {
 		Protocol: e.Protocol,
 		Path:     e.Path,
 	}
-	if len(e.IP) > 0 {
+	switch {
+	case len(e.IP) > 0:
 		address.Hostname = e.IP.String()
 		address.Port = e.Port
+	case e.Hostname != "":
+		address.Hostname = e.Hostname
+		address.Port = e.Port
 	}
 	return address
 }
@@ -161,7 +170,7 @@ func (e Endpoint) String() string {
 	// If this is a non-networked endpoint with a valid node ID as a path,
 	// assume that path is a node ID (to handle opaque URLs of the form
 	// scheme:id).
-	if e.IP == nil {
+	if e.IP == nil && e.Hostname == "" {
 		if nodeID, err := types.NewNodeID(e.Path); err == nil {
 			return e.NodeAddress(nodeID).String()
 		}
@@ -178,10 +187,13 @@ func (e Endpoint) Validate() error {
 	case len(e.IP) > 0 && e.IP.To16() == nil:
 		return fmt.Errorf("invalid IP address %v", e.IP)
 
-	case e.Port > 0 && len(e.IP) == 0:
-		return fmt.Errorf("endpoint has port %v but no IP", e.Port)
+	case len(e.IP) > 0 && e.Hostname != "":
+		return errors.New("endpoint has both IP and hostname")
+
+	case e.Port > 0 && len(e.IP) == 0 && e.Hostname == "":
+		return fmt.Errorf("endpoint has port %v but no IP or hostname", e.Port)
 
-	case len(e.IP) == 0 && e.Path == "":
+	case len(e.IP) == 0 && e.Hostname == "" && e.Path == "":
 		return errors.New("endpoint has neither path nor IP")
 
 	default:
@@ -1,16 +1,19 @@
 package p2p
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"net"
+	"net/url"
 	"strconv"
 	"sync"
 
 	"golang.org/x/net/netutil"
+	"golang.org/x/net/proxy"
 
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/internal/libs/protoio"
@@ -25,6 +28,17 @@ const (
 	TCPProtocol   Protocol = "tcp"
 )
 
+// Handshakes used to secure MConnTransport connections.
+const (
+	HandshakeSTS   = "sts"   // conn.SecretConnection
+	HandshakeNoise = "noise" // conn.NoiseConnection
+)
+
+// noisePreamble is sent by dialers to select the Noise handshake. The STS
+// handshake never starts with a 0 byte, since it starts with a non-zero
+// message length.
+var noisePreamble = []byte("\x00NOISE_XX/1")
+
 // MConnTransportOptions sets options for MConnTransport.
 type MConnTransportOptions struct {
 	// MaxAcceptedConnections is the maximum number of simultaneous accepted
@@ -35,6 +49,17 @@ type MConnTransportOptions struct {
 	// Router, since it will need to do e.g. rate limiting and such as well.
 	// But it might also make sense to have per-transport limits.
 	MaxAcceptedConnections uint32
+
+	// Handshake is the handshake used for dialed connections, HandshakeSTS
+	// (the default) or HandshakeNoise. Accepted connections use whichever
+	// handshake the peer dials with.
+	Handshake string
+
+	// Proxy is the URL of a SOCKS5 proxy to dial connections through, e.g.
+	// socks5://127.0.0.1:9050 for a local Tor daemon, with optional
+	// credentials as user info. Endpoint hostnames, including .onion
+	// addresses, are resolved by the proxy. Empty dials directly.
+	Proxy string
 }
 
 // MConnTransport is a Transport implementation using the current multiplexed
@@ -150,7 +175,7 @@ func (m *MConnTransport) Accept(ctx context.Context) (Connection, error) {
 		}
 	}
 
-	return newMConnConnection(m.logger, tcpConn, m.mConnConfig, m.channelDescs), nil
+	return newMConnConnection(m.logger, tcpConn, m.mConnConfig, m.channelDescs, ""), nil
 }
 
 // Dial implements Transport.
@@ -162,9 +187,21 @@ func (m *MConnTransport) Dial(ctx context.Context, endpoint Endpoint) (Connectio
 		endpoint.Port = 26657
 	}
 
-	dialer := net.Dialer{}
-	tcpConn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(
-		endpoint.IP.String(), strconv.Itoa(int(endpoint.Port))))
+	host := endpoint.Hostname
+	if len(endpoint.IP) > 0 {
+		host = endpoint.IP.String()
+	}
+	var dialer proxy.ContextDialer = &net.Dialer{}
+	if m.options.Proxy != "" {
+		var err error
+		if dialer, err = newProxyDialer(m.options.Proxy); err != nil {
+			return nil, err
+		}
+	} else if isOnionHostname(host) {
+		return nil, fmt.Errorf("can't dial onion address %v without a proxy", endpoint)
+	}
+
+	tcpConn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(int(endpoint.Port))))
 	if err != nil {
 		select {
 		case <-ctx.Done():
@@ -174,7 +211,36 @@ func (m *MConnTransport) Dial(ctx context.Context, endpoint Endpoint) (Connectio
 		}
 	}
 
-	return newMConnConnection(m.logger, tcpConn, m.mConnConfig, m.channelDescs), nil
+	handshake := m.options.Handshake
+	if handshake == "" {
+		handshake = HandshakeSTS
+	}
+	mConnConn := newMConnConnection(m.logger, tcpConn, m.mConnConfig, m.channelDescs, handshake)
+	if m.options.Proxy != "" {
+		// The socket's remote address is the proxy's.
+		mConnConn.remoteEndpoint = &endpoint
+	}
+	return mConnConn, nil
+}
+
+// newProxyDialer returns a dialer for a SOCKS5 proxy URL.
+func newProxyDialer(proxyURL string) (proxy.ContextDialer, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", proxyURL, err)
+	}
+	if u.Scheme != "socks5" && u.Scheme != "socks5h" {
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	dialer, err := proxy.FromURL(u, proxy.Direct)
+	if err != nil {
+		return nil, err
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("proxy dialer %T does not support contexts", dialer)
+	}
+	return contextDialer, nil
 }
 
 // Close implements Transport.
@@ -208,8 +274,8 @@ func (m *MConnTransport) validateEndpoint(endpoint Endpoint) error {
 	if endpoint.Protocol != MConnProtocol && endpoint.Protocol != TCPProtocol {
 		return fmt.Errorf("unsupported protocol %q", endpoint.Protocol)
 	}
-	if len(endpoint.IP) == 0 {
-		return errors.New("endpoint has no IP address")
+	if len(endpoint.IP) == 0 && endpoint.Hostname == "" {
+		return errors.New("endpoint has no IP address or hostname")
 	}
 	if endpoint.Path != "" {
 		return fmt.Errorf("endpoints with path not supported (got %q)", endpoint.Path)
@@ -219,14 +285,16 @@ func (m *MConnTransport) validateEndpoint(endpoint Endpoint) error {
 
 // mConnConnection implements Connection for MConnTransport.
 type mConnConnection struct {
-	logger       log.Logger
-	conn         net.Conn
-	mConnConfig  conn.MConnConfig
-	channelDescs []*ChannelDescriptor
-	receiveCh    chan mConnMessage
-	errorCh      chan error
-	doneCh       chan struct{}
-	closeOnce    sync.Once
+	logger         log.Logger
+	conn           net.Conn
+	mConnConfig    conn.MConnConfig
+	channelDescs   []*ChannelDescriptor
+	dialShake      string    // handshake to initiate, empty for accepted connections
+	remoteEndpoint *Endpoint // dialed endpoint, for connections via a proxy
+	receiveCh      chan mConnMessage
+	errorCh        chan error
+	doneCh         chan struct{}
+	closeOnce      sync.Once
 
 	mconn *conn.MConnection // set during Handshake()
 }
@@ -243,12 +311,14 @@ func newMConnConnection(
 	conn net.Conn,
 	mConnConfig conn.MConnConfig,
 	channelDescs []*ChannelDescriptor,
+	dialShake string,
 ) *mConnConnection {
 	return &mConnConnection{
 		logger:       logger,
 		conn:         conn,
 		mConnConfig:  mConnConfig,
 		channelDescs: channelDescs,
+		dialShake:    dialShake,
 		receiveCh:    make(chan mConnMessage),
 		errorCh:      make(chan error, 1), // buffered to avoid onError leak
 		doneCh:       make(chan struct{}),
@@ -317,7 +387,7 @@ func (c *mConnConnection) handshake(
 		return nil, types.NodeInfo{}, nil, errors.New("connection is already handshaked")
 	}
 
-	secretConn, err := conn.MakeSecretConnection(c.conn, privKey)
+	secretConn, err := c.secure(privKey)
 	if err != nil {
 		return nil, types.NodeInfo{}, nil, err
 	}
@@ -372,6 +442,76 @@ func (c *mConnConnection) handshake(
 	return mconn, peerInfo, secretConn.RemotePubKey(), nil
 }
 
+// secureConn is a connection secured by a handshake.
+type secureConn interface {
+	net.Conn
+	RemotePubKey() crypto.PubKey
+}
+
+// secure performs the handshake that secures the connection. Dialers
+// initiate the configured handshake, while accepted connections use the
+// handshake selected by the dialer's first bytes (see noisePreamble).
+func (c *mConnConnection) secure(privKey crypto.PrivKey) (secureConn, error) {
+	switch c.dialShake {
+	case HandshakeSTS:
+		return c.secureSTS(c.conn, privKey)
+	case HandshakeNoise:
+		if _, err := c.conn.Write(noisePreamble); err != nil {
+			return nil, err
+		}
+		return c.secureNoise(privKey, true)
+	case "":
+	default:
+		return nil, fmt.Errorf("unknown handshake %q", c.dialShake)
+	}
+
+	first := make([]byte, 1)
+	if _, err := io.ReadFull(c.conn, first); err != nil {
+		return nil, err
+	}
+	if first[0] != noisePreamble[0] {
+		return c.secureSTS(&prefixedConn{
+			Conn:   c.conn,
+			reader: io.MultiReader(bytes.NewReader(first), c.conn),
+		}, privKey)
+	}
+	rest := make([]byte, len(noisePreamble)-1)
+	if _, err := io.ReadFull(c.conn, rest); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(rest, noisePreamble[1:]) {
+		return nil, fmt.Errorf("unsupported handshake %q", rest)
+	}
+	return c.secureNoise(privKey, false)
+}
+
+func (c *mConnConnection) secureSTS(netConn net.Conn, privKey crypto.PrivKey) (secureConn, error) {
+	secretConn, err := conn.MakeSecretConnection(netConn, privKey)
+	if err != nil {
+		return nil, err
+	}
+	return secretConn, nil
+}
+
+func (c *mConnConnection) secureNoise(privKey crypto.PrivKey, initiator bool) (secureConn, error) {
+	noiseConn, err := conn.MakeNoiseConnection(c.conn, privKey, initiator)
+	if err != nil {
+		return nil, err
+	}
+	return noiseConn, nil
+}
+
+// prefixedConn is a net.Conn whose reads are served from reader, used to put
+// back bytes read while negotiating the handshake.
+type prefixedConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c *prefixedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
 // onReceive is a callback for MConnection received messages.
 func (c *mConnConnection) onReceive(ctx context.Context, chID ChannelID, payload []byte) {
 	select {
@@ -448,6 +588,9 @@ func (c *mConnConnection) LocalEndpoint() Endpoint {
 
 // RemoteEndpoint implements Connection.
 func (c *mConnConnection) RemoteEndpoint() Endpoint {
+	if c.remoteEndpoint != nil {
+		return *c.remoteEndpoint
+	}
 	endpoint := Endpoint{
 		Protocol: MConnProtocol,
 	}
@@ -2,8 +2,12 @@ package p2p_test
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
 	"io"
 	"net"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,25 +23,202 @@ import (
 // register a transport factory here to get included in those tests.
 func init() {
 	testTransports["mconn"] = func(t *testing.T) p2p.Transport {
-		transport := p2p.NewMConnTransport(
-			log.TestingLogger(),
-			conn.DefaultMConnConfig(),
-			[]*p2p.ChannelDescriptor{{ID: chID, Priority: 1}},
-			p2p.MConnTransportOptions{},
-		)
-		err := transport.Listen(p2p.Endpoint{
-			Protocol: p2p.MConnProtocol,
-			IP:       net.IPv4(127, 0, 0, 1),
-			Port:     0, // assign a random port
-		})
+		return makeMConnTransport(t, p2p.MConnTransportOptions{})
+	}
+	testTransports["mconn-noise"] = func(t *testing.T) p2p.Transport {
+		return makeMConnTransport(t, p2p.MConnTransportOptions{Handshake: p2p.HandshakeNoise})
+	}
+}
+
+func makeMConnTransport(t *testing.T, options p2p.MConnTransportOptions) *p2p.MConnTransport {
+	transport := p2p.NewMConnTransport(
+		log.TestingLogger(),
+		conn.DefaultMConnConfig(),
+		[]*p2p.ChannelDescriptor{{ID: chID, Priority: 1}},
+		options,
+	)
+	err := transport.Listen(p2p.Endpoint{
+		Protocol: p2p.MConnProtocol,
+		IP:       net.IPv4(127, 0, 0, 1),
+		Port:     0, // assign a random port
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, transport.Close())
+	})
+
+	return transport
+}
+
+func TestMConnTransport_Handshakes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Accepted connections use the handshake chosen by the dialer, so
+	// transports dialing with different handshakes can connect to each other.
+	sts := makeMConnTransport(t, p2p.MConnTransportOptions{Handshake: p2p.HandshakeSTS})
+	noise := makeMConnTransport(t, p2p.MConnTransportOptions{Handshake: p2p.HandshakeNoise})
+
+	for _, pair := range [][2]p2p.Transport{{sts, noise}, {noise, sts}} {
+		ab, ba := dialAcceptHandshake(ctx, t, pair[0], pair[1])
+
+		require.NoError(t, ab.SendMessage(ctx, chID, []byte("foo")))
+		ch, msg, err := ba.ReceiveMessage(ctx)
 		require.NoError(t, err)
+		require.Equal(t, chID, ch)
+		require.Equal(t, []byte("foo"), msg)
 
-		t.Cleanup(func() {
-			require.NoError(t, transport.Close())
-		})
+		require.NoError(t, ab.Close())
+		require.NoError(t, ba.Close())
+	}
+}
+
+func TestMConnTransport_DialProxy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The proxy resolves the onion address to the listening transport.
+	b := makeMConnTransport(t, p2p.MConnTransportOptions{})
+	bEndpoint := b.Endpoints()[0]
+	onion := p2p.Endpoint{
+		Protocol: p2p.MConnProtocol,
+		Hostname: strings.Repeat("a", 56) + ".onion",
+		Port:     bEndpoint.Port,
+	}
+	proxyAddr, requests := startSOCKS5Proxy(t, map[string]string{
+		onion.Hostname: bEndpoint.IP.String(),
+	})
+
+	// Onion addresses can't be dialed without a proxy.
+	direct := makeMConnTransport(t, p2p.MConnTransportOptions{})
+	_, err := direct.Dial(ctx, onion)
+	require.Error(t, err)
 
-		return transport
+	a := makeMConnTransport(t, p2p.MConnTransportOptions{Proxy: "socks5://" + proxyAddr})
+	ab, ba := dialAcceptHandshake(ctx, t, a, &endpointTransport{Transport: b, endpoint: onion})
+	require.Equal(t, net.JoinHostPort(onion.Hostname, strconv.Itoa(int(onion.Port))), <-requests)
+	require.Equal(t, onion, ab.RemoteEndpoint())
+
+	require.NoError(t, ab.SendMessage(ctx, chID, []byte("foo")))
+	ch, msg, err := ba.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, chID, ch)
+	require.Equal(t, []byte("foo"), msg)
+}
+
+// endpointTransport overrides the endpoint of a transport, for dialAccept.
+type endpointTransport struct {
+	p2p.Transport
+	endpoint p2p.Endpoint
+}
+
+func (t *endpointTransport) Endpoints() []p2p.Endpoint {
+	return []p2p.Endpoint{t.endpoint}
+}
+
+// startSOCKS5Proxy starts a minimal SOCKS5 proxy supporting unauthenticated
+// CONNECT requests, which resolves hostnames using hosts. It returns the
+// proxy's address and a channel of the requested addresses.
+func startSOCKS5Proxy(t *testing.T, hosts map[string]string) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	requests := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				target, err := socks5Handshake(conn, hosts)
+				if err != nil {
+					return
+				}
+				defer target.Close()
+				requests <- target.requested
+				go func() { _, _ = io.Copy(target, conn) }()
+				_, _ = io.Copy(conn, target)
+			}()
+		}
+	}()
+	return listener.Addr().String(), requests
+}
+
+// socks5Target is a connection to a SOCKS5 CONNECT target.
+type socks5Target struct {
+	net.Conn
+	requested string
+}
+
+func socks5Handshake(conn net.Conn, hosts map[string]string) (*socks5Target, error) {
+	// Version and authentication methods, to which we pick "no authentication".
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return nil, err
+	}
+
+	// Request: version, command, reserved, address type and address.
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return nil, err
+	}
+	if request[1] != 1 {
+		return nil, fmt.Errorf("unsupported command %v", request[1])
+	}
+	var host string
+	switch request[3] {
+	case 1, 4:
+		ip := make(net.IP, 4)
+		if request[3] == 4 {
+			ip = make(net.IP, 16)
+		}
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return nil, err
+		}
+		host = ip.String()
+	case 3:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return nil, err
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return nil, err
+		}
+		host = string(name)
+	default:
+		return nil, fmt.Errorf("unsupported address type %v", request[3])
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return nil, err
+	}
+	portString := strconv.Itoa(int(binary.BigEndian.Uint16(port)))
+
+	dialHost := host
+	if resolved, ok := hosts[host]; ok {
+		dialHost = resolved
+	}
+	target, err := net.Dial("tcp", net.JoinHostPort(dialHost, portString))
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 4, 0, 1, 0, 0, 0, 0, 0, 0})
+		return nil, err
+	}
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		_ = target.Close()
+		return nil, err
 	}
+	return &socks5Target{Conn: target, requested: net.JoinHostPort(host, portString)}, nil
 }
 
 func TestMConnTransport_AcceptBeforeListen(t *testing.T) {
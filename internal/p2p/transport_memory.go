@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/tendermint/tendermint/crypto"
 	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
@@ -23,12 +26,22 @@ const (
 //
 // Network endpoints are allocated via CreateTransport(), which takes a node ID,
 // and the endpoint is then immediately accessible via the URL "memory:<nodeID>".
+//
+// Messages are delivered instantly and losslessly by default. Real-world
+// network conditions can be emulated with SetLinkConditions() and
+// Partition().
 type MemoryNetwork struct {
 	logger log.Logger
 
-	mtx        sync.RWMutex
-	transports map[types.NodeID]*MemoryTransport
-	bufferSize int
+	mtx         sync.RWMutex
+	transports  map[types.NodeID]*MemoryTransport
+	bufferSize  int
+	defaultLink LinkConditions
+	links       map[memoryLinkID]LinkConditions
+	partitions  map[types.NodeID]int // partition group by node, 0 if unassigned
+
+	randMtx sync.Mutex
+	rand    *rand.Rand
 }
 
 // NewMemoryNetwork creates a new in-memory network.
@@ -37,6 +50,9 @@ func NewMemoryNetwork(logger log.Logger, bufferSize int) *MemoryNetwork {
 		bufferSize: bufferSize,
 		logger:     logger,
 		transports: map[types.NodeID]*MemoryTransport{},
+		links:      map[memoryLinkID]LinkConditions{},
+		partitions: map[types.NodeID]int{},
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())), // nolint:gosec
 	}
 }
 
@@ -162,7 +178,9 @@ func (t *MemoryTransport) Dial(ctx context.Context, endpoint Endpoint) (Connecti
 		return nil, err
 	}
 
-	nodeID, err := types.NewNodeID(endpoint.Path)
+	// Addresses exchanged via PEX have the node ID as a URL path, e.g.
+	// memory://<id>@0.0.0.0/<id>, so the path may have a leading slash.
+	nodeID, err := types.NewNodeID(strings.TrimPrefix(endpoint.Path, "/"))
 	if err != nil {
 		return nil, err
 	}
@@ -172,13 +190,18 @@ func (t *MemoryTransport) Dial(ctx context.Context, endpoint Endpoint) (Connecti
 	if peer == nil {
 		return nil, fmt.Errorf("unknown peer %q", nodeID)
 	}
+	if _, up := t.network.linkConditions(t.nodeID, peer.nodeID); !up {
+		return nil, fmt.Errorf("peer %q is unreachable", nodeID)
+	}
 
 	inCh := make(chan memoryMessage, t.bufferSize)
 	outCh := make(chan memoryMessage, t.bufferSize)
 	closer := tmsync.NewCloser()
 
-	outConn := newMemoryConnection(t.logger, t.nodeID, peer.nodeID, inCh, outCh, closer)
-	inConn := newMemoryConnection(peer.logger, peer.nodeID, t.nodeID, outCh, inCh, closer)
+	outConn := newMemoryConnection(t.logger, t.nodeID, peer.nodeID, inCh,
+		newMemoryLink(t.network, t.nodeID, peer.nodeID, outCh, closer), closer)
+	inConn := newMemoryConnection(peer.logger, peer.nodeID, t.nodeID, outCh,
+		newMemoryLink(t.network, peer.nodeID, t.nodeID, inCh, closer), closer)
 
 	select {
 	case peer.acceptCh <- inConn:
@@ -201,7 +224,7 @@ type MemoryConnection struct {
 	remoteID types.NodeID
 
 	receiveCh <-chan memoryMessage
-	sendCh    chan<- memoryMessage
+	link      *memoryLink
 	closer    *tmsync.Closer
 }
 
@@ -221,7 +244,7 @@ func newMemoryConnection(
 	localID types.NodeID,
 	remoteID types.NodeID,
 	receiveCh <-chan memoryMessage,
-	sendCh chan<- memoryMessage,
+	link *memoryLink,
 	closer *tmsync.Closer,
 ) *MemoryConnection {
 	return &MemoryConnection{
@@ -229,7 +252,7 @@ func newMemoryConnection(
 		localID:   localID,
 		remoteID:  remoteID,
 		receiveCh: receiveCh,
-		sendCh:    sendCh,
+		link:      link,
 		closer:    closer,
 	}
 }
@@ -261,13 +284,17 @@ func (c *MemoryConnection) Handshake(
 	nodeInfo types.NodeInfo,
 	privKey crypto.PrivKey,
 ) (types.NodeInfo, crypto.PubKey, error) {
-	select {
-	case c.sendCh <- memoryMessage{nodeInfo: &nodeInfo, pubKey: privKey.PubKey()}:
-		c.logger.Debug("sent handshake", "nodeInfo", nodeInfo)
-	case <-c.closer.Done():
-		return types.NodeInfo{}, nil, io.EOF
-	case <-ctx.Done():
-		return types.NodeInfo{}, nil, ctx.Err()
+	// Handshakes are dropped across partitions, but otherwise not affected by
+	// link conditions.
+	if _, up := c.link.network.linkConditions(c.localID, c.remoteID); up {
+		select {
+		case c.link.sendCh <- memoryMessage{nodeInfo: &nodeInfo, pubKey: privKey.PubKey()}:
+			c.logger.Debug("sent handshake", "nodeInfo", nodeInfo)
+		case <-c.closer.Done():
+			return types.NodeInfo{}, nil, io.EOF
+		case <-ctx.Done():
+			return types.NodeInfo{}, nil, ctx.Err()
+		}
 	}
 
 	select {
@@ -317,15 +344,11 @@ func (c *MemoryConnection) SendMessage(ctx context.Context, chID ChannelID, msg
 	default:
 	}
 
-	select {
-	case c.sendCh <- memoryMessage{channelID: chID, message: msg}:
-		c.logger.Debug("sent message", "chID", chID, "msg", msg)
-		return nil
-	case <-ctx.Done():
-		return io.EOF
-	case <-c.closer.Done():
-		return io.EOF
+	if err := c.link.send(ctx, memoryMessage{channelID: chID, message: msg}); err != nil {
+		return err
 	}
+	c.logger.Debug("sent message", "chID", chID, "msg", msg)
+	return nil
 }
 
 // Close implements Connection.
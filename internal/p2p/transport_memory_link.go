@@ -0,0 +1,311 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// LinkConditions describe a link from one MemoryNetwork transport to another,
+// emulating real-world network conditions in tests. The zero value is a
+// perfect link, which delivers messages instantly and losslessly.
+//
+// Conditions apply to channel messages. Handshakes are only affected by
+// partitions, see MemoryNetwork.Partition().
+type LinkConditions struct {
+	// Latency delays the delivery of every message.
+	Latency time.Duration
+
+	// Jitter adds a random delay of up to Jitter to every message. Messages
+	// are still delivered in order, as with a TCP connection.
+	Jitter time.Duration
+
+	// Bandwidth limits the rate at which messages are sent, in bytes per
+	// second. Senders block while the link is busy. 0 means unlimited.
+	Bandwidth int64
+
+	// Loss is the probability of dropping a message, between 0 and 1.
+	Loss float64
+
+	// Duplicate is the probability of delivering a message twice, between 0
+	// and 1.
+	Duplicate float64
+}
+
+// Validate validates the link conditions.
+func (c LinkConditions) Validate() error {
+	switch {
+	case c.Latency < 0:
+		return errors.New("latency can't be negative")
+	case c.Jitter < 0:
+		return errors.New("jitter can't be negative")
+	case c.Bandwidth < 0:
+		return errors.New("bandwidth can't be negative")
+	case c.Loss < 0 || c.Loss > 1:
+		return errors.New("loss must be between 0 and 1")
+	case c.Duplicate < 0 || c.Duplicate > 1:
+		return errors.New("duplicate must be between 0 and 1")
+	}
+	return nil
+}
+
+// memoryLinkID identifies a link between two transports, in one direction.
+type memoryLinkID struct {
+	from types.NodeID
+	to   types.NodeID
+}
+
+// SetLinkConditions sets the conditions of the link from one node to another,
+// overriding the default conditions. It applies to existing connections too.
+func (n *MemoryNetwork) SetLinkConditions(from, to types.NodeID, conditions LinkConditions) error {
+	if err := conditions.Validate(); err != nil {
+		return err
+	}
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.links[memoryLinkID{from: from, to: to}] = conditions
+	return nil
+}
+
+// SetDefaultLinkConditions sets the conditions of links that don't have their
+// own conditions set via SetLinkConditions().
+func (n *MemoryNetwork) SetDefaultLinkConditions(conditions LinkConditions) error {
+	if err := conditions.Validate(); err != nil {
+		return err
+	}
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.defaultLink = conditions
+	return nil
+}
+
+// Partition splits the network into the given groups of nodes, replacing any
+// previous partition. Nodes that aren't in any group form a group of their
+// own. Nodes in different groups can't dial each other, and messages between
+// them are dropped, including those already in flight. Existing connections
+// are not closed, as with a real network partition.
+func (n *MemoryNetwork) Partition(groups ...[]types.NodeID) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.partitions = map[types.NodeID]int{}
+	for i, group := range groups {
+		for _, id := range group {
+			n.partitions[id] = i + 1
+		}
+	}
+}
+
+// Heal removes a partition set via Partition().
+func (n *MemoryNetwork) Heal() {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.partitions = map[types.NodeID]int{}
+}
+
+// linkConditions returns the conditions of the link from one node to another,
+// and whether the link is up (i.e. not partitioned).
+func (n *MemoryNetwork) linkConditions(from, to types.NodeID) (LinkConditions, bool) {
+	n.mtx.RLock()
+	defer n.mtx.RUnlock()
+	if n.partitions[from] != n.partitions[to] {
+		return LinkConditions{}, false
+	}
+	if conditions, ok := n.links[memoryLinkID{from: from, to: to}]; ok {
+		return conditions, true
+	}
+	return n.defaultLink, true
+}
+
+// chance returns true with the given probability.
+func (n *MemoryNetwork) chance(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+	n.randMtx.Lock()
+	defer n.randMtx.Unlock()
+	return n.rand.Float64() < probability
+}
+
+// randDuration returns a random duration in [0, max).
+func (n *MemoryNetwork) randDuration(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	n.randMtx.Lock()
+	defer n.randMtx.Unlock()
+	return time.Duration(n.rand.Int63n(int64(max)))
+}
+
+// memoryLink sends messages from one MemoryConnection to its peer, applying
+// the link conditions of the network.
+type memoryLink struct {
+	network *MemoryNetwork
+	from    types.NodeID
+	to      types.NodeID
+	sendCh  chan<- memoryMessage
+	closer  *tmsync.Closer
+
+	// slots holds a token for every message in queue, which bounds the
+	// messages in flight by the receiver's buffer size, like a direct send.
+	slots chan struct{}
+
+	mtx         sync.Mutex
+	busyUntil   time.Time // when the link is done sending, for bandwidth limits
+	lastDeliver time.Time
+	queue       []delayedMemoryMessage // in delivery order
+	delivering  bool
+}
+
+func newMemoryLink(
+	network *MemoryNetwork,
+	from, to types.NodeID,
+	sendCh chan memoryMessage,
+	closer *tmsync.Closer,
+) *memoryLink {
+	slots := cap(sendCh)
+	if slots < 1 {
+		slots = 1
+	}
+	return &memoryLink{
+		network: network,
+		from:    from,
+		to:      to,
+		sendCh:  sendCh,
+		closer:  closer,
+		slots:   make(chan struct{}, slots),
+	}
+}
+
+// delayedMemoryMessage is a message in flight on a memoryLink.
+type delayedMemoryMessage struct {
+	message   memoryMessage
+	deliverAt time.Time
+	copies    int
+}
+
+// send sends a channel message, blocking while the link is busy, or while as
+// many messages as the receiver's buffer holds are in flight.
+func (l *memoryLink) send(ctx context.Context, msg memoryMessage) error {
+	conditions, up := l.network.linkConditions(l.from, l.to)
+	if !up {
+		return nil
+	}
+
+	// Perfect links send directly, unless delayed messages are still in
+	// flight and must be delivered first.
+	l.mtx.Lock()
+	if conditions == (LinkConditions{}) && len(l.queue) == 0 {
+		l.mtx.Unlock()
+		return l.sendDirect(ctx, msg)
+	}
+	now := time.Now()
+	sentAt := now
+	if conditions.Bandwidth > 0 {
+		if l.busyUntil.After(sentAt) {
+			sentAt = l.busyUntil
+		}
+		sentAt = sentAt.Add(time.Duration(int64(len(msg.message)) * int64(time.Second) / conditions.Bandwidth))
+		l.busyUntil = sentAt
+	}
+	l.mtx.Unlock()
+
+	if wait := sentAt.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return io.EOF
+		case <-l.closer.Done():
+			return io.EOF
+		}
+	}
+
+	if l.network.chance(conditions.Loss) {
+		return nil
+	}
+	copies := 1
+	if l.network.chance(conditions.Duplicate) {
+		copies = 2
+	}
+	deliverAt := sentAt.Add(conditions.Latency + l.network.randDuration(conditions.Jitter))
+
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return io.EOF
+	case <-l.closer.Done():
+		return io.EOF
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if deliverAt.Before(l.lastDeliver) {
+		deliverAt = l.lastDeliver
+	}
+	l.lastDeliver = deliverAt
+	l.queue = append(l.queue, delayedMemoryMessage{message: msg, deliverAt: deliverAt, copies: copies})
+	if !l.delivering {
+		l.delivering = true
+		go l.deliver()
+	}
+	return nil
+}
+
+// sendDirect sends a message to the receiver without delay.
+func (l *memoryLink) sendDirect(ctx context.Context, msg memoryMessage) error {
+	select {
+	case l.sendCh <- msg:
+		return nil
+	case <-ctx.Done():
+		return io.EOF
+	case <-l.closer.Done():
+		return io.EOF
+	}
+}
+
+// deliver delivers queued messages when they're due, until the queue is empty
+// or the connection is closed. Messages are dropped if the link is partitioned
+// in the meanwhile.
+func (l *memoryLink) deliver() {
+	for {
+		l.mtx.Lock()
+		if len(l.queue) == 0 {
+			l.delivering = false
+			l.mtx.Unlock()
+			return
+		}
+		next := l.queue[0]
+		l.mtx.Unlock()
+
+		if wait := time.Until(next.deliverAt); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-l.closer.Done():
+				timer.Stop()
+				return
+			}
+		}
+		for i := 0; i < next.copies; i++ {
+			if _, up := l.network.linkConditions(l.from, l.to); !up {
+				break
+			}
+			select {
+			case l.sendCh <- next.message:
+			case <-l.closer.Done():
+				return
+			}
+		}
+
+		l.mtx.Lock()
+		l.queue = l.queue[1:]
+		l.mtx.Unlock()
+		<-l.slots
+	}
+}
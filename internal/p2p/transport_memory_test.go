@@ -2,11 +2,15 @@ package p2p_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
 	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/internal/p2p/p2ptest"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/types"
 )
@@ -33,3 +37,127 @@ func init() {
 		return transport
 	}
 }
+
+func TestMemoryNetwork_LinkConditions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aID := types.NodeID(bytes.Repeat([]byte("a"), 40))
+	bID := types.NodeID(bytes.Repeat([]byte("b"), 40))
+	network := p2p.NewMemoryNetwork(log.TestingLogger(), 10)
+	a := network.CreateTransport(aID)
+	b := network.CreateTransport(bID)
+	t.Cleanup(func() {
+		require.NoError(t, a.Close())
+		require.NoError(t, b.Close())
+	})
+	ab, ba := dialAcceptHandshake(ctx, t, a, b)
+
+	require.Error(t, network.SetLinkConditions(aID, bID, p2p.LinkConditions{Loss: 2}))
+	require.Error(t, network.SetDefaultLinkConditions(p2p.LinkConditions{Latency: -1}))
+
+	// Latency and jitter delay messages, which are still delivered in order.
+	require.NoError(t, network.SetDefaultLinkConditions(p2p.LinkConditions{
+		Latency: 50 * time.Millisecond,
+		Jitter:  20 * time.Millisecond,
+	}))
+	start := time.Now()
+	for _, msg := range []string{"foo", "bar", "baz"} {
+		require.NoError(t, ab.SendMessage(ctx, chID, []byte(msg)))
+	}
+	for _, msg := range []string{"foo", "bar", "baz"} {
+		_, received, err := ba.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		require.Equal(t, msg, string(received))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	require.NoError(t, network.SetDefaultLinkConditions(p2p.LinkConditions{}))
+
+	// Bandwidth limits block the sender. Link conditions apply in one
+	// direction only.
+	require.NoError(t, network.SetLinkConditions(aID, bID, p2p.LinkConditions{Bandwidth: 1000}))
+	start = time.Now()
+	require.NoError(t, ab.SendMessage(ctx, chID, make([]byte, 100)))
+	require.NoError(t, ab.SendMessage(ctx, chID, make([]byte, 100)))
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		_, _, err := ba.ReceiveMessage(ctx)
+		require.NoError(t, err)
+	}
+	require.NoError(t, ba.SendMessage(ctx, chID, make([]byte, 10000)))
+	_, _, err := ab.ReceiveMessage(ctx)
+	require.NoError(t, err)
+
+	// Lost messages are dropped, and duplicated messages delivered twice.
+	require.NoError(t, network.SetLinkConditions(aID, bID, p2p.LinkConditions{Loss: 1}))
+	require.NoError(t, ab.SendMessage(ctx, chID, []byte("lost")))
+	require.NoError(t, network.SetLinkConditions(aID, bID, p2p.LinkConditions{Duplicate: 1}))
+	require.NoError(t, ab.SendMessage(ctx, chID, []byte("dup")))
+	for i := 0; i < 2; i++ {
+		_, received, err := ba.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "dup", string(received))
+	}
+}
+
+func TestMemoryNetwork_LinkBackpressure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aID := types.NodeID(bytes.Repeat([]byte("a"), 40))
+	bID := types.NodeID(bytes.Repeat([]byte("b"), 40))
+	network := p2p.NewMemoryNetwork(log.TestingLogger(), 1)
+	a := network.CreateTransport(aID)
+	b := network.CreateTransport(bID)
+	t.Cleanup(func() {
+		require.NoError(t, a.Close())
+		require.NoError(t, b.Close())
+	})
+	ab, ba := dialAcceptHandshake(ctx, t, a, b)
+
+	// With a receiver that doesn't read, one delayed message fills its
+	// buffer and another one is in flight, after which senders block.
+	require.NoError(t, network.SetDefaultLinkConditions(p2p.LinkConditions{Latency: 10 * time.Millisecond}))
+	require.NoError(t, ab.SendMessage(ctx, chID, []byte("foo")))
+	require.NoError(t, ab.SendMessage(ctx, chID, []byte("bar")))
+
+	sendCtx, sendCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer sendCancel()
+	require.Error(t, ab.SendMessage(sendCtx, chID, []byte("baz")))
+
+	// Receiving makes room for further messages.
+	_, received, err := ba.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(received))
+	require.NoError(t, ab.SendMessage(ctx, chID, []byte("baz")))
+	for _, msg := range []string{"bar", "baz"} {
+		_, received, err := ba.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		require.Equal(t, msg, string(received))
+	}
+}
+
+func TestMemoryNetwork_Partition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	network := p2ptest.MakeNetwork(ctx, t, p2ptest.NetworkOptions{NumNodes: 3})
+	ids := network.NodeIDs()
+	channels := network.MakeChannels(ctx, t, p2ptest.MakeChannelDesc(chID))
+	a, b := channels[ids[0]], channels[ids[1]]
+
+	network.StartTopology(ctx, t, p2ptest.Line(ids...))
+	require.Equal(t, []types.NodeID{ids[1]}, network.Nodes[ids[0]].PeerManager.Peers())
+
+	// Messages across the partition are dropped.
+	network.Partition([]types.NodeID{ids[0]})
+	p2ptest.RequireSend(ctx, t, a, p2p.Envelope{To: ids[1], Message: &p2ptest.Message{Value: "lost"}})
+	time.Sleep(100 * time.Millisecond)
+	p2ptest.RequireEmpty(t, b)
+
+	// Once healed, messages are delivered again.
+	network.Heal()
+	p2ptest.RequireSend(ctx, t, a, p2p.Envelope{To: ids[1], Message: &p2ptest.Message{Value: "foo"}})
+	p2ptest.RequireReceive(t, b, p2p.Envelope{From: ids[0], Message: &p2ptest.Message{Value: "foo"}})
+}
@@ -0,0 +1,690 @@
+package p2p
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/encoding"
+	"github.com/tendermint/tendermint/internal/libs/protoio"
+	"github.com/tendermint/tendermint/libs/log"
+	p2pproto "github.com/tendermint/tendermint/proto/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	QUICProtocol Protocol = "quic"
+
+	// quicALPN is the TLS application protocol negotiated by QUIC connections.
+	quicALPN = "tendermint/p2p/1"
+
+	// quicExporterLabel is the TLS exporter label of the handshake challenge.
+	quicExporterLabel = "EXPORTER-tendermint-p2p-handshake"
+
+	quicDefaultPort = 26656
+)
+
+// QUICTransportOptions sets options for QUICTransport.
+type QUICTransportOptions struct {
+	// MaxIdleTimeout is the time after which an idle connection is closed.
+	// Defaults to 30 seconds.
+	MaxIdleTimeout time.Duration
+
+	// KeepAlivePeriod is the interval at which keep-alive packets are sent,
+	// so that connections without traffic stay open. Defaults to half of
+	// MaxIdleTimeout.
+	KeepAlivePeriod time.Duration
+}
+
+// QUICTransport is a Transport implementation using QUIC.
+//
+// Each channel is carried by its own unidirectional QUIC stream in each
+// direction, so a lost packet or an exhausted flow control window on one
+// channel does not hold up traffic on the others, unlike with MConnection
+// where all channels share a single TCP stream.
+//
+// QUIC connections are secured by TLS 1.3, using an ephemeral self-signed
+// certificate that is not verified. Peers are instead authenticated during
+// Handshake, by signing a value exported from the TLS session with their node
+// key, which binds the node key to the connection.
+type QUICTransport struct {
+	logger       log.Logger
+	options      QUICTransportOptions
+	tlsConfig    *tls.Config
+	quicConfig   *quic.Config
+	channelDescs []*ChannelDescriptor
+
+	closeOnce sync.Once
+	doneCh    chan struct{}
+	listener  *quic.EarlyListener
+	udpConn   *net.UDPConn
+
+	// The UDP socket of the listener is shared by the accepted connections,
+	// so it is closed once the transport and all of them are closed.
+	mtx       sync.Mutex
+	transport *quic.Transport
+	refs      int
+}
+
+// NewQUICTransport sets up a new QUIC transport.
+func NewQUICTransport(
+	logger log.Logger,
+	channelDescs []*ChannelDescriptor,
+	options QUICTransportOptions,
+) (*QUICTransport, error) {
+	if options.MaxIdleTimeout == 0 {
+		options.MaxIdleTimeout = 30 * time.Second
+	}
+	if options.KeepAlivePeriod == 0 {
+		options.KeepAlivePeriod = options.MaxIdleTimeout / 2
+	}
+
+	cert, err := newQUICCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TLS certificate: %w", err)
+	}
+
+	return &QUICTransport{
+		logger:  logger,
+		options: options,
+		tlsConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{quicALPN},
+			MinVersion:   tls.VersionTLS13,
+			// Peers are authenticated by their node key during Handshake.
+			InsecureSkipVerify: true, // nolint:gosec
+		},
+		quicConfig: &quic.Config{
+			MaxIdleTimeout:  options.MaxIdleTimeout,
+			KeepAlivePeriod: options.KeepAlivePeriod,
+		},
+		channelDescs: channelDescs,
+		doneCh:       make(chan struct{}),
+	}, nil
+}
+
+// newQUICCertificate generates an ephemeral self-signed TLS certificate.
+func newQUICCertificate() (tls.Certificate, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(100 * 365 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: priv}, nil
+}
+
+// String implements Transport.
+func (q *QUICTransport) String() string {
+	return string(QUICProtocol)
+}
+
+// Protocols implements Transport.
+func (q *QUICTransport) Protocols() []Protocol {
+	return []Protocol{QUICProtocol}
+}
+
+// Endpoints implements Transport.
+func (q *QUICTransport) Endpoints() []Endpoint {
+	if q.listener == nil {
+		return []Endpoint{}
+	}
+	select {
+	case <-q.doneCh:
+		return []Endpoint{}
+	default:
+	}
+
+	endpoint := Endpoint{
+		Protocol: QUICProtocol,
+	}
+	if addr, ok := q.listener.Addr().(*net.UDPAddr); ok {
+		endpoint.IP = addr.IP
+		endpoint.Port = uint16(addr.Port)
+	}
+	return []Endpoint{endpoint}
+}
+
+// Listen asynchronously listens for inbound connections on the given endpoint.
+// It must be called exactly once before calling Accept(), and the caller must
+// call Close() to shut down the listener.
+func (q *QUICTransport) Listen(endpoint Endpoint) error {
+	if q.listener != nil {
+		return errors.New("transport is already listening")
+	}
+	if err := q.validateEndpoint(endpoint); err != nil {
+		return err
+	}
+
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: endpoint.IP, Port: int(endpoint.Port)})
+	if err != nil {
+		return err
+	}
+	// Connections are accepted as soon as the server has sent its handshake
+	// flight, rather than when the client finishes the handshake, since a
+	// client that closes the connection right away may never do so. Handshake()
+	// waits for the TLS handshake to complete.
+	transport := &quic.Transport{Conn: udpConn}
+	listener, err := transport.ListenEarly(q.tlsConfig, q.quicConfig)
+	if err != nil {
+		_ = udpConn.Close()
+		return err
+	}
+
+	q.udpConn = udpConn
+	q.transport = transport
+	q.refs = 1
+	q.listener = listener
+	return nil
+}
+
+// Accept implements Transport.
+func (q *QUICTransport) Accept(ctx context.Context) (Connection, error) {
+	if q.listener == nil {
+		return nil, errors.New("transport is not listening")
+	}
+	select {
+	case <-q.doneCh:
+		return nil, io.EOF
+	default:
+	}
+
+	conn, err := q.listener.Accept(ctx)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+			return nil, io.EOF
+		case <-q.doneCh:
+			return nil, io.EOF
+		default:
+			return nil, err
+		}
+	}
+
+	q.mtx.Lock()
+	if q.refs == 0 {
+		q.mtx.Unlock()
+		_ = conn.CloseWithError(0, "")
+		return nil, io.EOF
+	}
+	q.refs++
+	channelDescs := q.channelDescs
+	q.mtx.Unlock()
+
+	return newQUICConnection(q.logger, conn, channelDescs, false, q.release), nil
+}
+
+// release drops a reference to the listening socket, closing it with the
+// last one.
+func (q *QUICTransport) release() error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	q.refs--
+	if q.refs > 0 {
+		return nil
+	}
+	err := q.transport.Close()
+	if cerr := q.udpConn.Close(); err == nil && !errors.Is(cerr, net.ErrClosed) {
+		err = cerr
+	}
+	return err
+}
+
+// Dial implements Transport. Outbound connections each use their own UDP
+// socket, so they are independent of the listener.
+func (q *QUICTransport) Dial(ctx context.Context, endpoint Endpoint) (Connection, error) {
+	if err := q.validateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	if endpoint.Port == 0 {
+		endpoint.Port = quicDefaultPort
+	}
+
+	// As with TCP, dialing the unspecified address dials the local host.
+	ip := endpoint.IP
+	if ip.IsUnspecified() {
+		ip = net.IPv4(127, 0, 0, 1)
+	}
+	raddr := &net.UDPAddr{IP: ip, Port: int(endpoint.Port)}
+	laddr, err := quicLocalAddr(raddr)
+	if err != nil {
+		return nil, err
+	}
+	udpConn, err := net.ListenUDP("udp", laddr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := quic.Dial(ctx, udpConn, raddr, q.tlsConfig, q.quicConfig)
+	if err != nil {
+		_ = udpConn.Close()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			return nil, err
+		}
+	}
+
+	q.mtx.Lock()
+	channelDescs := q.channelDescs
+	q.mtx.Unlock()
+
+	return newQUICConnection(q.logger, conn, channelDescs, true, udpConn.Close), nil
+}
+
+// quicLocalAddr returns the local address to bind a socket dialing raddr to,
+// which is the address the kernel routes from. Binding it explicitly, rather
+// than the unspecified address, lets connections report their actual local
+// endpoint.
+func quicLocalAddr(raddr *net.UDPAddr) (*net.UDPAddr, error) {
+	probe, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		return nil, err
+	}
+	defer probe.Close()
+
+	addr, ok := probe.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return nil, fmt.Errorf("unexpected local address %v", probe.LocalAddr())
+	}
+	return &net.UDPAddr{IP: addr.IP, Zone: addr.Zone}, nil
+}
+
+// Close implements Transport.
+func (q *QUICTransport) Close() error {
+	var err error
+	q.closeOnce.Do(func() {
+		close(q.doneCh)
+		if q.listener != nil {
+			err = q.listener.Close()
+			if rerr := q.release(); err == nil {
+				err = rerr
+			}
+		}
+	})
+	return err
+}
+
+// AddChannelDescriptors implements Transport.
+func (q *QUICTransport) AddChannelDescriptors(channelDesc []*ChannelDescriptor) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.channelDescs = append(q.channelDescs, channelDesc...)
+}
+
+// validateEndpoint validates an endpoint.
+func (q *QUICTransport) validateEndpoint(endpoint Endpoint) error {
+	if err := endpoint.Validate(); err != nil {
+		return err
+	}
+	if endpoint.Protocol != QUICProtocol {
+		return fmt.Errorf("unsupported protocol %q", endpoint.Protocol)
+	}
+	if len(endpoint.IP) == 0 {
+		return errors.New("endpoint has no IP address")
+	}
+	if endpoint.IP.IsMulticast() || endpoint.IP.Equal(net.IPv4bcast) {
+		return fmt.Errorf("cannot use broadcast or multicast address %v", endpoint.IP)
+	}
+	if endpoint.Path != "" {
+		return fmt.Errorf("endpoints with path not supported (got %q)", endpoint.Path)
+	}
+	return nil
+}
+
+// quicConnection implements Connection for QUICTransport.
+type quicConnection struct {
+	logger    log.Logger
+	conn      quic.Connection
+	dialed    bool
+	onClose   func() error
+	receiveCh chan mConnMessage
+	doneCh    chan struct{}
+	closeOnce sync.Once
+
+	maxMsgSize map[ChannelID]int // set during Handshake()
+
+	sendMtx     sync.Mutex
+	sendStreams map[ChannelID]*quicSendStream
+}
+
+// quicSendStream is the outbound stream of a channel.
+type quicSendStream struct {
+	mtx    sync.Mutex
+	stream quic.SendStream
+	buf    []byte
+}
+
+func newQUICConnection(
+	logger log.Logger,
+	conn quic.Connection,
+	channelDescs []*ChannelDescriptor,
+	dialed bool,
+	onClose func() error,
+) *quicConnection {
+	maxMsgSize := make(map[ChannelID]int, len(channelDescs))
+	for _, chDesc := range channelDescs {
+		maxMsgSize[chDesc.ID] = chDesc.FillDefaults().RecvMessageCapacity
+	}
+	return &quicConnection{
+		logger:      logger,
+		conn:        conn,
+		dialed:      dialed,
+		onClose:     onClose,
+		receiveCh:   make(chan mConnMessage),
+		doneCh:      make(chan struct{}),
+		maxMsgSize:  maxMsgSize,
+		sendStreams: make(map[ChannelID]*quicSendStream),
+	}
+}
+
+// Handshake implements Connection.
+//
+// The dialing side opens a bidirectional stream, over which both sides send
+// their NodeInfo followed by their public key and signature of a challenge
+// exported from the TLS session.
+func (c *quicConnection) Handshake(
+	ctx context.Context,
+	nodeInfo types.NodeInfo,
+	privKey crypto.PrivKey,
+) (types.NodeInfo, crypto.PubKey, error) {
+	var (
+		peerInfo types.NodeInfo
+		peerKey  crypto.PubKey
+		errCh    = make(chan error, 1)
+	)
+	// To handle context cancellation, we need to do the handshake in a
+	// goroutine and abort the blocking network calls by closing the connection
+	// when the context is canceled.
+	go func() {
+		var err error
+		peerInfo, peerKey, err = c.handshake(ctx, nodeInfo, privKey)
+		errCh <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+		_ = c.Close()
+		return types.NodeInfo{}, nil, ctx.Err()
+
+	case err := <-errCh:
+		if err != nil {
+			return types.NodeInfo{}, nil, err
+		}
+		go c.acceptStreams()
+		return peerInfo, peerKey, nil
+	}
+}
+
+// handshake is a helper for Handshake, simplifying error handling so we can
+// keep context handling in Handshake.
+func (c *quicConnection) handshake(
+	ctx context.Context,
+	nodeInfo types.NodeInfo,
+	privKey crypto.PrivKey,
+) (types.NodeInfo, crypto.PubKey, error) {
+	// Accepted connections are early connections, see Listen().
+	if early, ok := c.conn.(quic.EarlyConnection); ok && !c.dialed {
+		select {
+		case <-early.HandshakeComplete():
+		case <-c.conn.Context().Done():
+			return types.NodeInfo{}, nil, io.EOF
+		case <-ctx.Done():
+			return types.NodeInfo{}, nil, ctx.Err()
+		}
+	}
+	tlsState := c.conn.ConnectionState().TLS
+	challenge, err := tlsState.ExportKeyingMaterial(quicExporterLabel, nil, 32)
+	if err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+	sig, err := privKey.Sign(challenge)
+	if err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+	pbPubKey, err := encoding.PubKeyToProto(privKey.PubKey())
+	if err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+
+	var stream quic.Stream
+	if c.dialed {
+		stream, err = c.conn.OpenStreamSync(ctx)
+	} else {
+		stream, err = c.conn.AcceptStream(ctx)
+	}
+	if err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+	defer stream.Close()
+
+	// Both sides write before reading, which can't deadlock since the
+	// messages fit into the stream's flow control window.
+	writer := protoio.NewDelimitedWriter(stream)
+	if _, err := writer.WriteMsg(nodeInfo.ToProto()); err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+	if _, err := writer.WriteMsg(&p2pproto.AuthSigMessage{PubKey: pbPubKey, Sig: sig}); err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+
+	var (
+		pbPeerInfo p2pproto.NodeInfo
+		authSig    p2pproto.AuthSigMessage
+	)
+	if _, err := protoio.NewDelimitedReader(stream, types.MaxNodeInfoSize()).ReadMsg(&pbPeerInfo); err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+	if _, err := protoio.NewDelimitedReader(stream, 1024).ReadMsg(&authSig); err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+
+	peerKey, err := encoding.PubKeyFromProto(authSig.PubKey)
+	if err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+	if !peerKey.VerifySignature(challenge, authSig.Sig) {
+		return types.NodeInfo{}, nil, errors.New("challenge verification failed")
+	}
+	peerInfo, err := types.NodeInfoFromProto(&pbPeerInfo)
+	if err != nil {
+		return types.NodeInfo{}, nil, err
+	}
+	return peerInfo, peerKey, nil
+}
+
+// acceptStreams accepts the channel streams opened by the peer. Each starts
+// with the uvarint channel ID, followed by the channel's messages.
+func (c *quicConnection) acceptStreams() {
+	ctx := c.conn.Context()
+	for {
+		stream, err := c.conn.AcceptUniStream(ctx)
+		if err != nil {
+			return
+		}
+		go c.receiveStream(stream)
+	}
+}
+
+// receiveStream reads the messages of a channel stream.
+func (c *quicConnection) receiveStream(stream quic.ReceiveStream) {
+	r := bufio.NewReader(stream)
+	id, err := binary.ReadUvarint(r)
+	if err != nil {
+		stream.CancelRead(0)
+		return
+	}
+	chID := ChannelID(id)
+	maxMsgSize, ok := c.maxMsgSize[chID]
+	if !ok || id != uint64(chID) {
+		c.logger.Debug("peer opened stream for unknown channel", "peer", c.String(), "channel", id)
+		stream.CancelRead(0)
+		return
+	}
+
+	for {
+		size, err := binary.ReadUvarint(r)
+		if err != nil {
+			stream.CancelRead(0)
+			return
+		}
+		if size > uint64(maxMsgSize) {
+			c.logger.Debug("peer sent oversized message", "peer", c.String(), "channel", chID, "size", size)
+			_ = c.Close()
+			return
+		}
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			stream.CancelRead(0)
+			return
+		}
+
+		select {
+		case c.receiveCh <- mConnMessage{channelID: chID, payload: msg}:
+		case <-c.doneCh:
+			return
+		case <-c.conn.Context().Done():
+			return
+		}
+	}
+}
+
+// String displays connection information.
+func (c *quicConnection) String() string {
+	return c.RemoteEndpoint().String()
+}
+
+// sendStream returns the outbound stream of a channel, opening it if needed.
+func (c *quicConnection) sendStream(chID ChannelID) (*quicSendStream, error) {
+	c.sendMtx.Lock()
+	defer c.sendMtx.Unlock()
+
+	if s, ok := c.sendStreams[chID]; ok {
+		return s, nil
+	}
+	stream, err := c.conn.OpenUniStream()
+	if err != nil {
+		return nil, err
+	}
+	s := &quicSendStream{stream: stream}
+	s.buf = binary.AppendUvarint(s.buf, uint64(chID))
+	c.sendStreams[chID] = s
+	return s, nil
+}
+
+// SendMessage implements Connection.
+func (c *quicConnection) SendMessage(ctx context.Context, chID ChannelID, msg []byte) error {
+	select {
+	case <-c.doneCh:
+		return io.EOF
+	case <-c.conn.Context().Done():
+		return io.EOF
+	case <-ctx.Done():
+		return io.EOF
+	default:
+	}
+
+	s, err := c.sendStream(chID)
+	if err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.stream.SetWriteDeadline(deadline)
+		defer func() { _ = s.stream.SetWriteDeadline(time.Time{}) }()
+	}
+
+	// The stream header, if not sent yet, the length prefix and the message
+	// are sent with a single write.
+	s.buf = binary.AppendUvarint(s.buf, uint64(len(msg)))
+	s.buf = append(s.buf, msg...)
+	_, err = s.stream.Write(s.buf)
+	s.buf = s.buf[:0]
+	if err != nil {
+		select {
+		case <-c.doneCh:
+			return io.EOF
+		case <-c.conn.Context().Done():
+			return io.EOF
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// ReceiveMessage implements Connection.
+func (c *quicConnection) ReceiveMessage(ctx context.Context) (ChannelID, []byte, error) {
+	select {
+	case <-c.doneCh:
+		return 0, nil, io.EOF
+	case <-c.conn.Context().Done():
+		return 0, nil, io.EOF
+	case <-ctx.Done():
+		return 0, nil, io.EOF
+	case msg := <-c.receiveCh:
+		return msg.channelID, msg.payload, nil
+	}
+}
+
+// LocalEndpoint implements Connection.
+func (c *quicConnection) LocalEndpoint() Endpoint {
+	return quicEndpoint(c.conn.LocalAddr())
+}
+
+// RemoteEndpoint implements Connection.
+func (c *quicConnection) RemoteEndpoint() Endpoint {
+	return quicEndpoint(c.conn.RemoteAddr())
+}
+
+func quicEndpoint(addr net.Addr) Endpoint {
+	endpoint := Endpoint{
+		Protocol: QUICProtocol,
+	}
+	if addr, ok := addr.(*net.UDPAddr); ok {
+		endpoint.IP = addr.IP
+		endpoint.Port = uint16(addr.Port)
+	}
+	return endpoint
+}
+
+// Close implements Connection.
+func (c *quicConnection) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.doneCh)
+		err = c.conn.CloseWithError(0, "")
+		if c.onClose != nil {
+			if cerr := c.onClose(); err == nil {
+				err = cerr
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,92 @@
+package p2p_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// Transports are mainly tested by common tests in transport_test.go, we
+// register a transport factory here to get included in those tests.
+func init() {
+	testTransports["quic"] = func(t *testing.T) p2p.Transport {
+		transport, err := p2p.NewQUICTransport(
+			log.TestingLogger(),
+			[]*p2p.ChannelDescriptor{{ID: chID, Priority: 1}},
+			p2p.QUICTransportOptions{},
+		)
+		require.NoError(t, err)
+		err = transport.Listen(p2p.Endpoint{
+			Protocol: p2p.QUICProtocol,
+			IP:       net.IPv4(127, 0, 0, 1),
+			Port:     0, // assign a random port
+		})
+		require.NoError(t, err)
+
+		t.Cleanup(func() {
+			require.NoError(t, transport.Close())
+		})
+
+		return transport
+	}
+}
+
+func TestQUICTransport_Channels(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	makeTransport := func() p2p.Transport {
+		transport, err := p2p.NewQUICTransport(
+			log.TestingLogger(),
+			[]*p2p.ChannelDescriptor{
+				{ID: 1, Priority: 1, RecvMessageCapacity: 100},
+				{ID: 2, Priority: 1, RecvMessageCapacity: 100},
+			},
+			p2p.QUICTransportOptions{},
+		)
+		require.NoError(t, err)
+		require.NoError(t, transport.Listen(p2p.Endpoint{
+			Protocol: p2p.QUICProtocol,
+			IP:       net.IPv4(127, 0, 0, 1),
+		}))
+		t.Cleanup(func() { _ = transport.Close() })
+		return transport
+	}
+	ab, ba := dialAcceptHandshake(ctx, t, makeTransport(), makeTransport())
+
+	// Messages on different channels use separate streams, so only the
+	// order within a channel is preserved.
+	received := map[p2p.ChannelID][]string{}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, ab.SendMessage(ctx, 1, []byte{'a', byte('0' + i)}))
+		require.NoError(t, ab.SendMessage(ctx, 2, []byte{'b', byte('0' + i)}))
+	}
+	for i := 0; i < 6; i++ {
+		chID, msg, err := ba.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		received[chID] = append(received[chID], string(msg))
+	}
+	require.Equal(t, map[p2p.ChannelID][]string{
+		1: {"a0", "a1", "a2"},
+		2: {"b0", "b1", "b2"},
+	}, received)
+
+	// Messages on unknown channels are dropped.
+	require.NoError(t, ab.SendMessage(ctx, 3, []byte("unknown")))
+	require.NoError(t, ab.SendMessage(ctx, 1, []byte("known")))
+	chID, msg, err := ba.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, p2p.ChannelID(1), chID)
+	require.Equal(t, []byte("known"), msg)
+
+	// Oversized messages close the connection.
+	require.NoError(t, ab.SendMessage(ctx, 2, make([]byte, 101)))
+	_, _, err = ba.ReceiveMessage(ctx)
+	require.Equal(t, io.EOF, err)
+}
@@ -476,6 +476,10 @@ func TestEndpoint_NodeAddress(t *testing.T) {
 			p2p.Endpoint{Protocol: "tcp", IP: ip6, Port: 8080, Path: "path"},
 			p2p.NodeAddress{Protocol: "tcp", Hostname: "b10c::1", Port: 8080, Path: "path"},
 		},
+		{
+			p2p.Endpoint{Protocol: "tcp", Hostname: "host.onion", Port: 8080},
+			p2p.NodeAddress{Protocol: "tcp", Hostname: "host.onion", Port: 8080},
+		},
 		{
 			p2p.Endpoint{Protocol: "memory", Path: "foo"},
 			p2p.NodeAddress{Protocol: "memory", Path: "foo"},
@@ -536,6 +540,9 @@ func TestEndpoint_String(t *testing.T) {
 		{p2p.Endpoint{Protocol: "tcp", IP: ip6, Port: 8080, Path: "/path"}, "tcp://[b10c::1]:8080/path"},
 		{p2p.Endpoint{Protocol: "tcp", IP: ip6, Path: "path/👋"}, "tcp://b10c::1/path/%F0%9F%91%8B"},
 
+		// Hostname endpoints.
+		{p2p.Endpoint{Protocol: "tcp", Hostname: "host.onion", Port: 8080}, "tcp://host.onion:8080"},
+
 		// Partial (invalid) endpoints.
 		{p2p.Endpoint{}, ""},
 		{p2p.Endpoint{Protocol: "tcp"}, "tcp:"},
@@ -570,6 +577,7 @@ func TestEndpoint_Validate(t *testing.T) {
 		{p2p.Endpoint{Protocol: "tcp", IP: ip4, Port: 8008}, true},
 		{p2p.Endpoint{Protocol: "tcp", IP: ip4, Port: 8080, Path: "path"}, true},
 		{p2p.Endpoint{Protocol: "memory", Path: "path"}, true},
+		{p2p.Endpoint{Protocol: "tcp", Hostname: "host.onion", Port: 8080}, true},
 
 		// Invalid endpoints.
 		{p2p.Endpoint{}, false},
@@ -577,6 +585,7 @@ func TestEndpoint_Validate(t *testing.T) {
 		{p2p.Endpoint{Protocol: "tcp"}, false},
 		{p2p.Endpoint{Protocol: "tcp", IP: []byte{1, 2, 3}}, false},
 		{p2p.Endpoint{Protocol: "tcp", Port: 8080, Path: "path"}, false},
+		{p2p.Endpoint{Protocol: "tcp", IP: ip4, Hostname: "host.onion"}, false},
 	}
 	for _, tc := range testcases {
 		tc := tc
@@ -2,6 +2,7 @@ package proxy
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/go-kit/kit/metrics"
@@ -43,6 +44,7 @@ type AppConnQuery interface {
 	EchoSync(context.Context, string) (*types.ResponseEcho, error)
 	InfoSync(context.Context, types.RequestInfo) (*types.ResponseInfo, error)
 	QuerySync(context.Context, types.RequestQuery) (*types.ResponseQuery, error)
+	QueryStream(context.Context, types.RequestQuery) (*types.ResponseQuery, io.ReadCloser, error)
 }
 
 type AppConnSnapshot interface {
@@ -52,6 +54,7 @@ type AppConnSnapshot interface {
 	OfferSnapshotSync(context.Context, types.RequestOfferSnapshot) (*types.ResponseOfferSnapshot, error)
 	LoadSnapshotChunkSync(context.Context, types.RequestLoadSnapshotChunk) (*types.ResponseLoadSnapshotChunk, error)
 	ApplySnapshotChunkSync(context.Context, types.RequestApplySnapshotChunk) (*types.ResponseApplySnapshotChunk, error)
+	LoadSnapshotChunkStream(context.Context, types.RequestLoadSnapshotChunk) (io.ReadCloser, error)
 }
 
 //-----------------------------------------------------------------------------------------
@@ -81,7 +84,8 @@ func (app *appConnConsensus) InitChainSync(
 	ctx context.Context,
 	req types.RequestInitChain,
 ) (*types.ResponseInitChain, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "init_chain", "type", "sync"))()
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "init_chain", "type", "sync", "connection", connConsensus))()
 	return app.appConn.InitChainSync(ctx, req)
 }
 
@@ -89,7 +93,8 @@ func (app *appConnConsensus) BeginBlockSync(
 	ctx context.Context,
 	req types.RequestBeginBlock,
 ) (*types.ResponseBeginBlock, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "begin_block", "type", "sync"))()
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "begin_block", "type", "sync", "connection", connConsensus))()
 	return app.appConn.BeginBlockSync(ctx, req)
 }
 
@@ -97,7 +102,8 @@ func (app *appConnConsensus) DeliverTxAsync(
 	ctx context.Context,
 	req types.RequestDeliverTx,
 ) (*abciclient.ReqRes, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "deliver_tx", "type", "async"))()
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "deliver_tx", "type", "async", "connection", connConsensus))()
 	return app.appConn.DeliverTxAsync(ctx, req)
 }
 
@@ -105,12 +111,12 @@ func (app *appConnConsensus) EndBlockSync(
 	ctx context.Context,
 	req types.RequestEndBlock,
 ) (*types.ResponseEndBlock, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "deliver_tx", "type", "sync"))()
+	defer addTimeSample(app.metrics.MethodTiming.With("method", "end_block", "type", "sync", "connection", connConsensus))()
 	return app.appConn.EndBlockSync(ctx, req)
 }
 
 func (app *appConnConsensus) CommitSync(ctx context.Context) (*types.ResponseCommit, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "commit", "type", "sync"))()
+	defer addTimeSample(app.metrics.MethodTiming.With("method", "commit", "type", "sync", "connection", connConsensus))()
 	return app.appConn.CommitSync(ctx)
 }
 
@@ -120,12 +126,20 @@ func (app *appConnConsensus) CommitSync(ctx context.Context) (*types.ResponseCom
 type appConnMempool struct {
 	metrics *Metrics
 	appConn abciclient.Client
+	policy  *callPolicy
 }
 
 func NewAppConnMempool(appConn abciclient.Client, metrics *Metrics) AppConnMempool {
+	return newAppConnMempool(appConn, metrics, nil)
+}
+
+// newAppConnMempool returns an AppConnMempool whose synchronous CheckTx calls
+// are subject to policy.
+func newAppConnMempool(appConn abciclient.Client, metrics *Metrics, policy *callPolicy) AppConnMempool {
 	return &appConnMempool{
 		metrics: metrics,
 		appConn: appConn,
+		policy:  policy,
 	}
 }
 
@@ -138,23 +152,32 @@ func (app *appConnMempool) Error() error {
 }
 
 func (app *appConnMempool) FlushAsync(ctx context.Context) (*abciclient.ReqRes, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "flush", "type", "async"))()
+	defer addTimeSample(app.metrics.MethodTiming.With("method", "flush", "type", "async", "connection", connMempool))()
 	return app.appConn.FlushAsync(ctx)
 }
 
 func (app *appConnMempool) FlushSync(ctx context.Context) error {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "flush", "type", "sync"))()
+	defer addTimeSample(app.metrics.MethodTiming.With("method", "flush", "type", "sync", "connection", connMempool))()
 	return app.appConn.FlushSync(ctx)
 }
 
 func (app *appConnMempool) CheckTxAsync(ctx context.Context, req types.RequestCheckTx) (*abciclient.ReqRes, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "check_tx", "type", "async"))()
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "check_tx", "type", "async", "connection", connMempool))()
 	return app.appConn.CheckTxAsync(ctx, req)
 }
 
 func (app *appConnMempool) CheckTxSync(ctx context.Context, req types.RequestCheckTx) (*types.ResponseCheckTx, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "check_tx", "type", "sync"))()
-	return app.appConn.CheckTxSync(ctx, req)
+	defer addTimeSample(app.metrics.MethodTiming.With("method", "check_tx", "type", "sync", "connection", connMempool))()
+	var res *types.ResponseCheckTx
+	err := app.policy.run(ctx, "check_tx", func(ctx context.Context) (err error) {
+		res, err = app.appConn.CheckTxSync(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 //------------------------------------------------
@@ -163,12 +186,19 @@ func (app *appConnMempool) CheckTxSync(ctx context.Context, req types.RequestChe
 type appConnQuery struct {
 	metrics *Metrics
 	appConn abciclient.Client
+	policy  *callPolicy
 }
 
 func NewAppConnQuery(appConn abciclient.Client, metrics *Metrics) AppConnQuery {
+	return newAppConnQuery(appConn, metrics, nil)
+}
+
+// newAppConnQuery returns an AppConnQuery whose calls are subject to policy.
+func newAppConnQuery(appConn abciclient.Client, metrics *Metrics, policy *callPolicy) AppConnQuery {
 	return &appConnQuery{
 		metrics: metrics,
 		appConn: appConn,
+		policy:  policy,
 	}
 }
 
@@ -177,18 +207,64 @@ func (app *appConnQuery) Error() error {
 }
 
 func (app *appConnQuery) EchoSync(ctx context.Context, msg string) (*types.ResponseEcho, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "echo", "type", "sync"))()
-	return app.appConn.EchoSync(ctx, msg)
+	defer addTimeSample(app.metrics.MethodTiming.With("method", "echo", "type", "sync", "connection", connQuery))()
+	var res *types.ResponseEcho
+	err := app.policy.run(ctx, "echo", func(ctx context.Context) (err error) {
+		res, err = app.appConn.EchoSync(ctx, msg)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 func (app *appConnQuery) InfoSync(ctx context.Context, req types.RequestInfo) (*types.ResponseInfo, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "info", "type", "sync"))()
-	return app.appConn.InfoSync(ctx, req)
+	defer addTimeSample(app.metrics.MethodTiming.With("method", "info", "type", "sync", "connection", connQuery))()
+	var res *types.ResponseInfo
+	err := app.policy.run(ctx, "info", func(ctx context.Context) (err error) {
+		res, err = app.appConn.InfoSync(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 func (app *appConnQuery) QuerySync(ctx context.Context, reqQuery types.RequestQuery) (*types.ResponseQuery, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "query", "type", "sync"))()
-	return app.appConn.QuerySync(ctx, reqQuery)
+	defer addTimeSample(app.metrics.MethodTiming.With("method", "query", "type", "sync", "connection", connQuery))()
+	var res *types.ResponseQuery
+	err := app.policy.run(ctx, "query", func(ctx context.Context) (err error) {
+		res, err = app.appConn.QuerySync(ctx, reqQuery)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// QueryStream opens a streamed query. The call timeout of the connection
+// does not apply, ctx bounds the whole response instead.
+func (app *appConnQuery) QueryStream(
+	ctx context.Context,
+	reqQuery types.RequestQuery,
+) (*types.ResponseQuery, io.ReadCloser, error) {
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "query_stream", "type", "sync", "connection", connQuery))()
+	var (
+		res  *types.ResponseQuery
+		body io.ReadCloser
+	)
+	err := app.policy.runStream(ctx, "query_stream", func(ctx context.Context) (err error) {
+		res, body, err = app.appConn.QueryStream(ctx, reqQuery)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, body, nil
 }
 
 //------------------------------------------------
@@ -197,12 +273,20 @@ func (app *appConnQuery) QuerySync(ctx context.Context, reqQuery types.RequestQu
 type appConnSnapshot struct {
 	metrics *Metrics
 	appConn abciclient.Client
+	policy  *callPolicy
 }
 
 func NewAppConnSnapshot(appConn abciclient.Client, metrics *Metrics) AppConnSnapshot {
+	return newAppConnSnapshot(appConn, metrics, nil)
+}
+
+// newAppConnSnapshot returns an AppConnSnapshot whose calls are subject to
+// policy.
+func newAppConnSnapshot(appConn abciclient.Client, metrics *Metrics, policy *callPolicy) AppConnSnapshot {
 	return &appConnSnapshot{
 		metrics: metrics,
 		appConn: appConn,
+		policy:  policy,
 	}
 }
 
@@ -214,30 +298,85 @@ func (app *appConnSnapshot) ListSnapshotsSync(
 	ctx context.Context,
 	req types.RequestListSnapshots,
 ) (*types.ResponseListSnapshots, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "list_snapshots", "type", "sync"))()
-	return app.appConn.ListSnapshotsSync(ctx, req)
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "list_snapshots", "type", "sync", "connection", connSnapshot))()
+	var res *types.ResponseListSnapshots
+	err := app.policy.run(ctx, "list_snapshots", func(ctx context.Context) (err error) {
+		res, err = app.appConn.ListSnapshotsSync(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 func (app *appConnSnapshot) OfferSnapshotSync(
 	ctx context.Context,
 	req types.RequestOfferSnapshot,
 ) (*types.ResponseOfferSnapshot, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "offer_snapshot", "type", "sync"))()
-	return app.appConn.OfferSnapshotSync(ctx, req)
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "offer_snapshot", "type", "sync", "connection", connSnapshot))()
+	var res *types.ResponseOfferSnapshot
+	err := app.policy.run(ctx, "offer_snapshot", func(ctx context.Context) (err error) {
+		res, err = app.appConn.OfferSnapshotSync(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 func (app *appConnSnapshot) LoadSnapshotChunkSync(
 	ctx context.Context,
 	req types.RequestLoadSnapshotChunk) (*types.ResponseLoadSnapshotChunk, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "load_snapshot_chunk", "type", "sync"))()
-	return app.appConn.LoadSnapshotChunkSync(ctx, req)
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "load_snapshot_chunk", "type", "sync", "connection", connSnapshot))()
+	var res *types.ResponseLoadSnapshotChunk
+	err := app.policy.run(ctx, "load_snapshot_chunk", func(ctx context.Context) (err error) {
+		res, err = app.appConn.LoadSnapshotChunkSync(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 func (app *appConnSnapshot) ApplySnapshotChunkSync(
 	ctx context.Context,
 	req types.RequestApplySnapshotChunk) (*types.ResponseApplySnapshotChunk, error) {
-	defer addTimeSample(app.metrics.MethodTiming.With("method", "apply_snapshot_chunk", "type", "sync"))()
-	return app.appConn.ApplySnapshotChunkSync(ctx, req)
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "apply_snapshot_chunk", "type", "sync", "connection", connSnapshot))()
+	var res *types.ResponseApplySnapshotChunk
+	err := app.policy.run(ctx, "apply_snapshot_chunk", func(ctx context.Context) (err error) {
+		res, err = app.appConn.ApplySnapshotChunkSync(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// LoadSnapshotChunkStream opens a streamed snapshot chunk. The call timeout
+// of the connection does not apply, ctx bounds the whole chunk instead.
+func (app *appConnSnapshot) LoadSnapshotChunkStream(
+	ctx context.Context,
+	req types.RequestLoadSnapshotChunk,
+) (io.ReadCloser, error) {
+	defer addTimeSample(app.metrics.MethodTiming.With(
+		"method", "load_snapshot_chunk_stream", "type", "sync", "connection", connSnapshot))()
+	var body io.ReadCloser
+	err := app.policy.runStream(ctx, "load_snapshot_chunk_stream", func(ctx context.Context) (err error) {
+		body, err = app.appConn.LoadSnapshotChunkStream(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
 }
 
 // addTimeSample returns a function that, when called, adds an observation to m.
@@ -5,32 +5,69 @@ import (
 
 	abciclient "github.com/tendermint/tendermint/abci/client"
 	"github.com/tendermint/tendermint/abci/example/kvstore"
+	"github.com/tendermint/tendermint/abci/example/merklestore"
 	"github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
 	e2e "github.com/tendermint/tendermint/test/e2e/app"
 )
 
+// ClientCreatorOption sets an optional parameter on the Creator returned by
+// DefaultClientCreator.
+type ClientCreatorOption func(*clientCreatorOptions)
+
+type clientCreatorOptions struct {
+	concurrentQueries bool
+}
+
+// WithConcurrentLocalQueries makes the clients of a builtin application run
+// Query calls without the lock serializing its other calls, see
+// abciclient.NewConcurrentQueryLocalCreator. The merklestore application
+// always runs queries concurrently.
+func WithConcurrentLocalQueries(enabled bool) ClientCreatorOption {
+	return func(opts *clientCreatorOptions) { opts.concurrentQueries = enabled }
+}
+
 // DefaultClientCreator returns a default ClientCreator, which will create a
 // local client if addr is one of: 'kvstore',
-// 'persistent_kvstore', 'e2e', or 'noop', otherwise - a remote client.
+// 'persistent_kvstore', 'merklestore', 'e2e', or 'noop', otherwise - a remote
+// client.
 //
-// The Closer is a noop except for persistent_kvstore applications,
-// which will clean up the store.
-func DefaultClientCreator(logger log.Logger, addr, transport, dbDir string) (abciclient.Creator, io.Closer) {
+// The Closer is a noop except for persistent_kvstore and merklestore
+// applications, which will clean up the store.
+func DefaultClientCreator(
+	logger log.Logger,
+	addr, transport, dbDir string,
+	options ...ClientCreatorOption,
+) (abciclient.Creator, io.Closer) {
+	var opts clientCreatorOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	newLocalCreator := abciclient.NewLocalCreator
+	if opts.concurrentQueries {
+		newLocalCreator = abciclient.NewConcurrentQueryLocalCreator
+	}
+
 	switch addr {
 	case "kvstore":
-		return abciclient.NewLocalCreator(kvstore.NewApplication()), noopCloser{}
+		return newLocalCreator(kvstore.NewApplication()), noopCloser{}
 	case "persistent_kvstore":
 		app := kvstore.NewPersistentKVStoreApplication(dbDir)
-		return abciclient.NewLocalCreator(app), app
+		return newLocalCreator(app), app
+	case "merklestore":
+		app, err := merklestore.NewPersistentApplication(dbDir, merklestore.DefaultConfig())
+		if err != nil {
+			panic(err)
+		}
+		return abciclient.NewConcurrentQueryLocalCreator(app), app
 	case "e2e":
 		app, err := e2e.NewApplication(e2e.DefaultConfig(dbDir))
 		if err != nil {
 			panic(err)
 		}
-		return abciclient.NewLocalCreator(app), noopCloser{}
+		return newLocalCreator(app), noopCloser{}
 	case "noop":
-		return abciclient.NewLocalCreator(types.NewBaseApplication()), noopCloser{}
+		return newLocalCreator(types.NewBaseApplication()), noopCloser{}
 	default:
 		mustConnect := false // loop retrying
 		return abciclient.NewRemoteCreator(logger, addr, transport, mustConnect), noopCloser{}
@@ -15,7 +15,15 @@ const (
 
 // Metrics contains the prometheus metrics exposed by the proxy package.
 type Metrics struct {
+	// Timing of ABCI methods, by method, call type and connection.
 	MethodTiming metrics.Histogram
+	// Number of ABCI calls that exceeded their configured timeout.
+	MethodTimeouts metrics.Counter
+	// Number of ABCI calls rejected because the connection's circuit breaker
+	// was open.
+	MethodRejected metrics.Counter
+	// Whether the circuit breaker of a connection is open (1) or closed (0).
+	BreakerOpen metrics.Gauge
 }
 
 // PrometheusMetrics constructs a Metrics instance that collects metrics samples.
@@ -34,7 +42,25 @@ func PrometheusMetrics(namespace string, defaultLabelsAndValues ...string) *Metr
 			Name:      "method_timing",
 			Help:      "ABCI Method Timing",
 			Buckets:   []float64{.0001, .0004, .002, .009, .02, .1, .65, 2, 6, 25},
-		}, append(defaultLabels, []string{"method", "type"}...)).With(defaultLabelsAndValues...),
+		}, append(defaultLabels, []string{"method", "type", "connection"}...)).With(defaultLabelsAndValues...),
+		MethodTimeouts: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "method_timeouts",
+			Help:      "Number of ABCI calls that exceeded their timeout",
+		}, append(defaultLabels, []string{"method", "connection"}...)).With(defaultLabelsAndValues...),
+		MethodRejected: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "method_rejected",
+			Help:      "Number of ABCI calls rejected while the application was unresponsive",
+		}, append(defaultLabels, []string{"method", "connection"}...)).With(defaultLabelsAndValues...),
+		BreakerOpen: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "breaker_open",
+			Help:      "Whether the circuit breaker of an ABCI connection is open",
+		}, append(defaultLabels, "connection")).With(defaultLabelsAndValues...),
 	}
 }
 
@@ -42,6 +68,9 @@ func PrometheusMetrics(namespace string, defaultLabelsAndValues ...string) *Metr
 // for testing.
 func NopMetrics() *Metrics {
 	return &Metrics{
-		MethodTiming: discard.NewHistogram(),
+		MethodTiming:   discard.NewHistogram(),
+		MethodTimeouts: discard.NewCounter(),
+		MethodRejected: discard.NewCounter(),
+		BreakerOpen:    discard.NewGauge(),
 	}
 }
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"os"
 	"syscall"
+	"time"
 
 	abciclient "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/libs/service"
 )
@@ -34,9 +36,24 @@ type AppConns interface {
 	Snapshot() AppConnSnapshot
 }
 
+// AppConnsOption sets an optional parameter on the AppConns.
+type AppConnsOption func(*multiAppConn)
+
+// WithConnConfig applies the call timeouts and circuit breaker settings of
+// cfg to the query, mempool and snapshot connections. Calls on the consensus
+// connection are never bounded.
+func WithConnConfig(cfg *config.ABCIConnConfig) AppConnsOption {
+	return func(app *multiAppConn) { app.cfg = cfg }
+}
+
 // NewAppConns calls NewMultiAppConn.
-func NewAppConns(clientCreator abciclient.Creator, logger log.Logger, metrics *Metrics) AppConns {
-	return NewMultiAppConn(clientCreator, logger, metrics)
+func NewAppConns(
+	clientCreator abciclient.Creator,
+	logger log.Logger,
+	metrics *Metrics,
+	options ...AppConnsOption,
+) AppConns {
+	return NewMultiAppConn(clientCreator, logger, metrics, options...)
 }
 
 // multiAppConn implements AppConns.
@@ -49,6 +66,7 @@ type multiAppConn struct {
 	logger log.Logger
 
 	metrics       *Metrics
+	cfg           *config.ABCIConnConfig
 	consensusConn AppConnConsensus
 	mempoolConn   AppConnMempool
 	queryConn     AppConnQuery
@@ -56,7 +74,7 @@ type multiAppConn struct {
 
 	consensusConnClient stoppableClient
 	mempoolConnClient   stoppableClient
-	queryConnClient     stoppableClient
+	queryConnClients    []stoppableClient
 	snapshotConnClient  stoppableClient
 
 	clientCreator abciclient.Creator
@@ -71,12 +89,20 @@ type stoppableClient interface {
 }
 
 // NewMultiAppConn makes all necessary abci connections to the application.
-func NewMultiAppConn(clientCreator abciclient.Creator, logger log.Logger, metrics *Metrics) AppConns {
+func NewMultiAppConn(
+	clientCreator abciclient.Creator,
+	logger log.Logger,
+	metrics *Metrics,
+	options ...AppConnsOption,
+) AppConns {
 	multiAppConn := &multiAppConn{
 		logger:        logger,
 		metrics:       metrics,
 		clientCreator: clientCreator,
 	}
+	for _, option := range options {
+		option(multiAppConn)
+	}
 	multiAppConn.BaseService = *service.NewBaseService(logger, "multiAppConn", multiAppConn)
 	return multiAppConn
 }
@@ -98,20 +124,48 @@ func (app *multiAppConn) Snapshot() AppConnSnapshot {
 }
 
 func (app *multiAppConn) OnStart(ctx context.Context) error {
-	c, err := app.abciClientFor(ctx, connQuery)
-	if err != nil {
-		return err
+	numQueryConns := 1
+	if app.cfg != nil && app.cfg.QueryConnections > 1 {
+		numQueryConns = app.cfg.QueryConnections
+	}
+
+	// all query connections share a policy, so that an unresponsive
+	// application trips a single breaker
+	queryPolicy := app.callPolicyFor(connQuery)
+	queryConns := make([]AppConnQuery, 0, numQueryConns)
+	for i := 0; i < numQueryConns; i++ {
+		c, err := app.abciClientFor(ctx, connQuery)
+		if err != nil {
+			app.stopAllClients()
+			return err
+		}
+		app.queryConnClients = append(app.queryConnClients, c.(stoppableClient))
+		queryConns = append(queryConns, newAppConnQuery(c, app.metrics, queryPolicy))
+
+		// The clients of a local application share its lock, so spreading
+		// calls over several of them would gain nothing.
+		if i == 0 && numQueryConns > 1 && abciclient.IsLocalClient(c) {
+			app.logger.Info("ignoring query-connections for local application", "query-connections", numQueryConns)
+			break
+		}
+	}
+	if queryPolicy != nil {
+		// a slow query only holds up its own connection
+		queryPolicy.maxAbandoned = int32(len(queryConns))
+	}
+	if len(queryConns) == 1 {
+		app.queryConn = queryConns[0]
+	} else {
+		app.queryConn = newQueryConnPool(queryConns)
 	}
-	app.queryConnClient = c.(stoppableClient)
-	app.queryConn = NewAppConnQuery(c, app.metrics)
 
-	c, err = app.abciClientFor(ctx, connSnapshot)
+	c, err := app.abciClientFor(ctx, connSnapshot)
 	if err != nil {
 		app.stopAllClients()
 		return err
 	}
 	app.snapshotConnClient = c.(stoppableClient)
-	app.snapshotConn = NewAppConnSnapshot(c, app.metrics)
+	app.snapshotConn = newAppConnSnapshot(c, app.metrics, app.callPolicyFor(connSnapshot))
 
 	c, err = app.abciClientFor(ctx, connMempool)
 	if err != nil {
@@ -119,7 +173,7 @@ func (app *multiAppConn) OnStart(ctx context.Context) error {
 		return err
 	}
 	app.mempoolConnClient = c.(stoppableClient)
-	app.mempoolConn = NewAppConnMempool(c, app.metrics)
+	app.mempoolConn = newAppConnMempool(c, app.metrics, app.callPolicyFor(connMempool))
 
 	c, err = app.abciClientFor(ctx, connConsensus)
 	if err != nil {
@@ -159,7 +213,7 @@ func (app *multiAppConn) startWatchersForClientErrorToKillTendermint(ctx context
 		name       string
 	}
 
-	for _, client := range []op{
+	clients := []op{
 		{
 			connClient: app.consensusConnClient,
 			name:       connConsensus,
@@ -168,15 +222,19 @@ func (app *multiAppConn) startWatchersForClientErrorToKillTendermint(ctx context
 			connClient: app.mempoolConnClient,
 			name:       connMempool,
 		},
-		{
-			connClient: app.queryConnClient,
-			name:       connQuery,
-		},
 		{
 			connClient: app.snapshotConnClient,
 			name:       connSnapshot,
 		},
-	} {
+	}
+	for _, queryConnClient := range app.queryConnClients {
+		clients = append(clients, op{
+			connClient: queryConnClient,
+			name:       connQuery,
+		})
+	}
+
+	for _, client := range clients {
 		go func(name string, client stoppableClient) {
 			client.Wait()
 			if ctx.Err() != nil {
@@ -204,8 +262,8 @@ func (app *multiAppConn) stopAllClients() {
 			}
 		}
 	}
-	if app.queryConnClient != nil {
-		if err := app.queryConnClient.Stop(); err != nil {
+	for _, queryConnClient := range app.queryConnClients {
+		if err := queryConnClient.Stop(); err != nil {
 			if !errors.Is(err, service.ErrAlreadyStopped) {
 				app.logger.Error("error while stopping query client", "error", err)
 			}
@@ -220,6 +278,40 @@ func (app *multiAppConn) stopAllClients() {
 	}
 }
 
+// callPolicyFor returns the call policy configured for the given connection,
+// or nil if calls on it are unrestricted.
+func (app *multiAppConn) callPolicyFor(conn string) *callPolicy {
+	if app.cfg == nil {
+		return nil
+	}
+
+	var timeout time.Duration
+	switch conn {
+	case connQuery:
+		timeout = app.cfg.QueryTimeout
+	case connMempool:
+		timeout = app.cfg.CheckTxTimeout
+	case connSnapshot:
+		timeout = app.cfg.SnapshotTimeout
+	default:
+		return nil
+	}
+
+	breakerOpen := app.metrics.BreakerOpen.With("connection", conn)
+	breaker := newCircuitBreaker(app.cfg.BreakerThreshold, app.cfg.BreakerCooldown, func(open bool) {
+		if open {
+			breakerOpen.Set(1)
+			app.logger.Error("ABCI application is unresponsive, rejecting calls",
+				"connection", conn, "cooldown", app.cfg.BreakerCooldown)
+			return
+		}
+		breakerOpen.Set(0)
+		app.logger.Info("ABCI application is responsive again", "connection", conn)
+	})
+
+	return newCallPolicy(conn, timeout, app.cfg.MaxAbandonedCalls, breaker, app.metrics)
+}
+
 func (app *multiAppConn) abciClientFor(ctx context.Context, conn string) (abciclient.Client, error) {
 	c, err := app.clientCreator(app.logger.With(
 		"module", "abci-client",
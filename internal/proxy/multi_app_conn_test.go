@@ -15,6 +15,8 @@ import (
 
 	abciclient "github.com/tendermint/tendermint/abci/client"
 	abcimocks "github.com/tendermint/tendermint/abci/client/mocks"
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/libs/log"
 )
 
@@ -56,6 +58,65 @@ func TestAppConns_Start_Stop(t *testing.T) {
 	assert.Equal(t, 4, creatorCallCount)
 }
 
+func TestAppConns_QueryConnections(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clientMock := &abcimocks.Client{}
+	clientMock.On("Start", mock.Anything).Return(nil).Times(6)
+	clientMock.On("Error").Return(nil)
+	clientMock.On("Wait").Return(nil).Times(6)
+	cl := &noopStoppableClientImpl{Client: clientMock}
+
+	creatorCallCount := 0
+	creator := func(logger log.Logger) (abciclient.Client, error) {
+		creatorCallCount++
+		return cl, nil
+	}
+
+	cfg := config.TestABCIConnConfig()
+	cfg.QueryConnections = 3
+	appConns := NewAppConns(creator, log.TestingLogger(), NopMetrics(), WithConnConfig(cfg))
+
+	err := appConns.Start(ctx)
+	require.NoError(t, err)
+	require.IsType(t, &queryConnPool{}, appConns.Query())
+
+	time.Sleep(100 * time.Millisecond)
+
+	cancel()
+	appConns.Wait()
+
+	clientMock.AssertExpectations(t)
+	assert.Equal(t, 6, cl.count)
+	assert.Equal(t, 6, creatorCallCount)
+}
+
+func TestAppConns_QueryConnectionsLocal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	creatorCallCount := 0
+	localCreator := abciclient.NewLocalCreator(types.NewBaseApplication())
+	creator := func(logger log.Logger) (abciclient.Client, error) {
+		creatorCallCount++
+		return localCreator(logger)
+	}
+
+	cfg := config.TestABCIConnConfig()
+	cfg.QueryConnections = 3
+	appConns := NewAppConns(creator, log.TestingLogger(), NopMetrics(), WithConnConfig(cfg))
+
+	// the clients of a local application share a lock, so a single query
+	// connection is used
+	require.NoError(t, appConns.Start(ctx))
+	require.IsType(t, &appConnQuery{}, appConns.Query())
+	assert.Equal(t, 4, creatorCallCount)
+
+	cancel()
+	appConns.Wait()
+}
+
 // Upon failure, we call tmos.Kill
 func TestAppConns_Failure(t *testing.T) {
 	ok := make(chan struct{})
@@ -0,0 +1,243 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+)
+
+var (
+	// ErrAppTimeout is returned when the application does not answer a call
+	// within the timeout configured for its connection.
+	ErrAppTimeout = errors.New("abci call timed out")
+
+	// ErrAppUnresponsive is returned without calling the application while
+	// the circuit breaker of a connection is open.
+	ErrAppUnresponsive = errors.New("abci application is unresponsive")
+)
+
+// callPolicy bounds the duration of calls made on a single connection and,
+// if it has a breaker, rejects calls while the application is considered
+// unresponsive. A nil *callPolicy places no restriction on calls.
+type callPolicy struct {
+	conn    string
+	timeout time.Duration
+	breaker *circuitBreaker
+	metrics *Metrics
+
+	// maxAbandoned is the number of calls run may have given up on while
+	// they are still running. Further calls are rejected until one of them
+	// returns, so that abandoned calls cannot pile up behind an application
+	// that does not answer. It must be positive.
+	maxAbandoned int32
+	abandoned    int32 // accessed atomically
+}
+
+func newCallPolicy(
+	conn string,
+	timeout time.Duration,
+	maxAbandoned int,
+	breaker *circuitBreaker,
+	metrics *Metrics,
+) *callPolicy {
+	return &callPolicy{
+		conn:         conn,
+		timeout:      timeout,
+		breaker:      breaker,
+		metrics:      metrics,
+		maxAbandoned: int32(maxAbandoned),
+	}
+}
+
+// run calls fn under the policy. The context passed to fn carries the
+// policy's deadline, if any. Since not every client honors context
+// cancellation (the local client does not), fn runs in its own goroutine
+// when a timeout is set and is abandoned if it does not return in time;
+// callers must not read results written by fn unless run returns nil.
+func (p *callPolicy) run(ctx context.Context, method string, fn func(context.Context) error) error {
+	if p == nil {
+		return fn(ctx)
+	}
+	if err := p.admit(method); err != nil {
+		return err
+	}
+	if p.timeout <= 0 {
+		return p.runUnbounded(ctx, fn)
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- fn(cctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-cctx.Done():
+		err = cctx.Err()
+		atomic.AddInt32(&p.abandoned, 1)
+		go func() {
+			<-errCh
+			atomic.AddInt32(&p.abandoned, -1)
+		}()
+	}
+
+	// Only our own deadline counts against the application. The caller
+	// giving up on the call tells nothing about it either way.
+	switch {
+	case err == nil:
+		p.breaker.record(true)
+	case ctx.Err() != nil:
+		p.breaker.release()
+	case errors.Is(cctx.Err(), context.DeadlineExceeded):
+		p.metrics.MethodTimeouts.With("method", method, "connection", p.conn).Add(1)
+		p.breaker.record(false)
+		return fmt.Errorf("%s on %s connection after %v: %w", method, p.conn, p.timeout, ErrAppTimeout)
+	default:
+		p.breaker.record(true)
+	}
+	return err
+}
+
+// runStream calls fn, which opens a streamed response, under the policy.
+// Unlike run it applies no timeout, since the duration of a streamed call
+// depends on how fast the caller reads the response, which the caller bounds
+// with ctx.
+func (p *callPolicy) runStream(ctx context.Context, method string, fn func(context.Context) error) error {
+	if p == nil {
+		return fn(ctx)
+	}
+	if err := p.admit(method); err != nil {
+		return err
+	}
+	return p.runUnbounded(ctx, fn)
+}
+
+// admit returns an error if a call must be rejected without calling the
+// application.
+func (p *callPolicy) admit(method string) error {
+	if n := atomic.LoadInt32(&p.abandoned); n >= p.maxAbandoned {
+		p.metrics.MethodRejected.With("method", method, "connection", p.conn).Add(1)
+		return fmt.Errorf("%s on %s connection: %d abandoned calls still running: %w",
+			method, p.conn, n, ErrAppUnresponsive)
+	}
+	if !p.breaker.allow() {
+		p.metrics.MethodRejected.With("method", method, "connection", p.conn).Add(1)
+		return fmt.Errorf("%s on %s connection: %w", method, p.conn, ErrAppUnresponsive)
+	}
+	return nil
+}
+
+// runUnbounded calls fn once admitted, without a timeout.
+func (p *callPolicy) runUnbounded(ctx context.Context, fn func(context.Context) error) error {
+	err := fn(ctx)
+	if err != nil && ctx.Err() != nil {
+		p.breaker.release()
+	} else {
+		p.breaker.record(true)
+	}
+	return err
+}
+
+// circuitBreaker tracks consecutive timeouts on a connection. Once threshold
+// is reached the breaker opens and rejects all calls. Since only timeouts are
+// failures, a breaker never opens on a connection without a timeout. After
+// cooldown a single probe call is let through: if it succeeds the breaker
+// closes, otherwise it stays open for another cooldown. A nil
+// *circuitBreaker allows every call.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	onChange  func(open bool)
+	now       func() time.Time
+
+	mtx      tmsync.Mutex
+	failures int
+	open     bool
+	probing  bool
+	openedAt time.Time
+}
+
+// newCircuitBreaker returns a breaker opening after threshold consecutive
+// failures, or nil if threshold is not positive. onChange, if set, is
+// called with the breaker's lock held whenever it opens or closes.
+func newCircuitBreaker(threshold int, cooldown time.Duration, onChange func(open bool)) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		onChange:  onChange,
+		now:       time.Now,
+	}
+}
+
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if !b.open {
+		return true
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *circuitBreaker) record(ok bool) {
+	if b == nil {
+		return
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if ok {
+		b.failures = 0
+		if b.open {
+			b.open = false
+			b.probing = false
+			b.notify()
+		}
+		return
+	}
+
+	b.failures++
+	switch {
+	case b.open:
+		// the probe failed, wait for another cooldown
+		b.probing = false
+		b.openedAt = b.now()
+	case b.failures >= b.threshold:
+		b.open = true
+		b.openedAt = b.now()
+		b.notify()
+	}
+}
+
+// release ends a call that neither succeeded nor failed, e.g. because the
+// caller gave up on it. If it was the probe, another probe is let through.
+func (b *circuitBreaker) release() {
+	if b == nil {
+		return
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.probing = false
+}
+
+func (b *circuitBreaker) notify() {
+	if b.onChange != nil {
+		b.onChange(b.open)
+	}
+}
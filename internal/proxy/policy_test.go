@@ -0,0 +1,234 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abcimocks "github.com/tendermint/tendermint/abci/client/mocks"
+	"github.com/tendermint/tendermint/abci/types"
+)
+
+func TestCallPolicy_Timeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	client := &abcimocks.Client{}
+	client.On("QuerySync", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).
+		Return(&types.ResponseQuery{}, nil)
+
+	policy := newCallPolicy(connQuery, 50*time.Millisecond, 1, nil, NopMetrics())
+	conn := newAppConnQuery(client, NopMetrics(), policy)
+
+	start := time.Now()
+	res, err := conn.QuerySync(ctx, types.RequestQuery{})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrAppTimeout))
+	assert.Nil(t, res)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestCallPolicy_CallerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	release := make(chan struct{})
+	defer close(release)
+
+	client := &abcimocks.Client{}
+	client.On("QuerySync", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).
+		Return(&types.ResponseQuery{}, nil)
+
+	breaker := newCircuitBreaker(1, time.Minute, nil)
+	policy := newCallPolicy(connQuery, time.Minute, 1, breaker, NopMetrics())
+	conn := newAppConnQuery(client, NopMetrics(), policy)
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err := conn.QuerySync(ctx, types.RequestQuery{})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// giving up on a call is not the application's fault
+	assert.True(t, breaker.allow())
+}
+
+func TestCallPolicy_CallerCancelProbe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &abcimocks.Client{}
+	client.On("QuerySync", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+		Return(nil, context.Canceled)
+
+	now := time.Now()
+	breaker := newCircuitBreaker(1, time.Second, nil)
+	breaker.now = func() time.Time { return now }
+	breaker.record(false)
+	now = now.Add(time.Second)
+
+	for _, timeout := range []time.Duration{0, time.Minute} {
+		ctx, cancel := context.WithCancel(ctx)
+		policy := newCallPolicy(connQuery, timeout, 1, breaker, NopMetrics())
+		conn := newAppConnQuery(client, NopMetrics(), policy)
+
+		// the probe is abandoned by the caller, which must neither close
+		// the breaker nor restart its cooldown
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err := conn.QuerySync(ctx, types.RequestQuery{})
+		require.True(t, errors.Is(err, context.Canceled), "timeout %v", timeout)
+		require.True(t, breaker.allow(), "timeout %v", timeout)
+		require.False(t, breaker.allow(), "timeout %v", timeout)
+		breaker.release()
+	}
+}
+
+func TestCallPolicy_AbandonedCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// every send lets one blocked call return
+	release := make(chan struct{})
+
+	client := &abcimocks.Client{}
+	client.On("QuerySync", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).
+		Return(&types.ResponseQuery{}, nil)
+
+	policy := newCallPolicy(connQuery, 20*time.Millisecond, 2, nil, NopMetrics())
+	conn := newAppConnQuery(client, NopMetrics(), policy)
+
+	for i := 0; i < 2; i++ {
+		_, err := conn.QuerySync(ctx, types.RequestQuery{})
+		require.True(t, errors.Is(err, ErrAppTimeout))
+	}
+
+	// the timed out calls are still running, so the next one is rejected
+	// without reaching the application
+	_, err := conn.QuerySync(ctx, types.RequestQuery{})
+	require.True(t, errors.Is(err, ErrAppUnresponsive))
+	client.AssertNumberOfCalls(t, "QuerySync", 2)
+
+	// once one of them returns, a single call is let through again
+	release <- struct{}{}
+	require.Eventually(t, func() bool {
+		_, err := conn.QuerySync(ctx, types.RequestQuery{})
+		return errors.Is(err, ErrAppTimeout)
+	}, time.Second, 10*time.Millisecond)
+	client.AssertNumberOfCalls(t, "QuerySync", 3)
+	_, err = conn.QuerySync(ctx, types.RequestQuery{})
+	require.True(t, errors.Is(err, ErrAppUnresponsive))
+
+	// once all of them return, calls succeed again
+	close(release)
+	require.Eventually(t, func() bool {
+		_, err := conn.QuerySync(ctx, types.RequestQuery{})
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&policy.abandoned) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCallPolicy_StreamWithoutTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := &abcimocks.Client{}
+	client.On("LoadSnapshotChunkStream", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { time.Sleep(50 * time.Millisecond) }).
+		Return(io.NopCloser(bytes.NewReader([]byte{1})), nil)
+
+	// a streamed chunk takes as long as it takes to read it, so the call
+	// timeout of the connection does not apply
+	policy := newCallPolicy(connSnapshot, 10*time.Millisecond, 1, nil, NopMetrics())
+	conn := newAppConnSnapshot(client, NopMetrics(), policy)
+
+	body, err := conn.LoadSnapshotChunkStream(ctx, types.RequestLoadSnapshotChunk{})
+	require.NoError(t, err)
+	chunk, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, chunk)
+	require.NoError(t, body.Close())
+}
+
+func TestCallPolicy_BreakerFailsFast(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	client := &abcimocks.Client{}
+	client.On("CheckTxSync", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).
+		Return(&types.ResponseCheckTx{}, nil).
+		Times(2)
+
+	breaker := newCircuitBreaker(2, time.Minute, nil)
+	policy := newCallPolicy(connMempool, 10*time.Millisecond, 2, breaker, NopMetrics())
+	conn := newAppConnMempool(client, NopMetrics(), policy)
+
+	for i := 0; i < 2; i++ {
+		_, err := conn.CheckTxSync(ctx, types.RequestCheckTx{})
+		require.True(t, errors.Is(err, ErrAppTimeout))
+	}
+
+	// the breaker is open, the application must not be called again
+	_, err := conn.CheckTxSync(ctx, types.RequestCheckTx{})
+	require.True(t, errors.Is(err, ErrAppUnresponsive))
+	client.AssertNumberOfCalls(t, "CheckTxSync", 2)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	var states []bool
+
+	b := newCircuitBreaker(2, time.Second, func(open bool) { states = append(states, open) })
+	b.now = func() time.Time { return now }
+
+	// a success resets the failure count
+	b.record(false)
+	b.record(true)
+	b.record(false)
+	require.True(t, b.allow())
+
+	b.record(false)
+	require.False(t, b.allow())
+	require.Equal(t, []bool{true}, states)
+
+	// a single probe is allowed after the cooldown
+	now = now.Add(time.Second)
+	require.True(t, b.allow())
+	require.False(t, b.allow())
+
+	// a failed probe restarts the cooldown
+	b.record(false)
+	require.False(t, b.allow())
+	now = now.Add(time.Second)
+	require.True(t, b.allow())
+
+	// a successful probe closes the breaker
+	b.record(true)
+	require.True(t, b.allow())
+	require.True(t, b.allow())
+	require.Equal(t, []bool{true, false}, states)
+
+	// no breaker allows everything
+	var nilBreaker *circuitBreaker
+	require.True(t, nilBreaker.allow())
+	nilBreaker.record(false)
+	require.Nil(t, newCircuitBreaker(0, time.Second, nil))
+}
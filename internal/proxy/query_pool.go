@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tendermint/tendermint/abci/types"
+)
+
+// queryConnPool implements AppConnQuery over several query connections. Each
+// call goes to the connection with the fewest calls in flight, so that a slow
+// query only holds up the calls queued behind it on its own connection.
+type queryConnPool struct {
+	conns    []AppConnQuery
+	inflight []int32
+	next     uint32
+}
+
+var _ AppConnQuery = (*queryConnPool)(nil)
+
+func newQueryConnPool(conns []AppConnQuery) *queryConnPool {
+	return &queryConnPool{
+		conns:    conns,
+		inflight: make([]int32, len(conns)),
+	}
+}
+
+// acquire picks the least busy connection, starting the scan at a rotating
+// offset so that idle connections are used in turn, and returns it along
+// with the function to call once the call completed.
+func (p *queryConnPool) acquire() (AppConnQuery, func()) {
+	n := len(p.conns)
+	start := int(atomic.AddUint32(&p.next, 1) % uint32(n))
+
+	best := start
+	for i := 1; i < n; i++ {
+		idx := (start + i) % n
+		if atomic.LoadInt32(&p.inflight[idx]) < atomic.LoadInt32(&p.inflight[best]) {
+			best = idx
+		}
+	}
+
+	atomic.AddInt32(&p.inflight[best], 1)
+	return p.conns[best], func() { atomic.AddInt32(&p.inflight[best], -1) }
+}
+
+func (p *queryConnPool) Error() error {
+	for _, conn := range p.conns {
+		if err := conn.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *queryConnPool) EchoSync(ctx context.Context, msg string) (*types.ResponseEcho, error) {
+	conn, release := p.acquire()
+	defer release()
+	return conn.EchoSync(ctx, msg)
+}
+
+func (p *queryConnPool) InfoSync(ctx context.Context, req types.RequestInfo) (*types.ResponseInfo, error) {
+	conn, release := p.acquire()
+	defer release()
+	return conn.InfoSync(ctx, req)
+}
+
+func (p *queryConnPool) QuerySync(ctx context.Context, req types.RequestQuery) (*types.ResponseQuery, error) {
+	conn, release := p.acquire()
+	defer release()
+	return conn.QuerySync(ctx, req)
+}
+
+// QueryStream counts the streamed query as in flight on its connection until
+// the response is closed, since it occupies the connection until then.
+func (p *queryConnPool) QueryStream(
+	ctx context.Context,
+	req types.RequestQuery,
+) (*types.ResponseQuery, io.ReadCloser, error) {
+	conn, release := p.acquire()
+	res, body, err := conn.QueryStream(ctx, req)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return res, &releasingReader{ReadCloser: body, release: release}, nil
+}
+
+// releasingReader calls release once it's closed.
+type releasingReader struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (r *releasingReader) Close() error {
+	r.once.Do(r.release)
+	return r.ReadCloser.Close()
+}
@@ -0,0 +1,84 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/internal/proxy/mocks"
+)
+
+func TestQueryConnPool_SlowQueryDoesNotBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	slow := &mocks.AppConnQuery{}
+	slow.On("QuerySync", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { close(started); <-release }).
+		Return(&types.ResponseQuery{Code: 1}, nil).
+		Once()
+
+	fast := &mocks.AppConnQuery{}
+	fast.On("QuerySync", mock.Anything, mock.Anything).
+		Return(&types.ResponseQuery{Code: 2}, nil)
+
+	pool := newQueryConnPool([]AppConnQuery{slow, fast})
+	// make the first call land on the slow connection
+	pool.next = 1
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		res, err := pool.QuerySync(ctx, types.RequestQuery{})
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, res.Code)
+	}()
+	<-started
+
+	// while the slow connection is busy, every call goes to the other one
+	for i := 0; i < 5; i++ {
+		res, err := pool.QuerySync(ctx, types.RequestQuery{})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, res.Code)
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow query did not complete")
+	}
+	fast.AssertNumberOfCalls(t, "QuerySync", 5)
+}
+
+func TestQueryConnPool_StreamHoldsConnection(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conns := make([]AppConnQuery, 2)
+	for i := range conns {
+		conn := &mocks.AppConnQuery{}
+		conn.On("QueryStream", mock.Anything, mock.Anything).
+			Return(&types.ResponseQuery{}, io.NopCloser(bytes.NewReader(nil)), nil)
+		conns[i] = conn
+	}
+	pool := newQueryConnPool(conns)
+
+	// the connection is in flight until the response is closed, and only once
+	_, body, err := pool.QueryStream(ctx, types.RequestQuery{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, pool.inflight[0]+pool.inflight[1])
+	require.NoError(t, body.Close())
+	require.NoError(t, body.Close())
+	assert.EqualValues(t, 0, pool.inflight[0]+pool.inflight[1])
+}
@@ -20,8 +20,10 @@ Available endpoints:
 /dump_consensus_state
 /genesis
 /net_info
+/net_crawl
 /num_unconfirmed_txs
 /status
+/state_sync_status
 /health
 /unconfirmed_txs
 /unsafe_flush_mempool
@@ -37,6 +39,11 @@ Endpoints that require arguments:
 /commit?height=_
 /dial_seeds?seeds=_
 /dial_persistent_peers?persistent_peers=_
+/net_bans
+/unsafe_ban_peer?target=_&duration=_&reason=_
+/unsafe_dial_peer?address=_
+/unsafe_disconnect_peer?peer_id=_
+/unsafe_unban_peer?target=_
 /subscribe?event=_
 /tx?hash=_&prove=_
 /unsubscribe?event=_
@@ -11,6 +11,7 @@ import (
 	"github.com/tendermint/tendermint/internal/eventbus"
 	"github.com/tendermint/tendermint/internal/mempool"
 	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/internal/p2p/pex"
 	"github.com/tendermint/tendermint/internal/proxy"
 	sm "github.com/tendermint/tendermint/internal/state"
 	"github.com/tendermint/tendermint/internal/state/indexer"
@@ -57,9 +58,22 @@ type consensusReactor interface {
 	GetPeerState(peerID types.NodeID) (*consensus.PeerState, bool)
 }
 
+type router interface {
+	PeerTraffic(types.NodeID) []p2p.ChannelTraffic
+}
+
+type crawler interface {
+	Peers() ([]pex.CrawledPeer, time.Time)
+}
+
 type peerManager interface {
 	Peers() []types.NodeID
 	Addresses(types.NodeID) []p2p.NodeAddress
+	Bans() ([]p2p.PeerBan, error)
+	Ban(p2p.PeerBan) error
+	Unban(target string) error
+	DialNow(p2p.NodeAddress) error
+	Disconnect(types.NodeID) error
 }
 
 //----------------------------------------------
@@ -82,6 +96,8 @@ type Environment struct {
 
 	// interfaces for new p2p interfaces
 	PeerManager peerManager
+	Router      router
+	Crawler     crawler // only set on seed nodes
 
 	// objects
 	PubKey            crypto.PubKey
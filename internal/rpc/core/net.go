@@ -3,9 +3,12 @@ package core
 import (
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/rpc/coretypes"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
 )
 
 // NetInfo returns network info.
@@ -20,10 +23,25 @@ func (env *Environment) NetInfo(ctx *rpctypes.Context) (*coretypes.ResultNetInfo
 			continue
 		}
 
-		peers = append(peers, coretypes.Peer{
+		p := coretypes.Peer{
 			ID:  peer,
 			URL: addrs[0].String(),
-		})
+		}
+		if env.Router != nil {
+			for _, traffic := range env.Router.PeerTraffic(peer) {
+				p.Channels = append(p.Channels, coretypes.ChannelTraffic{
+					ChannelID:    uint16(traffic.ChannelID),
+					SendBytes:    traffic.SendBytes,
+					SendMessages: traffic.SendMessages,
+					SendRate:     traffic.SendRate,
+					RecvBytes:    traffic.RecvBytes,
+					RecvMessages: traffic.RecvMessages,
+					RecvRate:     traffic.RecvRate,
+					Dropped:      traffic.Dropped,
+				})
+			}
+		}
+		peers = append(peers, p)
 	}
 
 	return &coretypes.ResultNetInfo{
@@ -34,6 +52,144 @@ func (env *Environment) NetInfo(ctx *rpctypes.Context) (*coretypes.ResultNetInfo
 	}, nil
 }
 
+// NetBans returns the banned node IDs and IP networks.
+// More: https://docs.tendermint.com/master/rpc/#/Info/net_bans
+func (env *Environment) NetBans(ctx *rpctypes.Context) (*coretypes.ResultNetBans, error) {
+	bans, err := env.PeerManager.Bans()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &coretypes.ResultNetBans{Bans: make([]coretypes.PeerBan, 0, len(bans))}
+	for _, ban := range bans {
+		b := coretypes.PeerBan{Target: ban.Target(), Reason: ban.Reason}
+		if !ban.Until.IsZero() {
+			until := ban.Until
+			b.Until = &until
+		}
+		result.Bans = append(result.Bans, b)
+	}
+	return result, nil
+}
+
+// NetCrawl returns the peers recorded by the last network crawl. It is only
+// available on seed nodes.
+// More: https://docs.tendermint.com/master/rpc/#/Info/net_crawl
+func (env *Environment) NetCrawl(ctx *rpctypes.Context) (*coretypes.ResultNetCrawl, error) {
+	if env.Crawler == nil {
+		return nil, errors.New("network crawls are only available on seed nodes with crawling enabled")
+	}
+
+	peers, lastCrawl := env.Crawler.Peers()
+	result := &coretypes.ResultNetCrawl{Peers: make([]coretypes.CrawledPeer, 0, len(peers))}
+	if !lastCrawl.IsZero() {
+		result.LastCrawl = &lastCrawl
+	}
+	for _, peer := range peers {
+		p := coretypes.CrawledPeer{
+			ID:        peer.NodeID,
+			Addresses: make([]coretypes.CrawledAddress, 0, len(peer.Addresses)),
+			Connected: peer.Connected,
+			Reachable: peer.Reachable,
+			LastSeen:  optionalTime(peer.LastSeen),
+			Height:    peer.Height,
+		}
+		for _, address := range peer.Addresses {
+			p.Addresses = append(p.Addresses, coretypes.CrawledAddress{
+				URL:             address.Address.String(),
+				LastDialSuccess: optionalTime(address.LastDialSuccess),
+				LastDialFailure: optionalTime(address.LastDialFailure),
+				DialFailures:    address.DialFailures,
+			})
+		}
+		if peer.NodeInfo != nil {
+			protocolVersion := peer.NodeInfo.ProtocolVersion
+			p.Version = peer.NodeInfo.Version
+			p.ProtocolVersion = &protocolVersion
+			p.Network = peer.NodeInfo.Network
+			p.Channels = peer.NodeInfo.Channels
+			p.Moniker = peer.NodeInfo.Moniker
+		}
+		if peer.Reachable {
+			result.NReachable++
+		}
+		result.Peers = append(result.Peers, p)
+	}
+	result.NPeers = len(result.Peers)
+	return result, nil
+}
+
+// optionalTime returns a pointer to t, or nil if t is zero.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// UnsafeBanPeer bans a node ID, IP address or CIDR network for the given
+// duration (e.g. "24h"), or indefinitely if no duration is given. Connected
+// peers matching the ban are disconnected.
+func (env *Environment) UnsafeBanPeer(
+	ctx *rpctypes.Context,
+	target, duration, reason string,
+) (*coretypes.ResultUnsafeBanPeer, error) {
+	var d time.Duration
+	if duration != "" {
+		var err error
+		if d, err = time.ParseDuration(duration); err != nil {
+			return nil, fmt.Errorf("invalid duration %q: %w", duration, err)
+		}
+	}
+	ban, err := p2p.ParsePeerBan(target, d, reason)
+	if err != nil {
+		return nil, err
+	}
+	if err := env.PeerManager.Ban(ban); err != nil {
+		return nil, err
+	}
+	return &coretypes.ResultUnsafeBanPeer{}, nil
+}
+
+// UnsafeUnbanPeer removes the ban of a node ID, IP address or CIDR network.
+func (env *Environment) UnsafeUnbanPeer(ctx *rpctypes.Context, target string) (*coretypes.ResultUnsafeUnbanPeer, error) {
+	if err := env.PeerManager.Unban(target); err != nil {
+		return nil, err
+	}
+	return &coretypes.ResultUnsafeUnbanPeer{}, nil
+}
+
+// UnsafeDialPeer dials a peer address (e.g. "<node_id>@host:port") right away,
+// ignoring any retry backoff from earlier failed dials. If all connection
+// slots are in use, the lowest-scored non-persistent peer is evicted to make
+// room.
+func (env *Environment) UnsafeDialPeer(ctx *rpctypes.Context, address string) (*coretypes.ResultUnsafeDialPeer, error) {
+	addr, err := p2p.ParseNodeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if err := env.PeerManager.DialNow(addr); err != nil {
+		return nil, err
+	}
+	return &coretypes.ResultUnsafeDialPeer{}, nil
+}
+
+// UnsafeDisconnectPeer disconnects a connected peer. The peer may reconnect
+// later unless it is banned.
+func (env *Environment) UnsafeDisconnectPeer(
+	ctx *rpctypes.Context,
+	peerID string,
+) (*coretypes.ResultUnsafeDisconnectPeer, error) {
+	id, err := types.NewNodeID(peerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := env.PeerManager.Disconnect(id); err != nil {
+		return nil, err
+	}
+	return &coretypes.ResultUnsafeDisconnectPeer{}, nil
+}
+
 // Genesis returns genesis file.
 // More: https://docs.tendermint.com/master/rpc/#/Info/genesis
 func (env *Environment) Genesis(ctx *rpctypes.Context) (*coretypes.ResultGenesis, error) {
@@ -19,7 +19,10 @@ func (env *Environment) GetRoutes() RoutesMap {
 		// info API
 		"health":               rpc.NewRPCFunc(env.Health, "", false),
 		"status":               rpc.NewRPCFunc(env.Status, "", false),
+		"state_sync_status":    rpc.NewRPCFunc(env.StateSyncStatus, "", false),
 		"net_info":             rpc.NewRPCFunc(env.NetInfo, "", false),
+		"net_bans":             rpc.NewRPCFunc(env.NetBans, "", false),
+		"net_crawl":            rpc.NewRPCFunc(env.NetCrawl, "", false),
 		"blockchain":           rpc.NewRPCFunc(env.BlockchainInfo, "minHeight,maxHeight", true),
 		"genesis":              rpc.NewRPCFunc(env.Genesis, "", true),
 		"genesis_chunked":      rpc.NewRPCFunc(env.GenesisChunked, "chunk", true),
@@ -55,8 +58,25 @@ func (env *Environment) GetRoutes() RoutesMap {
 	}
 }
 
+// GetSeedRoutes returns the routes served by seed nodes, which have no state
+// and only run the PEX reactor.
+func (env *Environment) GetSeedRoutes() RoutesMap {
+	return RoutesMap{
+		"health":    rpc.NewRPCFunc(env.Health, "", false),
+		"net_info":  rpc.NewRPCFunc(env.NetInfo, "", false),
+		"net_bans":  rpc.NewRPCFunc(env.NetBans, "", false),
+		"net_crawl": rpc.NewRPCFunc(env.NetCrawl, "", false),
+	}
+}
+
 // AddUnsafeRoutes adds unsafe routes.
 func (env *Environment) AddUnsafe(routes RoutesMap) {
 	// control API
 	routes["unsafe_flush_mempool"] = rpc.NewRPCFunc(env.UnsafeFlushMempool, "", false)
+
+	// peer management API
+	routes["unsafe_ban_peer"] = rpc.NewRPCFunc(env.UnsafeBanPeer, "target,duration,reason", false)
+	routes["unsafe_unban_peer"] = rpc.NewRPCFunc(env.UnsafeUnbanPeer, "target", false)
+	routes["unsafe_dial_peer"] = rpc.NewRPCFunc(env.UnsafeDialPeer, "address", false)
+	routes["unsafe_disconnect_peer"] = rpc.NewRPCFunc(env.UnsafeDisconnectPeer, "peer_id", false)
 }
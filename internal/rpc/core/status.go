@@ -86,11 +86,22 @@ func (env *Environment) Status(ctx *rpctypes.Context) (*coretypes.ResultStatus,
 		result.SyncInfo.SnapshotChunksTotal = env.StateSyncMetricer.SnapshotChunksTotal()
 		result.SyncInfo.BackFilledBlocks = env.StateSyncMetricer.BackFilledBlocks()
 		result.SyncInfo.BackFillBlocksTotal = env.StateSyncMetricer.BackFillBlocksTotal()
+		result.SyncInfo.StateSync = env.StateSyncMetricer.Progress()
 	}
 
 	return result, nil
 }
 
+// StateSyncStatus returns the progress of the current or last state sync:
+// the snapshots discovered, the chunks fetched and applied, per-peer
+// throughput, the blocks backfilled and the estimated remaining time.
+func (env *Environment) StateSyncStatus(ctx *rpctypes.Context) (*coretypes.ResultStateSyncStatus, error) {
+	if env.StateSyncMetricer == nil {
+		return &coretypes.ResultStateSyncStatus{}, nil
+	}
+	return &coretypes.ResultStateSyncStatus{Progress: env.StateSyncMetricer.Progress()}, nil
+}
+
 func (env *Environment) validatorAtHeight(h int64) *types.Validator {
 	valsWithH, err := env.StateStore.LoadValidators(h)
 	if err != nil {
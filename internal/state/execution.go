@@ -44,8 +44,9 @@ type BlockExecutor struct {
 	logger  log.Logger
 	metrics *Metrics
 
-	// cache the verification results over a single height
-	cache map[string]struct{}
+	// cache the verification results over a single height, and whether the
+	// signatures of the block's last commit were verified
+	cache map[string]bool
 }
 
 type BlockExecutorOption func(executor *BlockExecutor)
@@ -75,7 +76,7 @@ func NewBlockExecutor(
 		evpool:     evpool,
 		logger:     logger,
 		metrics:    NopMetrics(),
-		cache:      make(map[string]struct{}),
+		cache:      make(map[string]bool),
 		blockStore: blockStore,
 	}
 
@@ -124,12 +125,25 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 // Validation does not mutate state, but does require historical information from the stateDB,
 // ie. to verify evidence from a validator at an old height.
 func (blockExec *BlockExecutor) ValidateBlock(state State, block *types.Block) error {
+	return blockExec.validateBlock(state, block, true)
+}
+
+// ValidateTrustedBlock validates the given block against the given state like
+// ValidateBlock, except for the signatures of its last commit. It is meant for
+// blocks whose hash is trusted by other means, e.g. a hash chain from a trusted
+// header, which also covers the last commit. ApplyBlock doesn't validate the
+// block again.
+func (blockExec *BlockExecutor) ValidateTrustedBlock(state State, block *types.Block) error {
+	return blockExec.validateBlock(state, block, false)
+}
+
+func (blockExec *BlockExecutor) validateBlock(state State, block *types.Block, verifyLastCommit bool) error {
 	hash := block.Hash()
-	if _, ok := blockExec.cache[hash.String()]; ok {
+	if verified, ok := blockExec.cache[hash.String()]; ok && (verified || !verifyLastCommit) {
 		return nil
 	}
 
-	err := validateBlock(state, block)
+	err := validateBlock(state, block, verifyLastCommit)
 	if err != nil {
 		return err
 	}
@@ -139,7 +153,7 @@ func (blockExec *BlockExecutor) ValidateBlock(state State, block *types.Block) e
 		return err
 	}
 
-	blockExec.cache[hash.String()] = struct{}{}
+	blockExec.cache[hash.String()] = verifyLastCommit
 	return nil
 }
 
@@ -156,9 +170,11 @@ func (blockExec *BlockExecutor) ApplyBlock(
 	block *types.Block,
 ) (State, error) {
 
-	// validate the block if we haven't already
-	if err := blockExec.ValidateBlock(state, block); err != nil {
-		return state, ErrInvalidBlock(err)
+	// validate the block if we haven't already, either fully or as trusted
+	if _, ok := blockExec.cache[block.Hash().String()]; !ok {
+		if err := blockExec.ValidateBlock(state, block); err != nil {
+			return state, ErrInvalidBlock(err)
+		}
 	}
 
 	startTime := time.Now().UnixNano()
@@ -231,7 +247,7 @@ func (blockExec *BlockExecutor) ApplyBlock(
 	}
 
 	// reset the verification cache
-	blockExec.cache = make(map[string]struct{})
+	blockExec.cache = make(map[string]bool)
 
 	// Events are fired after everything else.
 	// NOTE: if we crash between Commit and Save, events wont be fired during replay
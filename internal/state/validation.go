@@ -11,7 +11,9 @@ import (
 //-----------------------------------------------------
 // Validate block
 
-func validateBlock(state State, block *types.Block) error {
+// validateBlock validates the block against the state. The signatures of the
+// block's last commit are only verified if verifyLastCommit is true.
+func validateBlock(state State, block *types.Block, verifyLastCommit bool) error {
 	// Validate internal consistency.
 	if err := block.ValidateBasic(); err != nil {
 		return err
@@ -87,7 +89,7 @@ func validateBlock(state State, block *types.Block) error {
 		if len(block.LastCommit.Signatures) != 0 {
 			return errors.New("initial block can't have LastCommit signatures")
 		}
-	} else {
+	} else if verifyLastCommit {
 		// LastCommit.Signatures length is checked in VerifyCommit.
 		if err := state.LastValidators.VerifyCommit(
 			state.ChainID, state.LastBlockID, block.Height-1, block.LastCommit); err != nil {
@@ -240,6 +240,64 @@ func TestValidateBlockCommit(t *testing.T) {
 	}
 }
 
+func TestValidateTrustedBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	proxyApp := newTestApp()
+	require.NoError(t, proxyApp.Start(ctx))
+
+	state, stateDB, privVals := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB)
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	blockExec := sm.NewBlockExecutor(
+		stateStore,
+		log.TestingLogger(),
+		proxyApp.Consensus(),
+		memmock.Mempool{},
+		sm.EmptyEvidencePool{},
+		blockStore,
+	)
+	lastCommit := types.NewCommit(0, 0, types.BlockID{}, nil)
+
+	for height := int64(1); height < validationTestsStopHeight; height++ {
+		if height > 1 {
+			/*
+				The last commit is fine except for a forged signature
+			*/
+			sigs := make([]types.CommitSig, len(lastCommit.Signatures))
+			copy(sigs, lastCommit.Signatures)
+			sigs[0].Signature = tmhash.Sum([]byte("this signature is forged"))
+			forgedCommit := types.NewCommit(lastCommit.Height, lastCommit.Round, lastCommit.BlockID, sigs)
+
+			/*
+				The signatures of the last commit of a trusted block are not
+				verified, but the rest of the block is
+			*/
+			block := statefactory.MakeBlock(state, height, forgedCommit)
+			require.NoError(t, blockExec.ValidateTrustedBlock(state, block), "height %d", height)
+
+			block.AppHash = tmhash.Sum([]byte("this hash is wrong"))
+			require.Error(t, blockExec.ValidateTrustedBlock(state, block), "height %d", height)
+
+			/*
+				Validating a block as trusted doesn't make it pass ValidateBlock
+			*/
+			block = statefactory.MakeBlock(state, height, forgedCommit)
+			require.NoError(t, blockExec.ValidateTrustedBlock(state, block), "height %d", height)
+			require.Error(t, blockExec.ValidateBlock(state, block), "height %d", height)
+		}
+
+		/*
+			A good block passes
+		*/
+		var err error
+		state, _, lastCommit, err = makeAndCommitGoodBlock(ctx,
+			state, height, lastCommit, state.Validators.GetProposer().Address, blockExec, privVals, nil)
+		require.NoError(t, err, "height %d", height)
+	}
+}
+
 func TestValidateBlockEvidence(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
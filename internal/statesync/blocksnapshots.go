@@ -0,0 +1,484 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/tendermint/tendermint/internal/libs/protoio"
+	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+	"github.com/tendermint/tendermint/internal/p2p"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/store"
+	"github.com/tendermint/tendermint/libs/log"
+	ssproto "github.com/tendermint/tendermint/proto/tendermint/statesync"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Block snapshots are snapshots of a range of full blocks in the block store, taken and
+// served by Tendermint itself on the block snapshot channel, using the same messages as
+// application snapshots. The snapshot height is the top of the range, the hash is the hash
+// of the block at that height, and the metadata is the big-endian base height of the range.
+// Chunks contain length-delimited protobuf-encoded blocks in descending height order,
+// starting from the top, such that they can be verified by the hash chain from a trusted
+// block.
+const (
+	// blockSnapshotFormat is the format of block snapshots.
+	blockSnapshotFormat = 1
+
+	// blockSnapshotChunkBlocks is the number of blocks per block snapshot chunk.
+	blockSnapshotChunkBlocks = 100
+
+	// blockSnapshotKeepRecent is the number of recent block snapshots served.
+	blockSnapshotKeepRecent = 2
+
+	// blockSnapshotCheckInterval is how often the block store is checked for new
+	// block snapshot heights.
+	blockSnapshotCheckInterval = 5 * time.Second
+
+	// maxBlockChunkSize is the maximum size of a block snapshot chunk, leaving room for the
+	// rest of the chunk response in the message.
+	maxBlockChunkSize = blockSnapshotMsgSize - 1024
+)
+
+// errNoBlockSnapshotPeers is returned when no peers are left to fetch a block snapshot from.
+var errNoBlockSnapshotPeers = errors.New("no peers left to fetch block snapshot from")
+
+// blockSnapshotter takes block snapshots of the block store every interval heights, and
+// loads their chunks from the block store when requested.
+type blockSnapshotter struct {
+	logger   log.Logger
+	store    *store.BlockStore
+	interval int64
+	blocks   int64
+
+	mtx       tmsync.Mutex
+	snapshots []*snapshot // in ascending height order
+}
+
+func newBlockSnapshotter(logger log.Logger, store *store.BlockStore, interval, blocks int64) *blockSnapshotter {
+	return &blockSnapshotter{
+		logger:   logger,
+		store:    store,
+		interval: interval,
+		blocks:   blocks,
+	}
+}
+
+// run takes block snapshots until the context is canceled.
+func (b *blockSnapshotter) run(ctx context.Context) {
+	ticker := time.NewTicker(blockSnapshotCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		b.take()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// take takes a block snapshot at the latest interval height in the block store, if it
+// hasn't been taken yet. The snapshot covers the full blocks below it, up to the configured
+// number of blocks.
+func (b *blockSnapshotter) take() {
+	height := b.store.Height()
+	top := height - height%b.interval
+	if top <= 0 {
+		return
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if len(b.snapshots) > 0 && b.snapshots[len(b.snapshots)-1].Height >= uint64(top) {
+		return
+	}
+
+	topMeta := b.store.LoadBlockMeta(top)
+	if topMeta == nil || topMeta.BlockSize < 0 {
+		return
+	}
+	base := top
+	for base > top-b.blocks+1 {
+		meta := b.store.LoadBlockMeta(base - 1)
+		if meta == nil || meta.BlockSize < 0 {
+			break
+		}
+		base--
+	}
+
+	s := &snapshot{
+		Height:   uint64(top),
+		Format:   blockSnapshotFormat,
+		Chunks:   uint32((top-base)/blockSnapshotChunkBlocks + 1),
+		Hash:     topMeta.BlockID.Hash,
+		Metadata: encodeBlockSnapshotBase(base),
+	}
+	b.snapshots = append(b.snapshots, s)
+	if len(b.snapshots) > blockSnapshotKeepRecent {
+		b.snapshots = b.snapshots[len(b.snapshots)-blockSnapshotKeepRecent:]
+	}
+	b.logger.Info("Took block snapshot", "height", top, "base", base, "chunks", s.Chunks)
+}
+
+// list returns the block snapshots whose blocks are still in the block store.
+func (b *blockSnapshotter) list() []*snapshot {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	storeBase := b.store.Base()
+	snapshots := make([]*snapshot, 0, len(b.snapshots))
+	for _, s := range b.snapshots {
+		if base, err := decodeBlockSnapshotBase(s); err == nil && base >= storeBase {
+			snapshots = append(snapshots, s)
+		}
+	}
+	return snapshots
+}
+
+// loadChunk loads a chunk of a block snapshot from the block store. It returns nil if the
+// snapshot or its blocks are no longer available.
+func (b *blockSnapshotter) loadChunk(height uint64, format, index uint32) ([]byte, error) {
+	var s *snapshot
+	for _, candidate := range b.list() {
+		if candidate.Height == height && candidate.Format == format {
+			s = candidate
+		}
+	}
+	if s == nil || index >= s.Chunks {
+		return nil, nil
+	}
+
+	low, high, err := blockChunkRange(s, index)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make([]*types.Block, 0, high-low+1)
+	for h := high; h >= low; h-- {
+		block := b.store.LoadBlock(h)
+		if block == nil {
+			return nil, nil
+		}
+		blocks = append(blocks, block)
+	}
+	bz, err := encodeBlockChunk(blocks)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) > maxBlockChunkSize {
+		b.logger.Info("Block snapshot chunk is too large to send", "height", height, "chunk", index,
+			"size", len(bz))
+		return nil, nil
+	}
+	return bz, nil
+}
+
+// blockChunkRange returns the lowest and highest block heights in a block snapshot chunk.
+func blockChunkRange(s *snapshot, index uint32) (int64, int64, error) {
+	base, err := decodeBlockSnapshotBase(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	high := int64(s.Height) - int64(index)*blockSnapshotChunkBlocks
+	low := high - blockSnapshotChunkBlocks + 1
+	if low < base {
+		low = base
+	}
+	return low, high, nil
+}
+
+// blockChunkIndex returns the index of the block snapshot chunk containing a height.
+func blockChunkIndex(s *snapshot, height int64) uint32 {
+	return uint32((int64(s.Height) - height) / blockSnapshotChunkBlocks)
+}
+
+func encodeBlockSnapshotBase(base int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(base))
+	return bz
+}
+
+// decodeBlockSnapshotBase decodes and validates the base height of a block snapshot.
+func decodeBlockSnapshotBase(s *snapshot) (int64, error) {
+	if len(s.Metadata) != 8 {
+		return 0, fmt.Errorf("invalid block snapshot metadata length %d", len(s.Metadata))
+	}
+	base := int64(binary.BigEndian.Uint64(s.Metadata))
+	if base <= 0 || base > int64(s.Height) {
+		return 0, fmt.Errorf("invalid block snapshot base %d at height %d", base, s.Height)
+	}
+	if chunks := uint32((int64(s.Height)-base)/blockSnapshotChunkBlocks + 1); s.Chunks != chunks {
+		return 0, fmt.Errorf("expected %d chunks for block snapshot, got %d", chunks, s.Chunks)
+	}
+	return base, nil
+}
+
+// encodeBlockChunk encodes blocks as a block snapshot chunk.
+func encodeBlockChunk(blocks []*types.Block) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	writer := protoio.NewDelimitedWriter(buf)
+	for _, block := range blocks {
+		pb, err := block.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.WriteMsg(pb); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeBlockChunk decodes the blocks in a block snapshot chunk.
+func decodeBlockChunk(bz []byte) ([]*types.Block, error) {
+	reader := protoio.NewDelimitedReader(bytes.NewReader(bz), len(bz))
+	blocks := []*types.Block{}
+	for {
+		pb := new(tmproto.Block)
+		if _, err := reader.ReadMsg(pb); errors.Is(err, io.EOF) {
+			return blocks, nil
+		} else if err != nil {
+			return nil, err
+		}
+		block, err := types.BlockFromProto(pb)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+}
+
+// fetchBlockSnapshot downloads the full blocks below the restored height of a state sync
+// from a block snapshot served by peers, verifying them by the hash chain from the restored
+// block ID, and saves them in the block store. Blocks above the restored height in the
+// snapshot are skipped, since block sync fetches them. It returns nil if no peer offers a
+// suitable block snapshot.
+func (r *Reactor) fetchBlockSnapshot(ctx context.Context, state sm.State) error {
+	r.mtx.Lock()
+	if r.blockSnapshots != nil {
+		r.mtx.Unlock()
+		return errors.New("a block snapshot fetch is already in progress")
+	}
+	pool := newSnapshotPool()
+	r.blockSnapshots = pool
+	r.blockChunks = make(chan *chunk, 4)
+	r.mtx.Unlock()
+	defer func() {
+		r.mtx.Lock()
+		r.blockSnapshots = nil
+		r.blockChunks = nil
+		r.mtx.Unlock()
+	}()
+
+	if err := r.blockSnapshotCh.Send(ctx, p2p.Envelope{
+		Broadcast: true,
+		Message:   &ssproto.SnapshotsRequest{},
+	}); err != nil {
+		return err
+	}
+	discoveryTime := r.cfg.DiscoveryTime
+	if discoveryTime == 0 {
+		discoveryTime = minimumDiscoveryTime
+	}
+	r.logger.Info(fmt.Sprintf("Discovering block snapshots for %v", discoveryTime))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(discoveryTime):
+	}
+
+	// The snapshot with the lowest height at or above the restored height covers the most
+	// blocks below it. Ranked snapshots with the same height are ordered by number of peers.
+	height := state.LastBlockHeight
+	var best *snapshot
+	for _, s := range pool.Ranked() {
+		if s.Format != blockSnapshotFormat || s.Height < uint64(height) {
+			continue
+		}
+		if base, err := decodeBlockSnapshotBase(s); err != nil || base > height {
+			continue
+		}
+		if best == nil || s.Height < best.Height {
+			best = s
+		}
+	}
+	if best == nil {
+		r.logger.Info("No suitable block snapshots found", "height", height)
+		return nil
+	}
+	base, _ := decodeBlockSnapshotBase(best)
+	r.logger.Info("Fetching block snapshot", "height", best.Height, "base", base,
+		"peers", len(pool.GetPeers(best)))
+
+	expectID := state.LastBlockID
+	for index := blockChunkIndex(best, height); index < best.Chunks; index++ {
+		for {
+			blocks, peer, err := r.fetchBlockChunk(ctx, pool, best, index)
+			if err != nil {
+				return err
+			}
+			nextID, err := r.saveBlockChunk(best, index, height, expectID, blocks)
+			if errors.Is(err, errInvalidBlockChunk) {
+				r.logger.Error("Received invalid block snapshot chunk", "chunk", index, "peer", peer, "err", err)
+				pool.RejectPeer(peer)
+				if serr := r.blockSnapshotCh.SendError(ctx, p2p.PeerError{
+					NodeID:      peer,
+					Err:         err,
+					Misbehavior: p2p.MisbehaviorInvalidBlock,
+				}); serr != nil {
+					return serr
+				}
+				continue
+			} else if err != nil {
+				return err
+			}
+			expectID = nextID
+			break
+		}
+		low, _, _ := blockChunkRange(best, index)
+		height = low - 1
+	}
+
+	r.logger.Info("Fetched block snapshot", "height", best.Height, "base", base)
+	return nil
+}
+
+// fetchBlockChunk requests a block snapshot chunk from random peers offering the snapshot
+// until one of them returns it, and decodes it. Peers that don't respond in time or don't
+// have the chunk are removed from the pool.
+func (r *Reactor) fetchBlockChunk(
+	ctx context.Context,
+	pool *snapshotPool,
+	s *snapshot,
+	index uint32,
+) ([]*types.Block, types.NodeID, error) {
+	r.mtx.RLock()
+	chunks := r.blockChunks
+	r.mtx.RUnlock()
+
+	for {
+		peers := pool.GetPeers(s)
+		if len(peers) == 0 {
+			return nil, "", errNoBlockSnapshotPeers
+		}
+		peer := peers[rand.Intn(len(peers))] // nolint:gosec // G404: Use of weak random number generator
+
+		r.logger.Debug("Requesting block snapshot chunk", "height", s.Height, "chunk", index, "peer", peer)
+		if err := r.blockSnapshotCh.Send(ctx, p2p.Envelope{
+			To: peer,
+			Message: &ssproto.ChunkRequest{
+				Height: s.Height,
+				Format: s.Format,
+				Index:  index,
+			},
+		}); err != nil {
+			return nil, "", err
+		}
+
+		c, err := waitForBlockChunk(ctx, chunks, s, index, peer, r.cfg.ChunkRequestTimeout)
+		if err != nil {
+			return nil, "", err
+		}
+		if c == nil || c.Chunk == nil {
+			r.logger.Info("Peer didn't return block snapshot chunk", "chunk", index, "peer", peer)
+			pool.RemovePeer(peer)
+			continue
+		}
+
+		blocks, err := decodeBlockChunk(c.Chunk)
+		if err != nil {
+			return nil, peer, fmt.Errorf("%w: %v", errInvalidBlockChunk, err)
+		}
+		return blocks, peer, nil
+	}
+}
+
+// waitForBlockChunk waits for a chunk from a peer, ignoring other chunks. It returns nil if
+// the timeout expires.
+func waitForBlockChunk(
+	ctx context.Context,
+	chunks <-chan *chunk,
+	s *snapshot,
+	index uint32,
+	peer types.NodeID,
+	timeout time.Duration,
+) (*chunk, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case c := <-chunks:
+			if c.Height == s.Height && c.Format == s.Format && c.Index == index && c.Sender == peer {
+				return c, nil
+			}
+		case <-timer.C:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// errInvalidBlockChunk is returned when a block snapshot chunk fails verification.
+var errInvalidBlockChunk = errors.New("invalid block snapshot chunk")
+
+// saveBlockChunk verifies the blocks in a block snapshot chunk from the given height down,
+// the block at that height against the expected block ID and each block below it against
+// the last block ID of the block above it, and saves them. It returns the expected block ID
+// of the block below the chunk.
+func (r *Reactor) saveBlockChunk(
+	s *snapshot,
+	index uint32,
+	height int64,
+	expectID types.BlockID,
+	blocks []*types.Block,
+) (types.BlockID, error) {
+	low, high, err := blockChunkRange(s, index)
+	if err != nil {
+		return types.BlockID{}, err
+	}
+	if len(blocks) != int(high-low+1) {
+		return types.BlockID{}, fmt.Errorf("%w: expected %d blocks, got %d",
+			errInvalidBlockChunk, high-low+1, len(blocks))
+	}
+
+	parts := make([]*types.PartSet, 0, len(blocks))
+	verified := make([]*types.Block, 0, len(blocks))
+	for i, block := range blocks {
+		if block.Height != high-int64(i) {
+			return types.BlockID{}, fmt.Errorf("%w: expected block %d, got %d",
+				errInvalidBlockChunk, high-int64(i), block.Height)
+		}
+		if block.Height > height {
+			continue
+		}
+		if err := block.ValidateBasic(); err != nil {
+			return types.BlockID{}, fmt.Errorf("%w: %v", errInvalidBlockChunk, err)
+		}
+		blockParts := block.MakePartSet(types.BlockPartSizeBytes)
+		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: blockParts.Header()}
+		if !blockID.Equals(expectID) {
+			return types.BlockID{}, fmt.Errorf("%w: expected block ID %v at height %d, got %v",
+				errInvalidBlockChunk, expectID, block.Height, blockID)
+		}
+		expectID = block.LastBlockID
+		parts = append(parts, blockParts)
+		verified = append(verified, block)
+	}
+
+	for i, block := range verified {
+		if err := r.blockStore.SaveHistoricalBlock(block, parts[i]); err != nil {
+			return types.BlockID{}, fmt.Errorf("failed to save block %d: %w", block.Height, err)
+		}
+	}
+	return expectID, nil
+}
@@ -0,0 +1,186 @@
+package statesync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/store"
+	"github.com/tendermint/tendermint/internal/test/factory"
+	"github.com/tendermint/tendermint/libs/log"
+	ssproto "github.com/tendermint/tendermint/proto/tendermint/statesync"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// makeBlockStore returns a block store with a chain of full blocks from height 1 to height.
+func makeBlockStore(t *testing.T, height int64) *store.BlockStore {
+	t.Helper()
+
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	vals, privVals := factory.RandValidatorSet(1, 10)
+	lastCommit := types.NewCommit(0, 0, types.BlockID{}, nil)
+	lastBlockID := types.BlockID{}
+	blockTime := time.Now().Add(-time.Duration(height) * time.Second)
+	for h := int64(1); h <= height; h++ {
+		block := types.MakeBlock(h, []types.Tx{types.Tx(fmt.Sprintf("tx%d", h))}, lastCommit, nil)
+		block.ChainID = factory.DefaultTestChainID
+		block.Time = blockTime
+		block.LastBlockID = lastBlockID
+		block.ValidatorsHash = vals.Hash()
+		block.NextValidatorsHash = vals.Hash()
+		block.ProposerAddress = vals.Proposer.Address
+		parts := block.MakePartSet(types.BlockPartSizeBytes)
+		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+
+		voteSet := types.NewVoteSet(factory.DefaultTestChainID, h, 0, tmproto.PrecommitType, vals)
+		commit, err := factory.MakeCommit(blockID, h, 0, voteSet, privVals, blockTime)
+		require.NoError(t, err)
+		blockStore.SaveBlock(block, parts, commit)
+
+		lastCommit, lastBlockID = commit, blockID
+		blockTime = blockTime.Add(time.Second)
+	}
+	return blockStore
+}
+
+func TestBlockSnapshotter(t *testing.T) {
+	blockStore := makeBlockStore(t, 250)
+	snapshotter := newBlockSnapshotter(log.TestingLogger(), blockStore, 100, 150)
+
+	// A snapshot of blocks 51 to 200 is taken at height 200, in 2 chunks.
+	snapshotter.take()
+	snapshots := snapshotter.list()
+	require.Len(t, snapshots, 1)
+	s := snapshots[0]
+	require.EqualValues(t, 200, s.Height)
+	require.EqualValues(t, 2, s.Chunks)
+	require.Equal(t, blockStore.LoadBlockMeta(200).BlockID.Hash.Bytes(), s.Hash)
+	base, err := decodeBlockSnapshotBase(s)
+	require.NoError(t, err)
+	require.EqualValues(t, 51, base)
+
+	// Chunks contain blocks in descending order.
+	for index, heights := range [][2]int64{{200, 101}, {100, 51}} {
+		bz, err := snapshotter.loadChunk(200, blockSnapshotFormat, uint32(index))
+		require.NoError(t, err)
+		blocks, err := decodeBlockChunk(bz)
+		require.NoError(t, err)
+		require.Len(t, blocks, int(heights[0]-heights[1]+1))
+		require.EqualValues(t, heights[0], blocks[0].Height)
+		require.EqualValues(t, heights[1], blocks[len(blocks)-1].Height)
+		require.Equal(t, blockStore.LoadBlock(heights[0]).Hash(), blocks[0].Hash())
+	}
+
+	// Unknown snapshots and chunks aren't available.
+	for _, c := range []struct {
+		height uint64
+		format uint32
+		index  uint32
+	}{{100, blockSnapshotFormat, 0}, {200, 2, 0}, {200, blockSnapshotFormat, 2}} {
+		bz, err := snapshotter.loadChunk(c.height, c.format, c.index)
+		require.NoError(t, err)
+		require.Nil(t, bz)
+	}
+
+	// Snapshots are only taken once per interval.
+	snapshotter.take()
+	require.Len(t, snapshotter.list(), 1)
+
+	// Snapshots are no longer served once their blocks are pruned.
+	_, err = blockStore.PruneBlocks(60)
+	require.NoError(t, err)
+	require.Empty(t, snapshotter.list())
+}
+
+func TestReactor_fetchBlockSnapshot(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := makeBlockStore(t, 250)
+	snapshotter := newBlockSnapshotter(log.TestingLogger(), source, 100, 150)
+	snapshotter.take()
+	s := snapshotter.list()[0]
+
+	rts := setup(ctx, t, nil, nil, nil, 2)
+	rts.reactor.cfg.DiscoveryTime = 100 * time.Millisecond
+
+	// Peer aa serves the snapshot, while bb serves a chunk from another chain.
+	forged := makeBlockStore(t, 200)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case envelope := <-rts.blockSnapshotOutCh:
+				switch msg := envelope.Message.(type) {
+				case *ssproto.SnapshotsRequest:
+					for _, peer := range []types.NodeID{"aa", "bb"} {
+						rts.blockSnapshotInCh <- p2p.Envelope{
+							From: peer,
+							Message: &ssproto.SnapshotsResponse{
+								Height:   s.Height,
+								Format:   s.Format,
+								Chunks:   s.Chunks,
+								Hash:     s.Hash,
+								Metadata: s.Metadata,
+							},
+						}
+					}
+				case *ssproto.ChunkRequest:
+					bz, err := snapshotter.loadChunk(msg.Height, msg.Format, msg.Index)
+					require.NoError(t, err)
+					if envelope.To == "bb" {
+						low, high, err := blockChunkRange(s, msg.Index)
+						require.NoError(t, err)
+						blocks := []*types.Block{}
+						for h := high; h >= low; h-- {
+							blocks = append(blocks, forged.LoadBlock(h))
+						}
+						bz, err = encodeBlockChunk(blocks)
+						require.NoError(t, err)
+					}
+					rts.blockSnapshotInCh <- p2p.Envelope{
+						From: envelope.To,
+						Message: &ssproto.ChunkResponse{
+							Height: msg.Height,
+							Format: msg.Format,
+							Index:  msg.Index,
+							Chunk:  bz,
+						},
+					}
+				}
+			}
+		}
+	}()
+
+	// The state was restored at height 180, so blocks 51 to 180 are fetched.
+	state := sm.State{
+		LastBlockHeight: 180,
+		LastBlockID:     source.LoadBlockMeta(180).BlockID,
+	}
+	require.NoError(t, rts.reactor.fetchBlockSnapshot(ctx, state))
+
+	require.EqualValues(t, 51, rts.blockStore.Base())
+	for h := int64(51); h <= 250; h++ {
+		if h > 180 {
+			require.Nil(t, rts.blockStore.LoadBlockMeta(h))
+			continue
+		}
+		require.Equal(t, source.LoadBlock(h).Hash(), rts.blockStore.LoadBlock(h).Hash())
+	}
+
+	// bb is reported if its chunk was requested.
+	select {
+	case peerErr := <-rts.blockSnapshotPeerErrCh:
+		require.Equal(t, types.NodeID("bb"), peerErr.NodeID)
+		require.Equal(t, p2p.MisbehaviorInvalidBlock, peerErr.Misbehavior)
+	default:
+	}
+}
@@ -1,6 +1,7 @@
 package statesync
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -9,12 +10,17 @@ import (
 	"time"
 
 	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+	"github.com/tendermint/tendermint/internal/libs/tempfile"
 	"github.com/tendermint/tendermint/types"
 )
 
 // errDone is returned by chunkQueue.Next() when all chunks have been returned.
 var errDone = errors.New("chunk queue has completed")
 
+// chunkQueueStateFile is the name of the file in which a persistent chunk queue stores its
+// snapshot.
+const chunkQueueStateFile = "snapshot.json"
+
 // chunk contains data for a chunk.
 type chunk struct {
 	Height uint64
@@ -24,17 +30,31 @@ type chunk struct {
 	Sender types.NodeID
 }
 
+// chunkQueueState is the on-disk state of a persistent chunk queue, used to resume an
+// interrupted restoration.
+type chunkQueueState struct {
+	Height   uint64 `json:"height"`
+	Format   uint32 `json:"format"`
+	Chunks   uint32 `json:"chunks"`
+	Hash     []byte `json:"hash"`
+	Metadata []byte `json:"metadata"`
+}
+
 // chunkQueue manages chunks for a state sync process, ordering them if requested. It acts as an
 // iterator over all chunks, but callers can request chunks to be retried, optionally after
 // refetching.
 type chunkQueue struct {
 	tmsync.Mutex
 	snapshot       *snapshot                  // if this is nil, the queue has been closed
-	dir            string                     // temp dir for on-disk chunk storage
-	chunkFiles     map[uint32]string          // path to temporary chunk file
+	dir            string                     // dir for on-disk chunk storage
+	persistent     bool                       // if true, the dir is kept on Close()
+	resumed        bool                       // if true, the queue was loaded from disk
+	chunkFiles     map[uint32]string          // path to chunk file
+	chunkInPlace   map[uint32]bool            // chunks that were added in place via AddFile()
 	chunkSenders   map[uint32]types.NodeID    // the peer who sent the given chunk
 	chunkAllocated map[uint32]bool            // chunks that have been allocated via Allocate()
 	chunkReturned  map[uint32]bool            // chunks returned via Next()
+	chunkApplied   map[uint32]bool            // chunks applied by the app, via Applied()
 	waiters        map[uint32][]chan<- uint32 // signals WaitFor() waiters about chunk arrival
 }
 
@@ -49,15 +69,90 @@ func newChunkQueue(snapshot *snapshot, tempDir string) (*chunkQueue, error) {
 		return nil, errors.New("snapshot has no chunks")
 	}
 
+	return makeChunkQueue(snapshot, dir), nil
+}
+
+// newPersistentChunkQueue creates a new chunk queue for a snapshot, storing chunks and the
+// restoration progress in the given dir such that it can be resumed via loadChunkQueue(). Any
+// existing contents of the dir are removed. Callers must call Close() when done, and Remove()
+// once the queue is no longer needed.
+func newPersistentChunkQueue(snapshot *snapshot, dir string) (*chunkQueue, error) {
+	if snapshot.Chunks == 0 {
+		return nil, errors.New("snapshot has no chunks")
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to clean up state sync dir %v: %w", dir, err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create dir for state sync chunks: %w", err)
+	}
+
+	q := makeChunkQueue(snapshot, dir)
+	q.persistent = true
+	if err := q.save(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// loadChunkQueue loads a persistent chunk queue from the given dir, or returns nil if there is
+// none. Chunks that were already fetched are not allocated again, but all of them are returned by
+// Next() again, since the app may not have kept the chunks it applied. Chunk senders are not
+// persisted.
+func loadChunkQueue(dir string) (*chunkQueue, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, chunkQueueStateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load state sync progress: %w", err)
+	}
+	var state chunkQueueState
+	if err := json.Unmarshal(bz, &state); err != nil {
+		return nil, fmt.Errorf("invalid state sync progress in %v: %w", dir, err)
+	}
+	if state.Chunks == 0 {
+		return nil, fmt.Errorf("invalid state sync progress in %v: snapshot has no chunks", dir)
+	}
+
+	q := makeChunkQueue(&snapshot{
+		Height:   state.Height,
+		Format:   state.Format,
+		Chunks:   state.Chunks,
+		Hash:     state.Hash,
+		Metadata: state.Metadata,
+	}, dir)
+	q.persistent = true
+	q.resumed = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state sync chunks: %w", err)
+	}
+	for _, entry := range entries {
+		index, err := strconv.ParseUint(entry.Name(), 10, 32)
+		if err != nil || uint32(index) >= state.Chunks {
+			continue // not a chunk, e.g. the state file
+		}
+		q.chunkFiles[uint32(index)] = filepath.Join(dir, entry.Name())
+		q.chunkAllocated[uint32(index)] = true
+	}
+
+	return q, nil
+}
+
+// makeChunkQueue creates an empty chunk queue for a snapshot, using the given dir for storage.
+func makeChunkQueue(snapshot *snapshot, dir string) *chunkQueue {
 	return &chunkQueue{
 		snapshot:       snapshot,
 		dir:            dir,
 		chunkFiles:     make(map[uint32]string, snapshot.Chunks),
+		chunkInPlace:   make(map[uint32]bool),
 		chunkSenders:   make(map[uint32]types.NodeID, snapshot.Chunks),
 		chunkAllocated: make(map[uint32]bool, snapshot.Chunks),
 		chunkReturned:  make(map[uint32]bool, snapshot.Chunks),
+		chunkApplied:   make(map[uint32]bool, snapshot.Chunks),
 		waiters:        make(map[uint32][]chan<- uint32),
-	}, nil
+	}
 }
 
 // Add adds a chunk to the queue. It ignores chunks that already exist, returning false.
@@ -85,26 +180,56 @@ func (q *chunkQueue) Add(chunk *chunk) (bool, error) {
 		return false, nil
 	}
 
+	// Chunks are written atomically, such that a persistent queue never contains partial
+	// chunks after a crash.
 	path := filepath.Join(q.dir, strconv.FormatUint(uint64(chunk.Index), 10))
-	err := os.WriteFile(path, chunk.Chunk, 0600)
+	err := tempfile.WriteFileAtomic(path, chunk.Chunk, 0600)
 	if err != nil {
 		return false, fmt.Errorf("failed to save chunk %v to file %v: %w", chunk.Index, path, err)
 	}
 
-	q.chunkFiles[chunk.Index] = path
-	q.chunkSenders[chunk.Index] = chunk.Sender
+	q.added(chunk.Index, path, chunk.Sender)
+	return true, nil
+}
+
+// AddFile adds a chunk stored in the given file to the queue, reading it in place instead of
+// copying it into the queue's dir. The file is never removed by the queue, and since it's not
+// persisted it's added again when a restoration is resumed. A chunk is only added in place once:
+// if it's discarded, it must be fetched elsewhere. It returns false if the chunk already exists
+// or was added in place before.
+func (q *chunkQueue) AddFile(index uint32, path string, sender types.NodeID) (bool, error) {
+	q.Lock()
+	defer q.Unlock()
 
-	// Signal any waiters that the chunk has arrived.
-	for _, waiter := range q.waiters[chunk.Index] {
-		waiter <- chunk.Index
-		close(waiter)
+	if q.snapshot == nil {
+		return false, nil // queue is closed
+	}
+	if index >= q.snapshot.Chunks {
+		return false, fmt.Errorf("received unexpected chunk %v", index)
+	}
+	if q.chunkFiles[index] != "" || q.chunkInPlace[index] {
+		return false, nil
 	}
 
-	delete(q.waiters, chunk.Index)
-
+	q.chunkInPlace[index] = true
+	q.added(index, path, sender)
 	return true, nil
 }
 
+// added records a chunk that was added to the queue, and signals any waiters that it has
+// arrived. The caller must hold the mutex lock.
+func (q *chunkQueue) added(index uint32, path string, sender types.NodeID) {
+	q.chunkFiles[index] = path
+	q.chunkSenders[index] = sender
+
+	for _, waiter := range q.waiters[index] {
+		waiter <- index
+		close(waiter)
+	}
+
+	delete(q.waiters, index)
+}
+
 // Allocate allocates a chunk to the caller, making it responsible for fetching it. Returns
 // errDone once no chunks are left or the queue is closed.
 func (q *chunkQueue) Allocate() (uint32, error) {
@@ -129,7 +254,46 @@ func (q *chunkQueue) Allocate() (uint32, error) {
 	return 0, errDone
 }
 
-// Close closes the chunk queue, cleaning up all temporary files.
+// Applied records that a chunk was applied by the app.
+func (q *chunkQueue) Applied(index uint32) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.snapshot == nil || index >= q.snapshot.Chunks {
+		return
+	}
+	q.chunkReturned[index] = true
+	q.chunkApplied[index] = true
+}
+
+// Resumed returns true if the queue was loaded from disk via loadChunkQueue().
+func (q *chunkQueue) Resumed() bool {
+	q.Lock()
+	defer q.Unlock()
+	return q.resumed
+}
+
+// save saves the snapshot to disk. The caller must hold the mutex lock.
+func (q *chunkQueue) save() error {
+	bz, err := json.Marshal(chunkQueueState{
+		Height:   q.snapshot.Height,
+		Format:   q.snapshot.Format,
+		Chunks:   q.snapshot.Chunks,
+		Hash:     q.snapshot.Hash,
+		Metadata: q.snapshot.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(q.dir, chunkQueueStateFile)
+	if err := tempfile.WriteFileAtomic(path, bz, 0600); err != nil {
+		return fmt.Errorf("failed to save state sync progress to %v: %w", path, err)
+	}
+	return nil
+}
+
+// Close closes the chunk queue. Temporary files are cleaned up, but persistent queues are kept
+// on disk until removed via Remove().
 func (q *chunkQueue) Close() error {
 	q.Lock()
 	defer q.Unlock()
@@ -147,6 +311,9 @@ func (q *chunkQueue) Close() error {
 	q.waiters = nil
 	q.snapshot = nil
 
+	if q.persistent {
+		return nil
+	}
 	if err := os.RemoveAll(q.dir); err != nil {
 		return fmt.Errorf("failed to clean up state sync tempdir %v: %w", q.dir, err)
 	}
@@ -154,6 +321,17 @@ func (q *chunkQueue) Close() error {
 	return nil
 }
 
+// Remove closes the chunk queue and removes it from disk, including persistent queues.
+func (q *chunkQueue) Remove() error {
+	if err := q.Close(); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(q.dir); err != nil {
+		return fmt.Errorf("failed to clean up state sync dir %v: %w", q.dir, err)
+	}
+	return nil
+}
+
 // Discard discards a chunk. It will be removed from the queue, available for allocation, and can
 // be added and returned via Next() again. If the chunk is not already in the queue this does
 // nothing, to avoid it being allocated to multiple fetchers.
@@ -174,13 +352,17 @@ func (q *chunkQueue) discard(index uint32) error {
 		return nil
 	}
 
-	if err := os.Remove(path); err != nil {
-		return fmt.Errorf("failed to remove chunk %v: %w", index, err)
+	// Chunks added in place are stored outside of the queue's dir, and aren't ours to remove.
+	if filepath.Dir(path) == q.dir {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove chunk %v: %w", index, err)
+		}
 	}
 
 	delete(q.chunkFiles, index)
 	delete(q.chunkReturned, index)
 	delete(q.chunkAllocated, index)
+	delete(q.chunkApplied, index)
 
 	return nil
 }
@@ -304,6 +486,7 @@ func (q *chunkQueue) Retry(index uint32) {
 	q.Lock()
 	defer q.Unlock()
 	delete(q.chunkReturned, index)
+	delete(q.chunkApplied, index)
 }
 
 // RetryAll schedules all chunks to be retried, without refetching them.
@@ -311,6 +494,7 @@ func (q *chunkQueue) RetryAll() {
 	q.Lock()
 	defer q.Unlock()
 	q.chunkReturned = make(map[uint32]bool)
+	q.chunkApplied = make(map[uint32]bool)
 }
 
 // Size returns the total number of chunks for the snapshot and queue, or 0 when closed.
@@ -367,3 +551,17 @@ func (q *chunkQueue) numChunksReturned() int {
 	}
 	return cnt
 }
+
+// numChunksFetched returns the number of chunks in the queue, i.e. fetched but not discarded.
+func (q *chunkQueue) numChunksFetched() int {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.chunkFiles)
+}
+
+// numChunksApplied returns the number of chunks applied by the app.
+func (q *chunkQueue) numChunksApplied() int {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.chunkApplied)
+}
@@ -2,6 +2,7 @@ package statesync
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -53,6 +54,71 @@ func TestNewChunkQueue_TempDir(t *testing.T) {
 	assert.Len(t, files, 0)
 }
 
+func TestChunkQueue_Persistent(t *testing.T) {
+	snapshot := &snapshot{
+		Height:   3,
+		Format:   1,
+		Chunks:   5,
+		Hash:     []byte{7},
+		Metadata: []byte{9},
+	}
+	dir := filepath.Join(t.TempDir(), "chunks")
+
+	// A persistent queue for the snapshot is stored in the dir.
+	queue, err := newPersistentChunkQueue(snapshot, dir)
+	require.NoError(t, err)
+	for i := uint32(0); i < 3; i++ {
+		_, err = queue.Add(&chunk{Height: 3, Format: 1, Index: i, Chunk: []byte{3, 1, byte(i)}})
+		require.NoError(t, err)
+	}
+
+	// Chunks 0 and 1 are applied.
+	for i := uint32(0); i < 2; i++ {
+		c, err := queue.Next()
+		require.NoError(t, err)
+		require.EqualValues(t, i, c.Index)
+		queue.Applied(i)
+	}
+	require.False(t, queue.Resumed())
+
+	// Closing the queue keeps it on disk.
+	require.NoError(t, queue.Close())
+	require.DirExists(t, dir)
+
+	// Loading the queue only allocates missing chunks, but returns all chunks again, since the
+	// app may not have kept the applied ones.
+	queue, err = loadChunkQueue(dir)
+	require.NoError(t, err)
+	require.NotNil(t, queue)
+	require.True(t, queue.Resumed())
+	require.Equal(t, snapshot, queue.snapshot)
+	for i := uint32(0); i < 3; i++ {
+		assert.True(t, queue.Has(i))
+	}
+
+	index, err := queue.Allocate()
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, index)
+
+	for i := uint32(0); i < 3; i++ {
+		c, err := queue.Next()
+		require.NoError(t, err)
+		assert.Equal(t, &chunk{Height: 3, Format: 1, Index: i, Chunk: []byte{3, 1, byte(i)}}, c)
+	}
+
+	// Creating a new persistent queue in the dir discards the old one.
+	queue, err = newPersistentChunkQueue(snapshot, dir)
+	require.NoError(t, err)
+	assert.False(t, queue.Has(0))
+
+	// Removing the queue removes the dir, after which there is nothing to load.
+	require.NoError(t, queue.Remove())
+	require.NoDirExists(t, dir)
+	queue, err = loadChunkQueue(dir)
+	require.NoError(t, err)
+	require.Nil(t, queue)
+}
+
 func TestChunkQueue(t *testing.T) {
 	queue, teardown := setupChunkQueue(t)
 	defer teardown()
@@ -137,6 +203,48 @@ func TestChunkQueue_Add_ChunkErrors(t *testing.T) {
 	}
 }
 
+func TestChunkQueue_AddFile(t *testing.T) {
+	queue, teardown := setupChunkQueue(t)
+	defer teardown()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "0")
+	require.NoError(t, os.WriteFile(path, []byte{3, 1, 0}, 0644))
+
+	_, err := queue.AddFile(5, path, "local")
+	require.Error(t, err)
+
+	// The chunk is read in place, from the given file.
+	added, err := queue.AddFile(0, path, "local")
+	require.NoError(t, err)
+	require.True(t, added)
+	c, err := queue.Next()
+	require.NoError(t, err)
+	assert.Equal(t, &chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 0}, Sender: "local"}, c)
+
+	added, err = queue.AddFile(0, path, "local")
+	require.NoError(t, err)
+	require.False(t, added)
+
+	// Discarding the chunk keeps the file, and it's not added in place again, but it can be
+	// added from elsewhere.
+	require.NoError(t, queue.Discard(0))
+	assert.FileExists(t, path)
+	added, err = queue.AddFile(0, path, "local")
+	require.NoError(t, err)
+	require.False(t, added)
+	added, err = queue.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 9}, Sender: "peer"})
+	require.NoError(t, err)
+	require.True(t, added)
+	c, err = queue.Next()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3, 1, 9}, c.Chunk)
+
+	// Closing the queue doesn't remove files added in place.
+	require.NoError(t, queue.Close())
+	assert.FileExists(t, path)
+}
+
 func TestChunkQueue_Allocate(t *testing.T) {
 	queue, teardown := setupChunkQueue(t)
 	defer teardown()
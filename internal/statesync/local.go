@@ -0,0 +1,413 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/light"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+// A local snapshot directory contains a snapshot, and the data needed to
+// verify and restore it without any network access:
+//
+//	snapshot.json          the snapshot height, format, number of chunks, hash and metadata
+//	chunks/<index>         the contents of each chunk
+//	light-blocks/<height>  protobuf-encoded light blocks, at least at the trust height and at
+//	                       the snapshot height and the two heights above it, and ideally
+//	                       throughout the evidence window below the snapshot height
+//	params                 protobuf-encoded consensus params at the height above the snapshot
+//
+// Light blocks are verified in ascending height order from the trusted light block, each
+// against the last one verified, so light blocks at intermediate heights must be included
+// if the validator set changes by more than 2/3 between them. Light blocks below the trust
+// height must all be included, since they are verified by hash. Light blocks below the
+// snapshot height are backfilled from the directory, and those missing are fetched from peers.
+const (
+	localSnapshotFile   = "snapshot.json"
+	localChunksDir      = "chunks"
+	localLightBlocksDir = "light-blocks"
+	localParamsFile     = "params"
+)
+
+const (
+	// localSnapshotPeer is the pseudo peer offering the local snapshot in the snapshot pool.
+	localSnapshotPeer = types.NodeID("local")
+
+	// localMaxClockDrift is how far in the future local light blocks may be.
+	localMaxClockDrift = 10 * time.Second
+)
+
+// localSnapshotMetadata is the contents of snapshot.json in a local snapshot directory.
+type localSnapshotMetadata struct {
+	Height   uint64 `json:"height"`
+	Format   uint32 `json:"format"`
+	Chunks   uint32 `json:"chunks"`
+	Hash     []byte `json:"hash"`
+	Metadata []byte `json:"metadata"`
+}
+
+// loadLocalSnapshot loads the snapshot in a local snapshot directory.
+func loadLocalSnapshot(dir string) (*snapshot, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, localSnapshotFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load local snapshot: %w", err)
+	}
+	var metadata localSnapshotMetadata
+	if err := json.Unmarshal(bz, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid local snapshot in %v: %w", dir, err)
+	}
+	if metadata.Height == 0 || metadata.Chunks == 0 {
+		return nil, fmt.Errorf("invalid local snapshot in %v: missing height or chunks", dir)
+	}
+	return &snapshot{
+		Height:   metadata.Height,
+		Format:   metadata.Format,
+		Chunks:   metadata.Chunks,
+		Hash:     metadata.Hash,
+		Metadata: metadata.Metadata,
+	}, nil
+}
+
+// localChunkPath returns the path of a chunk in a local snapshot directory.
+func localChunkPath(dir string, index uint32) string {
+	return filepath.Join(dir, localChunksDir, strconv.FormatUint(uint64(index), 10))
+}
+
+// WriteLocalSnapshot writes a snapshot, its chunks and the data needed to verify it to a
+// directory, such that a node can restore it without network access by setting
+// statesync.snapshot-dir. The light blocks must include the snapshot height and the two
+// heights above it, and should include the evidence window below the snapshot height to be
+// backfilled without peers. The consensus params are those at the height above the snapshot.
+func WriteLocalSnapshot(
+	dir string,
+	snapshot *abci.Snapshot,
+	chunks [][]byte,
+	lightBlocks []*types.LightBlock,
+	params types.ConsensusParams,
+) error {
+	if uint32(len(chunks)) != snapshot.Chunks {
+		return fmt.Errorf("snapshot has %d chunks, got %d", snapshot.Chunks, len(chunks))
+	}
+	for _, sub := range []string{localChunksDir, localLightBlocksDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return fmt.Errorf("failed to create local snapshot dir: %w", err)
+		}
+	}
+
+	bz, err := json.Marshal(localSnapshotMetadata{
+		Height:   snapshot.Height,
+		Format:   snapshot.Format,
+		Chunks:   snapshot.Chunks,
+		Hash:     snapshot.Hash,
+		Metadata: snapshot.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, localSnapshotFile), bz, 0644); err != nil {
+		return fmt.Errorf("failed to write local snapshot: %w", err)
+	}
+
+	for index, chunk := range chunks {
+		path := filepath.Join(dir, localChunksDir, strconv.Itoa(index))
+		if err := os.WriteFile(path, chunk, 0644); err != nil {
+			return fmt.Errorf("failed to write local snapshot chunk %v: %w", index, err)
+		}
+	}
+
+	for _, lb := range lightBlocks {
+		pb, err := lb.ToProto()
+		if err != nil {
+			return fmt.Errorf("invalid light block at height %d: %w", lb.Height, err)
+		}
+		bz, err := pb.Marshal()
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, localLightBlocksDir, strconv.FormatInt(lb.Height, 10))
+		if err := os.WriteFile(path, bz, 0644); err != nil {
+			return fmt.Errorf("failed to write light block at height %d: %w", lb.Height, err)
+		}
+	}
+
+	pbParams := params.ToProto()
+	bz, err = pbParams.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, localParamsFile), bz, 0644); err != nil {
+		return fmt.Errorf("failed to write consensus params: %w", err)
+	}
+	return nil
+}
+
+// loadLocalLightBlocks loads the light blocks in a local snapshot directory, by height. They
+// are only checked with ValidateBasic, and must be verified by the caller.
+func loadLocalLightBlocks(dir string, chainID string) (map[int64]*types.LightBlock, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, localLightBlocksDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load local light blocks: %w", err)
+	}
+	lightBlocks := make(map[int64]*types.LightBlock, len(entries))
+	for _, entry := range entries {
+		height, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		bz, err := os.ReadFile(filepath.Join(dir, localLightBlocksDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load light block at height %d: %w", height, err)
+		}
+		var pb tmproto.LightBlock
+		if err := pb.Unmarshal(bz); err != nil {
+			return nil, fmt.Errorf("invalid light block at height %d: %w", height, err)
+		}
+		lb, err := types.LightBlockFromProto(&pb)
+		if err != nil {
+			return nil, fmt.Errorf("invalid light block at height %d: %w", height, err)
+		}
+		if err := lb.ValidateBasic(chainID); err != nil {
+			return nil, fmt.Errorf("invalid light block at height %d: %w", height, err)
+		}
+		if lb.Height != height {
+			return nil, fmt.Errorf("light block at height %d found in file for height %d", lb.Height, height)
+		}
+		lightBlocks[height] = lb
+	}
+	return lightBlocks, nil
+}
+
+// localLightBlocks hands out the light blocks in a local snapshot directory to backfill, each
+// at most once, such that light blocks that fail verification are fetched from peers instead.
+type localLightBlocks struct {
+	mtx    tmsync.Mutex
+	blocks map[int64]*types.LightBlock
+}
+
+// take returns the light block at the given height, or nil if there is none or it was
+// already taken. It's safe to call on a nil receiver, which has no light blocks.
+func (l *localLightBlocks) take(height int64) *types.LightBlock {
+	if l == nil {
+		return nil
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	lb := l.blocks[height]
+	delete(l.blocks, height)
+	return lb
+}
+
+// stateProviderLocal provides state data from a local snapshot directory, verifying the
+// light blocks against the trust options without network access.
+type stateProviderLocal struct {
+	tmsync.Mutex
+	chainID       string
+	initialHeight int64
+	dir           string
+	trustOptions  light.TrustOptions
+	lightBlocks   map[int64]*types.LightBlock // unverified
+	verified      map[int64]*types.LightBlock
+	params        types.ConsensusParams
+}
+
+// NewLocalStateProvider creates a new StateProvider using the light blocks and consensus
+// params in a local snapshot directory, as written by WriteLocalSnapshot. The light block
+// at the trusted height must match the trusted hash.
+func NewLocalStateProvider(
+	chainID string,
+	initialHeight int64,
+	dir string,
+	trustOptions light.TrustOptions,
+) (StateProvider, error) {
+	if err := trustOptions.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	lightBlocks, err := loadLocalLightBlocks(dir, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err := os.ReadFile(filepath.Join(dir, localParamsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load local consensus params: %w", err)
+	}
+	var pbParams tmproto.ConsensusParams
+	if err := pbParams.Unmarshal(bz); err != nil {
+		return nil, fmt.Errorf("invalid local consensus params: %w", err)
+	}
+
+	trusted, ok := lightBlocks[trustOptions.Height]
+	if !ok {
+		return nil, fmt.Errorf("no light block at the trusted height %d in %v", trustOptions.Height, dir)
+	}
+	if !bytes.Equal(trusted.Hash(), trustOptions.Hash) {
+		return nil, fmt.Errorf("expected light block at the trusted height %d to have hash %X, got %X",
+			trustOptions.Height, trustOptions.Hash, trusted.Hash())
+	}
+	if light.HeaderExpired(trusted.SignedHeader, trustOptions.Period, time.Now()) {
+		return nil, fmt.Errorf("trusted light block at height %d has expired", trustOptions.Height)
+	}
+
+	return &stateProviderLocal{
+		chainID:       chainID,
+		initialHeight: initialHeight,
+		dir:           dir,
+		trustOptions:  trustOptions,
+		lightBlocks:   lightBlocks,
+		verified:      map[int64]*types.LightBlock{trusted.Height: trusted},
+		params:        types.ConsensusParamsFromProto(pbParams),
+	}, nil
+}
+
+// verifyLightBlock verifies the light block at the given height, from the trusted light
+// block. The caller must hold the mutex lock.
+func (s *stateProviderLocal) verifyLightBlock(height int64) (*types.LightBlock, error) {
+	if lb, ok := s.verified[height]; ok {
+		return lb, nil
+	}
+	if _, ok := s.lightBlocks[height]; !ok {
+		return nil, fmt.Errorf("no light block at height %d in %v", height, s.dir)
+	}
+
+	trustHeight := s.trustOptions.Height
+	if height < trustHeight {
+		// Light blocks below the trusted height are verified by the hash chain.
+		for h := trustHeight - 1; h >= height; h-- {
+			if _, ok := s.verified[h]; ok {
+				continue
+			}
+			lb, ok := s.lightBlocks[h]
+			if !ok {
+				return nil, fmt.Errorf("no light block at height %d in %v to verify height %d", h, s.dir, height)
+			}
+			if err := light.VerifyBackwards(lb.Header, s.verified[h+1].Header); err != nil {
+				return nil, fmt.Errorf("failed to verify light block at height %d: %w", h, err)
+			}
+			s.verified[h] = lb
+		}
+		return s.verified[height], nil
+	}
+
+	heights := make([]int64, 0, len(s.lightBlocks))
+	for h := range s.lightBlocks {
+		if h > trustHeight && h <= height {
+			heights = append(heights, h)
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	trusted := s.verified[trustHeight]
+	now := time.Now()
+	for _, h := range heights {
+		lb := s.lightBlocks[h]
+		if verified, ok := s.verified[h]; ok {
+			trusted = verified
+			continue
+		}
+		err := light.Verify(trusted.SignedHeader, trusted.ValidatorSet, lb.SignedHeader, lb.ValidatorSet,
+			s.trustOptions.Period, now, localMaxClockDrift, light.DefaultTrustLevel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify light block at height %d from height %d: %w",
+				h, trusted.Height, err)
+		}
+		s.verified[h] = lb
+		trusted = lb
+	}
+	return s.verified[height], nil
+}
+
+// AppHash implements StateProvider.
+func (s *stateProviderLocal) AppHash(ctx context.Context, height uint64) ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	// The light block at the next height contains the app hash for the snapshot height,
+	// and the one after it is needed to build the state.
+	lb, err := s.verifyLightBlock(int64(height + 1))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.verifyLightBlock(int64(height + 2)); err != nil {
+		return nil, err
+	}
+	return lb.AppHash, nil
+}
+
+// Commit implements StateProvider.
+func (s *stateProviderLocal) Commit(ctx context.Context, height uint64) (*types.Commit, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	lb, err := s.verifyLightBlock(int64(height))
+	if err != nil {
+		return nil, err
+	}
+	return lb.Commit, nil
+}
+
+// State implements StateProvider.
+func (s *stateProviderLocal) State(ctx context.Context, height uint64) (sm.State, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	state := sm.State{
+		ChainID:       s.chainID,
+		InitialHeight: s.initialHeight,
+	}
+	if state.InitialHeight == 0 {
+		state.InitialHeight = 1
+	}
+
+	// See stateProviderRPC.State for how the snapshot height maps onto the state heights.
+	lastLightBlock, err := s.verifyLightBlock(int64(height))
+	if err != nil {
+		return sm.State{}, err
+	}
+	currentLightBlock, err := s.verifyLightBlock(int64(height + 1))
+	if err != nil {
+		return sm.State{}, err
+	}
+	nextLightBlock, err := s.verifyLightBlock(int64(height + 2))
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	state.Version = sm.Version{
+		Consensus: currentLightBlock.Version,
+		Software:  version.TMVersion,
+	}
+	state.LastBlockHeight = lastLightBlock.Height
+	state.LastBlockTime = lastLightBlock.Time
+	state.LastBlockID = lastLightBlock.Commit.BlockID
+	state.AppHash = currentLightBlock.AppHash
+	state.LastResultsHash = currentLightBlock.LastResultsHash
+	state.LastValidators = lastLightBlock.ValidatorSet
+	state.Validators = currentLightBlock.ValidatorSet
+	state.NextValidators = nextLightBlock.ValidatorSet
+	state.LastHeightValidatorsChanged = nextLightBlock.Height
+
+	// As for the P2P state provider, the consensus params are verified against the
+	// consensus hash of the next light block.
+	if !bytes.Equal(nextLightBlock.ConsensusHash, s.params.HashConsensusParams()) {
+		return sm.State{}, fmt.Errorf("consensus params hash mismatch at height %d. Expected %v, got %v",
+			currentLightBlock.Height, nextLightBlock.ConsensusHash, s.params.HashConsensusParams())
+	}
+	state.ConsensusParams = s.params
+	state.LastHeightConsensusParamsChanged = currentLightBlock.Height
+
+	return state, nil
+}
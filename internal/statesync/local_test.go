@@ -0,0 +1,207 @@
+package statesync
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/internal/proxy"
+	sm "github.com/tendermint/tendermint/internal/state"
+	"github.com/tendermint/tendermint/internal/statesync/mocks"
+	"github.com/tendermint/tendermint/internal/test/factory"
+	"github.com/tendermint/tendermint/light"
+	ssproto "github.com/tendermint/tendermint/proto/tendermint/statesync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// writeTestLocalSnapshot writes a local snapshot at height 5 with 3 chunks, along with light
+// blocks at heights 1-9, returning the light blocks.
+func writeTestLocalSnapshot(t *testing.T, dir string) (*abci.Snapshot, [][]byte, map[int64]*types.LightBlock) {
+	chain := buildLightBlockChain(t, 1, 10, time.Now())
+	lightBlocks := make([]*types.LightBlock, 0, len(chain))
+	for _, lb := range chain {
+		lightBlocks = append(lightBlocks, lb)
+	}
+	snapshot := &abci.Snapshot{Height: 5, Format: 1, Chunks: 3, Hash: []byte{1, 2, 3}, Metadata: []byte{4}}
+	chunks := [][]byte{{5, 1, 0}, {5, 1, 1}, {5, 1, 2}}
+	require.NoError(t, WriteLocalSnapshot(dir, snapshot, chunks, lightBlocks, *types.DefaultConsensusParams()))
+	return snapshot, chunks, chain
+}
+
+func TestLocalSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	local, chunks, _ := writeTestLocalSnapshot(t, dir)
+
+	s, err := loadLocalSnapshot(dir)
+	require.NoError(t, err)
+	require.Equal(t, &snapshot{
+		Height:   local.Height,
+		Format:   local.Format,
+		Chunks:   local.Chunks,
+		Hash:     local.Hash,
+		Metadata: local.Metadata,
+	}, s)
+	for index, chunk := range chunks {
+		bz, err := os.ReadFile(localChunkPath(dir, uint32(index)))
+		require.NoError(t, err)
+		require.Equal(t, chunk, bz)
+	}
+
+	_, err = loadLocalSnapshot(t.TempDir())
+	require.Error(t, err)
+}
+
+func TestLocalStateProvider(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	_, _, chain := writeTestLocalSnapshot(t, dir)
+
+	testcases := map[string]struct {
+		trustHeight int64
+	}{
+		"trust below snapshot": {1},
+		"trust above snapshot": {8},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			sp, err := NewLocalStateProvider(factory.DefaultTestChainID, 1, dir, light.TrustOptions{
+				Period: 24 * time.Hour,
+				Height: tc.trustHeight,
+				Hash:   chain[tc.trustHeight].Hash(),
+			})
+			require.NoError(t, err)
+
+			appHash, err := sp.AppHash(ctx, 5)
+			require.NoError(t, err)
+			require.Equal(t, chain[6].AppHash.Bytes(), appHash)
+
+			commit, err := sp.Commit(ctx, 5)
+			require.NoError(t, err)
+			require.Equal(t, chain[5].Commit.Hash(), commit.Hash())
+
+			state, err := sp.State(ctx, 5)
+			require.NoError(t, err)
+			require.EqualValues(t, 5, state.LastBlockHeight)
+			require.Equal(t, chain[5].Commit.BlockID, state.LastBlockID)
+			require.Equal(t, chain[6].AppHash.Bytes(), state.AppHash)
+			require.Equal(t, chain[7].ValidatorSet.Hash(), state.NextValidators.Hash())
+			require.Equal(t, *types.DefaultConsensusParams(), state.ConsensusParams)
+
+			// There are no light blocks above height 9.
+			_, err = sp.AppHash(ctx, 8)
+			require.Error(t, err)
+		})
+	}
+
+	// The light block at the trusted height must match the trusted hash.
+	_, err := NewLocalStateProvider(factory.DefaultTestChainID, 1, dir, light.TrustOptions{
+		Period: 24 * time.Hour,
+		Height: 1,
+		Hash:   chain[2].Hash(),
+	})
+	require.Error(t, err)
+
+	// A light block from another chain fails verification.
+	forged := buildLightBlockChain(t, 1, 10, time.Now())[3]
+	require.NoError(t, WriteLocalSnapshot(dir, &abci.Snapshot{Height: 5, Format: 1, Chunks: 1},
+		[][]byte{{1}}, []*types.LightBlock{forged}, *types.DefaultConsensusParams()))
+	sp, err := NewLocalStateProvider(factory.DefaultTestChainID, 1, dir, light.TrustOptions{
+		Period: 24 * time.Hour,
+		Height: 1,
+		Hash:   chain[1].Hash(),
+	})
+	require.NoError(t, err)
+	_, err = sp.AppHash(ctx, 5)
+	require.Error(t, err)
+}
+
+func TestSyncer_SyncAny_local(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	local, chunks, _ := writeTestLocalSnapshot(t, dir)
+
+	stateProvider := &mocks.StateProvider{}
+	stateProvider.On("AppHash", mock.Anything, uint64(5)).Return([]byte("app_hash"), nil)
+	stateProvider.On("Commit", mock.Anything, uint64(5)).Return(&types.Commit{}, nil)
+	stateProvider.On("State", mock.Anything, uint64(5)).Return(
+		sm.State{LastBlockHeight: 5, AppHash: []byte("app_hash")}, nil)
+
+	rts := setup(ctx, t, nil, nil, stateProvider, 2)
+	rts.syncer.localDir = dir
+
+	// Chunk 1 is missing from the dir, and is fetched from a peer offering the same
+	// snapshot. Snapshots from peers are otherwise ignored in favor of the local one.
+	require.NoError(t, os.Remove(localChunkPath(dir, 1)))
+	_, err := rts.syncer.AddSnapshot("aa", &snapshot{Height: 6, Format: 1, Chunks: 1, Hash: []byte{6}})
+	require.NoError(t, err)
+	requestSnapshots := func() error {
+		added, err := rts.syncer.AddSnapshot("aa", &snapshot{Height: 6, Format: 1, Chunks: 1, Hash: []byte{6}})
+		require.False(t, added)
+		if err != nil {
+			return err
+		}
+		_, err = rts.syncer.AddSnapshot("bb", &snapshot{
+			Height:   local.Height,
+			Format:   local.Format,
+			Chunks:   local.Chunks,
+			Hash:     local.Hash,
+			Metadata: local.Metadata,
+		})
+		return err
+	}
+	go func() {
+		for e := range rts.chunkOutCh {
+			msg, ok := e.Message.(*ssproto.ChunkRequest)
+			if !ok || msg.Index != 1 || e.To != "bb" {
+				t.Errorf("unexpected chunk request %v to %v", e.Message, e.To)
+				continue
+			}
+			_, err := rts.syncer.AddChunk(&chunk{
+				Height: local.Height,
+				Format: local.Format,
+				Index:  1,
+				Chunk:  chunks[1],
+				Sender: "bb",
+			})
+			assert.NoError(t, err)
+		}
+	}()
+
+	rts.conn.On("OfferSnapshotSync", mock.Anything, abci.RequestOfferSnapshot{
+		Snapshot: local, AppHash: []byte("app_hash"),
+	}).Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ACCEPT}, nil)
+	for index, chunk := range chunks {
+		sender := localSnapshotPeer
+		if index == 1 {
+			sender = "bb"
+		}
+		rts.conn.On("ApplySnapshotChunkSync", mock.Anything, abci.RequestApplySnapshotChunk{
+			Index: uint32(index), Chunk: chunk, Sender: string(sender),
+		}).Once().Return(&abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ACCEPT}, nil)
+	}
+	rts.connQuery.On("InfoSync", mock.Anything, proxy.RequestInfo).Return(&abci.ResponseInfo{
+		LastBlockHeight:  5,
+		LastBlockAppHash: []byte("app_hash"),
+	}, nil)
+
+	state, _, err := rts.syncer.SyncAny(ctx, 0, requestSnapshots)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, state.LastBlockHeight)
+	rts.conn.AssertExpectations(t)
+
+	// The local chunks were read in place, and are left in the dir.
+	for _, index := range []uint32{0, 2} {
+		assert.FileExists(t, localChunkPath(dir, index))
+	}
+}
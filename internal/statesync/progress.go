@@ -0,0 +1,202 @@
+package statesync
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// syncProgress tracks the progress of a state sync, as reported via RPC and
+// StateSyncProgress events. Snapshot discovery is reported from the
+// snapshot pool, and backfill progress by the reactor.
+type syncProgress struct {
+	mtx        tmsync.Mutex
+	phase      string
+	startTime  time.Time
+	phaseStart time.Time
+
+	snapshot       *snapshot
+	chunksFetched  int64
+	chunksApplied  int64
+	chunksRetried  int64
+	appliedAtStart int64                // chunks applied when the restore (re)started, for the ETA
+	requests       map[uint32]time.Time // chunk request times by index
+	peers          map[types.NodeID]*peerProgress
+}
+
+// peerProgress is the chunks fetched from a peer, and the total time it took
+// to respond to the requests for them.
+type peerProgress struct {
+	chunks   int64
+	bytes    int64
+	duration time.Duration
+}
+
+func newSyncProgress() *syncProgress {
+	now := time.Now()
+	return &syncProgress{
+		phase:      types.StateSyncPhaseDiscovery,
+		startTime:  now,
+		phaseStart: now,
+		requests:   make(map[uint32]time.Time),
+		peers:      make(map[types.NodeID]*peerProgress),
+	}
+}
+
+// setPhase moves the state sync to the given phase.
+func (p *syncProgress) setPhase(phase string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.setPhaseLocked(phase)
+}
+
+func (p *syncProgress) setPhaseLocked(phase string) {
+	if p.phase != phase {
+		p.phase = phase
+		p.phaseStart = time.Now()
+	}
+}
+
+// setSnapshot sets the snapshot being restored and the chunks already fetched
+// and applied, e.g. when resuming a restore, or goes back to discovery if the
+// snapshot is nil. Chunk counts are reset when the snapshot changes.
+func (p *syncProgress) setSnapshot(snapshot *snapshot, chunks *chunkQueue) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if snapshot == nil {
+		p.snapshot = nil
+		p.setPhaseLocked(types.StateSyncPhaseDiscovery)
+		return
+	}
+
+	applied := int64(chunks.numChunksApplied())
+	if p.snapshot != snapshot || applied < p.chunksApplied {
+		p.chunksFetched = int64(chunks.numChunksFetched())
+		p.chunksRetried = 0
+		p.appliedAtStart = applied
+		p.requests = make(map[uint32]time.Time)
+		p.phaseStart = time.Now()
+	}
+	p.snapshot = snapshot
+	p.chunksApplied = applied
+	p.setPhaseLocked(types.StateSyncPhaseRestore)
+}
+
+// chunkRequested records that a chunk was requested from a peer.
+func (p *syncProgress) chunkRequested(index uint32) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.requests[index] = time.Now()
+}
+
+// chunkReceived records a chunk received from a peer, and the time it took
+// since it was requested.
+func (p *syncProgress) chunkReceived(peerID types.NodeID, index uint32, size int) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.chunksFetched++
+	peer, ok := p.peers[peerID]
+	if !ok {
+		peer = &peerProgress{}
+		p.peers[peerID] = peer
+	}
+	peer.chunks++
+	peer.bytes += int64(size)
+	if requested, ok := p.requests[index]; ok {
+		peer.duration += time.Since(requested)
+		delete(p.requests, index)
+	}
+}
+
+// chunkApplied records that a chunk was applied by the app.
+func (p *syncProgress) chunkApplied() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.chunksApplied++
+}
+
+// chunkRetried records that a chunk was requested again, refetched or
+// reapplied.
+func (p *syncProgress) chunkRetried() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.chunksRetried++
+}
+
+// report returns the current progress, with the given discovered snapshots
+// and backfill progress.
+func (p *syncProgress) report(
+	snapshots []types.StateSyncSnapshot,
+	backfilled, backfillTotal int64,
+) types.EventDataStateSyncProgress {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	progress := types.EventDataStateSyncProgress{
+		Phase:               p.phase,
+		StartTime:           p.startTime,
+		Snapshots:           snapshots,
+		ChunksFetched:       p.chunksFetched,
+		ChunksApplied:       p.chunksApplied,
+		ChunksRetried:       p.chunksRetried,
+		Peers:               make([]types.StateSyncPeer, 0, len(p.peers)),
+		BackfilledBlocks:    backfilled,
+		BackfillBlocksTotal: backfillTotal,
+	}
+	if p.snapshot != nil {
+		progress.Snapshot = &types.StateSyncSnapshot{
+			Height: p.snapshot.Height,
+			Format: p.snapshot.Format,
+			Chunks: p.snapshot.Chunks,
+			Hash:   p.snapshot.Hash,
+		}
+		for _, s := range snapshots {
+			if s.Height == p.snapshot.Height && s.Format == p.snapshot.Format && bytes.Equal(s.Hash, p.snapshot.Hash) {
+				progress.Snapshot.Peers = s.Peers
+			}
+		}
+		progress.ChunksTotal = int64(p.snapshot.Chunks)
+	}
+
+	for peerID, peer := range p.peers {
+		var rate int64
+		if peer.duration > 0 {
+			rate = int64(float64(peer.bytes) / peer.duration.Seconds())
+		}
+		progress.Peers = append(progress.Peers, types.StateSyncPeer{
+			NodeID:         peerID,
+			ChunksFetched:  peer.chunks,
+			BytesFetched:   peer.bytes,
+			BytesPerSecond: rate,
+		})
+	}
+	sort.Slice(progress.Peers, func(i, j int) bool {
+		return progress.Peers[i].NodeID < progress.Peers[j].NodeID
+	})
+
+	// The remaining time is extrapolated from the rate of progress in the
+	// current phase.
+	switch p.phase {
+	case types.StateSyncPhaseRestore:
+		progress.RemainingTime = remainingTime(p.phaseStart,
+			p.chunksApplied-p.appliedAtStart, progress.ChunksTotal-p.chunksApplied)
+	case types.StateSyncPhaseBackfill:
+		progress.RemainingTime = remainingTime(p.phaseStart, backfilled, backfillTotal-backfilled)
+	}
+
+	return progress
+}
+
+// remainingTime estimates the time to process the remaining items, given the
+// number of items processed since start, or returns 0 if unknown.
+func remainingTime(start time.Time, done, remaining int64) time.Duration {
+	if done <= 0 || remaining <= 0 {
+		return 0
+	}
+	return time.Duration(int64(time.Since(start)) / done * remaining)
+}
@@ -0,0 +1,69 @@
+package statesync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestSyncProgress(t *testing.T) {
+	queue, teardown := setupChunkQueue(t)
+	defer teardown()
+
+	progress := newSyncProgress()
+	report := progress.report([]types.StateSyncSnapshot{}, 0, 0)
+	require.Equal(t, types.StateSyncPhaseDiscovery, report.Phase)
+	require.Nil(t, report.Snapshot)
+
+	snapshots := []types.StateSyncSnapshot{{Height: 3, Format: 1, Chunks: 5, Hash: []byte{7}, Peers: 2}}
+	progress.setSnapshot(queue.snapshot, queue)
+
+	// Two chunks are fetched from a peer, one of them after a retry, and
+	// applied.
+	for index := uint32(0); index < 2; index++ {
+		progress.chunkRequested(index)
+		_, err := queue.Add(&chunk{Height: 3, Format: 1, Index: index, Chunk: []byte{1, 2, 3}, Sender: "aa"})
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		progress.chunkReceived("aa", index, 3)
+		progress.chunkApplied()
+	}
+	progress.chunkRetried()
+
+	report = progress.report(snapshots, 0, 0)
+	require.Equal(t, types.StateSyncPhaseRestore, report.Phase)
+	require.Equal(t, &snapshots[0], report.Snapshot)
+	require.EqualValues(t, 5, report.ChunksTotal)
+	require.EqualValues(t, 2, report.ChunksFetched)
+	require.EqualValues(t, 2, report.ChunksApplied)
+	require.EqualValues(t, 1, report.ChunksRetried)
+	require.Len(t, report.Peers, 1)
+	require.Equal(t, types.NodeID("aa"), report.Peers[0].NodeID)
+	require.EqualValues(t, 2, report.Peers[0].ChunksFetched)
+	require.EqualValues(t, 6, report.Peers[0].BytesFetched)
+	require.Greater(t, report.Peers[0].BytesPerSecond, int64(0))
+	require.Greater(t, report.RemainingTime, time.Duration(0))
+
+	// Retrying the whole snapshot resets the chunk counts.
+	queue.RetryAll()
+	progress.setSnapshot(queue.snapshot, queue)
+	report = progress.report(snapshots, 0, 0)
+	require.EqualValues(t, 0, report.ChunksApplied)
+	require.EqualValues(t, 0, report.ChunksRetried)
+	require.Zero(t, report.RemainingTime)
+
+	// Rejecting the snapshot goes back to discovery.
+	progress.setSnapshot(nil, nil)
+	require.Equal(t, types.StateSyncPhaseDiscovery, progress.report(snapshots, 0, 0).Phase)
+
+	progress.setPhase(types.StateSyncPhaseBackfill)
+	time.Sleep(10 * time.Millisecond)
+	report = progress.report(snapshots, 10, 20)
+	require.Equal(t, types.StateSyncPhaseBackfill, report.Phase)
+	require.EqualValues(t, 10, report.BackfilledBlocks)
+	require.EqualValues(t, 20, report.BackfillBlocksTotal)
+	require.Greater(t, report.RemainingTime, time.Duration(0))
+}
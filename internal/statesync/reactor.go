@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"runtime/debug"
 	"sort"
@@ -12,6 +13,7 @@ import (
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/internal/eventbus"
 	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
 	"github.com/tendermint/tendermint/internal/p2p"
 	"github.com/tendermint/tendermint/internal/proxy"
@@ -43,6 +45,9 @@ const (
 	// ParamsChannel exchanges consensus params
 	ParamsChannel = p2p.ChannelID(0x63)
 
+	// BlockSnapshotChannel exchanges block snapshot metadata and chunks
+	BlockSnapshotChannel = p2p.ChannelID(0x64)
+
 	// recentSnapshots is the number of recent snapshots to send and receive per peer.
 	recentSnapshots = 10
 
@@ -58,6 +63,13 @@ const (
 	// paramMsgSize is the maximum size of a paramsResponseMessage
 	paramMsgSize = int(1e5) // ~100kb
 
+	// blockSnapshotMsgSize is the maximum size of a block snapshot chunkResponseMessage
+	blockSnapshotMsgSize = int(64e6) // ~64MB
+
+	// chunkLoadTimeout is how long loading a snapshot chunk requested by a
+	// peer from the application may take
+	chunkLoadTimeout = 15 * time.Second
+
 	// lightBlockResponseTimeout is how long the dispatcher waits for a peer to
 	// return a light block
 	lightBlockResponseTimeout = 10 * time.Second
@@ -69,6 +81,10 @@ const (
 	// maxLightBlockRequestRetries is the amount of retries acceptable before
 	// the backfill process aborts
 	maxLightBlockRequestRetries = 20
+
+	// progressReportInterval is how often StateSyncProgress events are
+	// published while state syncing, in addition to phase changes
+	progressReportInterval = 10 * time.Second
 )
 
 func GetChannelDescriptors() []*p2p.ChannelDescriptor {
@@ -106,6 +122,14 @@ func GetChannelDescriptors() []*p2p.ChannelDescriptor {
 			RecvMessageCapacity: paramMsgSize,
 			RecvBufferCapacity:  128,
 		},
+		{
+			ID:                  BlockSnapshotChannel,
+			MessageType:         new(ssproto.Message),
+			Priority:            1,
+			SendQueueCapacity:   4,
+			RecvMessageCapacity: blockSnapshotMsgSize,
+			RecvBufferCapacity:  32,
+		},
 	}
 
 }
@@ -120,6 +144,7 @@ type Metricer interface {
 	SnapshotChunksTotal() int64
 	BackFilledBlocks() int64
 	BackFillBlocksTotal() int64
+	Progress() *types.EventDataStateSyncProgress
 }
 
 // Reactor handles state sync, both restoring snapshots for the local node and
@@ -134,14 +159,16 @@ type Reactor struct {
 	stateStore    sm.Store
 	blockStore    *store.BlockStore
 
-	conn        proxy.AppConnSnapshot
-	connQuery   proxy.AppConnQuery
-	tempDir     string
-	snapshotCh  *p2p.Channel
-	chunkCh     *p2p.Channel
-	blockCh     *p2p.Channel
-	paramsCh    *p2p.Channel
-	peerUpdates *p2p.PeerUpdates
+	conn       proxy.AppConnSnapshot
+	connQuery  proxy.AppConnQuery
+	tempDir    string
+	snapshotCh *p2p.Channel
+	chunkCh    *p2p.Channel
+	blockCh    *p2p.Channel
+	paramsCh   *p2p.Channel
+	// blockSnapshotCh serves and fetches block snapshots
+	blockSnapshotCh *p2p.Channel
+	peerUpdates     *p2p.PeerUpdates
 
 	// Dispatcher is used to multiplex light block requests and responses over multiple
 	// peers used by the p2p state provider and in reverse sync.
@@ -159,6 +186,16 @@ type Reactor struct {
 	metrics            *Metrics
 	backfillBlockTotal int64
 	backfilledBlocks   int64
+
+	// progress is set when a state sync starts, and kept once it completes.
+	progress *syncProgress
+	eventBus *eventbus.EventBus
+
+	// blockSnapshotter is set if block snapshots are served, and blockSnapshots
+	// and blockChunks while a block snapshot is being fetched.
+	blockSnapshotter *blockSnapshotter
+	blockSnapshots   *snapshotPool
+	blockChunks      chan *chunk
 }
 
 // NewReactor returns a reference to a new state sync reactor, which implements
@@ -172,7 +209,7 @@ func NewReactor(
 	logger log.Logger,
 	conn proxy.AppConnSnapshot,
 	connQuery proxy.AppConnQuery,
-	snapshotCh, chunkCh, blockCh, paramsCh *p2p.Channel,
+	snapshotCh, chunkCh, blockCh, paramsCh, blockSnapshotCh *p2p.Channel,
 	peerUpdates *p2p.PeerUpdates,
 	stateStore sm.Store,
 	blockStore *store.BlockStore,
@@ -180,30 +217,41 @@ func NewReactor(
 	ssMetrics *Metrics,
 ) *Reactor {
 	r := &Reactor{
-		logger:        logger,
-		chainID:       chainID,
-		initialHeight: initialHeight,
-		cfg:           cfg,
-		conn:          conn,
-		connQuery:     connQuery,
-		snapshotCh:    snapshotCh,
-		chunkCh:       chunkCh,
-		blockCh:       blockCh,
-		paramsCh:      paramsCh,
-		peerUpdates:   peerUpdates,
-		tempDir:       tempDir,
-		stateStore:    stateStore,
-		blockStore:    blockStore,
-		peers:         newPeerList(),
-		dispatcher:    NewDispatcher(blockCh),
-		providers:     make(map[types.NodeID]*BlockProvider),
-		metrics:       ssMetrics,
+		logger:          logger,
+		chainID:         chainID,
+		initialHeight:   initialHeight,
+		cfg:             cfg,
+		conn:            conn,
+		connQuery:       connQuery,
+		snapshotCh:      snapshotCh,
+		chunkCh:         chunkCh,
+		blockCh:         blockCh,
+		paramsCh:        paramsCh,
+		blockSnapshotCh: blockSnapshotCh,
+		peerUpdates:     peerUpdates,
+		tempDir:         tempDir,
+		stateStore:      stateStore,
+		blockStore:      blockStore,
+		peers:           newPeerList(),
+		dispatcher:      NewDispatcher(blockCh),
+		providers:       make(map[types.NodeID]*BlockProvider),
+		metrics:         ssMetrics,
+	}
+
+	if cfg.BlockSnapshotInterval > 0 {
+		r.blockSnapshotter = newBlockSnapshotter(logger, blockStore, cfg.BlockSnapshotInterval, cfg.BlockSnapshotBlocks)
 	}
 
 	r.BaseService = *service.NewBaseService(logger, "StateSync", r)
 	return r
 }
 
+// SetEventBus sets the event bus on which StateSyncProgress events are
+// published.
+func (r *Reactor) SetEventBus(b *eventbus.EventBus) {
+	r.eventBus = b
+}
+
 // OnStart starts separate go routines for each p2p Channel and listens for
 // envelopes on each. In addition, it also listens for peer updates and handles
 // messages on that p2p channel accordingly. Note, we do not launch a go-routine to
@@ -215,7 +263,11 @@ func (r *Reactor) OnStart(ctx context.Context) error {
 	go r.processCh(ctx, r.chunkCh, "chunk")
 	go r.processCh(ctx, r.blockCh, "light block")
 	go r.processCh(ctx, r.paramsCh, "consensus params")
+	go r.processCh(ctx, r.blockSnapshotCh, "block snapshot")
 	go r.processPeerUpdates(ctx)
+	if r.blockSnapshotter != nil {
+		go r.blockSnapshotter.run(ctx)
+	}
 
 	return nil
 }
@@ -233,10 +285,27 @@ func (r *Reactor) OnStop() {
 // blocksync can commence. It will then proceed to backfill the necessary amount
 // of historical blocks before participating in consensus
 func (r *Reactor) Sync(ctx context.Context) (sm.State, error) {
+	// Prefer peers that are past the trusted height, and thus may have
+	// snapshots we can restore, until we're done syncing.
+	r.peerUpdates.SetSyncTarget(p2p.SyncTarget{Height: r.cfg.TrustHeight})
+	defer r.peerUpdates.SetSyncTarget(p2p.SyncTarget{})
+
+	r.mtx.Lock()
+	progress := newSyncProgress()
+	r.progress = progress
+	r.mtx.Unlock()
+
+	reportCtx, cancelReport := context.WithCancel(ctx)
+	defer cancelReport()
+	go r.reportProgress(reportCtx)
+
 	// We need at least two peers (for cross-referencing of light blocks) before we can
-	// begin state sync
-	if err := r.waitForEnoughPeers(ctx, 2); err != nil {
-		return sm.State{}, err
+	// begin state sync, unless we're restoring a local snapshot.
+	local := r.cfg.SnapshotDir != ""
+	if !local {
+		if err := r.waitForEnoughPeers(ctx, 2); err != nil {
+			return sm.State{}, err
+		}
 	}
 
 	r.mtx.Lock()
@@ -261,6 +330,7 @@ func (r *Reactor) Sync(ctx context.Context) (sm.State, error) {
 		r.tempDir,
 		r.metrics,
 	)
+	r.syncer.progress = progress
 	r.mtx.Unlock()
 	defer func() {
 		r.mtx.Lock()
@@ -293,18 +363,37 @@ func (r *Reactor) Sync(ctx context.Context) (sm.State, error) {
 		return sm.State{}, fmt.Errorf("failed to store last seen commit: %w", err)
 	}
 
+	// The restored snapshot is no longer needed to resume the sync.
+	if err := r.syncer.RemoveChunks(); err != nil {
+		r.logger.Error("failed to remove state sync chunks", "err", err)
+	}
+
+	progress.setPhase(types.StateSyncPhaseBackfill)
 	err = r.Backfill(ctx, state)
 	if err != nil {
 		r.logger.Error("backfill failed. Proceeding optimistically...", "err", err)
 	}
 
+	// Block snapshots replace the headers saved by backfill with full blocks. They
+	// need peers, which we may not have when restoring a local snapshot.
+	if r.cfg.FetchBlockSnapshot && !local {
+		if err := r.fetchBlockSnapshot(ctx, state); err != nil {
+			r.logger.Error("failed to fetch block snapshot. Proceeding optimistically...", "err", err)
+		}
+	}
+
+	cancelReport()
+	progress.setPhase(types.StateSyncPhaseComplete)
+	r.publishProgress(ctx, r.Progress())
+
 	return state, nil
 }
 
 // Backfill sequentially fetches, verifies and stores light blocks in reverse
 // order. It does not stop verifying blocks until reaching a block with a height
 // and time that is less or equal to the stopHeight and stopTime. The
-// trustedBlockID should be of the header at startHeight.
+// trustedBlockID should be of the header at startHeight. When restoring a
+// local snapshot, light blocks are taken from its directory where possible.
 func (r *Reactor) Backfill(ctx context.Context, state sm.State) error {
 	params := state.ConsensusParams.Evidence
 	stopHeight := state.LastBlockHeight - params.MaxAgeNumBlocks
@@ -315,6 +404,14 @@ func (r *Reactor) Backfill(ctx context.Context, state sm.State) error {
 		// this essentially makes stop time a void criteria for termination
 		stopTime = state.LastBlockTime
 	}
+	var local *localLightBlocks
+	if r.cfg.SnapshotDir != "" {
+		lightBlocks, err := loadLocalLightBlocks(r.cfg.SnapshotDir, state.ChainID)
+		if err != nil {
+			return err
+		}
+		local = &localLightBlocks{blocks: lightBlocks}
+	}
 	return r.backfill(
 		ctx,
 		state.ChainID,
@@ -323,6 +420,7 @@ func (r *Reactor) Backfill(ctx context.Context, state sm.State) error {
 		state.InitialHeight,
 		state.LastBlockID,
 		stopTime,
+		local,
 	)
 }
 
@@ -332,6 +430,7 @@ func (r *Reactor) backfill(
 	startHeight, stopHeight, initialHeight int64,
 	trustedBlockID types.BlockID,
 	stopTime time.Time,
+	local *localLightBlocks,
 ) error {
 	r.logger.Info("starting backfill process...", "startHeight", startHeight,
 		"stopHeight", stopHeight, "stopTime", stopTime, "trustedBlockID", trustedBlockID)
@@ -361,6 +460,24 @@ func (r *Reactor) backfill(
 				case <-ctx.Done():
 					return
 				case height := <-queue.nextHeight():
+					// light blocks from a local snapshot are verified like any other
+					if lb := local.take(height); lb != nil {
+						queue.add(lightBlockResponse{
+							block: lb,
+							peer:  localSnapshotPeer,
+						})
+						continue
+					}
+					// a local snapshot may be restored without any peers, so we
+					// don't wait for them indefinitely
+					if local != nil && r.peers.Len() == 0 {
+						queue.retry(height)
+						r.logger.Info("backfill: no local light block and no connected peers to fetch it from; sleeping...",
+							"height", height, "sleepTime", sleepTime)
+						time.Sleep(sleepTime)
+						continue
+					}
+
 					// pop the next peer of the list to send a request to
 					peer := r.peers.Pop(ctx)
 					r.logger.Debug("fetching next block", "height", height, "peer", peer)
@@ -406,8 +523,9 @@ func (r *Reactor) backfill(
 							"err", err, "height", height)
 						queue.retry(height)
 						if serr := r.blockCh.SendError(ctx, p2p.PeerError{
-							NodeID: peer,
-							Err:    fmt.Errorf("received invalid light block: %w", err),
+							NodeID:      peer,
+							Err:         fmt.Errorf("received invalid light block: %w", err),
+							Misbehavior: p2p.MisbehaviorInvalidBlock,
 						}); serr != nil {
 							return
 						}
@@ -441,10 +559,15 @@ func (r *Reactor) backfill(
 			// checked in the `ValidateBasic`
 			if w, g := trustedBlockID.Hash, resp.block.Hash(); !bytes.Equal(w, g) {
 				r.logger.Info("received invalid light block. header hash doesn't match trusted LastBlockID",
-					"trustedHash", w, "receivedHash", g, "height", resp.block.Height)
+					"trustedHash", w, "receivedHash", g, "height", resp.block.Height, "peer", resp.peer)
+				if resp.peer == localSnapshotPeer {
+					queue.retry(resp.block.Height)
+					continue
+				}
 				if err := r.blockCh.SendError(ctx, p2p.PeerError{
-					NodeID: resp.peer,
-					Err:    fmt.Errorf("received invalid light block. Expected hash %v, got: %v", w, g),
+					NodeID:      resp.peer,
+					Err:         fmt.Errorf("received invalid light block. Expected hash %v, got: %v", w, g),
+					Misbehavior: p2p.MisbehaviorInvalidBlock,
 				}); err != nil {
 					return nil
 				}
@@ -572,6 +695,37 @@ func (r *Reactor) handleSnapshotMessage(ctx context.Context, envelope p2p.Envelo
 	return nil
 }
 
+// loadChunk loads a requested snapshot chunk from the application, streaming
+// it so that it need not fit in a single ABCI message. It returns nil if the
+// application doesn't have the chunk, which can't be told apart from an empty
+// chunk on a stream.
+func (r *Reactor) loadChunk(ctx context.Context, msg *ssproto.ChunkRequest) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, chunkLoadTimeout)
+	defer cancel()
+
+	body, err := r.conn.LoadSnapshotChunkStream(ctx, abci.RequestLoadSnapshotChunk{
+		Height: msg.Height,
+		Format: msg.Format,
+		Chunk:  msg.Index,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	// The chunk must fit in a chunk response, along with its metadata.
+	chunk, err := io.ReadAll(io.LimitReader(body, int64(chunkMsgSize)))
+	switch {
+	case err != nil:
+		return nil, err
+	case len(chunk) >= chunkMsgSize:
+		return nil, fmt.Errorf("chunk exceeds the maximum message size of %d bytes", chunkMsgSize)
+	case len(chunk) == 0:
+		return nil, nil
+	}
+	return chunk, nil
+}
+
 // handleChunkMessage handles envelopes sent from peers on the ChunkChannel.
 // It returns an error only if the Envelope.Message is unknown for this channel.
 // This should never be called outside of handleMessage.
@@ -585,11 +739,7 @@ func (r *Reactor) handleChunkMessage(ctx context.Context, envelope p2p.Envelope)
 			"chunk", msg.Index,
 			"peer", envelope.From,
 		)
-		resp, err := r.conn.LoadSnapshotChunkSync(context.TODO(), abci.RequestLoadSnapshotChunk{
-			Height: msg.Height,
-			Format: msg.Format,
-			Chunk:  msg.Index,
-		})
+		chunk, err := r.loadChunk(ctx, msg)
 		if err != nil {
 			r.logger.Error(
 				"failed to load chunk",
@@ -615,8 +765,8 @@ func (r *Reactor) handleChunkMessage(ctx context.Context, envelope p2p.Envelope)
 				Height:  msg.Height,
 				Format:  msg.Format,
 				Index:   msg.Index,
-				Chunk:   resp.Chunk,
-				Missing: resp.Chunk == nil,
+				Chunk:   chunk,
+				Missing: chunk == nil,
 			},
 		}); err != nil {
 			return err
@@ -762,6 +912,111 @@ func (r *Reactor) handleParamsMessage(ctx context.Context, envelope p2p.Envelope
 	return nil
 }
 
+func (r *Reactor) handleBlockSnapshotMessage(ctx context.Context, envelope p2p.Envelope) error {
+	switch msg := envelope.Message.(type) {
+	case *ssproto.SnapshotsRequest:
+		if r.blockSnapshotter == nil {
+			return nil
+		}
+		for _, s := range r.blockSnapshotter.list() {
+			r.logger.Debug("advertising block snapshot", "height", s.Height, "peer", envelope.From)
+			if err := r.blockSnapshotCh.Send(ctx, p2p.Envelope{
+				To: envelope.From,
+				Message: &ssproto.SnapshotsResponse{
+					Height:   s.Height,
+					Format:   s.Format,
+					Chunks:   s.Chunks,
+					Hash:     s.Hash,
+					Metadata: s.Metadata,
+				},
+			}); err != nil {
+				return err
+			}
+		}
+
+	case *ssproto.SnapshotsResponse:
+		r.mtx.RLock()
+		defer r.mtx.RUnlock()
+
+		if r.blockSnapshots == nil {
+			r.logger.Debug("received unexpected block snapshot; no fetch in progress", "peer", envelope.From)
+			return nil
+		}
+		if msg.Format != blockSnapshotFormat {
+			r.logger.Debug("ignoring block snapshot with unknown format", "format", msg.Format, "peer", envelope.From)
+			return nil
+		}
+		r.logger.Info("received block snapshot", "height", msg.Height, "peer", envelope.From)
+		_, err := r.blockSnapshots.Add(envelope.From, &snapshot{
+			Height:   msg.Height,
+			Format:   msg.Format,
+			Chunks:   msg.Chunks,
+			Hash:     msg.Hash,
+			Metadata: msg.Metadata,
+		})
+		if err != nil {
+			r.logger.Error("failed to add block snapshot", "height", msg.Height, "err", err, "peer", envelope.From)
+			return nil
+		}
+
+	case *ssproto.ChunkRequest:
+		var (
+			bz  []byte
+			err error
+		)
+		if r.blockSnapshotter != nil {
+			bz, err = r.blockSnapshotter.loadChunk(msg.Height, msg.Format, msg.Index)
+			if err != nil {
+				r.logger.Error("failed to load block snapshot chunk", "height", msg.Height,
+					"chunk", msg.Index, "err", err, "peer", envelope.From)
+			}
+		}
+		r.logger.Debug("sending block snapshot chunk", "height", msg.Height, "chunk", msg.Index,
+			"missing", bz == nil, "peer", envelope.From)
+		if err := r.blockSnapshotCh.Send(ctx, p2p.Envelope{
+			To: envelope.From,
+			Message: &ssproto.ChunkResponse{
+				Height:  msg.Height,
+				Format:  msg.Format,
+				Index:   msg.Index,
+				Chunk:   bz,
+				Missing: bz == nil,
+			},
+		}); err != nil {
+			return err
+		}
+
+	case *ssproto.ChunkResponse:
+		r.mtx.RLock()
+		defer r.mtx.RUnlock()
+
+		if r.blockChunks == nil {
+			r.logger.Debug("received unexpected block snapshot chunk; no fetch in progress", "peer", envelope.From)
+			return nil
+		}
+		var bz []byte
+		if !msg.Missing {
+			bz = msg.Chunk
+		}
+		select {
+		case r.blockChunks <- &chunk{
+			Height: msg.Height,
+			Format: msg.Format,
+			Index:  msg.Index,
+			Chunk:  bz,
+			Sender: envelope.From,
+		}:
+		default:
+			r.logger.Debug("dropping block snapshot chunk", "chunk", msg.Index, "peer", envelope.From)
+		}
+
+	default:
+		return fmt.Errorf("received unknown message: %T", msg)
+	}
+
+	return nil
+}
+
 // handleMessage handles an Envelope sent from a peer on a specific p2p Channel.
 // It will handle errors and any possible panics gracefully. A caller can handle
 // any error returned by sending a PeerError on the respective channel.
@@ -788,6 +1043,8 @@ func (r *Reactor) handleMessage(ctx context.Context, chID p2p.ChannelID, envelop
 		err = r.handleLightBlockMessage(ctx, envelope)
 	case ParamsChannel:
 		err = r.handleParamsMessage(ctx, envelope)
+	case BlockSnapshotChannel:
+		err = r.handleBlockSnapshotMessage(ctx, envelope)
 	default:
 		err = fmt.Errorf("unknown channel ID (%d) for envelope (%v)", chID, envelope)
 	}
@@ -986,9 +1243,16 @@ func (r *Reactor) initStateProvider(ctx context.Context, chainID string, initial
 	}
 	spLogger := r.logger.With("module", "stateprovider")
 	spLogger.Info("initializing state provider", "trustPeriod", to.Period,
-		"trustHeight", to.Height, "useP2P", r.cfg.UseP2P)
+		"trustHeight", to.Height, "useP2P", r.cfg.UseP2P, "snapshotDir", r.cfg.SnapshotDir)
+
+	switch {
+	case r.cfg.SnapshotDir != "":
+		r.stateProvider, err = NewLocalStateProvider(chainID, initialHeight, r.cfg.SnapshotDir, to)
+		if err != nil {
+			return fmt.Errorf("failed to initialize local state provider: %w", err)
+		}
 
-	if r.cfg.UseP2P {
+	case r.cfg.UseP2P:
 		if err := r.waitForEnoughPeers(ctx, 2); err != nil {
 			return err
 		}
@@ -1003,7 +1267,8 @@ func (r *Reactor) initStateProvider(ctx context.Context, chainID string, initial
 		if err != nil {
 			return fmt.Errorf("failed to initialize P2P state provider: %w", err)
 		}
-	} else {
+
+	default:
 		r.stateProvider, err = NewRPCStateProvider(ctx, chainID, initialHeight, r.cfg.RPCServers, to, spLogger)
 		if err != nil {
 			return fmt.Errorf("failed to initialize RPC state provider: %w", err)
@@ -1074,3 +1339,67 @@ func (r *Reactor) BackFillBlocksTotal() int64 {
 
 	return r.backfillBlockTotal
 }
+
+// Progress returns the progress of the current or last state sync, or nil if
+// no state sync has been started.
+func (r *Reactor) Progress() *types.EventDataStateSyncProgress {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	if r.progress == nil {
+		return nil
+	}
+
+	snapshots := []types.StateSyncSnapshot{}
+	if r.syncer != nil {
+		for _, s := range r.syncer.snapshots.Ranked() {
+			snapshots = append(snapshots, types.StateSyncSnapshot{
+				Height: s.Height,
+				Format: s.Format,
+				Chunks: s.Chunks,
+				Hash:   s.Hash,
+				Peers:  len(r.syncer.snapshots.GetPeers(s)),
+			})
+		}
+	}
+
+	progress := r.progress.report(snapshots, r.backfilledBlocks, r.backfillBlockTotal)
+	return &progress
+}
+
+// reportProgress publishes StateSyncProgress events until the context is
+// canceled, whenever the phase changes or otherwise every
+// progressReportInterval.
+func (r *Reactor) reportProgress(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var (
+		lastPhase  string
+		lastReport time.Time
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			progress := r.Progress()
+			if progress.Phase == lastPhase && time.Since(lastReport) < progressReportInterval {
+				continue
+			}
+			lastPhase, lastReport = progress.Phase, time.Now()
+			r.publishProgress(ctx, progress)
+		}
+	}
+}
+
+// publishProgress publishes a StateSyncProgress event, if there is an event
+// bus.
+func (r *Reactor) publishProgress(ctx context.Context, progress *types.EventDataStateSyncProgress) {
+	if r.eventBus == nil {
+		return
+	}
+	if err := r.eventBus.PublishEventStateSyncProgress(ctx, *progress); err != nil {
+		r.logger.Error("failed to publish state sync progress", "err", err)
+	}
+}
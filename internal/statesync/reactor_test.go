@@ -1,8 +1,10 @@
 package statesync
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"testing"
@@ -61,6 +63,11 @@ type reactorTestSuite struct {
 	paramsOutCh     chan p2p.Envelope
 	paramsPeerErrCh chan p2p.PeerError
 
+	blockSnapshotChannel   *p2p.Channel
+	blockSnapshotInCh      chan p2p.Envelope
+	blockSnapshotOutCh     chan p2p.Envelope
+	blockSnapshotPeerErrCh chan p2p.PeerError
+
 	peerUpdateCh chan p2p.PeerUpdate
 	peerUpdates  *p2p.PeerUpdates
 
@@ -89,21 +96,24 @@ func setup(
 	}
 
 	rts := &reactorTestSuite{
-		snapshotInCh:      make(chan p2p.Envelope, chBuf),
-		snapshotOutCh:     make(chan p2p.Envelope, chBuf),
-		snapshotPeerErrCh: make(chan p2p.PeerError, chBuf),
-		chunkInCh:         make(chan p2p.Envelope, chBuf),
-		chunkOutCh:        make(chan p2p.Envelope, chBuf),
-		chunkPeerErrCh:    make(chan p2p.PeerError, chBuf),
-		blockInCh:         make(chan p2p.Envelope, chBuf),
-		blockOutCh:        make(chan p2p.Envelope, chBuf),
-		blockPeerErrCh:    make(chan p2p.PeerError, chBuf),
-		paramsInCh:        make(chan p2p.Envelope, chBuf),
-		paramsOutCh:       make(chan p2p.Envelope, chBuf),
-		paramsPeerErrCh:   make(chan p2p.PeerError, chBuf),
-		conn:              conn,
-		connQuery:         connQuery,
-		stateProvider:     stateProvider,
+		snapshotInCh:           make(chan p2p.Envelope, chBuf),
+		snapshotOutCh:          make(chan p2p.Envelope, chBuf),
+		snapshotPeerErrCh:      make(chan p2p.PeerError, chBuf),
+		chunkInCh:              make(chan p2p.Envelope, chBuf),
+		chunkOutCh:             make(chan p2p.Envelope, chBuf),
+		chunkPeerErrCh:         make(chan p2p.PeerError, chBuf),
+		blockInCh:              make(chan p2p.Envelope, chBuf),
+		blockOutCh:             make(chan p2p.Envelope, chBuf),
+		blockPeerErrCh:         make(chan p2p.PeerError, chBuf),
+		paramsInCh:             make(chan p2p.Envelope, chBuf),
+		paramsOutCh:            make(chan p2p.Envelope, chBuf),
+		paramsPeerErrCh:        make(chan p2p.PeerError, chBuf),
+		blockSnapshotInCh:      make(chan p2p.Envelope, chBuf),
+		blockSnapshotOutCh:     make(chan p2p.Envelope, chBuf),
+		blockSnapshotPeerErrCh: make(chan p2p.PeerError, chBuf),
+		conn:                   conn,
+		connQuery:              connQuery,
+		stateProvider:          stateProvider,
 	}
 
 	rts.peerUpdateCh = make(chan p2p.PeerUpdate, chBuf)
@@ -141,6 +151,14 @@ func setup(
 		rts.paramsPeerErrCh,
 	)
 
+	rts.blockSnapshotChannel = p2p.NewChannel(
+		BlockSnapshotChannel,
+		new(ssproto.Message),
+		rts.blockSnapshotInCh,
+		rts.blockSnapshotOutCh,
+		rts.blockSnapshotPeerErrCh,
+	)
+
 	rts.stateStore = &smmocks.Store{}
 	rts.blockStore = store.NewBlockStore(dbm.NewMemDB())
 
@@ -157,6 +175,7 @@ func setup(
 		rts.chunkChannel,
 		rts.blockChannel,
 		rts.paramsChannel,
+		rts.blockSnapshotChannel,
 		rts.peerUpdates,
 		rts.stateStore,
 		rts.blockStore,
@@ -271,10 +290,10 @@ func TestReactor_ChunkRequest(t *testing.T) {
 			[]byte{1, 2, 3},
 			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Chunk: []byte{1, 2, 3}},
 		},
-		"empty chunk is returned, as empty": {
+		"empty chunk is returned as missing, since a stream can't tell them apart": {
 			&ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1},
 			[]byte{},
-			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Chunk: []byte{}},
+			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Missing: true},
 		},
 		"nil (missing) chunk is returned as missing": {
 			&ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1},
@@ -298,11 +317,11 @@ func TestReactor_ChunkRequest(t *testing.T) {
 
 			// mock ABCI connection to return local snapshots
 			conn := &proxymocks.AppConnSnapshot{}
-			conn.On("LoadSnapshotChunkSync", mock.Anything, abci.RequestLoadSnapshotChunk{
+			conn.On("LoadSnapshotChunkStream", mock.Anything, abci.RequestLoadSnapshotChunk{
 				Height: tc.request.Height,
 				Format: tc.request.Format,
 				Chunk:  tc.request.Index,
-			}).Return(&abci.ResponseLoadSnapshotChunk{Chunk: tc.chunk}, nil)
+			}).Return(io.NopCloser(bytes.NewReader(tc.chunk)), nil)
 
 			rts := setup(ctx, t, conn, nil, nil, 2)
 
@@ -645,6 +664,7 @@ func TestReactor_Backfill(t *testing.T) {
 				1,
 				factory.MakeBlockIDWithHash(chain[startHeight].Header.Hash()),
 				stopTime,
+				nil,
 			)
 			if failureRate > 3 {
 				require.Error(t, err)
@@ -671,6 +691,94 @@ func TestReactor_Backfill(t *testing.T) {
 	}
 }
 
+func TestReactor_BackfillLocal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		startHeight int64 = 20
+		stopHeight  int64 = 10
+		stopTime          = time.Date(2020, 1, 1, 0, 100, 0, 0, time.UTC)
+	)
+
+	testcases := map[string]struct {
+		missing int64 // height missing from the local light blocks, fetched from a peer
+	}{
+		"all local":       {0},
+		"missing locally": {15},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			rts := setup(ctx, t, nil, nil, nil, 21)
+			rts.stateStore.On("SaveValidatorSets", mock.AnythingOfType("int64"), mock.AnythingOfType("int64"),
+				mock.AnythingOfType("*types.ValidatorSet")).Return(nil)
+
+			chain := buildLightBlockChain(t, stopHeight-1, startHeight+1, stopTime)
+			local := &localLightBlocks{blocks: make(map[int64]*types.LightBlock)}
+			for height, lb := range chain {
+				if height != tc.missing {
+					local.blocks[height] = lb
+				}
+			}
+
+			requests := 0
+			if tc.missing != 0 {
+				rts.peerUpdateCh <- p2p.PeerUpdate{
+					NodeID: types.NodeID("a"),
+					Status: p2p.PeerStatusUp,
+				}
+				retryUntil(ctx, t, func() bool { return rts.reactor.peers.Len() == 1 }, time.Second)
+				requests = 1
+			}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case envelope := <-rts.blockOutCh:
+						msg, ok := envelope.Message.(*ssproto.LightBlockRequest)
+						require.True(t, ok)
+						require.EqualValues(t, tc.missing, msg.Height)
+						requests--
+						lb, err := chain[int64(msg.Height)].ToProto()
+						require.NoError(t, err)
+						rts.blockInCh <- p2p.Envelope{
+							From:    envelope.To,
+							Message: &ssproto.LightBlockResponse{LightBlock: lb},
+						}
+					}
+				}
+			}()
+
+			err := rts.reactor.backfill(
+				ctx,
+				factory.DefaultTestChainID,
+				startHeight,
+				stopHeight,
+				1,
+				factory.MakeBlockIDWithHash(chain[startHeight].Header.Hash()),
+				stopTime,
+				local,
+			)
+			require.NoError(t, err)
+			for height := startHeight; height >= stopHeight; height-- {
+				require.NotNil(t, rts.blockStore.LoadBlockMeta(height), "height %d", height)
+			}
+
+			cancel()
+			<-done
+			require.Zero(t, requests)
+		})
+	}
+}
+
 // retryUntil will continue to evaluate fn and will return successfully when true
 // or fail when the timeout is reached.
 func retryUntil(ctx context.Context, t *testing.T, fn func() bool, timeout time.Duration) {
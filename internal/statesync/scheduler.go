@@ -0,0 +1,217 @@
+package statesync
+
+import (
+	"math/rand"
+	"time"
+
+	tmsync "github.com/tendermint/tendermint/internal/libs/sync"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// chunkStatsWeight is the weight of the latest sample in the moving averages of peer
+	// response times and failure rates.
+	chunkStatsWeight = 0.3
+
+	// chunkFailurePenalty scales how much a peer's failure rate increases its score.
+	chunkFailurePenalty = 4
+
+	// defaultChunkResponseTime is the assumed response time of peers before any chunks have
+	// been received.
+	defaultChunkResponseTime = time.Second
+
+	// minChunkHedgeDelay is the minimum time to wait for a chunk before requesting it from
+	// another peer as well.
+	minChunkHedgeDelay = 500 * time.Millisecond
+)
+
+// chunkScheduler selects the peers to request chunks from. It tracks the response time and
+// failure rate of each peer, preferring fast and reliable peers with few requests in flight,
+// and bans peers that returned bad chunks.
+type chunkScheduler struct {
+	mtx      tmsync.Mutex
+	peers    map[types.NodeID]*chunkPeerStats
+	requests map[uint32][]chunkRequest // in-flight requests by chunk index
+	banned   map[types.NodeID]bool
+}
+
+// chunkPeerStats are the chunk request statistics of a peer.
+type chunkPeerStats struct {
+	inflight     int
+	responseTime time.Duration // moving average, 0 if unknown
+	failureRate  float64       // moving average of timed out requests
+}
+
+// chunkRequest is an in-flight chunk request.
+type chunkRequest struct {
+	peer types.NodeID
+	time time.Time
+}
+
+func newChunkScheduler() *chunkScheduler {
+	return &chunkScheduler{
+		peers:    make(map[types.NodeID]*chunkPeerStats),
+		requests: make(map[uint32][]chunkRequest),
+		banned:   make(map[types.NodeID]bool),
+	}
+}
+
+// selectPeer returns the best of the given peers to request a chunk from, excluding the given
+// peer and banned peers, or an empty node ID if there is none. Ties are broken randomly to
+// spread requests across peers.
+func (s *chunkScheduler) selectPeer(peers []types.NodeID, exclude types.NodeID) types.NodeID {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var (
+		best      types.NodeID
+		bestScore float64
+	)
+	defaultTime := s.defaultResponseTime()
+	for _, i := range rand.Perm(len(peers)) { // nolint:gosec // G404: Use of weak random number generator
+		peer := peers[i]
+		if peer == exclude || s.banned[peer] {
+			continue
+		}
+		score := s.score(peer, defaultTime)
+		if best == "" || score < bestScore {
+			best, bestScore = peer, score
+		}
+	}
+	return best
+}
+
+// score estimates how long it would take a peer to respond to a chunk request, taking the
+// requests it already has in flight and its failure rate into account. Lower is better. The
+// caller must hold the mutex lock.
+func (s *chunkScheduler) score(peer types.NodeID, defaultTime time.Duration) float64 {
+	stats, ok := s.peers[peer]
+	if !ok {
+		return defaultTime.Seconds()
+	}
+	responseTime := stats.responseTime
+	if responseTime == 0 {
+		responseTime = defaultTime
+	}
+	return responseTime.Seconds() * float64(stats.inflight+1) * (1 + chunkFailurePenalty*stats.failureRate)
+}
+
+// defaultResponseTime returns the response time assumed for peers that haven't responded to
+// any requests yet. It's optimistic, i.e. the fastest known response time, such that new peers
+// get tried. The caller must hold the mutex lock.
+func (s *chunkScheduler) defaultResponseTime() time.Duration {
+	var fastest time.Duration
+	for _, stats := range s.peers {
+		if stats.responseTime > 0 && (fastest == 0 || stats.responseTime < fastest) {
+			fastest = stats.responseTime
+		}
+	}
+	if fastest == 0 {
+		return defaultChunkResponseTime
+	}
+	return fastest
+}
+
+// hedgeDelay returns how long to wait for a chunk requested from a peer before also requesting
+// it from another peer: twice the peer's usual response time, but no more than half the
+// request timeout.
+func (s *chunkScheduler) hedgeDelay(peer types.NodeID, timeout time.Duration) time.Duration {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delay := timeout / 2
+	if stats, ok := s.peers[peer]; ok && stats.responseTime > 0 && 2*stats.responseTime < delay {
+		delay = 2 * stats.responseTime
+	}
+	if delay < minChunkHedgeDelay {
+		delay = minChunkHedgeDelay
+	}
+	return delay
+}
+
+// requested records a chunk request to a peer.
+func (s *chunkScheduler) requested(peer types.NodeID, index uint32) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.stats(peer).inflight++
+	s.requests[index] = append(s.requests[index], chunkRequest{peer: peer, time: time.Now()})
+}
+
+// received records a chunk received from a peer, completing all requests for it. Peers that
+// lost the race to a hedged request are at least as slow as the time they've taken so far.
+func (s *chunkScheduler) received(peer types.NodeID, index uint32) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, req := range s.requests[index] {
+		stats := s.stats(req.peer)
+		stats.inflight--
+		elapsed := time.Since(req.time)
+		switch {
+		case req.peer == peer:
+			stats.responseTime = movingAverage(stats.responseTime, elapsed)
+			stats.failureRate *= 1 - chunkStatsWeight
+		case elapsed > stats.responseTime:
+			stats.responseTime = movingAverage(stats.responseTime, elapsed)
+		}
+	}
+	delete(s.requests, index)
+}
+
+// timedOut records that the requests for a chunk timed out, counting as failures for all
+// peers it was requested from.
+func (s *chunkScheduler) timedOut(index uint32) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, req := range s.requests[index] {
+		stats := s.stats(req.peer)
+		stats.inflight--
+		stats.responseTime = movingAverage(stats.responseTime, time.Since(req.time))
+		stats.failureRate = stats.failureRate*(1-chunkStatsWeight) + chunkStatsWeight
+	}
+	delete(s.requests, index)
+}
+
+// ban bans a peer that returned bad chunks, such that no further chunks are requested from it.
+func (s *chunkScheduler) ban(peer types.NodeID) {
+	if peer == "" {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.banned[peer] = true
+}
+
+// clearRequests forgets all in-flight requests, e.g. when starting to restore another
+// snapshot. Peer statistics and bans are kept.
+func (s *chunkScheduler) clearRequests() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.requests = make(map[uint32][]chunkRequest)
+	for _, stats := range s.peers {
+		stats.inflight = 0
+	}
+}
+
+// stats returns the statistics of a peer, creating them if needed. The caller must hold the
+// mutex lock.
+func (s *chunkScheduler) stats(peer types.NodeID) *chunkPeerStats {
+	stats, ok := s.peers[peer]
+	if !ok {
+		stats = &chunkPeerStats{}
+		s.peers[peer] = stats
+	}
+	return stats
+}
+
+// movingAverage adds a sample to an exponential moving average, which is 0 if there are no
+// previous samples.
+func movingAverage(avg, sample time.Duration) time.Duration {
+	if avg == 0 {
+		return sample
+	}
+	return time.Duration((1-chunkStatsWeight)*float64(avg) + chunkStatsWeight*float64(sample))
+}
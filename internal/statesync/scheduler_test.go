@@ -0,0 +1,82 @@
+package statesync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestChunkScheduler_selectPeer(t *testing.T) {
+	scheduler := newChunkScheduler()
+	peers := []types.NodeID{"aa", "bb", "cc"}
+
+	// Without any statistics, requests are spread across peers.
+	selected := map[types.NodeID]bool{}
+	for index := uint32(0); index < 3; index++ {
+		peer := scheduler.selectPeer(peers, "")
+		require.NotEmpty(t, peer)
+		require.False(t, selected[peer], "peer %v selected twice", peer)
+		selected[peer] = true
+		scheduler.requested(peer, index)
+	}
+
+	// aa responds quickly, bb slowly, and cc times out.
+	scheduler.clearRequests()
+	scheduler.peers["aa"].responseTime = 10 * time.Millisecond
+	scheduler.peers["bb"].responseTime = 100 * time.Millisecond
+	scheduler.requested("cc", 3)
+	scheduler.timedOut(3)
+	require.Greater(t, scheduler.peers["cc"].failureRate, 0.0)
+	scheduler.peers["cc"].responseTime = time.Second
+
+	require.Equal(t, types.NodeID("aa"), scheduler.selectPeer(peers, ""))
+	require.Equal(t, types.NodeID("bb"), scheduler.selectPeer(peers, "aa"))
+
+	// The fast peer is preferred until it has enough requests in flight.
+	for index := uint32(4); index < 12; index++ {
+		scheduler.requested("aa", index)
+	}
+	require.Equal(t, types.NodeID("aa"), scheduler.selectPeer(peers, ""))
+	scheduler.requested("aa", 12)
+	scheduler.requested("aa", 13)
+	require.Equal(t, types.NodeID("bb"), scheduler.selectPeer(peers, ""))
+
+	// New peers are assumed to be as fast as the fastest known peer.
+	require.Equal(t, types.NodeID("dd"), scheduler.selectPeer(append(peers, "dd"), ""))
+
+	// Banned peers are never selected.
+	scheduler.ban("aa")
+	scheduler.ban("bb")
+	require.Equal(t, types.NodeID("cc"), scheduler.selectPeer(peers, ""))
+	require.Empty(t, scheduler.selectPeer(peers, "cc"))
+}
+
+func TestChunkScheduler_received(t *testing.T) {
+	scheduler := newChunkScheduler()
+
+	// A chunk is requested from aa, then hedged to bb which responds first. aa is
+	// considered at least as slow as the time it took so far.
+	scheduler.requested("aa", 0)
+	time.Sleep(20 * time.Millisecond)
+	scheduler.requested("bb", 0)
+	scheduler.received("bb", 0)
+
+	require.Zero(t, scheduler.peers["aa"].inflight)
+	require.Zero(t, scheduler.peers["bb"].inflight)
+	require.Greater(t, scheduler.peers["aa"].responseTime, scheduler.peers["bb"].responseTime)
+	require.Empty(t, scheduler.requests)
+}
+
+func TestChunkScheduler_hedgeDelay(t *testing.T) {
+	scheduler := newChunkScheduler()
+	require.Equal(t, 5*time.Second, scheduler.hedgeDelay("aa", 10*time.Second))
+
+	scheduler.stats("aa").responseTime = time.Second
+	require.Equal(t, 2*time.Second, scheduler.hedgeDelay("aa", 10*time.Second))
+
+	scheduler.stats("aa").responseTime = 10 * time.Millisecond
+	require.Equal(t, minChunkHedgeDelay, scheduler.hedgeDelay("aa", 10*time.Second))
+}
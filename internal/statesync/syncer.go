@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -26,6 +28,10 @@ const (
 	// minimumDiscoveryTime is the lowest allowable time for a
 	// SyncAny discovery time.
 	minimumDiscoveryTime = 5 * time.Second
+
+	// chunkQueueDir is the directory within the temp dir where the chunk queue of the snapshot
+	// being restored is persisted, to resume the restoration after a restart.
+	chunkQueueDir = "tm-statesync"
 )
 
 var (
@@ -57,15 +63,19 @@ type syncer struct {
 	conn          proxy.AppConnSnapshot
 	connQuery     proxy.AppConnQuery
 	snapshots     *snapshotPool
+	scheduler     *chunkScheduler
 	snapshotCh    *p2p.Channel
 	chunkCh       *p2p.Channel
-	tempDir       string
+	tempDir       string // if set, restorations are persisted here to be resumed after restarts
+	localDir      string // if set, the snapshot in this dir is restored instead of peers' snapshots
 	fetchers      int32
 	retryTimeout  time.Duration
 
-	mtx     tmsync.RWMutex
-	chunks  *chunkQueue
-	metrics *Metrics
+	mtx      tmsync.RWMutex
+	chunks   *chunkQueue
+	local    *snapshot // the snapshot in localDir, once loaded
+	metrics  *Metrics
+	progress *syncProgress
 
 	avgChunkTime             int64
 	lastSyncedSnapshotHeight int64
@@ -90,12 +100,15 @@ func newSyncer(
 		conn:          conn,
 		connQuery:     connQuery,
 		snapshots:     newSnapshotPool(),
+		scheduler:     newChunkScheduler(),
 		snapshotCh:    snapshotCh,
 		chunkCh:       chunkCh,
 		tempDir:       tempDir,
+		localDir:      cfg.SnapshotDir,
 		fetchers:      cfg.Fetchers,
 		retryTimeout:  cfg.ChunkRequestTimeout,
 		metrics:       metrics,
+		progress:      newSyncProgress(),
 	}
 }
 
@@ -112,6 +125,8 @@ func (s *syncer) AddChunk(chunk *chunk) (bool, error) {
 		return false, err
 	}
 	if added {
+		s.scheduler.received(chunk.Sender, chunk.Index)
+		s.progress.chunkReceived(chunk.Sender, chunk.Index, len(chunk.Chunk))
 		s.logger.Debug("Added chunk to queue", "height", chunk.Height, "format", chunk.Format,
 			"chunk", chunk.Index)
 	} else {
@@ -124,6 +139,16 @@ func (s *syncer) AddChunk(chunk *chunk) (bool, error) {
 // AddSnapshot adds a snapshot to the snapshot pool. It returns true if a new, previously unseen
 // snapshot was accepted and added.
 func (s *syncer) AddSnapshot(peerID types.NodeID, snapshot *snapshot) (bool, error) {
+	// Only the local snapshot is restored when a local snapshot dir is set, but peers
+	// offering it can serve chunks missing from the dir.
+	if s.localDir != "" && peerID != localSnapshotPeer {
+		s.mtx.RLock()
+		local := s.local
+		s.mtx.RUnlock()
+		if local == nil || snapshot.Key() != local.Key() {
+			return false, nil
+		}
+	}
 	added, err := s.snapshots.Add(peerID, snapshot)
 	if err != nil {
 		return false, err
@@ -165,21 +190,62 @@ func (s *syncer) SyncAny(
 		discoveryTime = minimumDiscoveryTime
 	}
 
+	// If a previous restoration was interrupted, e.g. by a crash or restart, we resume it
+	// before considering other snapshots. We still request snapshots from peers, since they
+	// may have the remaining chunks.
+	chunks, err := s.loadChunkQueue()
+	if err != nil {
+		return sm.State{}, nil, err
+	}
+
+	// When restoring a local snapshot, it's the only one in the pool, and an interrupted
+	// restoration of any other snapshot is discarded. We still request snapshots from peers
+	// without waiting for them, since they may have chunks missing from the local dir.
+	if s.localDir != "" {
+		local, err := loadLocalSnapshot(s.localDir)
+		if err != nil {
+			return sm.State{}, nil, err
+		}
+		if chunks != nil && chunks.snapshot.Key() != local.Key() {
+			s.logger.Info("Discarding interrupted restoration of another snapshot",
+				"height", chunks.snapshot.Height, "format", chunks.snapshot.Format)
+			if err := chunks.Remove(); err != nil {
+				return sm.State{}, nil, err
+			}
+			chunks = nil
+		}
+		s.mtx.Lock()
+		s.local = local
+		s.mtx.Unlock()
+		if _, err := s.AddSnapshot(localSnapshotPeer, local); err != nil {
+			return sm.State{}, nil, err
+		}
+		if err := requestSnapshots(); err != nil {
+			return sm.State{}, nil, err
+		}
+		discoveryTime = 0
+	}
+
+	var snapshot *snapshot
+	if chunks != nil {
+		defer chunks.Close()
+		snapshot = chunks.snapshot
+		s.logger.Info("Resuming snapshot restoration", "height", snapshot.Height,
+			"format", snapshot.Format, "hash", snapshot.Hash)
+	}
+
 	if discoveryTime > 0 {
 		if err := requestSnapshots(); err != nil {
 			return sm.State{}, nil, err
 		}
-		s.logger.Info(fmt.Sprintf("Discovering snapshots for %v", discoveryTime))
-		time.Sleep(discoveryTime)
+		if snapshot == nil {
+			s.logger.Info(fmt.Sprintf("Discovering snapshots for %v", discoveryTime))
+			time.Sleep(discoveryTime)
+		}
 	}
 
 	// The app may ask us to retry a snapshot restoration, in which case we need to reuse
 	// the snapshot and chunk queue from the previous loop iteration.
-	var (
-		snapshot *snapshot
-		chunks   *chunkQueue
-		err      error
-	)
 	for {
 		// If not nil, we're going to retry restoration of the same snapshot.
 		if snapshot == nil {
@@ -195,7 +261,7 @@ func (s *syncer) SyncAny(
 			continue
 		}
 		if chunks == nil {
-			chunks, err = newChunkQueue(snapshot, s.tempDir)
+			chunks, err = s.newChunkQueue(snapshot)
 			if err != nil {
 				return sm.State{}, nil, fmt.Errorf("failed to create chunk queue: %w", err)
 			}
@@ -204,6 +270,7 @@ func (s *syncer) SyncAny(
 
 		s.processingSnapshot = snapshot
 		s.metrics.SnapshotChunkTotal.Set(float64(snapshot.Chunks))
+		s.progress.setSnapshot(snapshot, chunks)
 
 		newState, commit, err := s.Sync(ctx, snapshot, chunks)
 		switch {
@@ -248,18 +315,57 @@ func (s *syncer) SyncAny(
 		}
 
 		// Discard snapshot and chunks for next iteration
-		err = chunks.Close()
+		err = chunks.Remove()
 		if err != nil {
 			s.logger.Error("Failed to clean up chunk queue", "err", err)
 		}
 		snapshot = nil
 		chunks = nil
 		s.processingSnapshot = nil
+		s.progress.setSnapshot(nil, nil)
+	}
+}
+
+// newChunkQueue creates a chunk queue for a snapshot, which is persisted if we have a temp dir.
+func (s *syncer) newChunkQueue(snapshot *snapshot) (*chunkQueue, error) {
+	if s.tempDir == "" {
+		return newChunkQueue(snapshot, "")
+	}
+	return newPersistentChunkQueue(snapshot, filepath.Join(s.tempDir, chunkQueueDir))
+}
+
+// loadChunkQueue loads the chunk queue of an interrupted restoration, if any.
+func (s *syncer) loadChunkQueue() (*chunkQueue, error) {
+	if s.tempDir == "" {
+		return nil, nil
+	}
+	chunks, err := loadChunkQueue(filepath.Join(s.tempDir, chunkQueueDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chunk queue: %w", err)
+	}
+	return chunks, nil
+}
+
+// RemoveChunks removes the persisted chunk queue, if any. It must be called once the node has
+// been bootstrapped with the restored state, since the restoration can't be resumed after that.
+func (s *syncer) RemoveChunks() error {
+	if s.tempDir == "" {
+		return nil
+	}
+	dir := filepath.Join(s.tempDir, chunkQueueDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clean up state sync dir %v: %w", dir, err)
 	}
+	return nil
 }
 
 // Sync executes a sync for a specific snapshot, returning the latest state and block commit which
 // the caller must use to bootstrap the node.
+//
+// If the chunk queue was resumed from disk, the app is first queried via Info to see if it
+// completed the restoration before it was interrupted, in which case the snapshot is only
+// verified. Otherwise, the snapshot is offered to the app again, which restores it from scratch,
+// and all chunks are applied again, using the ones already fetched from disk.
 func (s *syncer) Sync(ctx context.Context, snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.Commit, error) {
 	s.mtx.Lock()
 	if s.chunks != nil {
@@ -267,6 +373,7 @@ func (s *syncer) Sync(ctx context.Context, snapshot *snapshot, chunks *chunkQueu
 		return sm.State{}, nil, errors.New("a state sync is already in progress")
 	}
 	s.chunks = chunks
+	s.scheduler.clearRequests()
 	s.mtx.Unlock()
 	defer func() {
 		s.mtx.Lock()
@@ -295,10 +402,19 @@ func (s *syncer) Sync(ctx context.Context, snapshot *snapshot, chunks *chunkQueu
 	}
 	snapshot.trustedAppHash = appHash
 
-	// Offer snapshot to ABCI app.
-	err = s.offerSnapshot(ctx, snapshot)
-	if err != nil {
-		return sm.State{}, nil, err
+	// Offer snapshot to ABCI app, unless it has already been restored.
+	restored := false
+	if chunks.Resumed() {
+		restored, err = s.appRestored(ctx, snapshot, chunks)
+		if err != nil {
+			return sm.State{}, nil, err
+		}
+	}
+	if !restored {
+		err = s.offerSnapshot(ctx, snapshot)
+		if err != nil {
+			return sm.State{}, nil, err
+		}
 	}
 
 	// Spawn chunk fetchers. They will terminate when the chunk queue is closed or context canceled.
@@ -398,6 +514,27 @@ func (s *syncer) offerSnapshot(ctx context.Context, snapshot *snapshot) error {
 	}
 }
 
+// appRestored checks whether the app has already restored a resumed snapshot, by querying its
+// last block height and app hash via Info. If so, all chunks are marked as applied.
+func (s *syncer) appRestored(ctx context.Context, snapshot *snapshot, chunks *chunkQueue) (bool, error) {
+	resp, err := s.connQuery.InfoSync(ctx, proxy.RequestInfo)
+	if err != nil {
+		return false, fmt.Errorf("failed to query ABCI app for last block height: %w", err)
+	}
+	if uint64(resp.LastBlockHeight) != snapshot.Height ||
+		!bytes.Equal(resp.LastBlockAppHash, snapshot.trustedAppHash) {
+		return false, nil
+	}
+
+	s.logger.Info("ABCI app already restored snapshot", "height", snapshot.Height,
+		"format", snapshot.Format, "hash", snapshot.Hash)
+	for index := uint32(0); index < snapshot.Chunks; index++ {
+		chunks.Applied(index)
+	}
+	s.progress.setSnapshot(snapshot, chunks)
+	return true, nil
+}
+
 // applyChunks applies chunks to the app. It returns various errors depending on the app's
 // response, or nil once the snapshot is fully restored.
 func (s *syncer) applyChunks(ctx context.Context, chunks *chunkQueue, start time.Time) error {
@@ -420,12 +557,15 @@ func (s *syncer) applyChunks(ctx context.Context, chunks *chunkQueue, start time
 		s.logger.Info("Applied snapshot chunk to ABCI app", "height", chunk.Height,
 			"format", chunk.Format, "chunk", chunk.Index, "total", chunks.Size())
 
-		// Discard and refetch any chunks as requested by the app
+		// Discard and refetch any chunks as requested by the app, and ban their senders from
+		// serving further chunks
 		for _, index := range resp.RefetchChunks {
+			s.scheduler.ban(chunks.GetSender(index))
 			err := chunks.Discard(index)
 			if err != nil {
 				return fmt.Errorf("failed to discard chunk %v: %w", index, err)
 			}
+			s.progress.chunkRetried()
 		}
 
 		// Reject any senders as requested by the app
@@ -433,6 +573,7 @@ func (s *syncer) applyChunks(ctx context.Context, chunks *chunkQueue, start time
 			if sender != "" {
 				peerID := types.NodeID(sender)
 				s.snapshots.RejectPeer(peerID)
+				s.scheduler.ban(peerID)
 
 				if err := chunks.DiscardSender(peerID); err != nil {
 					return fmt.Errorf("failed to reject sender: %w", err)
@@ -442,13 +583,16 @@ func (s *syncer) applyChunks(ctx context.Context, chunks *chunkQueue, start time
 
 		switch resp.Result {
 		case abci.ResponseApplySnapshotChunk_ACCEPT:
+			chunks.Applied(chunk.Index)
 			s.metrics.SnapshotChunk.Add(1)
+			s.progress.chunkApplied()
 			s.avgChunkTime = time.Since(start).Nanoseconds() / int64(chunks.numChunksReturned())
 			s.metrics.ChunkProcessAvgTime.Set(float64(s.avgChunkTime))
 		case abci.ResponseApplySnapshotChunk_ABORT:
 			return errAbort
 		case abci.ResponseApplySnapshotChunk_RETRY:
 			chunks.Retry(chunk.Index)
+			s.progress.chunkRetried()
 		case abci.ResponseApplySnapshotChunk_RETRY_SNAPSHOT:
 			return errRetrySnapshot
 		case abci.ResponseApplySnapshotChunk_REJECT_SNAPSHOT:
@@ -489,39 +633,109 @@ func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *ch
 		s.logger.Info("Fetching snapshot chunk", "height", snapshot.Height,
 			"format", snapshot.Format, "chunk", index, "total", chunks.Size())
 
-		ticker := time.NewTicker(s.retryTimeout)
-		defer ticker.Stop()
-
-		if err := s.requestChunk(ctx, snapshot, index); err != nil {
+		next, err = s.fetchChunk(ctx, snapshot, chunks, index)
+		if err != nil {
 			return
 		}
+	}
+}
+
+// fetchChunk requests a chunk from the best available peer, and waits for it. If the peer is
+// slow to respond, the chunk is also requested from another peer, and whichever responds first
+// wins. It returns false if the requests timed out, and the chunk should be requested again.
+func (s *syncer) fetchChunk(ctx context.Context, snapshot *snapshot, chunks *chunkQueue, index uint32) (bool, error) {
+	// Chunks are read in place from a local snapshot dir, and only requested from peers if
+	// they're missing from it or were discarded.
+	if s.localDir != "" {
+		added, err := s.addLocalChunk(chunks, index)
+		if err != nil {
+			s.logger.Info("Failed to add local snapshot chunk, requesting it from peers",
+				"chunk", index, "err", err)
+		} else if added {
+			return true, nil
+		}
+	}
+
+	peer, err := s.requestChunk(ctx, snapshot, index, "")
+	if err != nil {
+		return false, err
+	}
 
+	timeout := time.NewTimer(s.retryTimeout)
+	defer timeout.Stop()
+	hedge := time.NewTimer(s.scheduler.hedgeDelay(peer, s.retryTimeout))
+	defer hedge.Stop()
+
+	received := chunks.WaitFor(index)
+	for {
 		select {
-		case <-chunks.WaitFor(index):
-			next = true
+		case <-received:
+			return true, nil
+
+		case <-hedge.C:
+			if peer != "" {
+				s.logger.Debug("Chunk request is slow, also requesting it from another peer",
+					"chunk", index, "peer", peer)
+				if _, err := s.requestChunk(ctx, snapshot, index, peer); err != nil {
+					return false, err
+				}
+			}
 
-		case <-ticker.C:
-			next = false
+		case <-timeout.C:
+			s.scheduler.timedOut(index)
+			s.progress.chunkRetried()
+			return false, nil
 
 		case <-ctx.Done():
-			return
+			return false, ctx.Err()
 		}
+	}
+}
 
-		ticker.Stop()
+// addLocalChunk adds a chunk from the local snapshot dir to the chunk queue, reading it in place.
+// It returns false if the chunk was already added from the dir before.
+func (s *syncer) addLocalChunk(chunks *chunkQueue, index uint32) (bool, error) {
+	path := localChunkPath(s.localDir, index)
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to load local snapshot chunk %v: %w", index, err)
+	}
+	added, err := chunks.AddFile(index, path, localSnapshotPeer)
+	if err != nil {
+		return false, err
+	}
+	if added {
+		s.progress.chunkReceived(localSnapshotPeer, index, int(info.Size()))
+		s.logger.Debug("Added local chunk to queue", "chunk", index)
 	}
+	return added, nil
 }
 
-// requestChunk requests a chunk from a peer.
+// requestChunk requests a chunk from the best peer for the snapshot, other than the excluded
+// peer, and returns the peer.
 //
-// returns nil if there are no peers for the given snapshot or the
-// request is successfully made and an error if the request cannot be
-// completed
-func (s *syncer) requestChunk(ctx context.Context, snapshot *snapshot, chunk uint32) error {
-	peer := s.snapshots.GetPeer(snapshot)
+// returns an empty peer and nil if there are no peers for the given snapshot, and an error
+// if the request cannot be completed
+func (s *syncer) requestChunk(
+	ctx context.Context,
+	snapshot *snapshot,
+	chunk uint32,
+	exclude types.NodeID,
+) (types.NodeID, error) {
+	// The local snapshot dir is in the pool as a pseudo peer, which can't be requested from.
+	peers := make([]types.NodeID, 0)
+	for _, peer := range s.snapshots.GetPeers(snapshot) {
+		if peer != localSnapshotPeer {
+			peers = append(peers, peer)
+		}
+	}
+	peer := s.scheduler.selectPeer(peers, exclude)
 	if peer == "" {
-		s.logger.Error("No valid peers found for snapshot", "height", snapshot.Height,
-			"format", snapshot.Format, "hash", snapshot.Hash)
-		return nil
+		if exclude == "" {
+			s.logger.Error("No valid peers found for snapshot", "height", snapshot.Height,
+				"format", snapshot.Format, "hash", snapshot.Hash)
+		}
+		return "", nil
 	}
 
 	s.logger.Debug(
@@ -542,9 +756,11 @@ func (s *syncer) requestChunk(ctx context.Context, snapshot *snapshot, chunk uin
 	}
 
 	if err := s.chunkCh.Send(ctx, msg); err != nil {
-		return err
+		return "", err
 	}
-	return nil
+	s.scheduler.requested(peer, chunk)
+	s.progress.chunkRequested(chunk)
+	return peer, nil
 }
 
 // verifyApp verifies the sync, checking the app hash and last block height. It returns the
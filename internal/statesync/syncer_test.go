@@ -3,6 +3,7 @@ package statesync
 import (
 	"context"
 	"errors"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -214,6 +215,104 @@ func TestSyncer_SyncAny(t *testing.T) {
 	connQuery.AssertExpectations(t)
 }
 
+func TestSyncer_SyncAny_resume(t *testing.T) {
+	state := sm.State{ChainID: "chain", LastBlockHeight: 1, AppHash: []byte("app_hash")}
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	chunks := []*chunk{
+		{Height: 1, Format: 1, Index: 0, Chunk: []byte{1, 1, 0}},
+		{Height: 1, Format: 1, Index: 1, Chunk: []byte{1, 1, 1}},
+		{Height: 1, Format: 1, Index: 2, Chunk: []byte{1, 1, 2}},
+	}
+
+	testcases := map[string]struct {
+		restored bool               // if true, the app restored the snapshot before the restart
+		info     *abci.ResponseInfo // the app's info on restart, if it didn't restore the snapshot
+	}{
+		"reapply":  {false, &abci.ResponseInfo{}},
+		"app hash": {false, &abci.ResponseInfo{LastBlockHeight: 1, LastBlockAppHash: []byte("other")}},
+		"restored": {true, nil},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			stateProvider := &mocks.StateProvider{}
+			stateProvider.On("AppHash", mock.Anything, uint64(1)).Return(state.AppHash, nil)
+			stateProvider.On("Commit", mock.Anything, uint64(1)).Return(commit, nil)
+			stateProvider.On("State", mock.Anything, uint64(1)).Return(state, nil)
+
+			rts := setup(ctx, t, nil, nil, stateProvider, 2)
+			rts.syncer.tempDir = t.TempDir()
+
+			// Before the restart, chunks 0 and 1 were fetched, and chunk 0 was applied. If the
+			// app restored the snapshot, all chunks were fetched.
+			fetched := chunks[:2]
+			if tc.restored {
+				fetched = chunks
+			}
+			s := &snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{1, 2, 3}}
+			queue, err := newPersistentChunkQueue(s, filepath.Join(rts.syncer.tempDir, chunkQueueDir))
+			require.NoError(t, err)
+			for _, c := range fetched {
+				_, err = queue.Add(c)
+				require.NoError(t, err)
+			}
+			queue.Applied(0)
+			require.NoError(t, queue.Close())
+
+			// A peer has the snapshot, so the missing chunk can be fetched.
+			_, err = rts.syncer.AddSnapshot("aa", &snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{1, 2, 3}})
+			require.NoError(t, err)
+			go func() {
+				for e := range rts.chunkOutCh {
+					msg, ok := e.Message.(*ssproto.ChunkRequest)
+					assert.True(t, ok)
+					assert.EqualValues(t, 2, msg.Index)
+					_, err := rts.syncer.AddChunk(chunks[msg.Index])
+					assert.NoError(t, err)
+				}
+			}()
+
+			if tc.restored {
+				rts.connQuery.On("InfoSync", mock.Anything, proxy.RequestInfo).Return(&abci.ResponseInfo{
+					LastBlockHeight:  1,
+					LastBlockAppHash: []byte("app_hash"),
+				}, nil)
+			} else {
+				// The app accepts the snapshot again, which restores it from scratch, so all
+				// chunks are applied again.
+				rts.connQuery.On("InfoSync", mock.Anything, proxy.RequestInfo).Once().Return(tc.info, nil)
+				rts.connQuery.On("InfoSync", mock.Anything, proxy.RequestInfo).Once().Return(&abci.ResponseInfo{
+					LastBlockHeight:  1,
+					LastBlockAppHash: []byte("app_hash"),
+				}, nil)
+				rts.conn.On("OfferSnapshotSync", mock.Anything, abci.RequestOfferSnapshot{
+					Snapshot: toABCI(s), AppHash: []byte("app_hash"),
+				}).Once().Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ACCEPT}, nil)
+				for _, c := range chunks {
+					rts.conn.On("ApplySnapshotChunkSync", mock.Anything, abci.RequestApplySnapshotChunk{
+						Index: c.Index, Chunk: c.Chunk,
+					}).Once().Return(&abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ACCEPT}, nil)
+				}
+			}
+
+			newState, lastCommit, err := rts.syncer.SyncAny(ctx, 0, func() error { return nil })
+			require.NoError(t, err)
+			require.Equal(t, state, newState)
+			require.Equal(t, commit, lastCommit)
+			rts.conn.AssertExpectations(t)
+			rts.connQuery.AssertExpectations(t)
+
+			// The chunks are kept until the node has been bootstrapped.
+			require.DirExists(t, filepath.Join(rts.syncer.tempDir, chunkQueueDir))
+			require.NoError(t, rts.syncer.RemoveChunks())
+			require.NoDirExists(t, filepath.Join(rts.syncer.tempDir, chunkQueueDir))
+		})
+	}
+}
+
 func TestSyncer_SyncAny_noSnapshots(t *testing.T) {
 	stateProvider := &mocks.StateProvider{}
 	stateProvider.On("AppHash", mock.Anything, mock.Anything).Return([]byte("app_hash"), nil)
@@ -712,6 +811,44 @@ func TestSyncer_applyChunks_RejectSenders(t *testing.T) {
 	}
 }
 
+func TestSyncer_fetchChunk_hedge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stateProvider := &mocks.StateProvider{}
+	rts := setup(ctx, t, nil, nil, stateProvider, 2)
+	rts.syncer.retryTimeout = time.Second
+
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	for _, peer := range []types.NodeID{"aa", "bb"} {
+		_, err := rts.syncer.AddSnapshot(peer, s)
+		require.NoError(t, err)
+	}
+	chunks, err := newChunkQueue(s, "")
+	require.NoError(t, err)
+	defer chunks.Close()
+	rts.syncer.chunks = chunks
+
+	fetched := make(chan bool)
+	go func() {
+		ok, err := rts.syncer.fetchChunk(ctx, s, chunks, 0)
+		assert.NoError(t, err)
+		fetched <- ok
+	}()
+
+	// The first peer doesn't respond, so the chunk is also requested from the other one,
+	// which responds.
+	first := <-rts.chunkOutCh
+	second := <-rts.chunkOutCh
+	require.NotEqual(t, first.To, second.To)
+	_, err = rts.syncer.AddChunk(&chunk{Height: 1, Format: 1, Index: 0, Chunk: []byte{1}, Sender: second.To})
+	require.NoError(t, err)
+	require.True(t, <-fetched)
+
+	// The peer that responded is now preferred.
+	require.Equal(t, second.To, rts.syncer.scheduler.selectPeer([]types.NodeID{"aa", "bb"}, ""))
+}
+
 func TestSyncer_verifyApp(t *testing.T) {
 	boom := errors.New("boom")
 	s := &snapshot{Height: 3, Format: 1, Chunks: 5, Hash: []byte{1, 2, 3}, trustedAppHash: []byte("app_hash")}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -572,6 +573,50 @@ func (bs *BlockStore) SaveSignedHeader(sh *types.SignedHeader, blockID types.Blo
 	return batch.Close()
 }
 
+// SaveHistoricalBlock persists a full block below the current height of the
+// store, e.g. when backfilling blocks after state sync, replacing the header
+// saved via SaveSignedHeader if any. Unlike SaveBlock, the block doesn't have
+// to be contiguous with the stored blocks and the seen commit is not updated.
+// The caller must verify the block against the stored headers.
+func (bs *BlockStore) SaveHistoricalBlock(block *types.Block, blockParts *types.PartSet) error {
+	if block == nil {
+		return errors.New("BlockStore can only save a non-nil block")
+	}
+	if !blockParts.IsComplete() {
+		return errors.New("BlockStore can only save complete block part sets")
+	}
+	if meta := bs.LoadBlockMeta(block.Height); meta != nil && meta.BlockSize >= 0 {
+		return fmt.Errorf("block at height %d already saved", block.Height)
+	}
+
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+
+	// As in SaveBlock, parts are saved before the block meta.
+	for i := 0; i < int(blockParts.Total()); i++ {
+		pbp, err := blockParts.GetPart(i).ToProto()
+		if err != nil {
+			return fmt.Errorf("unable to make part into proto: %w", err)
+		}
+		if err := batch.Set(blockPartKey(block.Height, i), mustEncode(pbp)); err != nil {
+			return fmt.Errorf("unable to save block part: %w", err)
+		}
+	}
+
+	pbm := types.NewBlockMeta(block, blockParts).ToProto()
+	if err := batch.Set(blockMetaKey(block.Height), mustEncode(pbm)); err != nil {
+		return fmt.Errorf("unable to save block meta: %w", err)
+	}
+	if err := batch.Set(blockHashKey(block.Hash()), []byte(fmt.Sprintf("%d", block.Height))); err != nil {
+		return fmt.Errorf("unable to save block hash: %w", err)
+	}
+	if err := batch.Set(blockCommitKey(block.Height-1), mustEncode(block.LastCommit.ToProto())); err != nil {
+		return fmt.Errorf("unable to save commit: %w", err)
+	}
+
+	return batch.WriteSync()
+}
+
 func (bs *BlockStore) Close() error {
 	return bs.db.Close()
 }
@@ -561,3 +561,31 @@ func newBlock(hdr types.Header, lastCommit *types.Commit) *types.Block {
 		LastCommit: lastCommit,
 	}
 }
+
+func TestSaveHistoricalBlock(t *testing.T) {
+	state, bs, cleanup := makeStateAndBlockStore(log.NewNopLogger())
+	defer cleanup()
+
+	blocks := make([]*types.Block, 3)
+	for i := range blocks {
+		blocks[i] = factory.MakeBlock(state, int64(i+1), new(types.Commit))
+	}
+	seenCommit := makeTestCommit(3, tmtime.Now())
+	bs.SaveBlock(blocks[2], blocks[2].MakePartSet(2), seenCommit)
+
+	// Blocks below the base can be saved in any order, without changing the
+	// height or seen commit.
+	for _, height := range []int64{1, 2} {
+		block := blocks[height-1]
+		require.NoError(t, bs.SaveHistoricalBlock(block, block.MakePartSet(2)))
+		require.Equal(t, block.Hash(), bs.LoadBlock(height).Hash())
+		require.Equal(t, block.Hash(), bs.LoadBlockByHash(block.Hash()).Hash())
+	}
+	require.EqualValues(t, 1, bs.Base())
+	require.EqualValues(t, 3, bs.Height())
+	require.Equal(t, seenCommit, bs.LoadSeenCommit())
+
+	// Full blocks can't be saved again.
+	require.Error(t, bs.SaveHistoricalBlock(blocks[0], blocks[0].MakePartSet(2)))
+	require.Error(t, bs.SaveHistoricalBlock(blocks[2], blocks[2].MakePartSet(2)))
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/tendermint/tendermint/internal/eventbus"
 	"github.com/tendermint/tendermint/internal/mempool"
 	"github.com/tendermint/tendermint/internal/p2p"
+	"github.com/tendermint/tendermint/internal/p2p/pex"
 	"github.com/tendermint/tendermint/internal/proxy"
 	rpccore "github.com/tendermint/tendermint/internal/rpc/core"
 	sm "github.com/tendermint/tendermint/internal/state"
@@ -72,6 +73,7 @@ type nodeImpl struct {
 	stateSyncReactor *statesync.Reactor // for hosting and restoring state sync snapshots
 	consensusReactor *consensus.Reactor // for participating in the consensus
 	pexReactor       service.Service    // for exchanging peer addresses
+	crawler          *pex.Crawler       // for crawling the network, on seed nodes
 	evidenceReactor  service.Service
 	rpcListeners     []net.Listener // rpc servers
 	shutdownOps      closer
@@ -113,7 +115,8 @@ func newDefaultNode(
 		pval = nil
 	}
 
-	appClient, _ := proxy.DefaultClientCreator(logger, cfg.ProxyApp, cfg.ABCI, cfg.DBDir())
+	appClient, _ := proxy.DefaultClientCreator(logger, cfg.ProxyApp, cfg.ABCI, cfg.DBDir(),
+		proxy.WithConcurrentLocalQueries(cfg.ABCIConn.ConcurrentLocalQueries))
 
 	return makeNode(
 		ctx,
@@ -171,7 +174,7 @@ func makeNode(
 	nodeMetrics := defaultMetricsProvider(cfg.Instrumentation)(genDoc.ChainID)
 
 	// Create the proxyApp and establish connections to the ABCI app (consensus, mempool, query).
-	proxyApp, err := createAndStartProxyAppConns(ctx, clientCreator, logger, nodeMetrics.proxy)
+	proxyApp, err := createAndStartProxyAppConns(ctx, clientCreator, cfg.ABCIConn, logger, nodeMetrics.proxy)
 	if err != nil {
 		return nil, combineCloseError(err, makeCloser(closers))
 	}
@@ -330,6 +333,7 @@ func makeNode(
 	bcReactor, err := createBlockchainReactor(ctx,
 		logger, state, blockExec, blockStore, csReactor,
 		peerManager, router, blockSync && !stateSync, nodeMetrics.consensus,
+		cfg.StateSync, stateStore, eventSinks, cfg.BaseConfig,
 	)
 	if err != nil {
 		return nil, combineCloseError(
@@ -361,6 +365,13 @@ func makeNode(
 		channels[ch.ID] = ch
 	}
 
+	// State sync chunks are kept in the data dir by default, rather than
+	// os.TempDir(), so that an interrupted state sync can be resumed.
+	stateSyncDir := cfg.StateSync.TempDir
+	if stateSyncDir == "" {
+		stateSyncDir = cfg.DBDir()
+	}
+
 	stateSyncReactor := statesync.NewReactor(
 		genDoc.ChainID,
 		genDoc.InitialHeight,
@@ -372,12 +383,14 @@ func makeNode(
 		channels[statesync.ChunkChannel],
 		channels[statesync.LightBlockChannel],
 		channels[statesync.ParamsChannel],
+		channels[statesync.BlockSnapshotChannel],
 		peerManager.Subscribe(ctx),
 		stateStore,
 		blockStore,
-		cfg.StateSync.TempDir,
+		stateSyncDir,
 		nodeMetrics.statesync,
 	)
+	stateSyncReactor.SetEventBus(eventBus)
 
 	var pexReactor service.Service
 	if cfg.P2P.PexReactor {
@@ -423,10 +436,12 @@ func makeNode(
 			EvidencePool:   evPool,
 			ConsensusState: csState,
 
-			ConsensusReactor: csReactor,
-			BlockSyncReactor: bcReactor.(consensus.BlockSyncReactor),
+			ConsensusReactor:  csReactor,
+			BlockSyncReactor:  bcReactor.(consensus.BlockSyncReactor),
+			StateSyncMetricer: stateSyncReactor,
 
 			PeerManager: peerManager,
+			Router:      router,
 
 			GenDoc:     genDoc,
 			EventSinks: eventSinks,
@@ -495,6 +510,13 @@ func makeSeedNode(
 		return nil, combineCloseError(err, closer)
 	}
 
+	var crawler *pex.Crawler
+	if cfg.P2P.CrawlInterval > 0 {
+		crawler = pex.NewCrawler(logger, pexReactor, peerManager, router, peerManager.Subscribe(ctx),
+			pex.CrawlerOptions{Interval: cfg.P2P.CrawlInterval},
+			pex.PrometheusMetrics(cfg.Instrumentation.Namespace, "chain_id", genDoc.ChainID))
+	}
+
 	node := &nodeImpl{
 		config:     cfg,
 		logger:     logger,
@@ -508,7 +530,21 @@ func makeSeedNode(
 		shutdownOps: closer,
 
 		pexReactor: pexReactor,
+		crawler:    crawler,
+
+		rpcEnv: &rpccore.Environment{
+			PeerManager: peerManager,
+			Router:      router,
+			GenDoc:      genDoc,
+
+			Logger: logger.With("module", "rpc"),
+			Config: *cfg.RPC,
+		},
 	}
+	if crawler != nil {
+		node.rpcEnv.Crawler = crawler
+	}
+	node.rpcEnv.P2PTransport = node
 	node.BaseService = *service.NewBaseService(logger, "SeedNode", node)
 
 	return node, nil
@@ -548,7 +584,7 @@ func (n *nodeImpl) OnStart(ctx context.Context) error {
 
 	// Start the RPC server before the P2P server
 	// so we can eg. receive txs for the first block
-	if n.config.RPC.ListenAddress != "" && n.config.Mode != config.ModeSeed {
+	if n.config.RPC.ListenAddress != "" {
 		listeners, err := n.startRPC(ctx)
 		if err != nil {
 			return err
@@ -599,6 +635,12 @@ func (n *nodeImpl) OnStart(ctx context.Context) error {
 		}
 	}
 
+	if n.crawler != nil {
+		if err := n.crawler.Start(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Run state sync
 	// TODO: We shouldn't run state sync if we already have state that has a
 	// LastBlockHeight that is not InitialHeight
@@ -697,6 +739,9 @@ func (n *nodeImpl) OnStop() {
 		n.evidenceReactor.Wait()
 	}
 	n.pexReactor.Wait()
+	if n.crawler != nil {
+		n.crawler.Wait()
+	}
 	n.router.Wait()
 	n.isListening = false
 
@@ -749,10 +794,15 @@ func (n *nodeImpl) startRPC(ctx context.Context) ([]net.Listener, error) {
 	}
 
 	listenAddrs := strings.SplitAndTrimEmpty(n.config.RPC.ListenAddress, ",", " ")
-	routes := n.rpcEnv.GetRoutes()
-
-	if n.config.RPC.Unsafe {
-		n.rpcEnv.AddUnsafe(routes)
+	var routes rpccore.RoutesMap
+	if n.config.Mode == config.ModeSeed {
+		// Seed nodes only serve network information.
+		routes = n.rpcEnv.GetSeedRoutes()
+	} else {
+		routes = n.rpcEnv.GetRoutes()
+		if n.config.RPC.Unsafe {
+			n.rpcEnv.AddUnsafe(routes)
+		}
 	}
 
 	cfg := rpcserver.DefaultConfig()
@@ -782,7 +832,9 @@ func (n *nodeImpl) startRPC(ctx context.Context) ([]net.Listener, error) {
 			rpcserver.ReadLimit(cfg.MaxBodyBytes),
 		)
 		wm.SetLogger(wmLogger)
-		mux.HandleFunc("/websocket", wm.WebsocketHandler)
+		if n.eventBus != nil {
+			mux.HandleFunc("/websocket", wm.WebsocketHandler)
+		}
 		rpcserver.RegisterRPCFuncs(mux, routes, rpcLogger)
 		listener, err := rpcserver.Listen(
 			listenAddr,
@@ -1053,9 +1105,27 @@ func createAndStartPrivValidatorGRPCClient(
 	return pvsc, nil
 }
 
-func getRouterConfig(conf *config.Config, proxyApp proxy.AppConns) p2p.RouterOptions {
+func getRouterConfig(conf *config.Config, proxyApp proxy.AppConns) (p2p.RouterOptions, error) {
 	opts := p2p.RouterOptions{
-		QueueType: conf.P2P.QueueType,
+		QueueType:       conf.P2P.QueueType,
+		ResolveViaProxy: conf.P2P.ProxyOnly,
+	}
+
+	sendRates, err := config.ParseChannelRates(conf.P2P.ChannelSendRates)
+	if err != nil {
+		return opts, fmt.Errorf("invalid channel-send-rates: %w", err)
+	}
+	recvRates, err := config.ParseChannelRates(conf.P2P.ChannelRecvRates)
+	if err != nil {
+		return opts, fmt.Errorf("invalid channel-recv-rates: %w", err)
+	}
+	opts.ChannelSendRates = make(map[p2p.ChannelID]int64, len(sendRates))
+	for chID, rate := range sendRates {
+		opts.ChannelSendRates[p2p.ChannelID(chID)] = rate
+	}
+	opts.ChannelRecvRates = make(map[p2p.ChannelID]int64, len(recvRates))
+	for chID, rate := range recvRates {
+		opts.ChannelRecvRates[p2p.ChannelID(chID)] = rate
 	}
 
 	if conf.FilterPeers && proxyApp != nil {
@@ -1089,5 +1159,5 @@ func getRouterConfig(conf *config.Config, proxyApp proxy.AppConns) p2p.RouterOpt
 
 	}
 
-	return opts
+	return opts, nil
 }
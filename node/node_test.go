@@ -544,11 +544,14 @@ func TestNodeNewSeedNode(t *testing.T) {
 	err = n.Start(ctx)
 	require.NoError(t, err)
 	assert.True(t, n.pexReactor.IsRunning())
+	assert.True(t, n.crawler.IsRunning())
+	assert.NotEmpty(t, n.rpcListeners)
 
 	cancel()
 	n.Wait()
 
 	assert.False(t, n.pexReactor.IsRunning())
+	assert.False(t, n.crawler.IsRunning())
 }
 
 func TestNodeSetEventSink(t *testing.T) {
@@ -96,10 +96,12 @@ func initDBs(
 func createAndStartProxyAppConns(
 	ctx context.Context,
 	clientCreator abciclient.Creator,
+	cfg *config.ABCIConnConfig,
 	logger log.Logger,
 	metrics *proxy.Metrics,
 ) (proxy.AppConns, error) {
-	proxyApp := proxy.NewAppConns(clientCreator, logger.With("module", "proxy"), metrics)
+	proxyApp := proxy.NewAppConns(clientCreator, logger.With("module", "proxy"), metrics,
+		proxy.WithConnConfig(cfg))
 
 	if err := proxyApp.Start(ctx); err != nil {
 		return nil, fmt.Errorf("error starting proxy app connections: %v", err)
@@ -281,6 +283,10 @@ func createBlockchainReactor(
 	router *p2p.Router,
 	blockSync bool,
 	metrics *consensus.Metrics,
+	stateSyncCfg *config.StateSyncConfig,
+	stateStore sm.Store,
+	eventSinks []indexer.EventSink,
+	baseCfg config.BaseConfig,
 ) (service.Service, error) {
 
 	logger = logger.With("module", "blockchain")
@@ -301,6 +307,26 @@ func createBlockchainReactor(
 		return nil, err
 	}
 
+	if blockArchive := baseCfg.BlockArchiveFile(); blockArchive != "" {
+		reactor.SetArchive(blockArchive)
+	}
+
+	checkpointHeight, checkpointHash, err := baseCfg.TrustedCheckpointParams()
+	if err != nil {
+		return nil, err
+	}
+	if checkpointHeight > 0 {
+		reactor.SetCheckpoint(checkpointHeight, checkpointHash)
+	}
+
+	if target := stateSyncCfg.BackfillHeight(state.InitialHeight); target > 0 {
+		reactor.SetBackfill(blocksync.BackfillOptions{
+			TargetHeight: target,
+			StateStore:   stateStore,
+			EventSinks:   eventSinks,
+		})
+	}
+
 	return reactor, nil
 }
 
@@ -379,6 +405,8 @@ func createTransport(logger log.Logger, cfg *config.Config) *p2p.MConnTransport
 		logger, conf, []*p2p.ChannelDescriptor{},
 		p2p.MConnTransportOptions{
 			MaxAcceptedConnections: uint32(cfg.P2P.MaxConnections),
+			Handshake:              cfg.P2P.HandshakeProtocol,
+			Proxy:                  cfg.P2P.Proxy,
 		},
 	)
 }
@@ -411,6 +439,9 @@ func createPeerManager(
 		MaxRetryTime:           8 * time.Hour,
 		MaxRetryTimePersistent: 5 * time.Minute,
 		RetryTimeJitter:        3 * time.Second,
+		ReputationHalfLife:     cfg.P2P.ReputationHalfLife,
+		ReputationBanThreshold: float64(cfg.P2P.ReputationBanThreshold),
+		ReputationBanDuration:  cfg.P2P.ReputationBanDuration,
 		PrivatePeers:           privatePeerIDs,
 	}
 
@@ -464,12 +495,41 @@ func createRouter(
 ) (*p2p.Router, error) {
 
 	p2pLogger := logger.With("module", "p2p")
-	transport := createTransport(p2pLogger, conf)
+	transports := []p2p.Transport{createTransport(p2pLogger, conf)}
 
 	ep, err := p2p.NewEndpoint(nodeKey.ID.AddressString(conf.P2P.ListenAddress))
 	if err != nil {
 		return nil, err
 	}
+	endpoints := []p2p.Endpoint{ep}
+
+	if conf.P2P.QUICListenAddress != "" {
+		quicTransport, err := p2p.NewQUICTransport(
+			p2pLogger, []*p2p.ChannelDescriptor{}, p2p.QUICTransportOptions{})
+		if err != nil {
+			return nil, err
+		}
+		quicEp, err := p2p.NewEndpoint(nodeKey.ID.AddressString(conf.P2P.QUICListenAddress))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quic-laddr: %w", err)
+		}
+		quicEp.Protocol = p2p.QUICProtocol
+
+		transports = append(transports, quicTransport)
+		endpoints = append(endpoints, quicEp)
+	}
+
+	options, err := getRouterConfig(conf, proxyApp)
+	if err != nil {
+		return nil, err
+	}
+
+	if captureFile := conf.P2P.CaptureFile(); captureFile != "" {
+		options.Capture, err = createCapture(p2pLogger, conf.P2P, captureFile)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return p2p.NewRouter(
 		ctx,
@@ -478,18 +538,39 @@ func createRouter(
 		nodeInfo,
 		nodeKey.PrivKey,
 		peerManager,
-		[]p2p.Transport{transport},
-		[]p2p.Endpoint{ep},
-		getRouterConfig(conf, proxyApp),
+		transports,
+		endpoints,
+		options,
 	)
 }
 
+func createCapture(logger log.Logger, cfg *config.P2PConfig, path string) (*p2p.Capture, error) {
+	channels, err := config.ParseChannelIDs(cfg.CaptureChannels)
+	if err != nil {
+		return nil, fmt.Errorf("invalid capture-channels: %w", err)
+	}
+	options := p2p.CaptureOptions{}
+	for _, chID := range channels {
+		options.Channels = append(options.Channels, p2p.ChannelID(chID))
+	}
+	for _, id := range tmstrings.SplitAndTrimEmpty(cfg.CapturePeers, ",", " ") {
+		options.Peers = append(options.Peers, types.NodeID(id))
+	}
+
+	capture, err := p2p.NewCapture(logger, path, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %w", err)
+	}
+	logger.Info("capturing p2p messages", "file", path, "channels", cfg.CaptureChannels, "peers", cfg.CapturePeers)
+	return capture, nil
+}
+
 func createPEXReactor(
 	ctx context.Context,
 	logger log.Logger,
 	peerManager *p2p.PeerManager,
 	router *p2p.Router,
-) (service.Service, error) {
+) (*pex.Reactor, error) {
 
 	channel, err := router.OpenChannel(ctx, pex.ChannelDescriptor())
 	if err != nil {
@@ -535,6 +616,7 @@ func makeNodeInfo(
 			byte(statesync.ChunkChannel),
 			byte(statesync.LightBlockChannel),
 			byte(statesync.ParamsChannel),
+			byte(statesync.BlockSnapshotChannel),
 		},
 		Moniker: cfg.Moniker,
 		Other: types.NodeInfoOther{
@@ -18,6 +18,9 @@ func (m *Message) Wrap(pb proto.Message) error {
 	case *BlockRequest:
 		m.Sum = &Message_BlockRequest{BlockRequest: msg}
 
+	case *BlockRangeRequest:
+		m.Sum = &Message_BlockRangeRequest{BlockRangeRequest: msg}
+
 	case *BlockResponse:
 		m.Sum = &Message_BlockResponse{BlockResponse: msg}
 
@@ -30,6 +33,21 @@ func (m *Message) Wrap(pb proto.Message) error {
 	case *StatusResponse:
 		m.Sum = &Message_StatusResponse{StatusResponse: msg}
 
+	case *BlockResultsRequest:
+		m.Sum = &Message_BlockResultsRequest{BlockResultsRequest: msg}
+
+	case *NoBlockResultsResponse:
+		m.Sum = &Message_NoBlockResultsResponse{NoBlockResultsResponse: msg}
+
+	case *BlockResultsResponse:
+		m.Sum = &Message_BlockResultsResponse{BlockResultsResponse: msg}
+
+	case *HeaderRequest:
+		m.Sum = &Message_HeaderRequest{HeaderRequest: msg}
+
+	case *HeaderResponse:
+		m.Sum = &Message_HeaderResponse{HeaderResponse: msg}
+
 	default:
 		return fmt.Errorf("unknown message: %T", msg)
 	}
@@ -44,6 +62,9 @@ func (m *Message) Unwrap() (proto.Message, error) {
 	case *Message_BlockRequest:
 		return m.GetBlockRequest(), nil
 
+	case *Message_BlockRangeRequest:
+		return m.GetBlockRangeRequest(), nil
+
 	case *Message_BlockResponse:
 		return m.GetBlockResponse(), nil
 
@@ -56,6 +77,21 @@ func (m *Message) Unwrap() (proto.Message, error) {
 	case *Message_StatusResponse:
 		return m.GetStatusResponse(), nil
 
+	case *Message_BlockResultsRequest:
+		return m.GetBlockResultsRequest(), nil
+
+	case *Message_NoBlockResultsResponse:
+		return m.GetNoBlockResultsResponse(), nil
+
+	case *Message_BlockResultsResponse:
+		return m.GetBlockResultsResponse(), nil
+
+	case *Message_HeaderRequest:
+		return m.GetHeaderRequest(), nil
+
+	case *Message_HeaderResponse:
+		return m.GetHeaderResponse(), nil
+
 	default:
 		return nil, fmt.Errorf("unknown message: %T", msg)
 	}
@@ -73,6 +109,17 @@ func (m *Message) Validate() error {
 			return errors.New("negative Height")
 		}
 
+	case *Message_BlockRangeRequest:
+		if m.GetBlockRangeRequest().StartHeight < 0 {
+			return errors.New("negative StartHeight")
+		}
+		if m.GetBlockRangeRequest().EndHeight < m.GetBlockRangeRequest().StartHeight {
+			return fmt.Errorf(
+				"end height %v cannot be less than start height %v",
+				m.GetBlockRangeRequest().EndHeight, m.GetBlockRangeRequest().StartHeight,
+			)
+		}
+
 	case *Message_BlockResponse:
 		// validate basic is called later when converting from proto
 		return nil
@@ -99,6 +146,36 @@ func (m *Message) Validate() error {
 	case *Message_StatusRequest:
 		return nil
 
+	case *Message_BlockResultsRequest:
+		if m.GetBlockResultsRequest().Height < 0 {
+			return errors.New("negative Height")
+		}
+
+	case *Message_NoBlockResultsResponse:
+		if m.GetNoBlockResultsResponse().Height < 0 {
+			return errors.New("negative Height")
+		}
+
+	case *Message_BlockResultsResponse:
+		if m.GetBlockResultsResponse().Height < 0 {
+			return errors.New("negative Height")
+		}
+		for _, result := range m.GetBlockResultsResponse().DeliverTxs {
+			if result == nil {
+				return errors.New("nil DeliverTx result")
+			}
+		}
+
+	case *Message_HeaderRequest:
+		if m.GetHeaderRequest().Height < 0 {
+			return errors.New("negative Height")
+		}
+
+	case *Message_HeaderResponse:
+		if m.GetHeaderResponse().Header == nil {
+			return errors.New("nil Header")
+		}
+
 	default:
 		return fmt.Errorf("unknown message type: %T", msg)
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/require"
 
+	abci "github.com/tendermint/tendermint/abci/types"
 	bcproto "github.com/tendermint/tendermint/proto/tendermint/blocksync"
 	"github.com/tendermint/tendermint/types"
 )
@@ -34,6 +35,30 @@ func TestBlockRequest_Validate(t *testing.T) {
 	}
 }
 
+func TestBlockRangeRequest_Validate(t *testing.T) {
+	testCases := []struct {
+		testName    string
+		startHeight int64
+		endHeight   int64
+		expectErr   bool
+	}{
+		{"Valid Range Request Message", 0, 0, false},
+		{"Valid Range Request Message", 1, 20, false},
+		{"Invalid Range Request Message", -1, 1, true},
+		{"Invalid Range Request Message", 5, 4, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			msg := &bcproto.Message{}
+			require.NoError(t, msg.Wrap(&bcproto.BlockRangeRequest{StartHeight: tc.startHeight, EndHeight: tc.endHeight}))
+
+			require.Equal(t, tc.expectErr, msg.Validate() != nil)
+		})
+	}
+}
+
 func TestNoBlockResponse_Validate(t *testing.T) {
 	testCases := []struct {
 		testName          string
@@ -56,6 +81,30 @@ func TestNoBlockResponse_Validate(t *testing.T) {
 	}
 }
 
+func TestBlockResultsResponse_Validate(t *testing.T) {
+	testCases := []struct {
+		testName   string
+		height     int64
+		deliverTxs []*abci.ResponseDeliverTx
+		expectErr  bool
+	}{
+		{"Valid Results Response Message", 1, []*abci.ResponseDeliverTx{{Code: 1}}, false},
+		{"Valid Results Response Message", 1, nil, false},
+		{"Invalid Results Response Message", -1, nil, true},
+		{"Invalid Results Response Message", 1, []*abci.ResponseDeliverTx{nil}, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			msg := &bcproto.Message{}
+			require.NoError(t, msg.Wrap(&bcproto.BlockResultsResponse{Height: tc.height, DeliverTxs: tc.deliverTxs}))
+
+			require.Equal(t, tc.expectErr, msg.Validate() != nil)
+		})
+	}
+}
+
 func TestStatusRequest_Validate(t *testing.T) {
 	msg := &bcproto.Message{}
 	require.NoError(t, msg.Wrap(&bcproto.StatusRequest{}))
@@ -117,6 +166,25 @@ func TestBlockchainMessageVectors(t *testing.T) {
 		{"StatusResponseMessage", &bcproto.Message{Sum: &bcproto.Message_StatusResponse{
 			StatusResponse: &bcproto.StatusResponse{Height: math.MaxInt64, Base: math.MaxInt64}}},
 			"2a1408ffffffffffffffff7f10ffffffffffffffff7f"},
+		{"StatusResponseMessage", &bcproto.Message{Sum: &bcproto.Message_StatusResponse{
+			StatusResponse: &bcproto.StatusResponse{Height: 1, Base: 2, RangeRequests: true}}},
+			"2a06080110021801"},
+		{"BlockRangeRequestMessage", &bcproto.Message{Sum: &bcproto.Message_BlockRangeRequest{
+			BlockRangeRequest: &bcproto.BlockRangeRequest{StartHeight: 5, EndHeight: 9}}},
+			"320408051009"},
+		{"BlockResultsRequestMessage", &bcproto.Message{Sum: &bcproto.Message_BlockResultsRequest{
+			BlockResultsRequest: &bcproto.BlockResultsRequest{Height: 1}}},
+			"3a020801"},
+		{"NoBlockResultsResponseMessage", &bcproto.Message{Sum: &bcproto.Message_NoBlockResultsResponse{
+			NoBlockResultsResponse: &bcproto.NoBlockResultsResponse{Height: 1}}},
+			"42020801"},
+		{"BlockResultsResponseMessage", &bcproto.Message{Sum: &bcproto.Message_BlockResultsResponse{
+			BlockResultsResponse: &bcproto.BlockResultsResponse{
+				Height: 1, DeliverTxs: []*abci.ResponseDeliverTx{{Code: 1}}}}},
+			"4a06080112020801"},
+		{"HeaderRequestMessage", &bcproto.Message{Sum: &bcproto.Message_HeaderRequest{
+			HeaderRequest: &bcproto.HeaderRequest{Height: 1}}},
+			"52020801"},
 	}
 
 	for _, tc := range testCases {
@@ -4,6 +4,7 @@
 package p2p
 
 import (
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -367,57 +368,186 @@ func (m *PeerAddressInfo) GetDialFailures() uint32 {
 	return 0
 }
 
+// PeerBan is a ban of a node ID or IP network, persisted in the peer store.
+type PeerBan struct {
+	Target string     `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	Until  *time.Time `protobuf:"bytes,2,opt,name=until,proto3,stdtime" json:"until,omitempty"`
+	Reason string     `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (m *PeerBan) Reset()         { *m = PeerBan{} }
+func (m *PeerBan) String() string { return proto.CompactTextString(m) }
+func (*PeerBan) ProtoMessage()    {}
+func (*PeerBan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8a29e659aeca578, []int{5}
+}
+func (m *PeerBan) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PeerBan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PeerBan.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PeerBan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerBan.Merge(m, src)
+}
+func (m *PeerBan) XXX_Size() int {
+	return m.Size()
+}
+func (m *PeerBan) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerBan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerBan proto.InternalMessageInfo
+
+func (m *PeerBan) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *PeerBan) GetUntil() *time.Time {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+func (m *PeerBan) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// PeerReputation is a peer's reputation, persisted in the peer store next to
+// its PeerInfo.
+type PeerReputation struct {
+	ID         string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reputation float64    `protobuf:"fixed64,2,opt,name=reputation,proto3" json:"reputation,omitempty"`
+	Updated    *time.Time `protobuf:"bytes,3,opt,name=updated,proto3,stdtime" json:"updated,omitempty"`
+}
+
+func (m *PeerReputation) Reset()         { *m = PeerReputation{} }
+func (m *PeerReputation) String() string { return proto.CompactTextString(m) }
+func (*PeerReputation) ProtoMessage()    {}
+func (*PeerReputation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8a29e659aeca578, []int{6}
+}
+func (m *PeerReputation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PeerReputation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PeerReputation.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PeerReputation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerReputation.Merge(m, src)
+}
+func (m *PeerReputation) XXX_Size() int {
+	return m.Size()
+}
+func (m *PeerReputation) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerReputation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerReputation proto.InternalMessageInfo
+
+func (m *PeerReputation) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *PeerReputation) GetReputation() float64 {
+	if m != nil {
+		return m.Reputation
+	}
+	return 0
+}
+
+func (m *PeerReputation) GetUpdated() *time.Time {
+	if m != nil {
+		return m.Updated
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ProtocolVersion)(nil), "tendermint.p2p.ProtocolVersion")
 	proto.RegisterType((*NodeInfo)(nil), "tendermint.p2p.NodeInfo")
 	proto.RegisterType((*NodeInfoOther)(nil), "tendermint.p2p.NodeInfoOther")
 	proto.RegisterType((*PeerInfo)(nil), "tendermint.p2p.PeerInfo")
 	proto.RegisterType((*PeerAddressInfo)(nil), "tendermint.p2p.PeerAddressInfo")
+	proto.RegisterType((*PeerBan)(nil), "tendermint.p2p.PeerBan")
+	proto.RegisterType((*PeerReputation)(nil), "tendermint.p2p.PeerReputation")
 }
 
 func init() { proto.RegisterFile("tendermint/p2p/types.proto", fileDescriptor_c8a29e659aeca578) }
 
 var fileDescriptor_c8a29e659aeca578 = []byte{
-	// 610 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcd, 0x4e, 0x1b, 0x3d,
-	0x14, 0xcd, 0x24, 0x21, 0x09, 0x37, 0x84, 0xf0, 0x59, 0xe8, 0xd3, 0x10, 0xa9, 0x19, 0x14, 0x36,
-	0xac, 0x26, 0x52, 0xaa, 0x2e, 0xba, 0x64, 0x40, 0xad, 0x22, 0x55, 0x25, 0x9a, 0xa2, 0x2e, 0xda,
-	0xc5, 0x68, 0x32, 0x76, 0x82, 0xc5, 0xc4, 0xb6, 0x3c, 0x4e, 0x4b, 0xdf, 0x82, 0x37, 0xe9, 0x63,
-	0x94, 0x25, 0xcb, 0xae, 0xd2, 0x6a, 0xd8, 0xf6, 0x21, 0x2a, 0xdb, 0x33, 0x40, 0xa2, 0x2e, 0xd8,
-	0xf9, 0xdc, 0xe3, 0x73, 0xee, 0x8f, 0xad, 0x0b, 0x3d, 0x45, 0x18, 0x26, 0x72, 0x41, 0x99, 0x1a,
-	0x8a, 0x91, 0x18, 0xaa, 0x6f, 0x82, 0x64, 0xbe, 0x90, 0x5c, 0x71, 0xb4, 0xfb, 0xc8, 0xf9, 0x62,
-	0x24, 0x7a, 0xfb, 0x73, 0x3e, 0xe7, 0x86, 0x1a, 0xea, 0x93, 0xbd, 0xd5, 0xf3, 0xe6, 0x9c, 0xcf,
-	0x53, 0x32, 0x34, 0x68, 0xba, 0x9c, 0x0d, 0x15, 0x5d, 0x90, 0x4c, 0xc5, 0x0b, 0x61, 0x2f, 0x0c,
-	0x2e, 0xa0, 0x3b, 0xd1, 0x87, 0x84, 0xa7, 0x1f, 0x89, 0xcc, 0x28, 0x67, 0xe8, 0x00, 0x6a, 0x62,
-	0x24, 0x5c, 0xe7, 0xd0, 0x39, 0xae, 0x07, 0xcd, 0x7c, 0xe5, 0xd5, 0x26, 0xa3, 0x49, 0xa8, 0x63,
-	0x68, 0x1f, 0xb6, 0xa6, 0x29, 0x4f, 0xae, 0xdc, 0xaa, 0x26, 0x43, 0x0b, 0xd0, 0x1e, 0xd4, 0x62,
-	0x21, 0xdc, 0x9a, 0x89, 0xe9, 0xe3, 0xe0, 0x47, 0x15, 0x5a, 0xef, 0x39, 0x26, 0x63, 0x36, 0xe3,
-	0x68, 0x02, 0x7b, 0xa2, 0x48, 0x11, 0x7d, 0xb1, 0x39, 0x8c, 0x79, 0x7b, 0xe4, 0xf9, 0xeb, 0x4d,
-	0xf8, 0x1b, 0xa5, 0x04, 0xf5, 0xdb, 0x95, 0x57, 0x09, 0xbb, 0x62, 0xa3, 0xc2, 0x23, 0x68, 0x32,
-	0x8e, 0x49, 0x44, 0xb1, 0x29, 0x64, 0x3b, 0x80, 0x7c, 0xe5, 0x35, 0x4c, 0xc2, 0xb3, 0xb0, 0xa1,
-	0xa9, 0x31, 0x46, 0x1e, 0xb4, 0x53, 0x9a, 0x29, 0xc2, 0xa2, 0x18, 0x63, 0x69, 0xaa, 0xdb, 0x0e,
-	0xc1, 0x86, 0x4e, 0x30, 0x96, 0xc8, 0x85, 0x26, 0x23, 0xea, 0x2b, 0x97, 0x57, 0x6e, 0xdd, 0x90,
-	0x25, 0xd4, 0x4c, 0x59, 0xe8, 0x96, 0x65, 0x0a, 0x88, 0x7a, 0xd0, 0x4a, 0x2e, 0x63, 0xc6, 0x48,
-	0x9a, 0xb9, 0x8d, 0x43, 0xe7, 0x78, 0x27, 0x7c, 0xc0, 0x5a, 0xb5, 0xe0, 0x8c, 0x5e, 0x11, 0xe9,
-	0x36, 0xad, 0xaa, 0x80, 0xe8, 0x35, 0x6c, 0x71, 0x75, 0x49, 0xa4, 0xdb, 0x32, 0x6d, 0xbf, 0xd8,
-	0x6c, 0xbb, 0x1c, 0xd5, 0xb9, 0xbe, 0x54, 0x34, 0x6d, 0x15, 0x83, 0xcf, 0xd0, 0x59, 0x63, 0xd1,
-	0x01, 0xb4, 0xd4, 0x75, 0x44, 0x19, 0x26, 0xd7, 0x66, 0x8a, 0xdb, 0x61, 0x53, 0x5d, 0x8f, 0x35,
-	0x44, 0x43, 0x68, 0x4b, 0x91, 0x98, 0x76, 0x49, 0x96, 0x15, 0xa3, 0xd9, 0xcd, 0x57, 0x1e, 0x84,
-	0x93, 0xd3, 0x13, 0x1b, 0x0d, 0x41, 0x8a, 0xa4, 0x38, 0x0f, 0xbe, 0x3b, 0xd0, 0x9a, 0x10, 0x22,
-	0xcd, 0x33, 0xfd, 0x0f, 0x55, 0x8a, 0xad, 0x65, 0xd0, 0xc8, 0x57, 0x5e, 0x75, 0x7c, 0x16, 0x56,
-	0x29, 0x46, 0x01, 0xec, 0x14, 0x8e, 0x11, 0x65, 0x33, 0xee, 0x56, 0x0f, 0x6b, 0xff, 0x7c, 0x3a,
-	0x42, 0x64, 0xe1, 0xab, 0xed, 0xc2, 0x76, 0xfc, 0x08, 0xd0, 0x5b, 0xd8, 0x4d, 0xe3, 0x4c, 0x45,
-	0x09, 0x67, 0x8c, 0x24, 0x8a, 0x60, 0xf3, 0x1c, 0xed, 0x51, 0xcf, 0xb7, 0xff, 0xd3, 0x2f, 0xff,
-	0xa7, 0x7f, 0x51, 0xfe, 0xcf, 0xa0, 0x7e, 0xf3, 0xcb, 0x73, 0xc2, 0x8e, 0xd6, 0x9d, 0x96, 0xb2,
-	0xc1, 0x1f, 0x07, 0xba, 0x1b, 0x99, 0xf4, 0xdc, 0xcb, 0x96, 0x8b, 0x81, 0x14, 0x10, 0xbd, 0x83,
-	0xff, 0x4c, 0x5a, 0x4c, 0xe3, 0x34, 0xca, 0x96, 0x49, 0x52, 0x8e, 0xe5, 0x39, 0x99, 0xbb, 0x5a,
-	0x7a, 0x46, 0xe3, 0xf4, 0x83, 0x15, 0xae, 0xbb, 0xcd, 0x62, 0x9a, 0x2e, 0x25, 0x79, 0x76, 0x1f,
-	0x0f, 0x6e, 0x6f, 0xac, 0x10, 0x1d, 0x41, 0xe7, 0xa9, 0x51, 0x66, 0xfe, 0x60, 0x27, 0xdc, 0xc1,
-	0x8f, 0x77, 0xb2, 0xe0, 0xfc, 0x36, 0xef, 0x3b, 0x77, 0x79, 0xdf, 0xf9, 0x9d, 0xf7, 0x9d, 0x9b,
-	0xfb, 0x7e, 0xe5, 0xee, 0xbe, 0x5f, 0xf9, 0x79, 0xdf, 0xaf, 0x7c, 0x7a, 0x35, 0xa7, 0xea, 0x72,
-	0x39, 0xf5, 0x13, 0xbe, 0x18, 0x3e, 0xd9, 0x12, 0x4f, 0x17, 0x86, 0xd9, 0x05, 0xeb, 0x1b, 0x64,
-	0xda, 0x30, 0xd1, 0x97, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x0b, 0xe9, 0x56, 0xd3, 0x5a, 0x04,
-	0x00, 0x00,
+	// 685 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x16, 0xf5, 0xef, 0x91, 0x25, 0xb9, 0x0b, 0xc3, 0xa0, 0x05, 0x54, 0x34, 0xe4, 0x8b, 0x4f,
+	0x14, 0xa0, 0xa2, 0x05, 0xda, 0x9b, 0x69, 0xa3, 0x85, 0x80, 0xa2, 0x16, 0x58, 0xa3, 0x87, 0xe6,
+	0x20, 0x50, 0xdc, 0x95, 0xbc, 0x30, 0xb5, 0xbb, 0x59, 0xae, 0x12, 0xe7, 0x9e, 0x07, 0xf0, 0x9b,
+	0xe4, 0x31, 0xe2, 0xa3, 0x8f, 0x39, 0x29, 0x81, 0x7c, 0xcd, 0x43, 0x04, 0xbb, 0x4b, 0xda, 0x92,
+	0x90, 0x00, 0xf6, 0x6d, 0xbe, 0xf9, 0xf9, 0x66, 0xbe, 0xd9, 0x21, 0xa1, 0xa3, 0x08, 0xc3, 0x44,
+	0xce, 0x29, 0x53, 0x7d, 0x31, 0x10, 0x7d, 0xf5, 0x4e, 0x90, 0xd4, 0x17, 0x92, 0x2b, 0x8e, 0x5a,
+	0x4f, 0x31, 0x5f, 0x0c, 0x44, 0x67, 0x7f, 0xc6, 0x67, 0xdc, 0x84, 0xfa, 0xda, 0xb2, 0x59, 0x1d,
+	0x6f, 0xc6, 0xf9, 0x2c, 0x21, 0x7d, 0x83, 0x26, 0x8b, 0x69, 0x5f, 0xd1, 0x39, 0x49, 0x55, 0x34,
+	0x17, 0x36, 0xa1, 0x77, 0x09, 0xed, 0x91, 0x36, 0x62, 0x9e, 0xfc, 0x47, 0x64, 0x4a, 0x39, 0x43,
+	0x87, 0x50, 0x12, 0x03, 0xe1, 0x3a, 0x47, 0xce, 0x49, 0x39, 0xa8, 0xad, 0x96, 0x5e, 0x69, 0x34,
+	0x18, 0x85, 0xda, 0x87, 0xf6, 0xa1, 0x32, 0x49, 0x78, 0x7c, 0xed, 0x16, 0x75, 0x30, 0xb4, 0x00,
+	0xed, 0x41, 0x29, 0x12, 0xc2, 0x2d, 0x19, 0x9f, 0x36, 0x7b, 0x1f, 0x8b, 0x50, 0xff, 0x87, 0x63,
+	0x32, 0x64, 0x53, 0x8e, 0x46, 0xb0, 0x27, 0xb2, 0x16, 0xe3, 0x37, 0xb6, 0x87, 0x21, 0x6f, 0x0c,
+	0x3c, 0x7f, 0x53, 0x84, 0xbf, 0x35, 0x4a, 0x50, 0xbe, 0x5b, 0x7a, 0x85, 0xb0, 0x2d, 0xb6, 0x26,
+	0x3c, 0x86, 0x1a, 0xe3, 0x98, 0x8c, 0x29, 0x36, 0x83, 0xec, 0x04, 0xb0, 0x5a, 0x7a, 0x55, 0xd3,
+	0xf0, 0x3c, 0xac, 0xea, 0xd0, 0x10, 0x23, 0x0f, 0x1a, 0x09, 0x4d, 0x15, 0x61, 0xe3, 0x08, 0x63,
+	0x69, 0xa6, 0xdb, 0x09, 0xc1, 0xba, 0x4e, 0x31, 0x96, 0xc8, 0x85, 0x1a, 0x23, 0xea, 0x2d, 0x97,
+	0xd7, 0x6e, 0xd9, 0x04, 0x73, 0xa8, 0x23, 0xf9, 0xa0, 0x15, 0x1b, 0xc9, 0x20, 0xea, 0x40, 0x3d,
+	0xbe, 0x8a, 0x18, 0x23, 0x49, 0xea, 0x56, 0x8f, 0x9c, 0x93, 0xdd, 0xf0, 0x11, 0xeb, 0xaa, 0x39,
+	0x67, 0xf4, 0x9a, 0x48, 0xb7, 0x66, 0xab, 0x32, 0x88, 0x7e, 0x87, 0x0a, 0x57, 0x57, 0x44, 0xba,
+	0x75, 0x23, 0xfb, 0xe7, 0x6d, 0xd9, 0xf9, 0xaa, 0x2e, 0x74, 0x52, 0x26, 0xda, 0x56, 0xf4, 0x5e,
+	0x41, 0x73, 0x23, 0x8a, 0x0e, 0xa1, 0xae, 0x6e, 0xc6, 0x94, 0x61, 0x72, 0x63, 0xb6, 0xb8, 0x13,
+	0xd6, 0xd4, 0xcd, 0x50, 0x43, 0xd4, 0x87, 0x86, 0x14, 0xb1, 0x91, 0x4b, 0xd2, 0x34, 0x5b, 0x4d,
+	0x6b, 0xb5, 0xf4, 0x20, 0x1c, 0x9d, 0x9d, 0x5a, 0x6f, 0x08, 0x52, 0xc4, 0x99, 0xdd, 0xfb, 0xe0,
+	0x40, 0x7d, 0x44, 0x88, 0x34, 0xcf, 0x74, 0x00, 0x45, 0x8a, 0x2d, 0x65, 0x50, 0x5d, 0x2d, 0xbd,
+	0xe2, 0xf0, 0x3c, 0x2c, 0x52, 0x8c, 0x02, 0xd8, 0xcd, 0x18, 0xc7, 0x94, 0x4d, 0xb9, 0x5b, 0x3c,
+	0x2a, 0x7d, 0xf7, 0xe9, 0x08, 0x91, 0x19, 0xaf, 0xa6, 0x0b, 0x1b, 0xd1, 0x13, 0x40, 0x7f, 0x41,
+	0x2b, 0x89, 0x52, 0x35, 0x8e, 0x39, 0x63, 0x24, 0x56, 0x04, 0x9b, 0xe7, 0x68, 0x0c, 0x3a, 0xbe,
+	0xbd, 0x4f, 0x3f, 0xbf, 0x4f, 0xff, 0x32, 0xbf, 0xcf, 0xa0, 0x7c, 0xfb, 0xd9, 0x73, 0xc2, 0xa6,
+	0xae, 0x3b, 0xcb, 0xcb, 0x7a, 0x5f, 0x1d, 0x68, 0x6f, 0x75, 0xd2, 0x7b, 0xcf, 0x25, 0x67, 0x0b,
+	0xc9, 0x20, 0xfa, 0x1b, 0x7e, 0x32, 0x6d, 0x31, 0x8d, 0x92, 0x71, 0xba, 0x88, 0xe3, 0x7c, 0x2d,
+	0xcf, 0xe9, 0xdc, 0xd6, 0xa5, 0xe7, 0x34, 0x4a, 0xfe, 0xb5, 0x85, 0x9b, 0x6c, 0xd3, 0x88, 0x26,
+	0x0b, 0x49, 0xdc, 0xd2, 0x4b, 0xd9, 0xfe, 0xb4, 0x85, 0xe8, 0x18, 0x9a, 0xeb, 0x44, 0xa9, 0xb9,
+	0xc1, 0x66, 0xb8, 0x8b, 0x9f, 0x72, 0xd2, 0xde, 0x6b, 0xa8, 0x69, 0xb5, 0x41, 0xc4, 0xd0, 0x01,
+	0x54, 0x55, 0x24, 0x67, 0x44, 0x65, 0x22, 0x33, 0x84, 0x7e, 0x83, 0xca, 0x82, 0x29, 0x9a, 0x3c,
+	0x5b, 0x97, 0x4d, 0xd7, 0x7c, 0x92, 0x44, 0x29, 0x67, 0xd9, 0x97, 0x91, 0xa1, 0xde, 0x7b, 0x07,
+	0x5a, 0xba, 0x67, 0x48, 0xc4, 0x42, 0x45, 0x4a, 0x1f, 0xfd, 0x8f, 0x2e, 0xa3, 0x0b, 0x20, 0x1f,
+	0xb3, 0x4c, 0x7f, 0x27, 0x5c, 0xf3, 0xa0, 0x3f, 0xa0, 0xb6, 0x10, 0x38, 0x7a, 0xc9, 0x73, 0xe7,
+	0x05, 0xc1, 0xc5, 0xdd, 0xaa, 0xeb, 0xdc, 0xaf, 0xba, 0xce, 0x97, 0x55, 0xd7, 0xb9, 0x7d, 0xe8,
+	0x16, 0xee, 0x1f, 0xba, 0x85, 0x4f, 0x0f, 0xdd, 0xc2, 0xff, 0xbf, 0xce, 0xa8, 0xba, 0x5a, 0x4c,
+	0xfc, 0x98, 0xcf, 0xfb, 0x6b, 0xff, 0xc7, 0x35, 0xd3, 0xfe, 0x05, 0x37, 0xff, 0x9d, 0x93, 0xaa,
+	0xf1, 0xfe, 0xf2, 0x6d, 0x00, 0x67, 0x4c, 0x46, 0x26, 0x54, 0x05, 0x00, 0x00,
 }
 
 func (m *ProtocolVersion) Marshal() (dAtA []byte, err error) {
@@ -689,6 +819,99 @@ func (m *PeerAddressInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *PeerBan) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PeerBan) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PeerBan) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Reason) > 0 {
+		i -= len(m.Reason)
+		copy(dAtA[i:], m.Reason)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Reason)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Until != nil {
+		n6, err6 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.Until, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.Until):])
+		if err6 != nil {
+			return 0, err6
+		}
+		i -= n6
+		i = encodeVarintTypes(dAtA, i, uint64(n6))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Target) > 0 {
+		i -= len(m.Target)
+		copy(dAtA[i:], m.Target)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Target)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PeerReputation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PeerReputation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PeerReputation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Updated != nil {
+		n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.Updated, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.Updated):])
+		if err7 != nil {
+			return 0, err7
+		}
+		i -= n7
+		i = encodeVarintTypes(dAtA, i, uint64(n7))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Reputation != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Reputation))))
+		i--
+		dAtA[i] = 0x11
+	}
+	if len(m.ID) > 0 {
+		i -= len(m.ID)
+		copy(dAtA[i:], m.ID)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTypes(v)
 	base := offset
@@ -819,6 +1042,47 @@ func (m *PeerAddressInfo) Size() (n int) {
 	return n
 }
 
+func (m *PeerBan) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Target)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.Until != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.Until)
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
+func (m *PeerReputation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.Reputation != 0 {
+		n += 9
+	}
+	if m.Updated != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.Updated)
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func sovTypes(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1681,6 +1945,285 @@ func (m *PeerAddressInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *PeerBan) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerBan: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerBan: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Target = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Until", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Until == nil {
+				m.Until = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.Until, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PeerReputation) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerReputation: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerReputation: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reputation", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Reputation = float64(math.Float64frombits(v))
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Updated", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Updated == nil {
+				m.Updated = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.Updated, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTypes(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
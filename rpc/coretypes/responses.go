@@ -113,6 +113,15 @@ type SyncInfo struct {
 	SnapshotChunksTotal int64         `json:"snapshot_chunks_total"`
 	BackFilledBlocks    int64         `json:"backfilled_blocks"`
 	BackFillBlocksTotal int64         `json:"backfill_blocks_total"`
+
+	// StateSync is the progress of the current or last state sync, if any.
+	StateSync *types.EventDataStateSyncProgress `json:"state_sync,omitempty"`
+}
+
+// State sync progress. Progress is omitted if the node has not started a
+// state sync.
+type ResultStateSyncStatus struct {
+	Progress *types.EventDataStateSyncProgress `json:"progress,omitempty"`
 }
 
 // Info about the node's validator
@@ -157,8 +166,71 @@ type ResultDialPeers struct {
 
 // A peer
 type Peer struct {
-	ID  types.NodeID `json:"node_id"`
-	URL string       `json:"url"`
+	ID       types.NodeID     `json:"node_id"`
+	URL      string           `json:"url"`
+	Channels []ChannelTraffic `json:"channels,omitempty"`
+}
+
+// Traffic with a peer on a channel. Rates are in bytes/second, and Dropped
+// counts the messages dropped by the channel rate limits, which are not
+// included in the other counts.
+type ChannelTraffic struct {
+	ChannelID    uint16 `json:"channel_id"`
+	SendBytes    int64  `json:"send_bytes"`
+	SendMessages int64  `json:"send_messages"`
+	SendRate     int64  `json:"send_rate"`
+	RecvBytes    int64  `json:"recv_bytes"`
+	RecvMessages int64  `json:"recv_messages"`
+	RecvRate     int64  `json:"recv_rate"`
+	Dropped      int64  `json:"dropped"`
+}
+
+// Banned node IDs and IP networks
+type ResultNetBans struct {
+	Bans []PeerBan `json:"bans"`
+}
+
+// A ban of a node ID or IP network. Until is omitted for bans that never
+// expire.
+type PeerBan struct {
+	Target string     `json:"target"`
+	Until  *time.Time `json:"until,omitempty"`
+	Reason string     `json:"reason"`
+}
+
+// Network crawl of a seed node. LastCrawl is omitted if no crawl has
+// completed yet.
+type ResultNetCrawl struct {
+	LastCrawl  *time.Time    `json:"last_crawl,omitempty"`
+	NPeers     int           `json:"n_peers"`
+	NReachable int           `json:"n_reachable"`
+	Peers      []CrawledPeer `json:"peers"`
+}
+
+// A peer recorded by a network crawl. The version, protocol version, network,
+// channels and moniker are those the peer advertised when it was last
+// connected, and are omitted if it has not been connected since the seed
+// started.
+type CrawledPeer struct {
+	ID              types.NodeID           `json:"node_id"`
+	Addresses       []CrawledAddress       `json:"addresses"`
+	Connected       bool                   `json:"connected"`
+	Reachable       bool                   `json:"reachable"`
+	LastSeen        *time.Time             `json:"last_seen,omitempty"`
+	Height          int64                  `json:"height"`
+	Version         string                 `json:"version,omitempty"`
+	ProtocolVersion *types.ProtocolVersion `json:"protocol_version,omitempty"`
+	Network         string                 `json:"network,omitempty"`
+	Channels        bytes.HexBytes         `json:"channels,omitempty"`
+	Moniker         string                 `json:"moniker,omitempty"`
+}
+
+// The dial history of a crawled peer address.
+type CrawledAddress struct {
+	URL             string     `json:"url"`
+	LastDialSuccess *time.Time `json:"last_dial_success,omitempty"`
+	LastDialFailure *time.Time `json:"last_dial_failure,omitempty"`
+	DialFailures    uint32     `json:"dial_failures"`
 }
 
 // Validators for a height.
@@ -266,11 +338,15 @@ type ResultBroadcastEvidence struct {
 
 // empty results
 type (
-	ResultUnsafeFlushMempool struct{}
-	ResultUnsafeProfile      struct{}
-	ResultSubscribe          struct{}
-	ResultUnsubscribe        struct{}
-	ResultHealth             struct{}
+	ResultUnsafeFlushMempool   struct{}
+	ResultUnsafeProfile        struct{}
+	ResultUnsafeBanPeer        struct{}
+	ResultUnsafeUnbanPeer      struct{}
+	ResultUnsafeDialPeer       struct{}
+	ResultUnsafeDisconnectPeer struct{}
+	ResultSubscribe            struct{}
+	ResultUnsubscribe          struct{}
+	ResultHealth               struct{}
 )
 
 // Event data from a subscription
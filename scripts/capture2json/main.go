@@ -0,0 +1,63 @@
+/*
+	capture2json converts a binary p2p message capture file to JSON, one message
+	per line. Payloads are base64-encoded Protobuf messages.
+
+	Usage:
+			capture2json <path-to-capture>
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/tendermint/tendermint/internal/p2p"
+)
+
+type capturedMessage struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"direction"`
+	PeerID    string    `json:"peer_id"`
+	ChannelID uint16    `json:"channel_id"`
+	Payload   []byte    `json:"payload"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("missing one argument: <path-to-capture>")
+		os.Exit(1)
+	}
+
+	f, err := os.Open(os.Args[1])
+	if err != nil {
+		panic(fmt.Errorf("failed to open capture file: %v", err))
+	}
+	defer f.Close()
+
+	dec := p2p.NewCaptureDecoder(f)
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		msg, err := dec.Decode()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			panic(fmt.Errorf("failed to decode msg: %v", err))
+		}
+
+		err = enc.Encode(capturedMessage{
+			Time:      msg.Time,
+			Direction: msg.Direction.String(),
+			PeerID:    string(msg.PeerID),
+			ChannelID: uint16(msg.ChannelID),
+			Payload:   msg.Payload,
+		})
+		if err != nil {
+			fmt.Println("Failed to write message", err)
+			os.Exit(1) //nolint:gocritic
+		}
+	}
+}
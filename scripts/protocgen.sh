@@ -18,7 +18,18 @@ REF=$(curl -H "Accept: application/vnd.github.v3.sha" -qL \
 readonly OUTDIR="tendermint-spec-${REF}"
 curl -qL "https://api.github.com/repos/tendermint/spec/tarball/${REF}" | tar -xzf - ${OUTDIR}/
 
-cp -r ${OUTDIR}/proto/tendermint/* ./proto/tendermint
+# Proto files kept in this repository extend their spec counterparts with
+# messages the spec doesn't have yet, so they take precedence over the spec.
+# Only the spec files which are copied are removed again below.
+readonly COPIED="${OUTDIR}/copied.txt"
+touch ${COPIED}
+for FILE in $(cd ${OUTDIR}/proto && find tendermint -name '*.proto'); do
+	if [ ! -e "./proto/${FILE}" ]; then
+		mkdir -p "$(dirname ./proto/${FILE})"
+		cp "${OUTDIR}/proto/${FILE}" "./proto/${FILE}"
+		echo "./proto/${FILE}" >> ${COPIED}
+	fi
+done
 cp -r ${OUTDIR}/third_party/** ./third_party
 
 MODNAME="$(go list -m)"
@@ -37,8 +48,6 @@ echo "proto files have been compiled"
 
 echo "removing copied files"
 
-find ${OUTDIR}/proto/tendermint/ -name *.proto \
-	| sed "s/$OUTDIR\/\(.*\)/\1/g" \
-	| xargs -I {} rm {}
+xargs -I {} rm {} < ${COPIED}
 
 rm -rf ${OUTDIR}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 	tmjson "github.com/tendermint/tendermint/libs/json"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
@@ -37,11 +39,14 @@ const (
 	EventPolkaValue           = "Polka"
 	EventRelockValue          = "Relock"
 	EventStateSyncStatusValue = "StateSyncStatus"
-	EventTimeoutProposeValue  = "TimeoutPropose"
-	EventTimeoutWaitValue     = "TimeoutWait"
-	EventUnlockValue          = "Unlock"
-	EventValidBlockValue      = "ValidBlock"
-	EventVoteValue            = "Vote"
+	// The StateSyncProgress event is emitted periodically while state syncing,
+	// and whenever the state sync moves to another phase.
+	EventStateSyncProgressValue = "StateSyncProgress"
+	EventTimeoutProposeValue    = "TimeoutPropose"
+	EventTimeoutWaitValue       = "TimeoutWait"
+	EventUnlockValue            = "Unlock"
+	EventValidBlockValue        = "ValidBlock"
+	EventVoteValue              = "Vote"
 )
 
 // Pre-populated ABCI Tendermint-reserved events
@@ -107,6 +112,7 @@ func init() {
 	tmjson.RegisterType(EventDataString(""), "tendermint/event/ProposalString")
 	tmjson.RegisterType(EventDataBlockSyncStatus{}, "tendermint/event/FastSyncStatus")
 	tmjson.RegisterType(EventDataStateSyncStatus{}, "tendermint/event/StateSyncStatus")
+	tmjson.RegisterType(EventDataStateSyncProgress{}, "tendermint/event/StateSyncProgress")
 }
 
 // Most event messages are basic types (a block, a transaction)
@@ -191,6 +197,55 @@ type EventDataStateSyncStatus struct {
 	Height   int64 `json:"height"`
 }
 
+// State sync phases, see EventDataStateSyncProgress.
+const (
+	StateSyncPhaseDiscovery = "discovery"
+	StateSyncPhaseRestore   = "restore"
+	StateSyncPhaseBackfill  = "backfill"
+	StateSyncPhaseComplete  = "complete"
+)
+
+// EventDataStateSyncProgress shows the progress of a state sync: the
+// snapshots discovered, the chunks of the snapshot being restored and the
+// light blocks backfilled afterwards. RemainingTime is an estimate for the
+// current phase, and is zero if unknown.
+type EventDataStateSyncProgress struct {
+	Phase     string              `json:"phase"`
+	StartTime time.Time           `json:"start_time"`
+	Snapshots []StateSyncSnapshot `json:"snapshots"`
+	Snapshot  *StateSyncSnapshot  `json:"snapshot,omitempty"`
+
+	ChunksTotal   int64           `json:"chunks_total"`
+	ChunksFetched int64           `json:"chunks_fetched"`
+	ChunksApplied int64           `json:"chunks_applied"`
+	ChunksRetried int64           `json:"chunks_retried"`
+	Peers         []StateSyncPeer `json:"peers"`
+
+	BackfilledBlocks    int64 `json:"backfilled_blocks"`
+	BackfillBlocksTotal int64 `json:"backfill_blocks_total"`
+
+	RemainingTime time.Duration `json:"remaining_time"`
+}
+
+// StateSyncSnapshot is a snapshot discovered by state sync, and the number
+// of peers offering it.
+type StateSyncSnapshot struct {
+	Height uint64           `json:"height"`
+	Format uint32           `json:"format"`
+	Chunks uint32           `json:"chunks"`
+	Hash   tmbytes.HexBytes `json:"hash"`
+	Peers  int              `json:"peers"`
+}
+
+// StateSyncPeer shows the chunks fetched from a peer, and the average rate at
+// which the peer sent them since they were requested.
+type StateSyncPeer struct {
+	NodeID         NodeID `json:"node_id"`
+	ChunksFetched  int64  `json:"chunks_fetched"`
+	BytesFetched   int64  `json:"bytes_fetched"`
+	BytesPerSecond int64  `json:"bytes_per_second"`
+}
+
 // PUBSUB
 
 const (
@@ -230,6 +285,7 @@ var (
 	EventQueryVote                = QueryForEvent(EventVoteValue)
 	EventQueryBlockSyncStatus     = QueryForEvent(EventBlockSyncStatusValue)
 	EventQueryStateSyncStatus     = QueryForEvent(EventStateSyncStatusValue)
+	EventQueryStateSyncProgress   = QueryForEvent(EventStateSyncProgressValue)
 )
 
 func EventQueryTxFor(tx Tx) tmpubsub.Query {